@@ -5,44 +5,55 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"time"
 
 	api "github.com/robstave/rto/internal"
 
 	"github.com/labstack/echo/v4"
 	"github.com/robstave/rto/internal/adapters/controller"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/database"
 	"github.com/robstave/rto/logger"
 	slogecho "github.com/samber/slog-echo"
 )
 
-// Quarter configuration – centralizing start/end dates for the quarter
-var (
-	QuarterStart = time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC)
-	QuarterEnd   = time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
-)
-
 // TemplateRenderer is a custom renderer for Echo
 type TemplateRenderer struct {
 	templates *template.Template
 }
 
-// Render renders a template document
+// Render renders a template document. Pages get the request's CSRF token as
+// .CSRF for their forms and AJAX calls.
 func (t *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	if m, ok := data.(map[string]interface{}); ok {
+		m["CSRF"] = c.Get(api.CSRFContextKey)
+	}
 	return t.templates.ExecuteTemplate(w, name, data)
 }
 
 func main() {
 
 	// Read DB_PATH from environment variable, set a default if not provided
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./data/db.sqlite3" // Default path
-	}
+	dbPath := config.DBPath()
 
 	slogger := logger.InitializeLogger()
 	logger.SetLogger(slogger) // Optional: If you prefer setting a package-level logger
-	rtoClt := controller.NewRTOController(dbPath, slogger, QuarterStart, QuarterEnd)
+
+	// Connect, migrate the schema and add the holidays and first period, the
+	// same steps as rto-admin migrate and seed-holidays
+	db, err := database.Open(dbPath, slogger)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	if err := database.Seed(db, slogger, config.QuarterStart, config.QuarterEnd); err != nil {
+		log.Fatalf("seed database: %v", err)
+	}
+
+	rtoClt := controller.NewRTOController(db, dbPath, slogger, config.QuarterStart, config.QuarterEnd)
+	rtoClt.ClaimUnownedData()
 
 	// Initialize session middleware with a cookie store
 
@@ -67,8 +78,13 @@ func main() {
 	log.Println("templates loaded")
 
 	log.Println("starting")
-	// Start the server on port 8761
-	if err := e.Start(":8761"); err != nil && err != http.ErrServerClosed {
+	// Start the server on port 8761, over HTTPS when a certificate is configured
+	if certFile, keyFile, ok := config.TLSFiles(); ok {
+		err = e.StartTLS(":8761", certFile, keyFile)
+	} else {
+		err = e.Start(":8761")
+	}
+	if err != nil && err != http.ErrServerClosed {
 		log.Fatal("shutting down the server")
 	}
 
@@ -0,0 +1,47 @@
+// Command mock-idp runs a throwaway OpenID Connect provider for trying single
+// sign-on locally. Every sign-in is approved as the user given by the flags.
+//
+//	go run ./cmd/mock-idp -email alice@example.com -groups rto-admins
+//	OIDC_ISSUER=http://localhost:8762 OIDC_CLIENT_ID=rto OIDC_CLIENT_SECRET=dev go run ./cmd/main
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/robstave/rto/internal/adapters/sso/mockidp"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8762", "listen address")
+	clientID := flag.String("client-id", "rto", "client ID the app must use")
+	clientSecret := flag.String("client-secret", "dev", "client secret the app must use")
+	subject := flag.String("sub", "mock-user", "subject of the signed-in user")
+	email := flag.String("email", "", "email of the signed-in user (sent as verified)")
+	username := flag.String("username", "", "preferred_username of the signed-in user")
+	groups := flag.String("groups", "", "comma separated groups; omitted from the token when empty")
+	flag.Parse()
+
+	provider, err := mockidp.New("http://"+*addr, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	claims := map[string]interface{}{"sub": *subject}
+	if *email != "" {
+		claims["email"] = *email
+		claims["email_verified"] = true
+	}
+	if *username != "" {
+		claims["preferred_username"] = *username
+	}
+	if *groups != "" {
+		claims["groups"] = strings.Split(*groups, ",")
+	}
+	provider.SetClaims(claims)
+
+	log.Printf("mock identity provider at %s (client %q, subject %q)", provider.Issuer, *clientID, *subject)
+	log.Fatal(http.ListenAndServe(*addr, provider))
+}
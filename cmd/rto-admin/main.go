@@ -0,0 +1,583 @@
+// Command rto-admin runs maintenance tasks directly against the SQLite
+// database, using the same domain service as the server.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/mail"
+	repo "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/database"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"golang.org/x/term"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: rto-admin [options] <command> [arguments]
+
+Commands:
+  migrate                      bring the schema up to date, add the first period
+                               if missing and give data from before accounts
+                               existed to the first admin
+  seed-holidays [--file F]     add holidays from a JSON file (default static/holidays.json)
+  backup DEST                  write a consistent copy of the database to DEST
+  restore SRC                  replace the database with a backup (stop the server first);
+                               the current database is kept as <db>.<time>.bak
+  check                        run SQLite's integrity check and look for
+                               duplicate or conflicting events
+  create-user [--role R | --admin] [--email E] NAME
+                               add an account (role employee, manager or admin);
+                               the password is read from the terminal, or the
+                               first line of stdin
+  set-role NAME ROLE           make the account an employee, manager or admin
+  set-manager NAME MANAGER     set who the account reports to ("" clears it)
+  set-email NAME EMAIL         set the address single sign-on links the account
+                               by ("" clears it)
+  reset-password NAME          set a new password and clear any lockout
+  reset-2fa NAME               turn off two-factor sign-in for a lost device
+  sign-out NAME                end every browser session of the account
+  fill-defaults [--user NAME] [--from D --to D]
+                               add default attendance to a user's empty weekdays
+                               (default: the only user and the current period)
+  send-reminders               email the reminders opted-in users are due today;
+                               needs SMTP_HOST (and the other SMTP_* settings)
+
+Options:
+`
+
+// errUsage marks errors caused by bad arguments
+var errUsage = errors.New("usage")
+
+// errIssues is returned by check when it finds problems
+var errIssues = errors.New("check found problems")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("rto-admin", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dbPath := flags.String("db", config.DBPath(), "SQLite database")
+	verbose := flags.Bool("v", false, "log service activity to stderr")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+
+	admin := &admin{dbPath: *dbPath, logger: logger, in: os.Stdin, out: stdout, errOut: stderr}
+	defer admin.close()
+
+	err := admin.dispatch(flags.Arg(0), flags.Args()[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errIssues):
+		return 1
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "rto-admin:", err)
+		return 2
+	default:
+		fmt.Fprintln(stderr, "rto-admin:", err)
+		return 1
+	}
+}
+
+type admin struct {
+	dbPath string
+	logger *slog.Logger
+	in     *os.File
+	out    io.Writer
+	errOut io.Writer
+
+	db *gorm.DB
+}
+
+// open connects to (and migrates) the database on first use
+func (a *admin) open() (*gorm.DB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+	db, err := database.OpenQuiet(a.dbPath, a.logger)
+	if err != nil {
+		return nil, err
+	}
+	a.db = db
+	return db, nil
+}
+
+func (a *admin) close() {
+	if a.db == nil {
+		return
+	}
+	if sqlDB, err := a.db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// service builds the domain service over the open database
+func (a *admin) service() (domain.RTOBLL, error) {
+	db, err := a.open()
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewService(
+		a.logger,
+		repo.NewEventRepositorySQLite(db),
+		repo.NewPreferenceRepositorySQLite(db),
+		repo.NewPeriodRepositorySQLite(db),
+		repo.NewTokenRepositorySQLite(db),
+		repo.NewWebhookRepositorySQLite(db),
+		repo.NewUserRepositorySQLite(db),
+		repo.NewSettingRepositorySQLite(db),
+		repo.NewSessionRepositorySQLite(db),
+		repo.NewCompanyRepositorySQLite(db),
+		repo.NewOfficeRepositorySQLite(db),
+		repo.NewNotificationRepositorySQLite(db),
+		config.QuarterStart,
+		config.QuarterEnd,
+	), nil
+}
+
+func (a *admin) dispatch(command string, args []string) error {
+	switch command {
+	case "migrate":
+		return a.migrate(args)
+	case "seed-holidays":
+		return a.seedHolidays(args)
+	case "backup":
+		return a.backup(args)
+	case "restore":
+		return a.restore(args)
+	case "check":
+		return a.check(args)
+	case "create-user":
+		return a.createUser(args)
+	case "reset-password":
+		return a.resetPassword(args)
+	case "set-email":
+		return a.setEmail(args)
+	case "set-role":
+		return a.setRole(args)
+	case "set-manager":
+		return a.setManager(args)
+	case "reset-2fa":
+		return a.resetTwoFactor(args)
+	case "sign-out":
+		return a.signOut(args)
+	case "fill-defaults":
+		return a.fillDefaults(args)
+	case "send-reminders":
+		return a.sendReminders(args)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+}
+
+func (a *admin) migrate(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: migrate takes no arguments", errUsage)
+	}
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+	if err := database.SeedPeriods(db, a.logger, config.QuarterStart, config.QuarterEnd); err != nil {
+		return err
+	}
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	claimed, err := service.ClaimUnownedData()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%s: schema up to date (%d tables)\n", a.dbPath, len(database.Models))
+	if claimed > 0 {
+		fmt.Fprintf(a.out, "assigned %d unowned row(s) to the first admin\n", claimed)
+	}
+	return nil
+}
+
+func (a *admin) seedHolidays(args []string) error {
+	flags := flag.NewFlagSet("seed-holidays", flag.ContinueOnError)
+	flags.SetOutput(a.errOut)
+	file := flags.String("file", database.HolidaysFile, "JSON file with date, description and type")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+	added, err := database.SeedHolidays(db, a.logger, *file)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "added %d holiday(s) from %s\n", added, *file)
+	return nil
+}
+
+func (a *admin) backup(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: backup needs a destination file", errUsage)
+	}
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+	if err := database.Backup(db, args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "backed up %s to %s\n", a.dbPath, args[0])
+	return nil
+}
+
+func (a *admin) restore(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: restore needs a backup file", errUsage)
+	}
+	saved, err := database.Restore(args[0], a.dbPath)
+	if err != nil {
+		return err
+	}
+	if saved != "" {
+		fmt.Fprintf(a.out, "previous database saved as %s\n", saved)
+	}
+	fmt.Fprintf(a.out, "restored %s from %s\n", a.dbPath, args[0])
+	return nil
+}
+
+func (a *admin) check(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: check takes no arguments", errUsage)
+	}
+	db, err := a.open()
+	if err != nil {
+		return err
+	}
+
+	problems, err := database.IntegrityCheck(db)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(a.out, "sqlite:", problem)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	issues, err := service.CheckData()
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(a.out, "%s: %s\n", issue.Check, issue.Message)
+	}
+
+	if len(problems)+len(issues) > 0 {
+		fmt.Fprintf(a.out, "%d problem(s) found\n", len(problems)+len(issues))
+		return errIssues
+	}
+	fmt.Fprintln(a.out, "ok")
+	return nil
+}
+
+func (a *admin) fillDefaults(args []string) error {
+	flags := flag.NewFlagSet("fill-defaults", flag.ContinueOnError)
+	flags.SetOutput(a.errOut)
+	fromStr := flags.String("from", "", "first date (YYYY-MM-DD)")
+	toStr := flags.String("to", "", "last date (YYYY-MM-DD)")
+	username := flags.String("user", "", "account to fill in (default: the only account)")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if (*fromStr == "") != (*toStr == "") {
+		return fmt.Errorf("%w: give both --from and --to, or neither", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	userID, err := service.ResolveUser(*username)
+	if err != nil {
+		return err
+	}
+
+	var from, to time.Time
+	if *fromStr == "" {
+		period, err := service.GetCurrentPeriod()
+		if err != nil {
+			return err
+		}
+		from, to = period.StartDate, period.EndDate
+	} else {
+		if from, err = time.Parse("2006-01-02", *fromStr); err != nil {
+			return fmt.Errorf("%w: --from must be YYYY-MM-DD", errUsage)
+		}
+		if to, err = time.Parse("2006-01-02", *toStr); err != nil {
+			return fmt.Errorf("%w: --to must be YYYY-MM-DD", errUsage)
+		}
+	}
+
+	added, err := service.FillDefaultDays(userID, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "added %d default day(s) between %s and %s\n", added,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return nil
+}
+
+func (a *admin) sendReminders(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: send-reminders takes no arguments", errUsage)
+	}
+	settings := config.SMTPSettings()
+	if settings.Host == "" {
+		return errors.New("SMTP_HOST is not set")
+	}
+	mailer, err := mail.New(mail.Config{
+		Host:     settings.Host,
+		Port:     settings.Port,
+		Username: settings.Username,
+		Password: settings.Password,
+		From:     settings.From,
+	})
+	if err != nil {
+		return err
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	service.SetMailer(mailer)
+	sent, err := service.SendReminders()
+	fmt.Fprintf(a.out, "sent %d reminder email(s)\n", sent)
+	return err
+}
+
+func (a *admin) createUser(args []string) error {
+	flags := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	flags.SetOutput(a.errOut)
+	isAdmin := flags.Bool("admin", false, "make the account an admin (same as --role admin)")
+	role := flags.String("role", types.RoleEmployee, "role of the account: employee, manager or admin")
+	email := flags.String("email", "", "address to link a single sign-on identity by")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: create-user needs a username", errUsage)
+	}
+
+	if *isAdmin {
+		*role = types.RoleAdmin
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	password, err := a.readPassword()
+	if err != nil {
+		return err
+	}
+	user, err := service.CreateUser(flags.Arg(0), password, *role)
+	if err != nil {
+		return err
+	}
+	if *email != "" {
+		if err := service.SetUserEmail(user.Username, *email); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(a.out, "created %s %q\n", user.Role, user.Username)
+	return nil
+}
+
+func (a *admin) resetPassword(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: reset-password needs a username", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	password, err := a.readPassword()
+	if err != nil {
+		return err
+	}
+	if err := service.ResetPassword(args[0], password); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "password for %q reset; existing sessions are signed out\n", args[0])
+	return nil
+}
+
+func (a *admin) setEmail(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: set-email needs a username and an email", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	if err := service.SetUserEmail(args[0], args[1]); err != nil {
+		return err
+	}
+	if args[1] == "" {
+		fmt.Fprintf(a.out, "email for %q cleared\n", args[0])
+	} else {
+		fmt.Fprintf(a.out, "email for %q set to %s\n", args[0], args[1])
+	}
+	return nil
+}
+
+func (a *admin) setRole(args []string) error {
+	if len(args) != 2 || args[0] == "" {
+		return fmt.Errorf("%w: set-role needs a username and a role", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	userID, err := service.ResolveUser(args[0])
+	if err != nil {
+		return err
+	}
+	if err := service.SetUserRole(userID, args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%q is now %s\n", args[0], args[1])
+	return nil
+}
+
+func (a *admin) setManager(args []string) error {
+	if len(args) != 2 || args[0] == "" {
+		return fmt.Errorf("%w: set-manager needs a username and a manager", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	userID, err := service.ResolveUser(args[0])
+	if err != nil {
+		return err
+	}
+	managerID := 0
+	if args[1] != "" {
+		if managerID, err = service.ResolveUser(args[1]); err != nil {
+			return err
+		}
+	}
+	if err := service.SetUserManager(userID, managerID); err != nil {
+		return err
+	}
+	if args[1] == "" {
+		fmt.Fprintf(a.out, "%q no longer has a manager\n", args[0])
+	} else {
+		fmt.Fprintf(a.out, "%q now reports to %q\n", args[0], args[1])
+	}
+	return nil
+}
+
+func (a *admin) resetTwoFactor(args []string) error {
+	if len(args) != 1 || args[0] == "" {
+		return fmt.Errorf("%w: reset-2fa needs a username", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	if err := service.ResetTOTP(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "two-factor sign-in turned off for %q\n", args[0])
+	return nil
+}
+
+func (a *admin) signOut(args []string) error {
+	if len(args) != 1 || args[0] == "" {
+		return fmt.Errorf("%w: sign-out needs a username", errUsage)
+	}
+
+	service, err := a.service()
+	if err != nil {
+		return err
+	}
+	userID, err := service.ResolveUser(args[0])
+	if err != nil {
+		return err
+	}
+	ended, err := service.RevokeAllSessions(userID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%q signed out of %d session(s)\n", args[0], ended)
+	return nil
+}
+
+// readPassword prompts twice on a terminal, or reads the first line of piped input
+func (a *admin) readPassword() (string, error) {
+	fd := int(a.in.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(a.in).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(a.errOut, "Password: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(a.errOut)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(a.errOut, "Repeat password: ")
+	second, err := term.ReadPassword(fd)
+	fmt.Fprintln(a.errOut)
+	if err != nil {
+		return "", err
+	}
+	if string(first) != string(second) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(first), nil
+}
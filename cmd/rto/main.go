@@ -0,0 +1,246 @@
+// Command rto logs attendance from the terminal.
+//
+// It works against the local SQLite database (DB_PATH or --db) as one account
+// (RTO_USER or --user) or, when a server URL is given (RTO_SERVER or --server),
+// against that server's REST API using a personal access token (RTO_TOKEN or --token).
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/client"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/tui"
+)
+
+const usage = `Usage: rto [options] <command> [arguments]
+
+Commands:
+  in [DATE]                    mark a day as in office (default today)
+  remote [DATE]                mark a day as remote (default today)
+  toggle [DATE]                flip a day between in office and remote
+  vacation FROM[..TO] DESC     book the weekdays in a range as vacation
+  stats                        stats for the current period
+  plan                         in-office days still needed to hit the target
+  list [list options]          list events
+  tui                          interactive month calendar
+
+DATE is YYYY-MM-DD, today, tomorrow or yesterday.
+
+Options:
+`
+
+// errUsage marks errors caused by bad arguments
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("rto", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dbPath := flags.String("db", config.DBPath(), "SQLite database for local mode")
+	user := flags.String("user", os.Getenv("RTO_USER"), "account for local mode; may be left out while there is only one")
+	server := flags.String("server", os.Getenv("RTO_SERVER"), "server URL for remote mode, e.g. http://localhost:8761")
+	token := flags.String("token", os.Getenv("RTO_TOKEN"), "personal access token for remote mode")
+	output := flags.String("o", "table", "output format: table or json")
+	verbose := flags.Bool("v", false, "log service activity to stderr")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintln(stderr, "rto: -o must be table or json")
+		return 2
+	}
+
+	level := slog.LevelWarn
+	if *verbose {
+		level = slog.LevelInfo
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+
+	var backend client.Backend
+	if *server != "" {
+		if *token == "" {
+			fmt.Fprintln(stderr, "rto: remote mode needs a token (--token or RTO_TOKEN)")
+			return 2
+		}
+		backend = client.NewRemote(*server, *token)
+	} else {
+		local, err := client.NewLocal(*dbPath, *user, logger, config.QuarterStart, config.QuarterEnd)
+		if err != nil {
+			fmt.Fprintln(stderr, "rto:", err)
+			return 1
+		}
+		backend = local
+	}
+	defer backend.Close()
+
+	out := &printer{w: stdout, json: *output == "json"}
+	err := dispatch(backend, out, flags.Arg(0), flags.Args()[1:], time.Now(), stderr)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "rto:", strings.TrimPrefix(err.Error(), "usage: "))
+		return 2
+	default:
+		fmt.Fprintln(stderr, "rto:", err)
+		return 1
+	}
+}
+
+func dispatch(backend client.Backend, out *printer, command string, args []string, now time.Time, stderr io.Writer) error {
+	switch command {
+	case "in", "remote":
+		date, err := optionalDate(args, now)
+		if err != nil {
+			return err
+		}
+		event, err := backend.SetAttendance(date, command == "in")
+		if err != nil {
+			return err
+		}
+		return out.event(*event)
+
+	case "toggle":
+		date, err := optionalDate(args, now)
+		if err != nil {
+			return err
+		}
+		status, err := backend.ToggleAttendance(date)
+		if err != nil {
+			return err
+		}
+		return out.toggled(date, status)
+
+	case "vacation":
+		if len(args) < 2 {
+			return fmt.Errorf("%w: vacation needs a date or FROM..TO and a description", errUsage)
+		}
+		from, to, err := client.ParseRange(args[0], now)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		result, err := backend.AddVacation(from, to, strings.Join(args[1:], " "))
+		if err != nil {
+			return err
+		}
+		return out.vacation(*result)
+
+	case "stats":
+		report, err := backend.Stats()
+		if err != nil {
+			return err
+		}
+		return out.stats(*report)
+
+	case "plan":
+		plan, err := backend.Plan()
+		if err != nil {
+			return err
+		}
+		return out.plan(*plan)
+
+	case "list":
+		filter, err := parseListFlags(args, stderr)
+		if err != nil {
+			return err
+		}
+		events, total, err := backend.ListEvents(filter)
+		if err != nil {
+			return err
+		}
+		return out.events(events, total)
+
+	case "tui":
+		if len(args) > 0 {
+			return fmt.Errorf("%w: tui takes no arguments", errUsage)
+		}
+		return tui.Run(backend, os.Stdin, out.w)
+
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+}
+
+// optionalDate reads the single optional DATE argument
+func optionalDate(args []string, now time.Time) (time.Time, error) {
+	if len(args) > 1 {
+		return time.Time{}, fmt.Errorf("%w: expected at most one date", errUsage)
+	}
+	value := ""
+	if len(args) == 1 {
+		value = args[0]
+	}
+	date, err := client.ParseDate(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return date, nil
+}
+
+func parseListFlags(args []string, stderr io.Writer) (types.EventFilter, error) {
+	var filter types.EventFilter
+
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	eventType := flags.String("type", "", "holiday, vacation or attendance")
+	from := flags.String("from", "", "first date (YYYY-MM-DD)")
+	to := flags.String("to", "", "last date (YYYY-MM-DD)")
+	status := flags.String("status", "", "in or remote (attendance only)")
+	search := flags.String("q", "", "search descriptions")
+	sort := flags.String("sort", "date", "date, type or description; prefix - for descending")
+	limit := flags.Int("limit", 50, "maximum number of events")
+	offset := flags.Int("offset", 0, "events to skip")
+	if err := flags.Parse(args); err != nil {
+		return filter, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if flags.NArg() > 0 {
+		return filter, fmt.Errorf("%w: unexpected argument %q", errUsage, flags.Arg(0))
+	}
+
+	var err error
+	filter.Type = *eventType
+	if *from != "" {
+		if filter.From, err = time.Parse("2006-01-02", *from); err != nil {
+			return filter, fmt.Errorf("%w: --from must be YYYY-MM-DD", errUsage)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = time.Parse("2006-01-02", *to); err != nil {
+			return filter, fmt.Errorf("%w: --to must be YYYY-MM-DD", errUsage)
+		}
+	}
+	switch *status {
+	case "":
+	case "in", "remote":
+		inOffice := *status == "in"
+		filter.InOffice = &inOffice
+	default:
+		return filter, fmt.Errorf("%w: --status must be in or remote", errUsage)
+	}
+	filter.Search = *search
+	filter.Sort = *sort
+	filter.Limit = *limit
+	filter.Offset = *offset
+	return filter, nil
+}
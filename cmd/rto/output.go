@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/controller"
+	"github.com/robstave/rto/internal/client"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// printer writes command results as aligned tables or as JSON shaped like the REST API
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p *printer) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *printer) table(write func(tw *tabwriter.Writer)) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	write(tw)
+	return tw.Flush()
+}
+
+func toAPIEvent(event types.Event) controller.APIEvent {
+	return controller.APIEvent{
+		ID:          event.ID,
+		Date:        event.Date.Format("2006-01-02"),
+		Type:        event.Type,
+		Description: event.Description,
+		IsInOffice:  event.IsInOffice,
+	}
+}
+
+// eventStatus is the label shown for an event in tables
+func eventStatus(event types.Event) string {
+	switch {
+	case event.Type != "attendance":
+		return event.Type
+	case event.IsInOffice:
+		return "in office"
+	default:
+		return "remote"
+	}
+}
+
+func (p *printer) event(event types.Event) error {
+	if p.json {
+		return p.writeJSON(toAPIEvent(event))
+	}
+	_, err := fmt.Fprintf(p.w, "%s  %s\n", event.Date.Format("Mon 2006-01-02"), eventStatus(event))
+	return err
+}
+
+func (p *printer) toggled(date time.Time, status string) error {
+	if p.json {
+		return p.writeJSON(map[string]string{"date": date.Format("2006-01-02"), "status": status})
+	}
+	label := "remote"
+	if status == "in" {
+		label = "in office"
+	}
+	_, err := fmt.Fprintf(p.w, "%s  %s\n", date.Format("Mon 2006-01-02"), label)
+	return err
+}
+
+func (p *printer) vacation(result types.BulkAddResponse) error {
+	if p.json {
+		return p.writeJSON(result)
+	}
+	return p.table(func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "DATE\tRESULT")
+		for _, r := range result.Results {
+			outcome := r.Action
+			if r.Error != "" {
+				outcome = "error: " + r.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", r.Date, outcome)
+		}
+		fmt.Fprintf(tw, "\n%s\n", result.Message)
+	})
+}
+
+func (p *printer) stats(report client.Report) error {
+	s := report.Stats
+	if p.json {
+		return p.writeJSON(controller.APIStats{
+			From:           report.From.Format("2006-01-02"),
+			To:             report.To.Format("2006-01-02"),
+			InOfficeCount:  s.InOfficeCount,
+			TotalDays:      s.TotalDays,
+			Average:        s.Average,
+			AverageDays:    s.AverageDays,
+			TargetDays:     s.TargetDays,
+			AveragePercent: s.AveragePercent,
+		})
+	}
+	return p.table(func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Period\t%s to %s\n", report.From.Format("2006-01-02"), report.To.Format("2006-01-02"))
+		fmt.Fprintf(tw, "In office\t%d of %d days\n", s.InOfficeCount, s.TotalDays)
+		fmt.Fprintf(tw, "Average\t%.2f days/week\n", s.AverageDays)
+		fmt.Fprintf(tw, "Target\t%.2f days/week (%.0f%%)\n", s.TargetDays, s.AveragePercent)
+	})
+}
+
+func (p *printer) plan(plan types.Plan) error {
+	if p.json {
+		return p.writeJSON(controller.APIPlan{
+			From:          plan.From.Format("2006-01-02"),
+			To:            plan.To.Format("2006-01-02"),
+			TargetDays:    plan.TargetDays,
+			TotalDays:     plan.TotalDays,
+			RequiredDays:  plan.RequiredDays,
+			LoggedDays:    plan.LoggedDays,
+			PlannedDays:   plan.PlannedDays,
+			RemainingDays: plan.RemainingDays,
+			OpenDays:      plan.OpenDays,
+			WeeksLeft:     plan.WeeksLeft,
+			PerWeek:       plan.PerWeek,
+			Achievable:    plan.Achievable,
+		})
+	}
+	return p.table(func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "Period\t%s to %s\n", plan.From.Format("2006-01-02"), plan.To.Format("2006-01-02"))
+		fmt.Fprintf(tw, "Target\t%.2f days/week = %d in-office days\n", plan.TargetDays, plan.RequiredDays)
+		fmt.Fprintf(tw, "Logged\t%d\n", plan.LoggedDays)
+		fmt.Fprintf(tw, "Planned\t%d\n", plan.PlannedDays)
+		fmt.Fprintf(tw, "Still needed\t%d (%.1f/week over %.1f weeks)\n", plan.RemainingDays, plan.PerWeek, plan.WeeksLeft)
+		fmt.Fprintf(tw, "Open days left\t%d\n", plan.OpenDays)
+		if plan.RemainingDays == 0 {
+			fmt.Fprintln(tw, "\nOn track: the target is met with what is already planned.")
+		} else if !plan.Achievable {
+			fmt.Fprintln(tw, "\nNot enough open days left to reach the target.")
+		}
+	})
+}
+
+func (p *printer) events(events []types.Event, total int64) error {
+	if p.json {
+		data := make([]controller.APIEvent, 0, len(events))
+		for _, event := range events {
+			data = append(data, toAPIEvent(event))
+		}
+		return p.writeJSON(controller.APIListResponse{Data: data, Total: total, Limit: len(events)})
+	}
+	return p.table(func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tDATE\tTYPE\tSTATUS\tDESCRIPTION")
+		for _, event := range events {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", event.ID, event.Date.Format("Mon 2006-01-02"),
+				event.Type, eventStatus(event), event.Description)
+		}
+		fmt.Fprintf(tw, "\n%d of %d events\n", len(events), total)
+	})
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// APIError is the error body returned by every /api/v1 endpoint
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIErrorResponse wraps an APIError so errors are always under the "error" key
+type APIErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIListResponse is the envelope for paginated collections
+type APIListResponse struct {
+	Data   interface{} `json:"data"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// apiError writes a consistent JSON error body
+func apiError(c echo.Context, status int, code string, message string) error {
+	return c.JSON(status, APIErrorResponse{
+		Error: APIError{Code: code, Message: message},
+	})
+}
+
+// apiServiceError maps errors coming back from the domain layer onto status codes
+func (ctlr *RTOController) apiServiceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return apiError(c, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		return apiError(c, http.StatusConflict, "conflict", err.Error())
+	}
+
+	ctlr.logger.Error("API request failed", "path", c.Path(), "error", err)
+	return apiError(c, http.StatusInternalServerError, "internal", "An internal error occurred.")
+}
+
+// parsePagination reads the limit and offset query parameters
+func parsePagination(c echo.Context) (int, int, error) {
+	limit := defaultPageLimit
+	offset := 0
+
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		limit = n
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD query parameter, returning the zero time when absent
+func parseOptionalDate(c echo.Context, name string) (time.Time, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date in YYYY-MM-DD format", name)
+	}
+	return date, nil
+}
+
+// parseIDParam reads a positive integer path parameter
+func parseIDParam(c echo.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return id, nil
+}
+
+// OpenAPISpec serves the OpenAPI document describing /api/v1
+func (ctlr *RTOController) OpenAPISpec(c echo.Context) error {
+	return c.File("static/openapi.yaml")
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIAnchors is the caller's team anchor days, how often they came in on
+// them this period, and the default days suggested to overlap with the team
+type APIAnchors struct {
+	AnchorDays           string  `json:"anchorDays"`
+	Days                 int     `json:"days"`
+	Attended             int     `json:"attended"`
+	Percent              float64 `json:"percent"`
+	SuggestedDefaultDays string  `json:"suggestedDefaultDays"`
+}
+
+// APIAnchorDaysRequest sets the anchor days of the caller's team
+type APIAnchorDaysRequest struct {
+	AnchorDays string `json:"anchorDays"` // e.g. "T,W"; empty clears them
+}
+
+// APIGetAnchors returns the caller's anchor-day compliance for the current period
+func (ctlr *RTOController) APIGetAnchors(c echo.Context) error {
+	userID := currentUserID(c)
+	period, err := ctlr.service.GetCurrentPeriod()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	compliance, err := ctlr.service.AnchorCompliance(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	suggested, err := ctlr.service.SuggestDefaultDays(userID)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, APIAnchors{
+		AnchorDays:           compliance.AnchorDays,
+		Days:                 compliance.Days,
+		Attended:             compliance.Attended,
+		Percent:              compliance.Percent,
+		SuggestedDefaultDays: suggested,
+	})
+}
+
+// APISetAnchorDays sets the days the caller's reports all come in
+func (ctlr *RTOController) APISetAnchorDays(c echo.Context) error {
+	var req APIAnchorDaysRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	if err := ctlr.service.SetAnchorDays(currentUserID(c), req.AnchorDays); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, req)
+}
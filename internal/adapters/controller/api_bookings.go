@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIOfficeRequest is the payload for adding an office or changing its capacity
+type APIOfficeRequest struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"` // people allowed in per day; 0 means one per desk
+}
+
+// APIResourceRequest is the payload for adding a desk or room
+type APIResourceRequest struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // "desk" or "room"
+}
+
+// APIBookingRequest books a desk or room for a day
+type APIBookingRequest struct {
+	Date       string `json:"date"`
+	Kind       string `json:"kind"`       // "desk" or "room"
+	ResourceID int    `json:"resourceId"` // required for rooms; any free desk when omitted
+}
+
+// APIBooking is the JSON representation of a booking
+type APIBooking struct {
+	ID         uint   `json:"id"`
+	Date       string `json:"date"`
+	OfficeID   uint   `json:"officeId"`
+	ResourceID uint   `json:"resourceId"`
+	Kind       string `json:"kind"`
+	UserID     uint   `json:"userId"`
+	Username   string `json:"username,omitempty"`
+}
+
+// APIBookingDay is one day of an office's booking calendar
+type APIBookingDay struct {
+	Date     string       `json:"date"`
+	Capacity int          `json:"capacity"`
+	Free     int          `json:"free"`
+	Bookings []APIBooking `json:"bookings"`
+}
+
+func toAPIBooking(booking types.Booking, usernames map[uint]string) APIBooking {
+	return APIBooking{
+		ID:         booking.ID,
+		Date:       booking.Date.Format("2006-01-02"),
+		OfficeID:   booking.OfficeID,
+		ResourceID: booking.ResourceID,
+		Kind:       booking.Kind,
+		UserID:     booking.UserID,
+		Username:   usernames[booking.UserID],
+	}
+}
+
+// APIListOffices returns every office
+func (ctlr *RTOController) APIListOffices(c echo.Context) error {
+	offices, err := ctlr.service.GetOffices()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  offices,
+		Total: int64(len(offices)),
+		Limit: len(offices),
+	})
+}
+
+// APICreateOffice adds an office
+func (ctlr *RTOController) APICreateOffice(c echo.Context) error {
+	var req APIOfficeRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	office, err := ctlr.service.CreateOffice(req.Name, req.Capacity)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, office)
+}
+
+// APIUpdateOffice changes the office's daily capacity
+func (ctlr *RTOController) APIUpdateOffice(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	var req APIOfficeRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	if err := ctlr.service.SetOfficeCapacity(id, req.Capacity); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	office, err := ctlr.service.GetOffice(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, office)
+}
+
+// APIDeleteOffice removes an office with its desks, rooms and bookings
+func (ctlr *RTOController) APIDeleteOffice(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	if err := ctlr.service.DeleteOffice(id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// APIListResources returns the office's desks and rooms
+func (ctlr *RTOController) APIListResources(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	resources, err := ctlr.service.GetResources(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  resources,
+		Total: int64(len(resources)),
+		Limit: len(resources),
+	})
+}
+
+// APICreateResource adds a desk or room to the office
+func (ctlr *RTOController) APICreateResource(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	var req APIResourceRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	resource, err := ctlr.service.AddResource(id, req.Name, req.Kind)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, resource)
+}
+
+// APIDeleteResource removes a desk or room, cancelling its bookings
+func (ctlr *RTOController) APIDeleteResource(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	resourceID, err := parseIDParam(c, "resourceId")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	if err := ctlr.service.DeleteResource(id, resourceID); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// APIOfficeBookings returns the office's booking calendar for ?month=YYYY-MM
+func (ctlr *RTOController) APIOfficeBookings(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	calendar, err := ctlr.service.OfficeCalendar(id, monthParam(c))
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	data := make([]APIBookingDay, 0, len(calendar.Days))
+	for _, day := range calendar.Days {
+		out := APIBookingDay{
+			Date:     day.Date.Format("2006-01-02"),
+			Capacity: day.Capacity,
+			Free:     day.Free(),
+			Bookings: []APIBooking{},
+		}
+		for _, booking := range append(day.Desks, day.Rooms...) {
+			out.Bookings = append(out.Bookings, toAPIBooking(booking, calendar.Usernames))
+		}
+		data = append(data, out)
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  data,
+		Total: int64(len(data)),
+		Limit: len(data),
+	})
+}
+
+// APICreateBooking books a desk or room for the caller. Booking a desk also
+// marks the day in office; a full office is a 409.
+func (ctlr *RTOController) APICreateBooking(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	var req APIBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	var booking *types.Booking
+	switch strings.ToLower(req.Kind) {
+	case types.ResourceDesk, "":
+		booking, err = ctlr.service.BookDesk(currentUserID(c), id, req.ResourceID, date)
+	case types.ResourceRoom:
+		booking, err = ctlr.service.BookRoom(currentUserID(c), id, req.ResourceID, date)
+	default:
+		return apiError(c, http.StatusBadRequest, "invalid_input", "kind must be 'desk' or 'room'")
+	}
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toAPIBooking(*booking, nil))
+}
+
+// APIListBookings returns the caller's bookings between ?from and ?to,
+// defaulting to the next two weeks
+func (ctlr *RTOController) APIListBookings(c echo.Context) error {
+	from, err := parseOptionalDate(c, "from")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	to, err := parseOptionalDate(c, "to")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	if from.IsZero() {
+		from = time.Now()
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 14)
+	}
+	bookings, err := ctlr.service.GetUserBookings(currentUserID(c), from, to)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	data := make([]APIBooking, 0, len(bookings))
+	for _, booking := range bookings {
+		data = append(data, toAPIBooking(booking, nil))
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  data,
+		Total: int64(len(data)),
+		Limit: len(data),
+	})
+}
+
+// APICancelBooking releases one of the caller's bookings
+func (ctlr *RTOController) APICancelBooking(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	if err := ctlr.service.CancelBooking(currentUserID(c), id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
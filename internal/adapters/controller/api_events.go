@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIEvent is the JSON representation of an event in the REST API
+type APIEvent struct {
+	ID          uint   `json:"id"`
+	Date        string `json:"date"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsInOffice  bool   `json:"isInOffice"`
+	OfficeID    uint   `json:"officeId"`             // where an in-office day was spent; 0 when unknown
+	ApprovedBy  *uint  `json:"approvedBy,omitempty"` // the manager who signed off a vacation
+	ApprovedAt  string `json:"approvedAt,omitempty"`
+}
+
+// APIEventRequest is the payload for creating or replacing an event
+type APIEventRequest struct {
+	Date        string `json:"date"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsInOffice  bool   `json:"isInOffice"`
+	OfficeID    uint   `json:"officeId"` // defaults to the user's default office
+}
+
+// APIToggleResponse is returned after toggling attendance for a day
+type APIToggleResponse struct {
+	Date   string   `json:"date"`
+	Status string   `json:"status"`
+	Stats  APIStats `json:"stats"`
+}
+
+func toAPIEvent(event types.Event) APIEvent {
+	out := APIEvent{
+		ID:          event.ID,
+		Date:        event.Date.Format("2006-01-02"),
+		Type:        event.Type,
+		Description: event.Description,
+		IsInOffice:  event.IsInOffice,
+		OfficeID:    event.OfficeID,
+		ApprovedBy:  event.ApprovedBy,
+	}
+	if event.ApprovedAt != nil {
+		out.ApprovedAt = event.ApprovedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func toAPIEvents(events []types.Event) []APIEvent {
+	out := make([]APIEvent, 0, len(events))
+	for _, event := range events {
+		out = append(out, toAPIEvent(event))
+	}
+	return out
+}
+
+// toEvent validates the request and converts it into a domain event
+func (req APIEventRequest) toEvent() (types.Event, string) {
+	if strings.TrimSpace(req.Date) == "" || strings.TrimSpace(req.Type) == "" {
+		return types.Event{}, "date and type are required"
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return types.Event{}, "date must be in YYYY-MM-DD format"
+	}
+	return types.Event{
+		Date:        date,
+		Type:        strings.ToLower(strings.TrimSpace(req.Type)),
+		Description: req.Description,
+		IsInOffice:  req.IsInOffice,
+		OfficeID:    req.OfficeID,
+	}, ""
+}
+
+// holidayDenied reports whether any of the events is a shared holiday and the
+// user may not manage holidays
+func holidayDenied(c echo.Context, events ...types.Event) bool {
+	if can(c, types.PermManageHolidays) {
+		return false
+	}
+	for _, event := range events {
+		if event.Type == "holiday" {
+			return true
+		}
+	}
+	return false
+}
+
+// APIListEvents returns a filtered, paginated list of events
+func (ctlr *RTOController) APIListEvents(c echo.Context) error {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	events, total, err := ctlr.service.QueryEvents(calendarUserID(c), filter)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:   toAPIEvents(events),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// APIGetEvent returns a single event
+func (ctlr *RTOController) APIGetEvent(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	event, err := ctlr.service.GetEventByID(currentUserID(c), id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIEvent(event))
+}
+
+// APICreateEvent creates an event, refusing duplicates of the same type on a date
+func (ctlr *RTOController) APICreateEvent(c echo.Context) error {
+	var req APIEventRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	event, msg := req.toEvent()
+	if msg != "" {
+		return apiError(c, http.StatusBadRequest, "invalid_input", msg)
+	}
+	if holidayDenied(c, event) {
+		return apiError(c, http.StatusForbidden, "forbidden", "Only admins can change the shared holidays.")
+	}
+
+	created, err := ctlr.service.CreateEvent(currentUserID(c), event)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/events/"+strconv.FormatUint(uint64(created.ID), 10))
+	return c.JSON(http.StatusCreated, toAPIEvent(*created))
+}
+
+// APIUpdateEvent replaces the fields of an existing event
+func (ctlr *RTOController) APIUpdateEvent(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	var req APIEventRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	event, msg := req.toEvent()
+	if msg != "" {
+		return apiError(c, http.StatusBadRequest, "invalid_input", msg)
+	}
+
+	existing, err := ctlr.service.GetEventByID(currentUserID(c), id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	if holidayDenied(c, existing, event) {
+		return apiError(c, http.StatusForbidden, "forbidden", "Only admins can change the shared holidays.")
+	}
+
+	event.ID = existing.ID
+	if err := ctlr.service.UpdateEvent(currentUserID(c), event); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIEvent(event))
+}
+
+// APIDeleteEvent permanently removes an event
+func (ctlr *RTOController) APIDeleteEvent(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	existing, err := ctlr.service.GetEventByID(currentUserID(c), id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	if holidayDenied(c, existing) {
+		return apiError(c, http.StatusForbidden, "forbidden", "Only admins can change the shared holidays.")
+	}
+
+	if err := ctlr.service.DeleteEvent(currentUserID(c), id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// APIToggleAttendance flips a day between in-office and remote
+func (ctlr *RTOController) APIToggleAttendance(c echo.Context) error {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	status, err := ctlr.service.ToggleAttendance(currentUserID(c), date)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	period, err := ctlr.service.GetCurrentPeriod()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	stats, err := ctlr.service.CalculateStatsBetween(currentUserID(c), period.StartDate, period.EndDate)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, APIToggleResponse{
+		Date:   date.Format("2006-01-02"),
+		Status: status,
+		Stats:  toAPIStats(*stats, period.StartDate, period.EndDate),
+	})
+}
+
+// APIAttendanceRequest sets a day to in office or remote
+type APIAttendanceRequest struct {
+	Status   string `json:"status"`             // "in" or "remote"
+	OfficeID int    `json:"officeId,omitempty"` // with "in", the office; the default office when omitted
+	BookDesk bool   `json:"bookDesk,omitempty"` // book DeskID; in-office days take any free desk anyway
+	DeskID   int    `json:"deskId,omitempty"`   // the desk to book; any free desk when omitted
+}
+
+// APISetAttendance marks a day as in office or remote, creating the attendance event if needed
+func (ctlr *RTOController) APISetAttendance(c echo.Context) error {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	var req APIAttendanceRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	if req.Status != "in" && req.Status != "remote" {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "status must be 'in' or 'remote'")
+	}
+
+	if req.Status == "in" && req.BookDesk {
+		officeID := req.OfficeID
+		if officeID == 0 {
+			officeID = int(ctlr.service.GetPrefs(currentUserID(c)).DefaultOfficeID)
+		}
+		if officeID == 0 {
+			return apiError(c, http.StatusBadRequest, "invalid_input", "officeId is required to book a desk when no default office is set")
+		}
+		if _, err := ctlr.service.BookDesk(currentUserID(c), officeID, req.DeskID, date); err != nil {
+			return ctlr.apiServiceError(c, err)
+		}
+		req.OfficeID = officeID
+	}
+
+	var event *types.Event
+	if req.Status == "in" && req.OfficeID != 0 {
+		event, err = ctlr.service.SetAttendanceAt(currentUserID(c), date, req.OfficeID)
+	} else {
+		event, err = ctlr.service.SetAttendance(currentUserID(c), date, req.Status == "in")
+	}
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIEvent(*event))
+}
+
+// APIVacationRequest books a range of weekdays as vacation
+type APIVacationRequest struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Description string `json:"description"`
+}
+
+// APIAddVacation books every weekday in a range as vacation
+func (ctlr *RTOController) APIAddVacation(c echo.Context) error {
+	var req APIVacationRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "invalid JSON body")
+	}
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "from must be in YYYY-MM-DD format")
+	}
+	to := from
+	if req.To != "" {
+		if to, err = time.Parse("2006-01-02", req.To); err != nil {
+			return apiError(c, http.StatusBadRequest, "invalid_input", "to must be in YYYY-MM-DD format")
+		}
+	}
+
+	result, err := ctlr.service.AddVacation(currentUserID(c), from, to, strings.TrimSpace(req.Description))
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// parseEventFilter builds an EventFilter from the type, from, to, limit and offset parameters
+func parseEventFilter(c echo.Context) (types.EventFilter, error) {
+	filter, err := parseEventCriteria(c)
+	if err != nil {
+		return filter, err
+	}
+	if filter.Limit, filter.Offset, err = parsePagination(c); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseEventCriteria reads the filter and sort query parameters shared by the
+// API and the events page: type, from, to, status (in|remote), q and sort
+func parseEventCriteria(c echo.Context) (types.EventFilter, error) {
+	var filter types.EventFilter
+	var err error
+
+	filter.Type = strings.ToLower(c.QueryParam("type"))
+	if filter.From, err = parseOptionalDate(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseOptionalDate(c, "to"); err != nil {
+		return filter, err
+	}
+
+	switch status := strings.ToLower(c.QueryParam("status")); status {
+	case "":
+	case "in", "remote":
+		inOffice := status == "in"
+		filter.InOffice = &inOffice
+	default:
+		return filter, errors.New("status must be 'in' or 'remote'")
+	}
+
+	filter.Search = c.QueryParam("q")
+	filter.Sort = c.QueryParam("sort")
+	return filter, nil
+}
@@ -0,0 +1,367 @@
+// controller/api_events_test.go
+
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAPIListEvents_Filtered(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	filter := types.EventFilter{
+		Type:   "vacation",
+		From:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		Limit:  10,
+		Offset: 20,
+	}
+	events := []types.Event{
+		{ID: 7, Date: time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC), Type: "vacation", Description: "Ski trip"},
+	}
+	mockService.On("QueryEvents", 0, filter).Return(events, int64(21), nil)
+
+	// Initialize the controller with the mock service
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events?type=vacation&from=2025-01-01&to=2025-03-31&limit=10&offset=20", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Call the handler
+	if assert.NoError(t, ctlr.APIListEvents(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"data": [{"id": 7, "date": "2025-02-14", "type": "vacation", "description": "Ski trip", "isInOffice": false, "officeId": 0}],
+			"total": 21,
+			"limit": 10,
+			"offset": 20
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIListEvents_InvalidLimit(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events?limit=0", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIListEvents(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		expected := `{"error": {"code": "invalid_input", "message": "limit must be between 1 and 500"}}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	// The service should never be reached
+	mockService.AssertNotCalled(t, "QueryEvents", 0)
+}
+
+func TestAPICreateEvent_Created(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	event := types.Event{
+		Date:       time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		Type:       "attendance",
+		IsInOffice: true,
+	}
+	stored := event
+	stored.ID = 42
+	mockService.On("CreateEvent", 0, event).Return(&stored, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	body := `{"date": "2025-02-03", "type": "attendance", "isInOffice": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APICreateEvent(c)) {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/api/v1/events/42", rec.Header().Get(echo.HeaderLocation))
+		expected := `{"id": 42, "date": "2025-02-03", "type": "attendance", "description": "", "isInOffice": true, "officeId": 0}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPICreateEvent_Conflict(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	event := types.Event{
+		Date:        time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		Type:        "vacation",
+		Description: "Day off",
+	}
+	mockService.On("CreateEvent", 0, event).Return(nil, domain.ErrConflict)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	body := `{"date": "2025-02-03", "type": "vacation", "description": "Day off"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APICreateEvent(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"conflict"`)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPICreateEvent_MissingFields(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(`{"type": "vacation"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APICreateEvent(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		expected := `{"error": {"code": "invalid_input", "message": "date and type are required"}}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+}
+
+func TestAPIGetEvent_NotFound(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("GetEventByID", 0, 99).Return(types.Event{}, gorm.ErrRecordNotFound)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/99", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("99")
+
+	if assert.NoError(t, ctlr.APIGetEvent(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"not_found"`)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIDeleteEvent_NoContent(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("GetEventByID", 0, 5).Return(types.Event{ID: 5, Type: "vacation"}, nil)
+	mockService.On("DeleteEvent", 0, 5).Return(nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/events/5", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+
+	if assert.NoError(t, ctlr.APIDeleteEvent(c)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIDeleteEvent_HolidayNeedsAdmin(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("GetEventByID", 2, 7).Return(types.Event{ID: 7, Type: "holiday"}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/events/7", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	c.Set(currentUserKey, &types.User{ID: 2, Username: "bob", Role: types.RoleManager})
+
+	// Holidays are shared, so only admins may remove them
+	if assert.NoError(t, ctlr.APIDeleteEvent(c)) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"forbidden"`)
+	}
+
+	mockService.AssertNotCalled(t, "DeleteEvent", mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIToggleAttendance_Success(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	period := &types.Period{
+		ID:        1,
+		Name:      "Q1 2025",
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("ToggleAttendance", 0, date).Return("in", nil)
+	mockService.On("GetCurrentPeriod").Return(period, nil)
+	mockService.On("CalculateStatsBetween", 0, period.StartDate, period.EndDate).Return(&types.AttendanceStats{
+		InOfficeCount: 9,
+		TotalDays:     90,
+		Average:       10,
+		AverageDays:   0.7,
+		TargetDays:    2.5,
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/attendance/2025-01-15/toggle", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("date")
+	c.SetParamValues("2025-01-15")
+
+	if assert.NoError(t, ctlr.APIToggleAttendance(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"date": "2025-01-15",
+			"status": "in",
+			"stats": {
+				"from": "2025-01-01",
+				"to": "2025-03-31",
+				"inOfficeCount": 9,
+				"totalDays": 90,
+				"average": 10,
+				"averageDays": 0.7,
+				"targetDays": 2.5,
+				"averagePercent": 0,
+				"byOffice": [],
+				"uncounted": 0
+			}
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPISetAttendance_Remote(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	date := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	mockService.On("SetAttendance", 0, date, false).Return(&types.Event{
+		ID: 12, Date: date, Type: "attendance", IsInOffice: false,
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/attendance/2025-01-16", strings.NewReader(`{"status":"remote"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("date")
+	c.SetParamValues("2025-01-16")
+
+	if assert.NoError(t, ctlr.APISetAttendance(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{"id": 12, "date": "2025-01-16", "type": "attendance", "description": "", "isInOffice": false, "officeId": 0}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPISetAttendance_InvalidStatus(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/attendance/2025-01-16", strings.NewReader(`{"status":"maybe"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("date")
+	c.SetParamValues("2025-01-16")
+
+	if assert.NoError(t, ctlr.APISetAttendance(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"invalid_input"`)
+	}
+
+	mockService.AssertNotCalled(t, "SetAttendance", 0)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIHolidayRequest is the payload for adding a holiday
+type APIHolidayRequest struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+// APIListHolidays returns holidays, optionally limited to a date range
+func (ctlr *RTOController) APIListHolidays(c echo.Context) error {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	filter.Type = "holiday"
+
+	holidays, total, err := ctlr.service.QueryEvents(currentUserID(c), filter)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:   toAPIEvents(holidays),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// APICreateHoliday adds a holiday on the given date
+func (ctlr *RTOController) APICreateHoliday(c echo.Context) error {
+	var req APIHolidayRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "description is required")
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	created, err := ctlr.service.CreateEvent(currentUserID(c), types.Event{
+		Date:        date,
+		Description: strings.TrimSpace(req.Description),
+		Type:        "holiday",
+	})
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toAPIEvent(*created))
+}
+
+// APIDeleteHoliday removes a holiday. Other event types are not reachable here.
+func (ctlr *RTOController) APIDeleteHoliday(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	event, err := ctlr.service.GetEventByID(currentUserID(c), id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	if event.Type != "holiday" {
+		return apiError(c, http.StatusNotFound, "not_found", "holiday not found")
+	}
+
+	if err := ctlr.service.DeleteEvent(currentUserID(c), id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/scheduler"
+)
+
+// APIJob is the JSON representation of a scheduled job and its last run
+type APIJob struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Schedule     string  `json:"schedule"` // cron expression, or "off"
+	NextRunAt    *string `json:"nextRunAt,omitempty"`
+	LastRunAt    *string `json:"lastRunAt,omitempty"`
+	LastDuration int64   `json:"lastDurationMs"`
+	LastStatus   string  `json:"lastStatus,omitempty"` // "ok" or "failed"
+	LastResult   string  `json:"lastResult,omitempty"`
+	LastError    string  `json:"lastError,omitempty"`
+	Runs         int     `json:"runs"`
+	Failures     int     `json:"failures"`
+	Running      bool    `json:"running"`
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+// APIListJobs returns every scheduled job with its state
+func (ctlr *RTOController) APIListJobs(c echo.Context) error {
+	if ctlr.scheduler == nil {
+		return apiError(c, http.StatusServiceUnavailable, "unavailable", "Scheduled jobs are not available.")
+	}
+	statuses, err := ctlr.scheduler.Status()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	out := make([]APIJob, 0, len(statuses))
+	for _, job := range statuses {
+		out = append(out, APIJob{
+			Name:         job.Name,
+			Description:  job.Description,
+			Schedule:     job.Spec,
+			NextRunAt:    formatOptionalTime(job.State.NextRunAt),
+			LastRunAt:    formatOptionalTime(job.State.LastRunAt),
+			LastDuration: job.State.LastDuration,
+			LastStatus:   job.State.LastStatus,
+			LastResult:   job.State.LastResult,
+			LastError:    job.State.LastError,
+			Runs:         job.State.Runs,
+			Failures:     job.State.Failures,
+			Running:      job.State.RunningSince != nil,
+		})
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// APIRunJob starts a job now; the outcome shows up in the job list
+func (ctlr *RTOController) APIRunJob(c echo.Context) error {
+	if ctlr.scheduler == nil {
+		return apiError(c, http.StatusServiceUnavailable, "unavailable", "Scheduled jobs are not available.")
+	}
+	err := ctlr.scheduler.RunNow(c.Param("name"))
+	switch {
+	case err == nil:
+		return c.NoContent(http.StatusAccepted)
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		return apiError(c, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, scheduler.ErrRunning):
+		return apiError(c, http.StatusConflict, "conflict", err.Error())
+	}
+	return ctlr.apiServiceError(c, err)
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APINotifications is the JSON representation of the user's email reminder settings
+type APINotifications struct {
+	EmailEnabled bool    `json:"emailEnabled"` // read-only; false when the server has no SMTP settings
+	Behind       bool    `json:"behind"`
+	BehindDays   float64 `json:"behindDays"`
+	Unlogged     bool    `json:"unlogged"`
+	Vacation     bool    `json:"vacation"`
+	VacationLead int     `json:"vacationLead"`
+	PTODays      int     `json:"ptoDays"`
+}
+
+// APIEmailDelivery is one logged reminder email
+type APIEmailDelivery struct {
+	ID        uint   `json:"id"`
+	Kind      string `json:"kind"`
+	To        string `json:"to"`
+	Subject   string `json:"subject"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func (ctlr *RTOController) toAPINotifications(settings types.NotificationSettings) APINotifications {
+	return APINotifications{
+		EmailEnabled: ctlr.service.EmailEnabled(),
+		Behind:       settings.Behind,
+		BehindDays:   settings.BehindDays,
+		Unlogged:     settings.Unlogged,
+		Vacation:     settings.Vacation,
+		VacationLead: settings.VacationLead,
+		PTODays:      settings.PTODays,
+	}
+}
+
+// APIGetNotifications returns the user's reminder settings
+func (ctlr *RTOController) APIGetNotifications(c echo.Context) error {
+	settings, err := ctlr.service.GetNotificationSettings(currentUserID(c))
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, ctlr.toAPINotifications(settings))
+}
+
+// APIUpdateNotifications replaces the user's reminder settings
+func (ctlr *RTOController) APIUpdateNotifications(c echo.Context) error {
+	var req APINotifications
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+
+	settings := types.NotificationSettings{
+		Behind:       req.Behind,
+		BehindDays:   req.BehindDays,
+		Unlogged:     req.Unlogged,
+		Vacation:     req.Vacation,
+		VacationLead: req.VacationLead,
+		PTODays:      req.PTODays,
+	}
+	if err := ctlr.service.UpdateNotificationSettings(currentUserID(c), settings); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, ctlr.toAPINotifications(settings))
+}
+
+// APIListEmailDeliveries returns the user's recent reminder emails, newest first
+func (ctlr *RTOController) APIListEmailDeliveries(c echo.Context) error {
+	deliveries, err := ctlr.service.GetEmailDeliveries(currentUserID(c), recentEmails)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	out := make([]APIEmailDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		out = append(out, APIEmailDelivery{
+			ID:        d.ID,
+			Kind:      d.Kind,
+			To:        d.To,
+			Subject:   d.Subject,
+			Success:   d.Success,
+			Error:     d.Error,
+			CreatedAt: d.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// APISendTestEmail sends the user a test message. A failure to reach the
+// mail server is a 502.
+func (ctlr *RTOController) APISendTestEmail(c echo.Context) error {
+	err := ctlr.service.SendTestEmail(currentUserID(c))
+	switch {
+	case err == nil:
+		return c.NoContent(http.StatusNoContent)
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrNotFound):
+		return ctlr.apiServiceError(c, err)
+	default:
+		return apiError(c, http.StatusBadGateway, "email_failed", err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+// controller/api_notifications_test.go
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGetNotifications(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+	mockService.On("GetNotificationSettings", 0).Return(types.NotificationSettings{Behind: true, BehindDays: 1.5, VacationLead: 3, PTODays: 20}, nil)
+	mockService.On("EmailEnabled").Return(true)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetNotifications(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{"emailEnabled": true, "behind": true, "behindDays": 1.5, "unlogged": false, "vacation": false, "vacationLead": 3, "ptoDays": 20}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIUpdateNotifications_Invalid(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+	settings := types.NotificationSettings{Vacation: true, BehindDays: 1, VacationLead: 30}
+	mockService.On("UpdateNotificationSettings", 0, settings).
+		Return(fmt.Errorf("%w: vacation notice must be between 1 and 14 days", domain.ErrInvalidInput))
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	body := `{"vacation": true, "behindDays": 1, "vacationLead": 30}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/notifications", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIUpdateNotifications(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestAPISendTestEmail_MailServerDown(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+	mockService.On("SendTestEmail", 0).Return(errors.New("dial tcp: connection refused"))
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications/test", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APISendTestEmail(c)) {
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, rec.Body.String(), "email_failed")
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// occupancyWindow is how many days the occupancy forecast covers by default
+const occupancyWindow = 28
+
+// APIOccupancy is the JSON representation of an occupancy forecast
+type APIOccupancy struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Planned  int               `json:"planned"`  // summed over the past days
+	Actual   int               `json:"actual"`   // summed over the past days
+	Accuracy float64           `json:"accuracy"` // percentage; 0 when no day is past
+	Days     []APIOccupancyDay `json:"days"`
+}
+
+// APIOccupancyDay is one office's head count for a day
+type APIOccupancyDay struct {
+	Date     string `json:"date"`
+	OfficeID uint   `json:"officeId"` // 0 for people with no office
+	Office   string `json:"office,omitempty"`
+	Planned  int    `json:"planned"`
+	Actual   *int   `json:"actual,omitempty"` // only for days already past
+}
+
+// occupancyRange reads ?from and ?to, defaulting to the next four weeks
+func occupancyRange(c echo.Context) (time.Time, time.Time, error) {
+	from, err := parseOptionalDate(c, "from")
+	if err != nil {
+		return from, from, err
+	}
+	to, err := parseOptionalDate(c, "to")
+	if err != nil {
+		return from, to, err
+	}
+	if from.IsZero() {
+		from = utils.NormalizeDate(time.Now())
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, occupancyWindow-1)
+	}
+	return from, to, nil
+}
+
+// APIGetOccupancy returns the per-office, per-day head count forecast
+func (ctlr *RTOController) APIGetOccupancy(c echo.Context) error {
+	from, to, err := occupancyRange(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	forecast, err := ctlr.service.OccupancyForecast(from, to)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	names := officeNames(forecast.Offices)
+	out := APIOccupancy{
+		From:     forecast.From.Format("2006-01-02"),
+		To:       forecast.To.Format("2006-01-02"),
+		Planned:  forecast.Planned,
+		Actual:   forecast.Actual,
+		Accuracy: forecast.Accuracy,
+		Days:     make([]APIOccupancyDay, 0, len(forecast.Days)),
+	}
+	for _, day := range forecast.Days {
+		row := APIOccupancyDay{
+			Date:     day.Date.Format("2006-01-02"),
+			OfficeID: day.OfficeID,
+			Office:   names[day.OfficeID],
+			Planned:  day.Planned,
+		}
+		if day.Past {
+			actual := day.Actual
+			row.Actual = &actual
+		}
+		out.Days = append(out.Days, row)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// APIExportOccupancy downloads the forecast as CSV for facilities and
+// catering. The actual column is empty for days still to come.
+func (ctlr *RTOController) APIExportOccupancy(c echo.Context) error {
+	from, to, err := occupancyRange(c)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	forecast, err := ctlr.service.OccupancyForecast(from, to)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	filename := fmt.Sprintf("occupancy_%s_%s.csv", forecast.From.Format("2006-01-02"), forecast.To.Format("2006-01-02"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+filename)
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+	c.Response().WriteHeader(http.StatusOK)
+
+	names := officeNames(forecast.Offices)
+	w := csv.NewWriter(c.Response())
+	w.Write([]string{"date", "office_id", "office", "planned", "actual"})
+	for _, day := range forecast.Days {
+		actual := ""
+		if day.Past {
+			actual = strconv.Itoa(day.Actual)
+		}
+		w.Write([]string{
+			day.Date.Format("2006-01-02"),
+			strconv.FormatUint(uint64(day.OfficeID), 10),
+			names[day.OfficeID],
+			strconv.Itoa(day.Planned),
+			actual,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
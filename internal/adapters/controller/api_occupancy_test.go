@@ -0,0 +1,101 @@
+// controller/api_occupancy_test.go
+
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOccupancyTest(t *testing.T) *RTOController {
+	from := time.Date(2024, 10, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 9, 0, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.RTOBLL)
+	mockService.On("OccupancyForecast", from, to).Return(&types.OccupancyForecast{
+		From:    from,
+		To:      to,
+		Offices: []types.Office{{ID: 1, Name: "HQ"}},
+		Days: []types.OccupancyDay{
+			{Date: from, OfficeID: 1, Planned: 4, Actual: 3, Past: true},
+			{Date: to, OfficeID: 1, Planned: 5},
+			{Date: to, OfficeID: 0, Planned: 1},
+		},
+		Planned:  4,
+		Actual:   3,
+		Accuracy: 66.7,
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	t.Cleanup(func() { mockService.AssertExpectations(t) })
+	return ctlr
+}
+
+func TestAPIGetOccupancy(t *testing.T) {
+	e := echo.New()
+	ctlr := newOccupancyTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/occupancy?from=2024-10-08&to=2024-10-09", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetOccupancy(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"from": "2024-10-08",
+			"to": "2024-10-09",
+			"planned": 4,
+			"actual": 3,
+			"accuracy": 66.7,
+			"days": [
+				{"date": "2024-10-08", "officeId": 1, "office": "HQ", "planned": 4, "actual": 3},
+				{"date": "2024-10-09", "officeId": 1, "office": "HQ", "planned": 5},
+				{"date": "2024-10-09", "officeId": 0, "planned": 1}
+			]
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+}
+
+func TestAPIExportOccupancy(t *testing.T) {
+	e := echo.New()
+	ctlr := newOccupancyTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/occupancy.csv?from=2024-10-08&to=2024-10-09", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIExportOccupancy(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "occupancy_2024-10-08_2024-10-09.csv")
+		expected := "date,office_id,office,planned,actual\n" +
+			"2024-10-08,1,HQ,4,3\n" +
+			"2024-10-09,1,HQ,5,\n" +
+			"2024-10-09,0,,1,\n"
+		assert.Equal(t, expected, rec.Body.String())
+	}
+}
+
+func TestAPIGetOccupancy_BadDate(t *testing.T) {
+	e := echo.New()
+	ctlr := NewRTOControllerWithMock("none", new(mocks.RTOBLL), QuarterStart, QuarterEnd)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/occupancy?from=next-week", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetOccupancy(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APITeammate is a teammate as shown in the who's-in view
+type APITeammate struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// APIOfficeDay is the JSON representation of who is in the office on a day
+type APIOfficeDay struct {
+	Date            string        `json:"date"`
+	Status          string        `json:"status"` // the caller's own: in, remote, vacation, holiday or ""
+	Headcount       int           `json:"headcount"`
+	InOffice        []APITeammate `json:"inOffice"`
+	WantCompany     []APITeammate `json:"wantCompany"`
+	AskedForCompany bool          `json:"askedForCompany"`
+}
+
+func toAPITeammates(users []types.User) []APITeammate {
+	teammates := make([]APITeammate, 0, len(users))
+	for _, user := range users {
+		teammates = append(teammates, APITeammate{ID: user.ID, Username: user.Username})
+	}
+	return teammates
+}
+
+func toAPIOfficeDay(day types.OfficeDay) APIOfficeDay {
+	return APIOfficeDay{
+		Date:            day.Date.Format("2006-01-02"),
+		Status:          day.Status,
+		Headcount:       day.Headcount(),
+		InOffice:        toAPITeammates(day.InOffice),
+		WantCompany:     toAPITeammates(day.WantCompany),
+		AskedForCompany: day.AskedForCompany,
+	}
+}
+
+// APIWhoIsIn lists the teammates in the office on a date
+func (ctlr *RTOController) APIWhoIsIn(c echo.Context) error {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	day, err := ctlr.service.WhoIsIn(currentUserID(c), date)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIOfficeDay(*day))
+}
+
+// APIOfficeSuggestions returns the upcoming days with the most teammates in
+func (ctlr *RTOController) APIOfficeSuggestions(c echo.Context) error {
+	days, err := ctlr.service.SuggestOfficeDays(currentUserID(c), time.Now())
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	data := make([]APIOfficeDay, 0, len(days))
+	for _, day := range days {
+		data = append(data, toAPIOfficeDay(day))
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  data,
+		Total: int64(len(data)),
+		Limit: len(data),
+	})
+}
+
+// APIRequestCompany marks the date as one the caller would like company on
+func (ctlr *RTOController) APIRequestCompany(c echo.Context) error {
+	return ctlr.apiSetCompany(c, true)
+}
+
+// APIClearCompany withdraws the request for company
+func (ctlr *RTOController) APIClearCompany(c echo.Context) error {
+	return ctlr.apiSetCompany(c, false)
+}
+
+func (ctlr *RTOController) apiSetCompany(c echo.Context, want bool) error {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "date must be in YYYY-MM-DD format")
+	}
+
+	if err := ctlr.service.SetCompanyRequest(currentUserID(c), date, want); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	day, err := ctlr.service.WhoIsIn(currentUserID(c), date)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIOfficeDay(*day))
+}
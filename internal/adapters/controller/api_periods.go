@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIPeriod is the JSON representation of a period
+type APIPeriod struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	Closed    bool   `json:"closed"`
+	ClosedAt  string `json:"closedAt,omitempty"`
+}
+
+// APIPeriodRequest is the payload for creating a period
+type APIPeriodRequest struct {
+	Name      string `json:"name"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// APIPeriodArchive is a user's archived stats for a closed period
+type APIPeriodArchive struct {
+	Period APIPeriod `json:"period"`
+	Stats  APIStats  `json:"stats"`
+	Weeks  []APIWeek `json:"weeks"`
+}
+
+// APIWeek counts one week's weekdays by status
+type APIWeek struct {
+	Start    string `json:"start"` // the Monday, or the period's first weekday
+	InOffice int    `json:"inOffice"`
+	Remote   int    `json:"remote"`
+	Vacation int    `json:"vacation"`
+	Holiday  int    `json:"holiday"`
+	Unlogged int    `json:"unlogged"`
+}
+
+func toAPIPeriod(period types.Period) APIPeriod {
+	out := APIPeriod{
+		ID:        period.ID,
+		Name:      period.Name,
+		StartDate: period.StartDate.Format("2006-01-02"),
+		EndDate:   period.EndDate.Format("2006-01-02"),
+		Closed:    period.Closed(),
+	}
+	if period.ClosedAt != nil {
+		out.ClosedAt = period.ClosedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// APIListPeriods returns every period
+func (ctlr *RTOController) APIListPeriods(c echo.Context) error {
+	periods, err := ctlr.service.GetPeriods()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	data := make([]APIPeriod, 0, len(periods))
+	for _, period := range periods {
+		data = append(data, toAPIPeriod(period))
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  data,
+		Total: int64(len(data)),
+		Limit: len(data),
+	})
+}
+
+// APIGetPeriod returns a single period
+func (ctlr *RTOController) APIGetPeriod(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	period, err := ctlr.service.GetPeriod(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPeriod(*period))
+}
+
+// APIGetCurrentPeriod returns the period containing today
+func (ctlr *RTOController) APIGetCurrentPeriod(c echo.Context) error {
+	period, err := ctlr.service.GetCurrentPeriod()
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPeriod(*period))
+}
+
+// APICreatePeriod adds a new, non-overlapping period
+func (ctlr *RTOController) APICreatePeriod(c echo.Context) error {
+	var req APIPeriodRequest
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "startDate must be in YYYY-MM-DD format")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "endDate must be in YYYY-MM-DD format")
+	}
+
+	created, err := ctlr.service.CreatePeriod(types.Period{
+		Name:      req.Name,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusCreated, toAPIPeriod(*created))
+}
+
+// APIDeletePeriod removes a period
+func (ctlr *RTOController) APIDeletePeriod(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	if err := ctlr.service.DeletePeriod(id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// APIClosePeriod archives everyone's stats for an ended period and locks it
+func (ctlr *RTOController) APIClosePeriod(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	period, err := ctlr.service.ClosePeriod(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPeriod(*period))
+}
+
+// APIReopenPeriod unlocks a closed period and drops its archive
+func (ctlr *RTOController) APIReopenPeriod(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	period, err := ctlr.service.ReopenPeriod(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPeriod(*period))
+}
+
+// APIGetPeriodArchive returns the stats and weekly breakdown archived when
+// the period closed
+func (ctlr *RTOController) APIGetPeriodArchive(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	period, err := ctlr.service.GetPeriod(id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	archive, err := ctlr.service.GetPeriodArchive(calendarUserID(c), id)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	weeks := make([]APIWeek, 0, len(archive.Weeks))
+	for _, week := range archive.Weeks {
+		weeks = append(weeks, APIWeek{
+			Start:    week.Start.Format("2006-01-02"),
+			InOffice: week.InOffice,
+			Remote:   week.Remote,
+			Vacation: week.Vacation,
+			Holiday:  week.Holiday,
+			Unlogged: week.Unlogged,
+		})
+	}
+	return c.JSON(http.StatusOK, APIPeriodArchive{
+		Period: toAPIPeriod(*period),
+		Stats:  toAPIStats(archive.Stats(), period.StartDate, period.EndDate),
+		Weeks:  weeks,
+	})
+}
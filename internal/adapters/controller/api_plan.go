@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIPlan is the JSON representation of a Plan
+type APIPlan struct {
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	TargetDays    float64 `json:"targetDays"`
+	TotalDays     int     `json:"totalDays"`
+	RequiredDays  int     `json:"requiredDays"`
+	LoggedDays    int     `json:"loggedDays"`
+	PlannedDays   int     `json:"plannedDays"`
+	RemainingDays int     `json:"remainingDays"`
+	OpenDays      int     `json:"openDays"`
+	WeeksLeft     float64 `json:"weeksLeft"`
+	PerWeek       float64 `json:"perWeek"`
+	Achievable    bool    `json:"achievable"`
+	Method        string  `json:"method,omitempty"`
+}
+
+func toAPIPlan(plan types.Plan) APIPlan {
+	return APIPlan{
+		From:          plan.From.Format("2006-01-02"),
+		To:            plan.To.Format("2006-01-02"),
+		TargetDays:    plan.TargetDays,
+		TotalDays:     plan.TotalDays,
+		RequiredDays:  plan.RequiredDays,
+		LoggedDays:    plan.LoggedDays,
+		PlannedDays:   plan.PlannedDays,
+		RemainingDays: plan.RemainingDays,
+		OpenDays:      plan.OpenDays,
+		WeeksLeft:     plan.WeeksLeft,
+		PerWeek:       plan.PerWeek,
+		Achievable:    plan.Achievable,
+		Method:        plan.Method,
+	}
+}
+
+// APIGetPlan returns how many more in-office days are needed in a period
+// (?period=ID), defaulting to the current period
+func (ctlr *RTOController) APIGetPlan(c echo.Context) error {
+	var period *types.Period
+	var err error
+	if v := c.QueryParam("period"); v != "" {
+		periodID, convErr := strconv.Atoi(v)
+		if convErr != nil || periodID < 1 {
+			return apiError(c, http.StatusBadRequest, "invalid_input", "period must be a positive integer")
+		}
+		period, err = ctlr.service.GetPeriod(periodID)
+	} else {
+		period, err = ctlr.service.GetCurrentPeriod()
+	}
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	plan, err := ctlr.service.CalculatePlan(currentUserID(c), period.StartDate, period.EndDate)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPlan(*plan))
+}
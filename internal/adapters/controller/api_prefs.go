@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIPreferences is the JSON representation of the user's preferences
+type APIPreferences struct {
+	DefaultDays       string  `json:"defaultDays"`
+	TargetDays        float64 `json:"targetDays"`
+	CalculationMethod string  `json:"calculationMethod"` // calendar or workdays; empty keeps the current one
+	DefaultOfficeID   uint    `json:"defaultOfficeId"`   // 0 when not set
+}
+
+// APIPreferenceVersion is one dated change to the preferences
+type APIPreferenceVersion struct {
+	ID                uint    `json:"id"`
+	EffectiveFrom     string  `json:"effectiveFrom,omitempty"` // YYYY-MM-DD; absent for the version from the start
+	DefaultDays       string  `json:"defaultDays"`
+	TargetDays        float64 `json:"targetDays"`
+	CalculationMethod string  `json:"calculationMethod"`
+}
+
+// validDayAbbrevs are the abbreviations AddDefaultDays understands
+var validDayAbbrevs = map[string]bool{
+	"m": true, "t": true, "w": true, "th": true, "f": true, "sat": true, "sun": true,
+}
+
+func parseTarget(targetDays string) float64 {
+	target, err := strconv.ParseFloat(targetDays, 64)
+	if err != nil {
+		return 2.5
+	}
+	return target
+}
+
+func toAPIPreferences(prefs types.Preferences) APIPreferences {
+	method := prefs.CalculationMethod
+	if method == "" {
+		method = types.MethodCalendar
+	}
+	return APIPreferences{
+		DefaultDays:       prefs.DefaultDays,
+		TargetDays:        parseTarget(prefs.TargetDays),
+		CalculationMethod: method,
+		DefaultOfficeID:   prefs.DefaultOfficeID,
+	}
+}
+
+func toAPIPreferenceVersion(version types.PreferenceVersion) APIPreferenceVersion {
+	v := APIPreferenceVersion{
+		ID:                version.ID,
+		DefaultDays:       version.DefaultDays,
+		TargetDays:        parseTarget(version.TargetDays),
+		CalculationMethod: version.CalculationMethod,
+	}
+	if !version.EffectiveFrom.IsZero() {
+		v.EffectiveFrom = version.EffectiveFrom.Format("2006-01-02")
+	}
+	return v
+}
+
+// validate checks the default day list and target range
+func (p APIPreferences) validate() error {
+	if strings.TrimSpace(p.DefaultDays) == "" {
+		return fmt.Errorf("defaultDays is required")
+	}
+	if strings.EqualFold(strings.TrimSpace(p.DefaultDays), types.TeamDefaultDays) {
+		return p.validateTarget()
+	}
+	for _, day := range strings.Split(p.DefaultDays, ",") {
+		if !validDayAbbrevs[strings.ToLower(strings.TrimSpace(day))] {
+			return fmt.Errorf("defaultDays contains unknown day %q", strings.TrimSpace(day))
+		}
+	}
+	return p.validateTarget()
+}
+
+func (p APIPreferences) validateTarget() error {
+	if p.TargetDays <= 0 || p.TargetDays > 7 {
+		return fmt.Errorf("targetDays must be greater than 0 and at most 7")
+	}
+	return nil
+}
+
+// APIGetPreferences returns the current preferences
+func (ctlr *RTOController) APIGetPreferences(c echo.Context) error {
+	return c.JSON(http.StatusOK, toAPIPreferences(ctlr.service.GetPrefs(currentUserID(c))))
+}
+
+// APIUpdatePreferences replaces the current preferences. The default days,
+// target and method change from today; earlier days keep the ones before.
+func (ctlr *RTOController) APIUpdatePreferences(c echo.Context) error {
+	var req APIPreferences
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	if err := req.validate(); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	userID := currentUserID(c)
+	if err := ctlr.service.SetPreferences(userID, types.PreferenceVersion{
+		DefaultDays:       req.DefaultDays,
+		TargetDays:        strconv.FormatFloat(req.TargetDays, 'f', -1, 64),
+		CalculationMethod: req.CalculationMethod,
+	}); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	if err := ctlr.service.SetDefaultOffice(userID, int(req.DefaultOfficeID)); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIPreferences(ctlr.service.GetPrefs(userID)))
+}
+
+// APIGetPreferenceHistory lists the dated versions of the preferences, oldest first
+func (ctlr *RTOController) APIGetPreferenceHistory(c echo.Context) error {
+	history, err := ctlr.service.GetPreferenceHistory(currentUserID(c))
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	versions := make([]APIPreferenceVersion, 0, len(history))
+	for _, version := range history {
+		versions = append(versions, toAPIPreferenceVersion(version))
+	}
+	return c.JSON(http.StatusOK, versions)
+}
+
+// APIAddPreferenceVersion records preferences in force from effectiveFrom,
+// or from today when it is left out
+func (ctlr *RTOController) APIAddPreferenceVersion(c echo.Context) error {
+	var req APIPreferenceVersion
+	if err := c.Bind(&req); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", "Invalid JSON payload.")
+	}
+	prefs := APIPreferences{DefaultDays: req.DefaultDays, TargetDays: req.TargetDays}
+	if err := prefs.validate(); err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	version := types.PreferenceVersion{
+		DefaultDays:       req.DefaultDays,
+		TargetDays:        strconv.FormatFloat(req.TargetDays, 'f', -1, 64),
+		CalculationMethod: req.CalculationMethod,
+	}
+	if req.EffectiveFrom != "" {
+		date, err := time.Parse("2006-01-02", req.EffectiveFrom)
+		if err != nil {
+			return apiError(c, http.StatusBadRequest, "invalid_input", "effectiveFrom must be a date in YYYY-MM-DD format")
+		}
+		version.EffectiveFrom = date
+	}
+
+	if err := ctlr.service.SetPreferences(currentUserID(c), version); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return ctlr.APIGetPreferenceHistory(c)
+}
+
+// APIDeletePreferenceVersion removes a dated version; the version from the
+// start cannot be removed
+func (ctlr *RTOController) APIDeletePreferenceVersion(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	if err := ctlr.service.DeletePreferenceVersion(currentUserID(c), id); err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIUser is the JSON representation of another user, as a manager sees them
+type APIUser struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role"`
+}
+
+func toAPIUser(user types.User) APIUser {
+	return APIUser{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+}
+
+// APIListReports returns the users who report to the signed-in manager. Their
+// events and stats are under /users/{id}/events and /users/{id}/stats.
+func (ctlr *RTOController) APIListReports(c echo.Context) error {
+	reports, err := ctlr.service.GetReports(currentUserID(c))
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+
+	data := make([]APIUser, 0, len(reports))
+	for _, user := range reports {
+		data = append(data, toAPIUser(user))
+	}
+	return c.JSON(http.StatusOK, APIListResponse{
+		Data:  data,
+		Total: int64(len(data)),
+		Limit: len(data),
+	})
+}
+
+// APIApproveEvent signs off one of a report's vacation days
+func (ctlr *RTOController) APIApproveEvent(c echo.Context) error {
+	userID, err := parseIDParam(c, "id")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	eventID, err := parseIDParam(c, "eventId")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	event, err := ctlr.service.ApproveVacation(currentUserID(c), userID, eventID)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIEvent(event))
+}
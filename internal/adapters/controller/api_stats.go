@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// APIStats is the JSON representation of AttendanceStats for a date range
+type APIStats struct {
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	InOfficeCount  int             `json:"inOfficeCount"`
+	TotalDays      int             `json:"totalDays"`
+	Average        float64         `json:"average"`
+	AverageDays    float64         `json:"averageDays"`
+	TargetDays     float64         `json:"targetDays"`
+	AveragePercent float64         `json:"averagePercent"`
+	ByOffice       []APIOfficeDays `json:"byOffice"`
+	Uncounted      int             `json:"uncounted"`        // days at another office left out by the policy
+	Method         string          `json:"method,omitempty"` // calculation method in force: calendar or workdays
+}
+
+// APIOfficeDays is how many in-office days were spent at one office; office 0
+// holds the days with no office recorded
+type APIOfficeDays struct {
+	OfficeID uint `json:"officeId"`
+	Days     int  `json:"days"`
+}
+
+func toAPIStats(stats types.AttendanceStats, from, to time.Time) APIStats {
+	byOffice := make([]APIOfficeDays, 0, len(stats.ByOffice))
+	for officeID, days := range stats.ByOffice {
+		byOffice = append(byOffice, APIOfficeDays{OfficeID: officeID, Days: days})
+	}
+	sort.Slice(byOffice, func(i, j int) bool { return byOffice[i].OfficeID < byOffice[j].OfficeID })
+
+	return APIStats{
+		From:           from.Format("2006-01-02"),
+		To:             to.Format("2006-01-02"),
+		InOfficeCount:  stats.InOfficeCount,
+		TotalDays:      stats.TotalDays,
+		Average:        stats.Average,
+		AverageDays:    stats.AverageDays,
+		TargetDays:     stats.TargetDays,
+		AveragePercent: stats.AveragePercent,
+		ByOffice:       byOffice,
+		Uncounted:      stats.Uncounted,
+		Method:         stats.Method,
+	}
+}
+
+// APIGetStats returns attendance stats for a period (?period=ID), an explicit
+// range (?from=&to=) or, by default, the current period. A closed period's
+// stats are the ones archived when it closed.
+func (ctlr *RTOController) APIGetStats(c echo.Context) error {
+	from, err := parseOptionalDate(c, "from")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+	to, err := parseOptionalDate(c, "to")
+	if err != nil {
+		return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+	}
+
+	periodParam := c.QueryParam("period")
+	switch {
+	case periodParam != "" && (!from.IsZero() || !to.IsZero()):
+		return apiError(c, http.StatusBadRequest, "invalid_input", "use either period or from/to, not both")
+	case periodParam != "":
+		id, err := strconv.Atoi(periodParam)
+		if err != nil || id < 1 {
+			return apiError(c, http.StatusBadRequest, "invalid_input", "period must be a positive integer")
+		}
+		period, err := ctlr.service.GetPeriod(id)
+		if err != nil {
+			return ctlr.apiServiceError(c, err)
+		}
+		stats, err := ctlr.service.PeriodStats(calendarUserID(c), id)
+		if err != nil {
+			return ctlr.apiServiceError(c, err)
+		}
+		return c.JSON(http.StatusOK, toAPIStats(*stats, period.StartDate, period.EndDate))
+	case from.IsZero() != to.IsZero():
+		return apiError(c, http.StatusBadRequest, "invalid_input", "from and to must be given together")
+	case from.IsZero():
+		period, err := ctlr.service.GetCurrentPeriod()
+		if err != nil {
+			return ctlr.apiServiceError(c, err)
+		}
+		from, to = period.StartDate, period.EndDate
+	}
+
+	stats, err := ctlr.service.CalculateStatsBetween(calendarUserID(c), from, to)
+	if err != nil {
+		return ctlr.apiServiceError(c, err)
+	}
+	return c.JSON(http.StatusOK, toAPIStats(*stats, from, to))
+}
@@ -0,0 +1,298 @@
+// controller/api_stats_test.go
+
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIGetStats_ForPeriod(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	period := &types.Period{
+		ID:        3,
+		Name:      "Q4 2024",
+		StartDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("GetPeriod", 3).Return(period, nil)
+	mockService.On("PeriodStats", 0, 3).Return(&types.AttendanceStats{
+		InOfficeCount:  30,
+		TotalDays:      92,
+		Average:        32.6,
+		AverageDays:    2.28,
+		TargetDays:     2.5,
+		AveragePercent: 91.3,
+		ByOffice:       map[uint]int{2: 4, 1: 26},
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats?period=3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"from": "2024-10-01",
+			"to": "2024-12-31",
+			"inOfficeCount": 30,
+			"totalDays": 92,
+			"average": 32.6,
+			"averageDays": 2.28,
+			"targetDays": 2.5,
+			"averagePercent": 91.3,
+			"byOffice": [{"officeId": 1, "days": 26}, {"officeId": 2, "days": 4}],
+			"uncounted": 0
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIGetStats_PeriodAndRange(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats?period=3&from=2024-10-01", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetStats(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"invalid_input"`)
+	}
+}
+
+func TestAPICreatePeriod_Overlap(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	period := types.Period{
+		Name:      "Q1 2025",
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("CreatePeriod", period).Return(nil, domain.ErrConflict)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	body := `{"name": "Q1 2025", "startDate": "2025-01-01", "endDate": "2025-03-31"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/periods", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APICreatePeriod(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIUpdatePreferences_InvalidDay(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	body := `{"defaultDays": "M,Tu", "targetDays": 3}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/preferences", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIUpdatePreferences(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		expected := `{"error": {"code": "invalid_input", "message": "defaultDays contains unknown day \"Tu\""}}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertNotCalled(t, "SetPreferences", mock.Anything, mock.Anything)
+}
+
+func TestAPIGetPlan_CurrentPeriod(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+
+	period := &types.Period{
+		ID:        1,
+		Name:      "Q1 2025",
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("GetCurrentPeriod").Return(period, nil)
+	mockService.On("CalculatePlan", 0, period.StartDate, period.EndDate).Return(&types.Plan{
+		From:          period.StartDate,
+		To:            period.EndDate,
+		TargetDays:    2.5,
+		TotalDays:     90,
+		RequiredDays:  33,
+		LoggedDays:    10,
+		PlannedDays:   3,
+		RemainingDays: 20,
+		OpenDays:      25,
+		WeeksLeft:     8,
+		PerWeek:       2.5,
+		Achievable:    true,
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plan", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetPlan(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"from": "2025-01-01",
+			"to": "2025-03-31",
+			"targetDays": 2.5,
+			"totalDays": 90,
+			"requiredDays": 33,
+			"loggedDays": 10,
+			"plannedDays": 3,
+			"remainingDays": 20,
+			"openDays": 25,
+			"weeksLeft": 8,
+			"perWeek": 2.5,
+			"achievable": true
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIGetPeriodArchive(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+
+	closedAt := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	period := &types.Period{
+		ID:        3,
+		Name:      "Q4 2024",
+		StartDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		ClosedAt:  &closedAt,
+	}
+	mockService.On("GetPeriod", 3).Return(period, nil)
+	mockService.On("GetPeriodArchive", 0, 3).Return(&types.PeriodArchive{
+		InOfficeCount: 30,
+		TotalDays:     92,
+		TargetDays:    2.5,
+		ByOffice:      map[uint]int{1: 30},
+		Weeks:         []types.WeekStats{{Start: period.StartDate, InOffice: 2, Remote: 1}},
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/periods/3/archive", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	if assert.NoError(t, ctlr.APIGetPeriodArchive(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `{
+			"period": {"id": 3, "name": "Q4 2024", "startDate": "2024-10-01", "endDate": "2024-12-31", "closed": true, "closedAt": "2025-01-01T01:00:00Z"},
+			"stats": {"from": "2024-10-01", "to": "2024-12-31", "inOfficeCount": 30, "totalDays": 92, "average": 0, "averageDays": 0,
+				"targetDays": 2.5, "averagePercent": 0, "byOffice": [{"officeId": 1, "days": 30}], "uncounted": 0},
+			"weeks": [{"start": "2024-10-01", "inOffice": 2, "remote": 1, "vacation": 0, "holiday": 0, "unlogged": 0}]
+		}`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIGetPreferenceHistory(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mockService.On("GetPreferenceHistory", 0).Return([]types.PreferenceVersion{
+		{ID: 1, DefaultDays: "M,T", TargetDays: "2.5", CalculationMethod: types.MethodCalendar},
+		{ID: 2, EffectiveFrom: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), DefaultDays: "T,W,Th", TargetDays: "3", CalculationMethod: types.MethodWorkdays},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/preferences/history", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIGetPreferenceHistory(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := `[
+			{"id": 1, "defaultDays": "M,T", "targetDays": 2.5, "calculationMethod": "calendar"},
+			{"id": 2, "effectiveFrom": "2025-07-01", "defaultDays": "T,W,Th", "targetDays": 3, "calculationMethod": "workdays"}
+		]`
+		assert.JSONEq(t, expected, rec.Body.String())
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIAddPreferenceVersion_InvalidMethod(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	version := types.PreferenceVersion{
+		EffectiveFrom:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		DefaultDays:       "M,T",
+		TargetDays:        "3",
+		CalculationMethod: "hours",
+	}
+	mockService.On("SetPreferences", 0, version).Return(domain.ErrInvalidInput)
+
+	body := `{"effectiveFrom": "2025-07-01", "defaultDays": "M,T", "targetDays": 3, "calculationMethod": "hours"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/preferences/history", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.APIAddPreferenceVersion(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	mockService.AssertExpectations(t)
+}
@@ -1,93 +1,382 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/gorilla/sessions"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Session values and context keys for the signed-in user
+const (
+	sessionUserKey    = "user_id"
+	sessionVersionKey = "session_version"
+	sessionMethodKey  = "auth_method" // authPassword or authSSO
+
+	// A password sign-in waiting for its second factor
+	pendingUserKey = "pending_user_id"
+	pendingAtKey   = "pending_at"
+
+	// currentUserKey is the context key holding the *types.User a request acts for:
+	// the signed-in user, or the owner of the bearer token
+	currentUserKey = "user"
 )
 
 // ShowLoginForm renders the login page
 func (ctlr *RTOController) ShowLoginForm(c echo.Context) error {
-	return c.Render(http.StatusOK, "login.html", nil)
+	return c.Render(http.StatusOK, "login.html", ctlr.loginData(""))
+}
+
+// loginData is the login page model; it offers sign-up while registration is
+// possible and password sign-in is on
+func (ctlr *RTOController) loginData(errMsg string) map[string]interface{} {
+	hasUsers, err := ctlr.service.HasUsers()
+	if err != nil {
+		hasUsers = true
+	}
+	return map[string]interface{}{
+		"Error":         errMsg,
+		"FirstUser":     !hasUsers,
+		"CanRegister":   ctlr.passwordLogin && (!hasUsers || ctlr.service.RegistrationOpen()),
+		"PasswordLogin": ctlr.passwordLogin,
+		"SSO":           ctlr.sso != nil,
+	}
 }
 
 // ProcessLogin handles the login form submission
 func (ctlr *RTOController) ProcessLogin(c echo.Context) error {
+	if !ctlr.passwordLogin {
+		return c.Render(http.StatusForbidden, "login.html", ctlr.loginData("Password sign-in is disabled. Use single sign-on."))
+	}
+
 	username := c.FormValue("username")
 	password := c.FormValue("password")
 
-	// Hardcoded credentials
-	const (
-		validUsername = "aaa"
-		validPassword = "aaa"
-	)
-
-	if username == validUsername && password == validPassword {
-		// Set session
-		sess, err := session.Get("session", c)
-		if err != nil {
-			ctlr.logger.Error("Failed to get session", "error", err)
-			return c.Render(http.StatusInternalServerError, "login.html", map[string]interface{}{
-				"Error": "Internal server error",
-			})
-		}
-		sess.Values["authenticated"] = true
-		err = sess.Save(c.Request(), c.Response())
-		if err != nil {
+	user, err := ctlr.service.Authenticate(username, password)
+	if errors.Is(err, domain.ErrSecondFactorRequired) {
+		if err := ctlr.startSecondFactor(c, user); err != nil {
 			ctlr.logger.Error("Failed to save session", "error", err)
-			return c.Render(http.StatusInternalServerError, "login.html", map[string]interface{}{
-				"Error": "Internal server error",
-			})
+			return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
 		}
+		return c.Redirect(http.StatusSeeOther, "/login/2fa")
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountLocked):
+			return c.Render(http.StatusTooManyRequests, "login.html", ctlr.loginData(err.Error()))
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			ctlr.logger.Info("Failed sign-in", "username", username)
+			return c.Render(http.StatusUnauthorized, "login.html", ctlr.loginData("Invalid username or password"))
+		default:
+			ctlr.logger.Error("Failed to authenticate", "error", err)
+			return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+		}
+	}
+
+	if err := ctlr.startSession(c, user, authPassword); err != nil {
+		ctlr.logger.Error("Failed to save session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+
+	ctlr.logger.Info("User signed in", "userID", user.ID)
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// How a session was signed in
+const (
+	authPassword = "password"
+	authSSO      = "sso"
+)
+
+// startSession signs the user in. Whatever the session held before is thrown
+// away, so values planted before login never carry over into the signed-in session.
+func (ctlr *RTOController) startSession(c echo.Context, user *types.User, method string) error {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return err
+	}
+	for key := range sess.Values {
+		delete(sess.Values, key)
+	}
+	sess.Values[sessionUserKey] = int(user.ID)
+	sess.Values[sessionVersionKey] = user.SessionVersion
+	sess.Values[sessionMethodKey] = method
+	return sess.Save(c.Request(), c.Response())
+}
 
-		ctlr.logger.Info("++++++++++++++++++++")
+// sessionMethod returns how the current session was signed in. Sessions from
+// before single sign-on existed carry no method and were password sign-ins.
+func (ctlr *RTOController) sessionMethod(c echo.Context) string {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return authPassword
+	}
+	if method, ok := sess.Values[sessionMethodKey].(string); ok {
+		return method
+	}
+	return authPassword
+}
 
-		return c.Redirect(http.StatusSeeOther, "/")
+// renewSession re-issues the current session after the account's session
+// version was bumped, so this session stays signed in while the others end
+func (ctlr *RTOController) renewSession(c echo.Context) error {
+	user, err := ctlr.service.GetUser(currentUserID(c))
+	if err != nil {
+		return err
 	}
+	if err := ctlr.startSession(c, user, ctlr.sessionMethod(c)); err != nil {
+		return err
+	}
+	c.Set(currentUserKey, user)
+	return nil
+}
 
-	// Authentication failed
-	return c.Render(http.StatusUnauthorized, "login.html", map[string]interface{}{
-		"Error": "Invalid username or password",
-	})
+// sessionUser returns the signed-in user, or nil when the session is missing,
+// the account is gone, or its password changed since the session started
+func (ctlr *RTOController) sessionUser(c echo.Context) *types.User {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		ctlr.logger.Error("Failed to get session", "error", err)
+		return nil
+	}
+	userID, ok := sess.Values[sessionUserKey].(int)
+	if !ok {
+		return nil
+	}
+	user, err := ctlr.service.GetUser(userID)
+	if err != nil {
+		return nil
+	}
+	if version, _ := sess.Values[sessionVersionKey].(int); version != user.SessionVersion {
+		return nil
+	}
+	return user
+}
+
+// currentUser returns the user stored on the context by the auth middleware
+func currentUser(c echo.Context) *types.User {
+	user, _ := c.Get(currentUserKey).(*types.User)
+	return user
+}
+
+// currentUserID returns the ID of the user whose calendar the request works on
+func currentUserID(c echo.Context) int {
+	if user := currentUser(c); user != nil {
+		return int(user.ID)
+	}
+	return 0
 }
 
-// Logout handles user logout
+// Logout handles user logout. It is a POST from a form carrying the CSRF token.
 func (ctlr *RTOController) Logout(c echo.Context) error {
 	sess, err := session.Get("session", c)
 	if err != nil {
 		ctlr.logger.Error("Failed to get session", "error", err)
-		return c.Render(http.StatusInternalServerError, "login.html", map[string]interface{}{
-			"Error": "Internal server error",
-		})
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+	for key := range sess.Values {
+		delete(sess.Values, key)
 	}
-	sess.Values["authenticated"] = false
 	sess.Options.MaxAge = -1 // Delete the session
 	sess.Save(c.Request(), c.Response())
 
 	return c.Redirect(http.StatusSeeOther, "/login")
 }
 
+// SessionKey returns the key for signing session cookies: SESSION_SECRET when
+// set, otherwise a key generated once and kept in the database
+func (ctlr *RTOController) SessionKey() []byte {
+	if secret := config.SessionSecret(); secret != "" {
+		return []byte(secret)
+	}
+	key, err := ctlr.service.SessionKey()
+	if err != nil {
+		ctlr.logger.Error("Failed to load session key", "error", err)
+		panic("Failed to load session key")
+	}
+	return key
+}
+
+// SessionStore returns the store for browser sessions with the given cookie
+// options. Sessions live in the database; controllers built with mocks fall
+// back to keeping them in the signed cookie.
+func (ctlr *RTOController) SessionStore(options *sessions.Options) sessions.Store {
+	if ctlr.sessions != nil {
+		ctlr.sessions.Options = options
+		return ctlr.sessions
+	}
+	store := sessions.NewCookieStore(ctlr.SessionKey())
+	store.Options = options
+	return store
+}
+
+// apiTokenKey is the context key holding the *types.APIToken of a bearer-authenticated request
+const apiTokenKey = "apiToken"
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(c echo.Context) (string, bool) {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(header[7:])
+	return token, token != ""
+}
+
+// authenticateBearer validates a bearer token and stores it and its owner on
+// the context. Failures always answer with a JSON 401 since bearer callers are scripts.
+func (ctlr *RTOController) authenticateBearer(c echo.Context, raw string, next echo.HandlerFunc) error {
+	token, err := ctlr.service.AuthenticateAPIToken(raw)
+	if err != nil {
+		ctlr.logger.Info("bearer authentication failed", "error", err)
+		return apiError(c, http.StatusUnauthorized, "unauthorized", "Invalid or expired API token.")
+	}
+	user, err := ctlr.service.GetUser(int(token.UserID))
+	if err != nil {
+		ctlr.logger.Info("bearer token has no owner", "tokenID", token.ID, "error", err)
+		return apiError(c, http.StatusUnauthorized, "unauthorized", "Invalid or expired API token.")
+	}
+	c.Set(apiTokenKey, token)
+	c.Set(currentUserKey, user)
+	return next(c)
+}
+
+// RequireScope rejects bearer-authenticated requests whose token lacks the scope.
+// Session-authenticated requests are not limited by scopes.
+func (ctlr *RTOController) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get(apiTokenKey).(*types.APIToken)
+			if ok && !token.HasScope(scope) {
+				return apiError(c, http.StatusForbidden, "insufficient_scope", "This token does not grant the '"+scope+"' scope.")
+			}
+			return next(c)
+		}
+	}
+}
+
+// SkipCSRF exempts requests from CSRF checks: bearer-authenticated calls,
+// which do not use the session cookie, and static files
+func (ctlr *RTOController) SkipCSRF(c echo.Context) bool {
+	if _, ok := bearerToken(c); ok {
+		return true
+	}
+	return strings.HasPrefix(c.Request().URL.Path, "/static/")
+}
+
+// CSRFFailed answers a state-changing request that lacks a valid CSRF token
+func (ctlr *RTOController) CSRFFailed(err error, c echo.Context) error {
+	ctlr.logger.Info("CSRF check failed", "path", c.Request().URL.Path, "error", err)
+	return apiError(c, http.StatusForbidden, "csrf_failed", "Missing or invalid CSRF token. Reload the page and try again.")
+}
+
+// SessionOnly rejects bearer-authenticated requests so tokens cannot manage tokens
+func (ctlr *RTOController) SessionOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, ok := c.Get(apiTokenKey).(*types.APIToken); ok {
+			return apiError(c, http.StatusForbidden, "session_required", "This page requires a browser session.")
+		}
+		return next(c)
+	}
+}
+
+// Require limits a route to users whose role grants the permission. Requests
+// made with an API token also need the scope the permission maps to.
+func (ctlr *RTOController) Require(permission string) echo.MiddlewareFunc {
+	requireScope := ctlr.RequireScope(types.PermissionScope(permission))
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		scoped := requireScope(next)
+		return func(c echo.Context) error {
+			if !can(c, permission) {
+				ctlr.logger.Info("permission denied", "userID", currentUserID(c), "permission", permission, "path", c.Path())
+				return apiError(c, http.StatusForbidden, "forbidden", "Your role does not allow this ('"+permission+"').")
+			}
+			return scoped(c)
+		}
+	}
+}
+
+// can reports whether the signed-in user's role grants the permission
+func can(c echo.Context, permission string) bool {
+	user := currentUser(c)
+	return user != nil && user.Can(permission)
+}
+
+// reportUserKey is the context key holding the *types.User whose calendar a
+// manager is reading
+const reportUserKey = "reportUser"
+
+// ForReport serves the calendar of the :id user instead of the signed-in
+// user's, provided they report to the signed-in user (or it is an admin)
+func (ctlr *RTOController) ForReport(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := parseIDParam(c, "id")
+		if err != nil {
+			return apiError(c, http.StatusBadRequest, "invalid_input", err.Error())
+		}
+		user, err := ctlr.service.GetReport(currentUserID(c), id)
+		if err != nil {
+			return ctlr.apiServiceError(c, err)
+		}
+		c.Set(reportUserKey, user)
+		return next(c)
+	}
+}
+
+// calendarUserID returns whose calendar a read-only request works on: the
+// report chosen by ForReport, otherwise the signed-in user
+func calendarUserID(c echo.Context) int {
+	if user, ok := c.Get(reportUserKey).(*types.User); ok {
+		return int(user.ID)
+	}
+	return currentUserID(c)
+}
+
 // AuthMiddleware is middleware to check if user is authenticated
 func (ctlr *RTOController) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		//ctlr.logger.Info("Miiiiiiiiiiidddddddleware", "result")
 
-		sess, err := session.Get("session", c)
-		if err != nil {
-			ctlr.logger.Error("Failed to get session", "error", err)
+		if raw, ok := bearerToken(c); ok {
+			return ctlr.authenticateBearer(c, raw, next)
+		}
+
+		user := ctlr.sessionUser(c)
+		if user == nil {
 			return c.Redirect(http.StatusSeeOther, "/login")
 		}
+		c.Set(currentUserKey, user)
 
-		auth, ok := sess.Values["authenticated"].(bool)
-		if !ok || !auth {
-			ctlr.logger.Info("auth middleware failed", "result", !ok || !auth)
+		if ctlr.mustEnrollTwoFactor(c, user) && !twoFactorEnrollPaths[c.Path()] {
+			return c.Redirect(http.StatusSeeOther, "/account/2fa")
+		}
+		return next(c)
+	}
+}
 
-			return c.Redirect(http.StatusSeeOther, "/login")
+// APIAuthMiddleware checks the bearer token or session like AuthMiddleware but answers with a
+// JSON 401 instead of redirecting to the login form
+func (ctlr *RTOController) APIAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if raw, ok := bearerToken(c); ok {
+			return ctlr.authenticateBearer(c, raw, next)
 		}
 
-		ctlr.logger.Info("++ auth good ++")
+		user := ctlr.sessionUser(c)
+		if user == nil {
+			return apiError(c, http.StatusUnauthorized, "unauthorized", "Authentication required.")
+		}
+		c.Set(currentUserKey, user)
 
+		if ctlr.mustEnrollTwoFactor(c, user) {
+			return apiError(c, http.StatusForbidden, "two_factor_required", "Set up two-factor sign-in at /account/2fa first.")
+		}
 		return next(c)
 	}
 }
@@ -0,0 +1,300 @@
+// controller/auth_test.go
+
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func okHandler(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	token := &types.APIToken{ID: 1, Name: "phone", Scopes: "write:events", UserID: 4}
+	owner := &types.User{ID: 4, Username: "alice"}
+	mockService.On("AuthenticateAPIToken", "rto_good").Return(token, nil)
+	mockService.On("GetUser", 4).Return(owner, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPost, "/toggle-attendance", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer rto_good")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Session lookups are never reached for bearer requests
+	handler := ctlr.AuthMiddleware(ctlr.RequireScope(types.ScopeWriteEvents)(okHandler))
+	if assert.NoError(t, handler(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, token, c.Get(apiTokenKey))
+		assert.Equal(t, 4, currentUserID(c))
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_InvalidBearerToken(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("AuthenticateAPIToken", "rto_revoked").Return(nil, domain.ErrInvalidToken)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer rto_revoked")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.AuthMiddleware(okHandler)(c)) {
+		// Scripts get a 401 rather than a redirect to the login form
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"unauthorized"`)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRequireScope_Denied(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("AuthenticateAPIToken", "rto_reader").Return(&types.APIToken{ID: 2, Scopes: "read", UserID: 4}, nil)
+	mockService.On("GetUser", 4).Return(&types.User{ID: 4, Username: "alice"}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", nil)
+	req.Header.Set(echo.HeaderAuthorization, "bearer rto_reader")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := ctlr.APIAuthMiddleware(ctlr.RequireScope(types.ScopeWriteEvents)(okHandler))
+	if assert.NoError(t, handler(c)) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"insufficient_scope"`)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSessionOnly_RejectsTokens(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	ctlr := NewRTOControllerWithMock("none", new(mocks.RTOBLL), QuarterStart, QuarterEnd)
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(apiTokenKey, &types.APIToken{ID: 3, Scopes: "admin"})
+
+	if assert.NoError(t, ctlr.SessionOnly(okHandler)(c)) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	}
+}
+
+// newSessionEcho serves the login handler and a protected route behind a cookie session store
+func newSessionEcho(ctlr *RTOController) *echo.Echo {
+	e := echo.New()
+	e.Use(session.Middleware(sessions.NewCookieStore([]byte("test-session-key-0123456789abcdef"))))
+	e.POST("/login", ctlr.ProcessLogin)
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, currentUser(c).Username)
+	}, ctlr.AuthMiddleware)
+	return e
+}
+
+func TestProcessLogin_StartsSession(t *testing.T) {
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	user := &types.User{ID: 7, Username: "alice", SessionVersion: 2}
+	mockService.On("Authenticate", "alice", "correct horse").Return(user, nil)
+	mockService.On("GetUser", 7).Return(user, nil)
+	mockService.On("TwoFactorRequired", *user).Return(false)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+	e := newSessionEcho(ctlr)
+
+	form := url.Values{"username": {"alice"}, "password": {"correct horse"}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	cookies := rec.Result().Cookies()
+	assert.NotEmpty(t, cookies)
+
+	// The session cookie opens protected pages as the signed-in user
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_StaleSessionAfterPasswordChange(t *testing.T) {
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	user := types.User{ID: 7, Username: "alice", SessionVersion: 2}
+	mockService.On("Authenticate", "alice", "correct horse").Return(&user, nil)
+
+	// The password changes after sign-in, bumping the session version
+	changed := user
+	changed.SessionVersion = 3
+	mockService.On("GetUser", 7).Return(&changed, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+	e := newSessionEcho(ctlr)
+
+	form := url.Values{"username": {"alice"}, "password": {"correct horse"}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/login", rec.Header().Get(echo.HeaderLocation))
+}
+
+func TestRequire_RoleDenials(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	ctlr := NewRTOControllerWithMock("none", new(mocks.RTOBLL), QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	tests := []struct {
+		role       string
+		permission string
+		want       int
+	}{
+		{types.RoleEmployee, types.PermEditOwn, http.StatusOK},
+		{types.RoleEmployee, types.PermViewReports, http.StatusForbidden},
+		{types.RoleEmployee, types.PermApprove, http.StatusForbidden},
+		{types.RoleEmployee, types.PermManageHolidays, http.StatusForbidden},
+		{types.RoleManager, types.PermViewReports, http.StatusOK},
+		{types.RoleManager, types.PermApprove, http.StatusOK},
+		{types.RoleManager, types.PermManagePeriods, http.StatusForbidden},
+		{types.RoleManager, types.PermManageUsers, http.StatusForbidden},
+		{types.RoleAdmin, types.PermManagePolicies, http.StatusOK},
+		{"", types.PermViewOwn, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.Set(currentUserKey, &types.User{ID: 2, Username: "bob", Role: tt.role})
+
+		if assert.NoError(t, ctlr.Require(tt.permission)(okHandler)(c)) {
+			assert.Equal(t, tt.want, rec.Code, tt.role+" "+tt.permission)
+		}
+	}
+
+	// No signed-in user at all
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(t, ctlr.Require(types.PermViewOwn)(okHandler)(c)) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"forbidden"`)
+	}
+}
+
+func TestRequire_TokenNeedsScope(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	mockService.On("AuthenticateAPIToken", "rto_reader").Return(&types.APIToken{ID: 2, Scopes: "read", UserID: 1}, nil)
+	mockService.On("GetUser", 1).Return(&types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/holidays", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer rto_reader")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// An admin's read-only token cannot do what the admin can
+	handler := ctlr.APIAuthMiddleware(ctlr.Require(types.PermManageHolidays)(okHandler))
+	if assert.NoError(t, handler(c)) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"insufficient_scope"`)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestForReport(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	// Create a mock RTOBLL
+	mockService := new(mocks.RTOBLL)
+	report := &types.User{ID: 5, Username: "carol"}
+	mockService.On("GetReport", 3, 5).Return(report, nil)
+	mockService.On("GetReport", 3, 6).Return(nil, domain.ErrNotFound)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+
+	for id, want := range map[string]int{"5": http.StatusOK, "6": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		c.Set(currentUserKey, &types.User{ID: 3, Username: "mia", Role: types.RoleManager})
+
+		if assert.NoError(t, ctlr.ForReport(okHandler)(c)) {
+			assert.Equal(t, want, rec.Code, id)
+		}
+		if want == http.StatusOK {
+			assert.Equal(t, 5, calendarUserID(c))
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -19,8 +19,8 @@ type ChartResponse struct {
 // GetChartData handles the retrieval of data for the D3 chart
 func (ctlr *RTOController) GetChartData(c echo.Context) error {
 	// Fetch all events from the service
-	events := ctlr.service.GetAllEvents()
-	prefs := ctlr.service.GetPrefs()
+	events := ctlr.service.GetAllEvents(currentUserID(c))
+	prefs := ctlr.service.GetPrefs(currentUserID(c))
 	targetDaysStr := prefs.TargetDays
 	targetDays, err := strconv.ParseFloat(targetDaysStr, 64)
 	if err != nil {
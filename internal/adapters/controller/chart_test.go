@@ -54,8 +54,8 @@ func TestGetChartData_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return(events)
-	mockService.On("GetPrefs").Return(prefs)
+	mockService.On("GetAllEvents", 0).Return(events)
+	mockService.On("GetPrefs", 0).Return(prefs)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -99,8 +99,8 @@ func TestGetChartData_TargetDaysParsingError(t *testing.T) {
 	events := []types.Event{}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return(events)
-	mockService.On("GetPrefs").Return(prefs)
+	mockService.On("GetAllEvents", 0).Return(events)
+	mockService.On("GetPrefs", 0).Return(prefs)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -144,8 +144,8 @@ func TestGetChartData_EmptyEvents(t *testing.T) {
 	events := []types.Event{}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return(events)
-	mockService.On("GetPrefs").Return(prefs)
+	mockService.On("GetAllEvents", 0).Return(events)
+	mockService.On("GetPrefs", 0).Return(prefs)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -1,13 +1,22 @@
 package controller
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/robstave/rto/internal/adapters/mail"
 	repo "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/adapters/scheduler"
+	"github.com/robstave/rto/internal/adapters/sessionstore"
+	"github.com/robstave/rto/internal/adapters/sso"
+	"github.com/robstave/rto/internal/adapters/stream"
+	"github.com/robstave/rto/internal/adapters/webhooks"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/database"
 	"github.com/robstave/rto/internal/domain"
-	"github.com/robstave/rto/internal/domain/types"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -17,9 +26,23 @@ type RTOController struct {
 	logger       *slog.Logger
 	quarterStart time.Time
 	quarterEnd   time.Time
+
+	webhooks *webhooks.Dispatcher
+	stream   *stream.Broker
+
+	sso           *sso.Provider // nil unless single sign-on is configured
+	passwordLogin bool
+
+	sessions *sessionstore.Store // nil in controllers built with mocks
+
+	scheduler *scheduler.Scheduler // nil in controllers built with mocks
 }
 
+// NewRTOController wires the service and adapters around an open, migrated
+// database. It writes no data: cmd/main seeds the database first and calls
+// ClaimUnownedData after, the same steps rto-admin runs.
 func NewRTOController(
+	db *gorm.DB,
 	dbPath string,
 	logger *slog.Logger,
 	quarterStart time.Time,
@@ -27,52 +50,188 @@ func NewRTOController(
 
 ) *RTOController {
 
-	// Read DB_PATH from environment variable, set a default if not provided
-
-	//db, err := gorm.Open(sqlite.Open("rto_attendance.db"), &gorm.Config{})
-	//dbPath = dbPath + "/rto_attendance.db"
-	logger.Info("creating Database", "db", dbPath)
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
-
-	if err != nil {
-		logger.Error("Failed to connect to database", "error", err)
-		panic("Failed to connect to database")
-	}
-
-	// Migrate the schema
-	if err := db.AutoMigrate(&types.Event{}, &types.Preferences{}); err != nil {
-		logger.Error("AutoMigrate failed", "error", err)
-		panic("Failed to migrate database")
-	}
 	// Initialize repositories
 	eventRepo := repo.NewEventRepositorySQLite(db)
 	preferenceRepo := repo.NewPreferenceRepositorySQLite(db)
-
-	// Insert default Preferences if none exist
-	err = initializeDefaultPreferences(db, logger)
-	if err != nil {
-		logger.Error("Failed to initialize default preferences", "error", err)
-		panic("Failed to initialize default preferences")
-	}
-
-	// Initialize holidays
-	err = initializeHolidays(db, logger)
-	if err != nil {
-		logger.Error("Failed to initialize holidays", "error", err)
-		panic("Failed to initialize holidays")
-	}
+	periodRepo := repo.NewPeriodRepositorySQLite(db)
+	tokenRepo := repo.NewTokenRepositorySQLite(db)
+	webhookRepo := repo.NewWebhookRepositorySQLite(db)
+	userRepo := repo.NewUserRepositorySQLite(db)
+	settingRepo := repo.NewSettingRepositorySQLite(db)
+	sessionRepo := repo.NewSessionRepositorySQLite(db)
+	companyRepo := repo.NewCompanyRepositorySQLite(db)
+	officeRepo := repo.NewOfficeRepositorySQLite(db)
+	notificationRepo := repo.NewNotificationRepositorySQLite(db)
 
 	service := domain.NewService(
 		logger,
 		eventRepo,
 		preferenceRepo,
+		periodRepo,
+		tokenRepo,
+		webhookRepo,
+		userRepo,
+		settingRepo,
+		sessionRepo,
+		companyRepo,
+		officeRepo,
+		notificationRepo,
 		quarterStart,
 		quarterEnd,
 	)
 
-	return &RTOController{service, logger, quarterStart, quarterEnd}
+	// Deliver service changes to registered webhooks
+	dispatcher := webhooks.NewDispatcher(webhookRepo, logger)
+	service.Subscribe(dispatcher.HandleChange)
+
+	// Email reminders, when an SMTP server is configured
+	if mailer := newMailer(logger); mailer != nil {
+		service.SetMailer(mailer)
+	}
+
+	ssoProvider := newSSOProvider(logger)
+	passwordLogin := config.PasswordLogin()
+	if !passwordLogin && ssoProvider == nil {
+		logger.Warn("PASSWORD_LOGIN=false ignored: single sign-on is not configured")
+		passwordLogin = true
+	}
+
+	ctlr := &RTOController{service, logger, quarterStart, quarterEnd, dispatcher, stream.NewBroker(logger), ssoProvider, passwordLogin, nil, nil}
+	ctlr.sessions = sessionstore.New(sessionRepo, logger, sessionUserKey, ctlr.SessionKey())
+
+	// Push changes to open browser tabs
+	service.Subscribe(ctlr.publishChange)
+
+	// Recurring upkeep: backups, period rollover, reminders and purging
+	ctlr.scheduler = newScheduler(service, db, dbPath, logger)
+	if config.SchedulerEnabled() {
+		if err := ctlr.scheduler.Start(context.Background()); err != nil {
+			logger.Error("Failed to start the scheduler", "error", err)
+		}
+	} else {
+		logger.Info("Scheduled jobs are off (SCHEDULER=false)")
+	}
+
+	return ctlr
+}
+
+// ClaimUnownedData gives data recorded before accounts existed to the first
+// admin, as rto-admin migrate does. Failures are logged; the server still starts.
+func (ctlr *RTOController) ClaimUnownedData() {
+	if _, err := ctlr.service.ClaimUnownedData(); err != nil {
+		ctlr.logger.Error("Failed to claim unowned data", "error", err)
+	}
 }
 
 func NewRTOControllerWithMock(dbPath string, service domain.RTOBLL, quarterStart time.Time, quarterEnd time.Time) *RTOController {
-	return &RTOController{service, nil, quarterStart, quarterEnd} // Pass a mock logger or nil if not used in tests
+	return &RTOController{service, slog.Default(), quarterStart, quarterEnd, nil, nil, nil, true, nil, nil}
+}
+
+// newScheduler registers the recurring jobs. Each schedule can be changed
+// with JOB_<NAME>; a schedule that does not parse leaves the job off.
+func newScheduler(service domain.RTOBLL, db *gorm.DB, dbPath string, logger *slog.Logger) *scheduler.Scheduler {
+	backupDir, keep := config.BackupDir(dbPath), config.BackupKeep()
+	reminders := "0 8 * * 1-5"
+	if !service.EmailEnabled() {
+		reminders = scheduler.Off
+	}
+
+	jobs := []struct {
+		name, description, spec string
+		run                     scheduler.Func
+	}{
+		{"backup", fmt.Sprintf("Copy the database into %s, keeping the last %d", backupDir, keep), "0 2 * * *",
+			func(ctx context.Context) (string, error) {
+				path, err := database.RotatingBackup(db, backupDir, keep, time.Now())
+				return "wrote " + path, err
+			}},
+		{"rollover", "Close and archive periods that have ended; two weeks before a period ends, set up the next one and fill in everyone's default days", "0 1 * * *",
+			func(ctx context.Context) (string, error) {
+				var done []string
+				closed, err := service.CloseEndedPeriods()
+				for _, period := range closed {
+					done = append(done, "closed "+period.Name)
+				}
+				if err != nil {
+					return strings.Join(done, "; "), err
+				}
+				period, added, err := service.RollOverPeriod()
+				if period == nil {
+					done = append(done, "the current period is not ending yet")
+				} else {
+					done = append(done, fmt.Sprintf("%s: added %d default day(s)", period.Name, added))
+				}
+				return strings.Join(done, "; "), err
+			}},
+		{"reminders", "Email the reminders people opted into (needs SMTP_HOST)", reminders,
+			func(ctx context.Context) (string, error) {
+				sent, err := service.SendReminders()
+				return fmt.Sprintf("sent %d email(s)", sent), err
+			}},
+		{"purge", "Remove expired sessions, and delivery logs and company requests older than 90 days", "30 3 * * *",
+			func(ctx context.Context) (string, error) {
+				purged, err := service.PurgeStaleData()
+				return fmt.Sprintf("removed %d session(s), %d webhook and %d email deliveries, %d company request(s)",
+					purged.Sessions, purged.WebhookDeliveries, purged.EmailDeliveries, purged.CompanyRequests), err
+			}},
+		{"holidays", "Add holidays from " + database.HolidaysFile + " that are not in the calendar yet, outside closed periods", "0 4 * * 1",
+			func(ctx context.Context) (string, error) {
+				added, err := database.SeedHolidays(db, logger, database.HolidaysFile)
+				return fmt.Sprintf("added %d holiday(s)", added), err
+			}},
+	}
+
+	jobScheduler := scheduler.New(repo.NewJobRepositorySQLite(db), logger)
+	for _, job := range jobs {
+		spec := config.JobSchedule(job.name, job.spec)
+		if err := jobScheduler.Add(job.name, job.description, spec, job.run); err != nil {
+			logger.Error("Invalid job schedule; the job is off", "job", job.name, "schedule", spec, "error", err)
+			jobScheduler.Add(job.name, job.description, scheduler.Off, job.run)
+		}
+	}
+	return jobScheduler
+}
+
+// newMailer sets up email reminders from the environment, or returns nil when SMTP_HOST is unset
+func newMailer(logger *slog.Logger) *mail.Mailer {
+	settings := config.SMTPSettings()
+	if settings.Host == "" {
+		return nil
+	}
+	mailer, err := mail.New(mail.Config{
+		Host:     settings.Host,
+		Port:     settings.Port,
+		Username: settings.Username,
+		Password: settings.Password,
+		From:     settings.From,
+	})
+	if err != nil {
+		logger.Error("Failed to load email templates", "error", err)
+		return nil
+	}
+	logger.Info("Email reminders enabled", "smtpHost", settings.Host, "from", settings.From)
+	return mailer
+}
+
+// newSSOProvider sets up OpenID Connect sign-on from the environment, or returns nil when OIDC_ISSUER is unset
+func newSSOProvider(logger *slog.Logger) *sso.Provider {
+	settings := config.OIDCSettings()
+	if settings.IssuerURL == "" {
+		return nil
+	}
+	if settings.RedirectURL == "" {
+		settings.RedirectURL = "http://localhost:8761/auth/oidc/callback"
+		logger.Warn("OIDC_REDIRECT_URL not set; using the local default", "redirectURL", settings.RedirectURL)
+	}
+	logger.Info("Single sign-on enabled", "issuer", settings.IssuerURL)
+	return sso.New(sso.Config{
+		IssuerURL:     settings.IssuerURL,
+		ClientID:      settings.ClientID,
+		ClientSecret:  settings.ClientSecret,
+		RedirectURL:   settings.RedirectURL,
+		Scopes:        settings.Scopes,
+		GroupsClaim:   settings.GroupsClaim,
+		AdminGroups:   settings.AdminGroups,
+		ManagerGroups: settings.ManagerGroups,
+	})
 }
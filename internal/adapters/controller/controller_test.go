@@ -15,6 +15,7 @@ import (
 	"github.com/robstave/rto/internal/domain/mocks"
 	"github.com/robstave/rto/internal/domain/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestEventsList(t *testing.T) {
@@ -50,7 +51,7 @@ func TestEventsList(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return(mockEvents)
+	mockService.On("QueryEvents", 0, types.EventFilter{Limit: 50}).Return(mockEvents, int64(3), nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -76,6 +77,66 @@ func TestEventsList(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestEventsList_FiltersFromQuery(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	mockService := new(mocks.RTOBLL)
+
+	inOffice := false
+	expected := types.EventFilter{
+		Type:     "attendance",
+		From:     time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC),
+		InOffice: &inOffice,
+		Search:   "dentist",
+		Sort:     "-date",
+		Limit:    25,
+		Offset:   50,
+	}
+	mockService.On("QueryEvents", 0, expected).Return([]types.Event{}, int64(120), nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	e.Renderer = &mockRenderer{}
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/events?type=attendance&status=remote&from=2024-10-01&q=dentist&sort=-date&per=25&page=3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.EventsList(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var data map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &data))
+		assert.Equal(t, float64(5), data["Pages"])
+		assert.Contains(t, data["PrevURL"], "page=2")
+		assert.Contains(t, data["NextURL"], "page=4")
+		assert.Contains(t, data["NextURL"], "q=dentist")
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestEventsList_InvalidStatus(t *testing.T) {
+	// Initialize Echo
+	e := echo.New()
+
+	mockService := new(mocks.RTOBLL)
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	e.Renderer = &mockRenderer{}
+
+	req := httptest.NewRequest(http.MethodGet, "/events?status=sometimes", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if assert.NoError(t, ctlr.EventsList(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	mockService.AssertNotCalled(t, "QueryEvents", 0, mock.Anything)
+}
+
 // mockRenderer is a minimal implementation of echo.Renderer for testing purposes
 type mockRenderer struct{}
 
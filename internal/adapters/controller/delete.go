@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"strconv"
 
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
 )
 
 // DeleteEvent handles deletion of a vacation event and transforms it into a remote day
@@ -22,10 +24,14 @@ func (ctlr *RTOController) DeleteEvent(c echo.Context) error {
 	}
 
 	// Call the service to transform the vacation to remote
-	err = ctlr.service.TransformVacationToRemote(eventID)
+	err = ctlr.service.TransformVacationToRemote(currentUserID(c), eventID)
 	if err != nil {
 		ctlr.logger.Error("Error transforming vacation to remote", "eventID", eventID, "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrConflict) {
+			status = http.StatusConflict
+		}
+		return c.JSON(status, map[string]interface{}{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -23,7 +23,7 @@ func TestDeleteEvent_Success(t *testing.T) {
 	mockService := new(mocks.RTOBLL)
 
 	// Mock the service method to return no error
-	mockService.On("TransformVacationToRemote", 1).Return(nil)
+	mockService.On("TransformVacationToRemote", 0, 1).Return(nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -83,7 +83,7 @@ func TestDeleteEvent_ServiceError(t *testing.T) {
 	mockService := new(mocks.RTOBLL)
 
 	// Mock the service method to return an error
-	mockService.On("TransformVacationToRemote", 2).Return(errors.New("event not found"))
+	mockService.On("TransformVacationToRemote", 0, 2).Return(errors.New("event not found"))
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/sessionstore"
+	"github.com/robstave/rto/internal/domain"
+)
+
+// ShowDevices lists the browsers the user is signed in on
+func (ctlr *RTOController) ShowDevices(c echo.Context) error {
+	return ctlr.renderDevices(c, http.StatusOK, map[string]interface{}{})
+}
+
+// RevokeDevice signs one browser out. Revoking the current one signs the user out here too.
+func (ctlr *RTOController) RevokeDevice(c echo.Context) error {
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid session ID.")
+	}
+
+	if err := ctlr.service.RevokeSession(currentUserID(c), sessionID); err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to sign out the device."
+		if errors.Is(err, domain.ErrNotFound) {
+			status, msg = http.StatusNotFound, "That device is no longer signed in."
+		} else {
+			ctlr.logger.Error("Error revoking session", "sessionID", sessionID, "error", err)
+		}
+		return ctlr.renderDevices(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/devices")
+}
+
+// SignOutEverywhere ends every session of the user, including this one
+func (ctlr *RTOController) SignOutEverywhere(c echo.Context) error {
+	if _, err := ctlr.service.RevokeAllSessions(currentUserID(c)); err != nil {
+		return ctlr.renderDevices(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to sign out everywhere.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/login")
+}
+
+func (ctlr *RTOController) renderDevices(c echo.Context, status int, data map[string]interface{}) error {
+	devices, err := ctlr.service.GetSessions(currentUserID(c))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load devices.")
+	}
+	data["Devices"] = devices
+	data["Current"] = ctlr.currentSessionHash(c)
+	return c.Render(status, "devices.html", data)
+}
+
+// currentSessionHash identifies this browser's row among the user's sessions
+func (ctlr *RTOController) currentSessionHash(c echo.Context) string {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return ""
+	}
+	return sessionstore.Hash(sess.ID)
+}
@@ -1,28 +1,96 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
 	"github.com/robstave/rto/internal/domain/types"
 )
 
-// EventsList handles displaying the list of events
+// Page sizes offered on the events list
+var eventPageSizes = []int{25, 50, 100, 200}
+
+const defaultEventPageSize = 50
+
+// EventsList handles displaying the list of events. Filters, sort and page
+// come from the query string so a filtered view can be bookmarked.
 func (ctlr *RTOController) EventsList(c echo.Context) error {
-	// Pass allEvents to the template
+	query := c.QueryParams()
 	data := map[string]interface{}{
-		"Events": ctlr.service.GetAllEvents(),
+		"Query":     query,
+		"PageSizes": eventPageSizes,
+		"SortBy":    query.Get("sort"),
+	}
+
+	filter, err := parseEventCriteria(c)
+	if err != nil {
+		data["ErrorMessage"] = err.Error()
+		return c.Render(http.StatusBadRequest, "events.html", data)
+	}
+
+	perPage := defaultEventPageSize
+	if n, err := strconv.Atoi(query.Get("per")); err == nil && slices.Contains(eventPageSizes, n) {
+		perPage = n
+	}
+	page := 1
+	if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 1 {
+		page = n
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	events, total, err := ctlr.service.QueryEvents(currentUserID(c), filter)
+	if err != nil {
+		ctlr.logger.Error("Error querying events", "error", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidInput) {
+			status = http.StatusBadRequest
+		}
+		data["ErrorMessage"] = err.Error()
+		return c.Render(status, "events.html", data)
+	}
+
+	pages := int((total + int64(perPage) - 1) / int64(perPage))
+	data["Events"] = events
+	data["Total"] = total
+	data["Page"] = page
+	data["Pages"] = pages
+	data["PerPage"] = perPage
+	if page > 1 {
+		data["PrevURL"] = eventsPageURL(query, page-1)
+	}
+	if page < pages {
+		data["NextURL"] = eventsPageURL(query, page+1)
 	}
 
 	return c.Render(http.StatusOK, "events.html", data)
 }
 
+// eventsPageURL returns the events list URL for another page of the same query
+func eventsPageURL(query url.Values, page int) string {
+	q := url.Values{}
+	for k, v := range query {
+		if len(v) > 0 && v[0] != "" {
+			q.Set(k, v[0])
+		}
+	}
+	q.Set("page", strconv.Itoa(page))
+	return "/events?" + q.Encode()
+}
+
 // ShowAddEventForm renders the Add Event form
 func (ctlr *RTOController) ShowAddEventForm(c echo.Context) error {
-	return c.Render(http.StatusOK, "add_event.html", nil)
+	return c.Render(http.StatusOK, "add_event.html", map[string]interface{}{
+		"CanManageHolidays": can(c, types.PermManageHolidays),
+	})
 }
 
 func (ctlr *RTOController) AddEvent(c echo.Context) error {
@@ -55,6 +123,12 @@ func (ctlr *RTOController) AddEvent(c echo.Context) error {
 		Description: description,
 		Type:        eventType,
 	}
+	if holidayDenied(c, newEvent) {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{
+			"success": false,
+			"message": "Only admins can add shared holidays",
+		})
+	}
 
 	// Handle Attendance Type
 	if eventType == "attendance" {
@@ -66,7 +140,13 @@ func (ctlr *RTOController) AddEvent(c echo.Context) error {
 	}
 
 	// Call domain service to add event
-	err = ctlr.service.AddEvent(newEvent)
+	err = ctlr.service.AddEvent(currentUserID(c), newEvent)
+	if errors.Is(err, domain.ErrConflict) {
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
 	if err != nil {
 		ctlr.logger.Error("Error adding event", "error", err)
 		// Check if the error is due to an existing attendance event
@@ -81,7 +161,7 @@ func (ctlr *RTOController) AddEvent(c echo.Context) error {
 	var message string
 	if eventType == "vacation" {
 		// Check if the description was updated or a new event was added
-		existingEvent, err := ctlr.service.GetEventByDateAndType(eventDate, "vacation")
+		existingEvent, err := ctlr.service.GetEventByDateAndType(currentUserID(c), eventDate, "vacation")
 		if err == nil && existingEvent.ID != 0 && existingEvent.Description == description {
 			message = "Vacation event updated successfully."
 		} else {
@@ -101,7 +181,7 @@ func (ctlr *RTOController) AddEvent(c echo.Context) error {
 }
 
 func (ctlr *RTOController) AddDefaultDays(c echo.Context) error {
-	err := ctlr.service.AddDefaultDays()
+	err := ctlr.service.AddDefaultDays(currentUserID(c))
 	if err != nil {
 		ctlr.logger.Error("Error adding default days", "error", err)
 		return c.String(http.StatusInternalServerError, "Failed to add default attendance events.")
@@ -178,7 +258,7 @@ func (ctlr *RTOController) BulkAddEventsJSON(c echo.Context) error {
 	}
 
 	// Delegate the processing to the service layer
-	response, err := ctlr.service.BulkAddEvents(domainEvents)
+	response, err := ctlr.service.BulkAddEvents(currentUserID(c), domainEvents)
 	if err != nil {
 		ctlr.logger.Error("Error in BulkAddEvents service method", "error", err)
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -213,7 +293,13 @@ func (ctlr *RTOController) ClearEventsForDate(c echo.Context) error {
 	}
 
 	// Retrieve all events for the date
-	err = ctlr.service.ClearEventsForDate(eventDate)
+	err = ctlr.service.ClearEventsForDate(currentUserID(c), eventDate)
+	if errors.Is(err, domain.ErrConflict) {
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+	}
 	if err != nil {
 		ctlr.logger.Error("Error fetching events for date", "date", eventDate, "error", err)
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -37,8 +37,8 @@ func TestAddEvent_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("AddEvent", event).Return(nil)
-	mockService.On("GetEventByDateAndType", event.Date, "vacation").Return(&event, nil)
+	mockService.On("AddEvent", 0, event).Return(nil)
+	mockService.On("GetEventByDateAndType", 0, event.Date, "vacation").Return(&event, nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -119,7 +119,7 @@ func TestAddEvent_InvalidDateFormat(t *testing.T) {
 	}
 
 	// Ensure that the service was not called
-	mockService.AssertNotCalled(t, "AddEvent", mock.Anything)
+	mockService.AssertNotCalled(t, "AddEvent", 0, mock.Anything)
 }
 
 func TestAddEvent_ServiceError(t *testing.T) {
@@ -138,7 +138,7 @@ func TestAddEvent_ServiceError(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("AddEvent", event).Return(errors.New("database error"))
+	mockService.On("AddEvent", 0, event).Return(errors.New("database error"))
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -215,7 +215,7 @@ func TestBulkAddEventsJSON_Success(t *testing.T) {
 			Type:        "vacation",
 		},
 	}
-	mockService.On("BulkAddEvents", events).Return(&bulkAddResponse, nil)
+	mockService.On("BulkAddEvents", 0, events).Return(&bulkAddResponse, nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -286,7 +286,7 @@ func TestBulkAddEventsJSON_InvalidJSON(t *testing.T) {
 	}
 
 	// Ensure that the service was not called
-	mockService.AssertNotCalled(t, "BulkAddEvents", mock.Anything)
+	mockService.AssertNotCalled(t, "BulkAddEvents", 0, mock.Anything)
 }
 
 func TestBulkAddEventsJSON_InvalidEventType(t *testing.T) {
@@ -325,7 +325,7 @@ func TestBulkAddEventsJSON_InvalidEventType(t *testing.T) {
 	}
 
 	// Ensure that the service was not called
-	mockService.AssertNotCalled(t, "BulkAddEvents", mock.Anything)
+	mockService.AssertNotCalled(t, "BulkAddEvents", 0, mock.Anything)
 }
 
 func TestBulkAddEventsJSON_InvalidDateFormat(t *testing.T) {
@@ -364,7 +364,7 @@ func TestBulkAddEventsJSON_InvalidDateFormat(t *testing.T) {
 	}
 
 	// Ensure that the service was not called
-	mockService.AssertNotCalled(t, "BulkAddEvents", mock.Anything)
+	mockService.AssertNotCalled(t, "BulkAddEvents", 0, mock.Anything)
 }
 
 func TestBulkAddEventsJSON_ServiceError(t *testing.T) {
@@ -375,7 +375,7 @@ func TestBulkAddEventsJSON_ServiceError(t *testing.T) {
 	mockService := new(mocks.RTOBLL)
 
 	// Define mock bulk add response with an error
-	mockService.On("BulkAddEvents", mock.Anything).Return(nil, errors.New("service error"))
+	mockService.On("BulkAddEvents", 0, mock.Anything).Return(nil, errors.New("service error"))
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
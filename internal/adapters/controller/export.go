@@ -14,7 +14,7 @@ import (
 // ExportEventsMarkdown handles exporting all events as a Markdown list
 func (ctlr *RTOController) ExportEventsMarkdown(c echo.Context) error {
 	// Fetch all events from the service
-	events := ctlr.service.GetAllEvents()
+	events := ctlr.service.GetAllEvents(currentUserID(c))
 
 	if len(events) == 0 {
 		return c.String(http.StatusOK, "No events available to export.")
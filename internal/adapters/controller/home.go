@@ -16,7 +16,7 @@ import (
 // Home renders the calendar on the home page
 func (ctlr *RTOController) Home(c echo.Context) error {
 
-	allEvents := ctlr.service.GetAllEvents()
+	allEvents := ctlr.service.GetAllEvents(currentUserID(c))
 	// Get current date or date from query parameters
 	currentDate := time.Now()
 	yearParam := c.QueryParam("year")
@@ -41,51 +41,23 @@ func (ctlr *RTOController) Home(c echo.Context) error {
 	prevMonthDate := currentDate.AddDate(0, -1, 0)
 	nextMonthDate := currentDate.AddDate(0, 1, 0)
 
-	// Define 'today' before the loop
-	today := time.Now()
-	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
 	log.Println("21")
-	// Assign events to the corresponding days
-	for weekIdx, week := range weeks {
-		for dayIdx, day := range week {
-			dateStr := day.Date.Format("2006-01-02") // YYYY-MM-DD
-			dayEvents := []types.Event{}
-
-			for _, event := range allEvents {
-				if event.Date.Format("2006-01-02") == dateStr {
-					dayEvents = append(dayEvents, event)
-				}
-			}
-
-			weeks[weekIdx][dayIdx].Events = dayEvents
-
-			if day.Date.Equal(today) {
-				weeks[weekIdx][dayIdx].Today = true
-			} else if day.Date.After(today) && !weeks[weekIdx][dayIdx].IsWeekend {
-				// Set IsFuture flag..but only for M-F
-				weeks[weekIdx][dayIdx].IsFuture = true
-			}
-
-		}
-	}
+	fillCalendar(weeks, allEvents)
 	log.Println("31")
-	currentYear := time.Now().Year()
-	startDate := time.Date(currentYear, time.October, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(currentYear, time.December, 31, 0, 0, 0, 0, time.UTC)
-	// Calculate In-Office Average
-	inOfficeCount, totalDays := utils.CalculateInOfficeAverage(allEvents, startDate, endDate)
-
-	average := 0.0
-	averageDays := 0.0
-	if totalDays > 0 {
-		average = (float64(inOfficeCount) / float64(totalDays)) * 100
-		averageDays = (float64(inOfficeCount) / float64(totalDays)) * 7 //average days/week
+	// The same stats /chart-data, the API and the stream report, so the page
+	// does not change its numbers on the first push
+	log.Println("41")
+	stats, err := ctlr.service.CalculateAttendanceStats(currentUserID(c))
+	if err != nil {
+		ctlr.logger.Error("Error calculating stats", "error", err)
+		return c.String(http.StatusInternalServerError, "Internal Server Error")
 	}
 
-	// Fetch target days from preferences
-	log.Println("41")
-	currentPreferences := ctlr.service.GetPrefs()
-	targetDaysFloat, _ := strconv.ParseFloat(currentPreferences.TargetDays, 64)
+	// Names for the offices in-office days were spent at
+	offices, err := ctlr.service.GetOffices()
+	if err != nil {
+		ctlr.logger.Error("Error loading offices", "error", err)
+	}
 
 	data := map[string]interface{}{
 		"CurrentDate": currentDate,
@@ -100,12 +72,14 @@ func (ctlr *RTOController) Home(c echo.Context) error {
 			"month": nextMonthDate.Format("01"),
 			"day":   nextMonthDate.Format("02"),
 		},
-		"InOfficeCount": inOfficeCount,
-		"TotalDays":     totalDays,
-		"Average":       average,
-		"AverageDays":   averageDays,
-		"TargetDays":    targetDaysFloat,
-		"Preferences":   currentPreferences, // Add Preferences here
+		"InOfficeCount": stats.InOfficeCount,
+		"TotalDays":     stats.TotalDays,
+		"Average":       stats.Average,
+		"AverageDays":   stats.AverageDays,
+		"TargetDays":    stats.TargetDays,
+		"DaysPerWeek":   types.DaysPerWeek(stats.Method),
+		"CanViewTeam":   can(c, types.PermViewReports),
+		"OfficeNames":   officeNames(offices),
 	}
 
 	log.Println("r1")
@@ -118,3 +92,31 @@ func (ctlr *RTOController) Home(c echo.Context) error {
 
 	return nil
 }
+
+// fillCalendar assigns events to the days of the month grid and marks today
+// and the working days still to come
+func fillCalendar(weeks [][]types.CalendarDay, allEvents []types.Event) {
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for weekIdx, week := range weeks {
+		for dayIdx, day := range week {
+			dateStr := day.Date.Format("2006-01-02") // YYYY-MM-DD
+			dayEvents := []types.Event{}
+
+			for _, event := range allEvents {
+				if event.Date.Format("2006-01-02") == dateStr {
+					dayEvents = append(dayEvents, event)
+				}
+			}
+
+			weeks[weekIdx][dayIdx].Events = dayEvents
+
+			if day.Date.Equal(today) {
+				weeks[weekIdx][dayIdx].Today = true
+			} else if day.Date.After(today) && !weeks[weekIdx][dayIdx].IsWeekend {
+				// Set IsFuture flag..but only for M-F
+				weeks[weekIdx][dayIdx].IsFuture = true
+			}
+		}
+	}
+}
@@ -52,9 +52,9 @@ func TestHome_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return(mockEvents)
-	mockService.On("GetPrefs").Return(prefs)
-	//mockService.On("CalculateAttendanceStats").Return(attendanceStats, nil)
+	mockService.On("GetAllEvents", 0).Return(mockEvents)
+	mockService.On("GetOffices").Return([]types.Office{{ID: 1, Name: "HQ"}}, nil)
+	mockService.On("CalculateAttendanceStats", 0).Return(attendanceStats, nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -94,11 +94,6 @@ func TestHome_NoEvents(t *testing.T) {
 	// Create a mock RTOBLL
 	mockService := new(mocks.RTOBLL)
 
-	// Define mock preferences
-	prefs := types.Preferences{
-		TargetDays: "2.5",
-	}
-
 	// Define mock attendance stats
 	attendanceStats := &types.AttendanceStats{
 		InOfficeCount:  0,
@@ -110,8 +105,9 @@ func TestHome_NoEvents(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("GetAllEvents").Return([]types.Event{})
-	mockService.On("GetPrefs").Return(prefs)
+	mockService.On("GetAllEvents", 0).Return([]types.Event{})
+	mockService.On("GetOffices").Return([]types.Office{{ID: 1, Name: "HQ"}}, nil)
+	mockService.On("CalculateAttendanceStats", 0).Return(attendanceStats, nil)
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
@@ -141,3 +137,48 @@ func TestHome_NoEvents(t *testing.T) {
 	// Verify that the expectations were met
 	mockService.AssertExpectations(t)
 }
+
+// A workdays version that took effect mid-period is what the page judges the
+// period by, not the calendar target saved before it
+func TestHome_MidPeriodWorkdaysVersion(t *testing.T) {
+	e := echo.New()
+	mockService := new(mocks.RTOBLL)
+
+	// 30 in-office days out of 60 workdays is 2.5 days a week of 5
+	attendanceStats := &types.AttendanceStats{
+		InOfficeCount:  30,
+		TotalDays:      60,
+		Average:        50.0,
+		AverageDays:    2.5,
+		TargetDays:     3,
+		AveragePercent: 83.3,
+		Method:         types.MethodWorkdays,
+	}
+
+	mockService.On("GetAllEvents", 0).Return([]types.Event{})
+	mockService.On("GetOffices").Return([]types.Office{{ID: 1, Name: "HQ"}}, nil)
+	mockService.On("CalculateAttendanceStats", 0).Return(attendanceStats, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	e.Renderer = &mockRenderer{}
+
+	if assert.NoError(t, ctlr.Home(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 60.0, response["TotalDays"])
+		assert.Equal(t, 2.5, response["AverageDays"])
+		assert.Equal(t, 3.0, response["TargetDays"])
+		assert.Equal(t, 5.0, response["DaysPerWeek"])
+	}
+
+	// Today's preferences are not consulted
+	mockService.AssertNotCalled(t, "GetPrefs", 0)
+	mockService.AssertExpectations(t)
+}
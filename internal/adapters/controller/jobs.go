@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/scheduler"
+	"github.com/robstave/rto/internal/config"
+)
+
+// ShowJobs renders the scheduled jobs with when they last and next run
+func (ctlr *RTOController) ShowJobs(c echo.Context) error {
+	return ctlr.renderJobs(c, http.StatusOK, map[string]interface{}{})
+}
+
+// RunJob starts a scheduled job now
+func (ctlr *RTOController) RunJob(c echo.Context) error {
+	if ctlr.scheduler == nil {
+		return c.String(http.StatusServiceUnavailable, "Scheduled jobs are not available.")
+	}
+	name := c.Param("name")
+	if err := ctlr.scheduler.RunNow(name); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, scheduler.ErrUnknownJob):
+			status = http.StatusNotFound
+		case errors.Is(err, scheduler.ErrRunning):
+			status = http.StatusConflict
+		default:
+			ctlr.logger.Error("Error starting job", "job", name, "error", err)
+		}
+		return ctlr.renderJobs(c, status, map[string]interface{}{
+			"ErrorMessage": "Could not start " + name + ": " + err.Error(),
+		})
+	}
+	return ctlr.renderJobs(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Started " + name + ". Reload to see how it went.",
+	})
+}
+
+func (ctlr *RTOController) renderJobs(c echo.Context, status int, data map[string]interface{}) error {
+	if ctlr.scheduler == nil {
+		return c.String(http.StatusServiceUnavailable, "Scheduled jobs are not available.")
+	}
+	jobs, err := ctlr.scheduler.Status()
+	if err != nil {
+		ctlr.logger.Error("Error listing jobs", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load jobs.")
+	}
+	data["Jobs"] = jobs
+	data["Off"] = scheduler.Off
+	data["SchedulerEnabled"] = config.SchedulerEnabled()
+	return c.Render(status, "jobs.html", data)
+}
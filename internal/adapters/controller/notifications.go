@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// recentEmails is how many sent reminders the notifications page lists
+const recentEmails = 20
+
+// ShowNotifications renders the email reminder settings and delivery log
+func (ctlr *RTOController) ShowNotifications(c echo.Context) error {
+	return ctlr.renderNotifications(c, http.StatusOK, map[string]interface{}{})
+}
+
+// UpdateNotifications handles the reminder settings form
+func (ctlr *RTOController) UpdateNotifications(c echo.Context) error {
+	behindDays, err := strconv.ParseFloat(c.FormValue("behindDays"), 64)
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid behind threshold.")
+	}
+	vacationLead, err := strconv.Atoi(c.FormValue("vacationLead"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid vacation notice.")
+	}
+	ptoDays, err := strconv.Atoi(c.FormValue("ptoDays"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid vacation allowance.")
+	}
+
+	settings := types.NotificationSettings{
+		Behind:       c.FormValue("behind") == "on",
+		BehindDays:   behindDays,
+		Unlogged:     c.FormValue("unlogged") == "on",
+		Vacation:     c.FormValue("vacation") == "on",
+		VacationLead: vacationLead,
+		PTODays:      ptoDays,
+	}
+	if err := ctlr.service.UpdateNotificationSettings(currentUserID(c), settings); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			return ctlr.renderNotifications(c, http.StatusBadRequest, map[string]interface{}{
+				"ErrorMessage": err.Error(),
+			})
+		}
+		ctlr.logger.Error("Error updating notification settings", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to update reminders.")
+	}
+	return ctlr.renderNotifications(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Reminder settings saved.",
+	})
+}
+
+// SendTestEmail sends the user a test message
+func (ctlr *RTOController) SendTestEmail(c echo.Context) error {
+	if err := ctlr.service.SendTestEmail(currentUserID(c)); err != nil {
+		ctlr.logger.Warn("Test email failed", "error", err)
+		return ctlr.renderNotifications(c, http.StatusOK, map[string]interface{}{
+			"ErrorMessage": "The test email could not be sent: " + err.Error(),
+		})
+	}
+	return ctlr.renderNotifications(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Test email sent.",
+	})
+}
+
+func (ctlr *RTOController) renderNotifications(c echo.Context, status int, data map[string]interface{}) error {
+	userID := currentUserID(c)
+	settings, err := ctlr.service.GetNotificationSettings(userID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load reminders.")
+	}
+	deliveries, err := ctlr.service.GetEmailDeliveries(userID, recentEmails)
+	if err != nil {
+		ctlr.logger.Error("Error listing email deliveries", "error", err)
+	}
+	if user, err := ctlr.service.GetUser(userID); err == nil {
+		data["Email"] = user.Email
+	}
+
+	data["Settings"] = settings
+	data["Deliveries"] = deliveries
+	data["EmailEnabled"] = ctlr.service.EmailEnabled()
+	return c.Render(status, "notifications.html", data)
+}
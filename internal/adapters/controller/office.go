@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// dateQuery reads ?date=YYYY-MM-DD, defaulting to today
+func dateQuery(c echo.Context) time.Time {
+	if date, err := time.Parse("2006-01-02", c.QueryParam("date")); err == nil {
+		return date
+	}
+	return utils.NormalizeDate(time.Now())
+}
+
+// ShowOffice renders who is in the office on a day, with suggested days to come in
+func (ctlr *RTOController) ShowOffice(c echo.Context) error {
+	return ctlr.renderOffice(c, http.StatusOK, dateQuery(c), map[string]interface{}{})
+}
+
+// SetCompany marks or clears "I'd like company" from the day view
+func (ctlr *RTOController) SetCompany(c echo.Context) error {
+	date, err := time.Parse("2006-01-02", c.FormValue("date"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid date.")
+	}
+
+	if err := ctlr.service.SetCompanyRequest(currentUserID(c), date, c.FormValue("want") == "true"); err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to update your request for company."
+		if errors.Is(err, domain.ErrInvalidInput) {
+			status, msg = http.StatusBadRequest, err.Error()
+		}
+		return ctlr.renderOffice(c, status, date, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/office?date="+date.Format("2006-01-02"))
+}
+
+func (ctlr *RTOController) renderOffice(c echo.Context, status int, date time.Time, data map[string]interface{}) error {
+	userID := currentUserID(c)
+	day, err := ctlr.service.WhoIsIn(userID, date)
+	if err != nil {
+		ctlr.logger.Error("Error loading who is in", "userID", userID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load who is in.")
+	}
+	suggestions, err := ctlr.service.SuggestOfficeDays(userID, time.Now())
+	if err != nil {
+		ctlr.logger.Error("Error suggesting office days", "userID", userID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load suggested days.")
+	}
+
+	data["Day"] = day
+	data["PrevDay"] = date.AddDate(0, 0, -1).Format("2006-01-02")
+	data["NextDay"] = date.AddDate(0, 0, 1).Format("2006-01-02")
+	data["CanAsk"] = !date.Before(utils.NormalizeDate(time.Now())) && !utils.IsWeekend(date)
+	data["Suggestions"] = suggestions
+	return c.Render(status, "office.html", data)
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// bookingWindow is how far ahead the offices page lists the user's bookings
+const bookingWindow = 14
+
+// officeError picks the status and message shown for a failed office or booking change
+func (ctlr *RTOController) officeError(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, err.Error()
+	}
+	ctlr.logger.Error(fallback, "error", err)
+	return http.StatusInternalServerError, fallback
+}
+
+// officeNames maps office IDs to names
+func officeNames(offices []types.Office) map[uint]string {
+	names := make(map[uint]string)
+	for _, office := range offices {
+		names[office.ID] = office.Name
+	}
+	return names
+}
+
+// ShowOffices lists the offices and the signed-in user's upcoming bookings
+func (ctlr *RTOController) ShowOffices(c echo.Context) error {
+	return ctlr.renderOffices(c, http.StatusOK, map[string]interface{}{})
+}
+
+// CreateOffice adds an office (admins only)
+func (ctlr *RTOController) CreateOffice(c echo.Context) error {
+	capacity, _ := strconv.Atoi(c.FormValue("capacity"))
+	if _, err := ctlr.service.CreateOffice(c.FormValue("name"), capacity); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to create the office.")
+		return ctlr.renderOffices(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/offices")
+}
+
+// UpdateOffice changes an office's daily capacity (admins only)
+func (ctlr *RTOController) UpdateOffice(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	capacity, err := strconv.Atoi(c.FormValue("capacity"))
+	if err != nil {
+		return ctlr.renderOffices(c, http.StatusBadRequest, map[string]interface{}{"ErrorMessage": "Capacity must be a number."})
+	}
+	if err := ctlr.service.SetOfficeCapacity(officeID, capacity); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to update the office.")
+		return ctlr.renderOffices(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/offices")
+}
+
+// DeleteOffice removes an office with its desks, rooms and bookings (admins only)
+func (ctlr *RTOController) DeleteOffice(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	if err := ctlr.service.DeleteOffice(officeID); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to delete the office.")
+		return ctlr.renderOffices(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/offices")
+}
+
+func (ctlr *RTOController) renderOffices(c echo.Context, status int, data map[string]interface{}) error {
+	userID := currentUserID(c)
+	offices, err := ctlr.service.GetOffices()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load offices.")
+	}
+	today := utils.NormalizeDate(time.Now())
+	bookings, err := ctlr.service.GetUserBookings(userID, today, today.AddDate(0, 0, bookingWindow))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load your bookings.")
+	}
+
+	data["Offices"] = offices
+	data["OfficeNames"] = officeNames(offices)
+	data["Bookings"] = bookings
+	data["CanManage"] = can(c, types.PermManageOffices)
+	data["CanViewOccupancy"] = can(c, types.PermViewOccupancy)
+	return c.Render(status, "offices.html", data)
+}
+
+// ShowOfficeBookings renders an office's booking calendar for ?month=YYYY-MM
+func (ctlr *RTOController) ShowOfficeBookings(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	return ctlr.renderOfficeBookings(c, http.StatusOK, officeID, map[string]interface{}{})
+}
+
+// BookResource books a desk or room from the booking calendar
+func (ctlr *RTOController) BookResource(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	date, err := time.Parse("2006-01-02", c.FormValue("date"))
+	if err != nil {
+		return ctlr.renderOfficeBookings(c, http.StatusBadRequest, officeID, map[string]interface{}{"ErrorMessage": "Invalid date."})
+	}
+	resourceID, _ := strconv.Atoi(c.FormValue("resource"))
+
+	if c.FormValue("kind") == types.ResourceRoom {
+		_, err = ctlr.service.BookRoom(currentUserID(c), officeID, resourceID, date)
+	} else {
+		_, err = ctlr.service.BookDesk(currentUserID(c), officeID, resourceID, date)
+	}
+	if err != nil {
+		status, msg := ctlr.officeError(err, "Failed to make the booking.")
+		return ctlr.renderOfficeBookings(c, status, officeID, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/offices/%d?month=%s", officeID, date.Format("2006-01")))
+}
+
+// CancelBooking releases one of the signed-in user's bookings and goes back
+// to the page it was cancelled from
+func (ctlr *RTOController) CancelBooking(c echo.Context) error {
+	bookingID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid booking ID.")
+	}
+	officeID, _ := strconv.Atoi(c.FormValue("office"))
+
+	if err := ctlr.service.CancelBooking(currentUserID(c), bookingID); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to cancel the booking.")
+		if officeID > 0 {
+			return ctlr.renderOfficeBookings(c, status, officeID, map[string]interface{}{"ErrorMessage": msg})
+		}
+		return ctlr.renderOffices(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	if officeID > 0 {
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/offices/%d?month=%s", officeID, monthParam(c).Format("2006-01")))
+	}
+	return c.Redirect(http.StatusSeeOther, "/offices")
+}
+
+// AddResource adds a desk or room to an office (admins only)
+func (ctlr *RTOController) AddResource(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	if _, err := ctlr.service.AddResource(officeID, c.FormValue("name"), c.FormValue("kind")); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to add the desk or room.")
+		return ctlr.renderOfficeBookings(c, status, officeID, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/offices/%d", officeID))
+}
+
+// DeleteResource removes a desk or room from an office (admins only)
+func (ctlr *RTOController) DeleteResource(c echo.Context) error {
+	officeID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office ID.")
+	}
+	resourceID, err := parseIDParam(c, "resourceId")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid resource ID.")
+	}
+	if err := ctlr.service.DeleteResource(officeID, resourceID); err != nil {
+		status, msg := ctlr.officeError(err, "Failed to remove the desk or room.")
+		return ctlr.renderOfficeBookings(c, status, officeID, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/offices/%d", officeID))
+}
+
+func (ctlr *RTOController) renderOfficeBookings(c echo.Context, status int, officeID int, data map[string]interface{}) error {
+	month := monthParam(c)
+	calendar, err := ctlr.service.OfficeCalendar(officeID, month)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return c.String(http.StatusNotFound, "No such office.")
+		}
+		ctlr.logger.Error("Error loading office calendar", "officeID", officeID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load the booking calendar.")
+	}
+
+	resourceNames := make(map[uint]string)
+	for _, resource := range calendar.Resources {
+		resourceNames[resource.ID] = resource.Name
+	}
+	data["Calendar"] = calendar
+	data["ResourceNames"] = resourceNames
+	data["Month"] = month
+	data["PrevMonth"] = month.AddDate(0, -1, 0).Format("2006-01")
+	data["NextMonth"] = month.AddDate(0, 1, 0).Format("2006-01")
+	data["Today"] = utils.NormalizeDate(time.Now())
+	data["UserID"] = uint(currentUserID(c))
+	data["CanManage"] = can(c, types.PermManageOffices)
+	return c.Render(status, "office_bookings.html", data)
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// periodRow is a period with the user's stats for it: archived once it is
+// closed, otherwise worked out from the days so far
+type periodRow struct {
+	Period   types.Period
+	Stats    *types.AttendanceStats
+	Weeks    []types.WeekStats
+	Archived bool
+}
+
+// ShowPeriods lists the periods with the user's results in each
+func (ctlr *RTOController) ShowPeriods(c echo.Context) error {
+	return ctlr.renderPeriods(c, http.StatusOK, map[string]interface{}{})
+}
+
+// ClosePeriod archives an ended period and locks its days
+func (ctlr *RTOController) ClosePeriod(c echo.Context) error {
+	return ctlr.changePeriod(c, ctlr.service.ClosePeriod, "Closed %s and archived everyone's stats.")
+}
+
+// ReopenPeriod unlocks a closed period
+func (ctlr *RTOController) ReopenPeriod(c echo.Context) error {
+	return ctlr.changePeriod(c, ctlr.service.ReopenPeriod, "Reopened %s; its days can be edited again.")
+}
+
+func (ctlr *RTOController) changePeriod(c echo.Context, change func(int) (*types.Period, error), done string) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid period ID.")
+	}
+	period, err := change(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrConflict):
+			status = http.StatusConflict
+		default:
+			ctlr.logger.Error("Error changing period", "periodID", id, "error", err)
+		}
+		return ctlr.renderPeriods(c, status, map[string]interface{}{"ErrorMessage": err.Error()})
+	}
+	return ctlr.renderPeriods(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": fmt.Sprintf(done, period.Name),
+	})
+}
+
+func (ctlr *RTOController) renderPeriods(c echo.Context, status int, data map[string]interface{}) error {
+	userID := currentUserID(c)
+	periods, err := ctlr.service.GetPeriods()
+	if err != nil {
+		ctlr.logger.Error("Error listing periods", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load periods.")
+	}
+
+	today := utils.NormalizeDate(time.Now())
+	var rows []periodRow
+	// Newest first, as people mostly look back at the last one or two
+	for i := len(periods) - 1; i >= 0; i-- {
+		row := periodRow{Period: periods[i]}
+		switch {
+		case row.Period.Closed():
+			archive, err := ctlr.service.GetPeriodArchive(userID, int(row.Period.ID))
+			if err == nil {
+				stats := archive.Stats()
+				row.Stats, row.Weeks, row.Archived = &stats, archive.Weeks, true
+			} else if !errors.Is(err, domain.ErrNotFound) {
+				ctlr.logger.Error("Error fetching period archive", "periodID", row.Period.ID, "error", err)
+			}
+		case !row.Period.StartDate.After(today):
+			if row.Stats, err = ctlr.service.PeriodStats(userID, int(row.Period.ID)); err != nil {
+				ctlr.logger.Error("Error calculating period stats", "periodID", row.Period.ID, "error", err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	data["Periods"] = rows
+	data["Today"] = today
+	data["CanManage"] = can(c, types.PermManagePeriods)
+	return c.Render(status, "periods.html", data)
+}
@@ -1,27 +1,77 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
-
-	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
-
+	"github.com/robstave/rto/internal/domain"
 	"github.com/robstave/rto/internal/domain/types"
-
-	"gorm.io/gorm"
 )
 
-// ShowPrefs renders the preferences page with current default in-office days and target
+// ShowPrefs renders the preferences page with current default in-office days and target,
+// and the history of changes to them
 func (ctlr *RTOController) ShowPrefs(c echo.Context) error {
+	return ctlr.renderPrefs(c, http.StatusOK, map[string]interface{}{})
+}
+
+// prefsVersion is one row of the preference history: a version, the day
+// before the next one takes over (zero for the last) and whether it is the
+// one in force today
+type prefsVersion struct {
+	types.PreferenceVersion
+	Until   time.Time
+	Current bool
+}
+
+func (ctlr *RTOController) renderPrefs(c echo.Context, status int, data map[string]interface{}) error {
+	userID := currentUserID(c)
+	data["Preferences"] = ctlr.service.GetPrefs(userID)
+	data["TeamDays"] = types.TeamDefaultDays
+	now := time.Now()
+	data["Today"] = now.Format("2006-01-02")
+
+	// Newest first, each with the day it lasted until
+	if history, err := ctlr.service.GetPreferenceHistory(userID); err == nil {
+		rows := make([]prefsVersion, len(history))
+		for i, version := range history {
+			row := prefsVersion{PreferenceVersion: version, Current: !version.EffectiveFrom.After(now)}
+			if i+1 < len(history) {
+				row.Until = history[i+1].EffectiveFrom.AddDate(0, 0, -1)
+				row.Current = row.Current && history[i+1].EffectiveFrom.After(now)
+			}
+			rows[len(history)-1-i] = row
+		}
+		data["History"] = rows
+	} else {
+		ctlr.logger.Error("Error loading preference history", "userID", userID, "error", err)
+	}
 
-	data := map[string]interface{}{
-		"Preferences": ctlr.service.GetPrefs(),
+	if offices, err := ctlr.service.GetOffices(); err == nil {
+		data["Offices"] = offices
+	} else {
+		ctlr.logger.Error("Error loading offices", "error", err)
 	}
 
-	return c.Render(http.StatusOK, "prefs.html", data)
+	// The team's anchor days and the pattern suggested to match the team
+	if period, err := ctlr.service.GetCurrentPeriod(); err == nil {
+		if anchors, err := ctlr.service.AnchorCompliance(userID, period.StartDate, period.EndDate); err == nil {
+			data["Anchors"] = anchors
+		}
+	}
+	if suggested, err := ctlr.service.SuggestDefaultDays(userID); err == nil {
+		data["Suggested"] = suggested
+	} else {
+		ctlr.logger.Error("Error suggesting default days", "userID", userID, "error", err)
+	}
+
+	return c.Render(status, "prefs.html", data)
 }
 
+// UpdatePreferences saves the default days, target and calculation method,
+// from today or from the date given
 func (ctlr *RTOController) UpdatePreferences(c echo.Context) error {
 	newDefaultDays := c.FormValue("defaultDays")
 	newTargetDays := c.FormValue("targetDays")
@@ -29,9 +79,24 @@ func (ctlr *RTOController) UpdatePreferences(c echo.Context) error {
 	if newDefaultDays == "" || newTargetDays == "" {
 		return c.String(http.StatusBadRequest, "Default Days and Target Days are required.")
 	}
+	version := types.PreferenceVersion{
+		DefaultDays:       newDefaultDays,
+		TargetDays:        newTargetDays,
+		CalculationMethod: c.FormValue("calculationMethod"),
+	}
+	if from := c.FormValue("effectiveFrom"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid effective date.")
+		}
+		version.EffectiveFrom = date
+	}
 
 	// Call domain service to update preferences
-	err := ctlr.service.UpdatePreferences(newDefaultDays, newTargetDays)
+	err := ctlr.service.SetPreferences(currentUserID(c), version)
+	if errors.Is(err, domain.ErrInvalidInput) {
+		return ctlr.renderPrefs(c, http.StatusBadRequest, map[string]interface{}{"ErrorMessage": err.Error()})
+	}
 	if err != nil {
 		ctlr.logger.Error("Error updating preferences", "error", err)
 		return c.String(http.StatusInternalServerError, "Failed to update preferences.")
@@ -40,27 +105,37 @@ func (ctlr *RTOController) UpdatePreferences(c echo.Context) error {
 	return c.Redirect(http.StatusSeeOther, "/prefs")
 }
 
-func initializeDefaultPreferences(db *gorm.DB, logger *slog.Logger) error {
-	var count int64
-	if err := db.Model(&types.Preferences{}).Count(&count).Error; err != nil {
-		logger.Error("Failed to count preferences", "error", err)
-		return err
+// DeletePreferenceVersion removes a change from the preference history
+func (ctlr *RTOController) DeletePreferenceVersion(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid version ID.")
 	}
+	err = ctlr.service.DeletePreferenceVersion(currentUserID(c), id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return ctlr.renderPrefs(c, http.StatusNotFound, map[string]interface{}{"ErrorMessage": err.Error()})
+	case errors.Is(err, domain.ErrConflict):
+		return ctlr.renderPrefs(c, http.StatusConflict, map[string]interface{}{"ErrorMessage": err.Error()})
+	case err != nil:
+		ctlr.logger.Error("Error deleting preference version", "versionID", id, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to remove the change.")
+	}
+	return c.Redirect(http.StatusSeeOther, "/prefs")
+}
 
-	if count == 0 {
-		// No preferences found; create default
-		prefs := types.Preferences{
-			DefaultDays: "M,T,W,Th", // Default to first 4 days in week
-			TargetDays:  "2.5",
-		}
-		if err := db.Create(&prefs).Error; err != nil {
-			logger.Error("Failed to create default preferences", "error", err)
-			return err
+// UpdateDefaultOffice sets the office the user's in-office days are spent at
+func (ctlr *RTOController) UpdateDefaultOffice(c echo.Context) error {
+	officeID, err := strconv.Atoi(c.FormValue("office"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid office.")
+	}
+	if err := ctlr.service.SetDefaultOffice(currentUserID(c), officeID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return c.String(http.StatusBadRequest, "No such office.")
 		}
-		logger.Info("Default preferences created")
-	} else {
-		logger.Info("Preferences already exist")
+		ctlr.logger.Error("Error updating default office", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to update the default office.")
 	}
-
-	return nil
+	return c.Redirect(http.StatusSeeOther, "/prefs")
 }
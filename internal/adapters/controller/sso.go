@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/sso"
+	"github.com/robstave/rto/internal/domain"
+)
+
+// Session values kept between the redirect to the identity provider and the callback
+const (
+	sessionSSOStateKey    = "sso_state"
+	sessionSSONonceKey    = "sso_nonce"
+	sessionSSOVerifierKey = "sso_verifier"
+)
+
+// SSOLogin sends the browser to the identity provider
+func (ctlr *RTOController) SSOLogin(c echo.Context) error {
+	if ctlr.sso == nil {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	authURL, flow, err := ctlr.sso.AuthURL(c.Request().Context())
+	if err != nil {
+		ctlr.logger.Error("Failed to start single sign-on", "error", err)
+		return c.Render(http.StatusBadGateway, "login.html", ctlr.loginData("Single sign-on is unavailable. Try again later."))
+	}
+
+	sess, err := session.Get("session", c)
+	if err != nil {
+		ctlr.logger.Error("Failed to get session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+	sess.Values[sessionSSOStateKey] = flow.State
+	sess.Values[sessionSSONonceKey] = flow.Nonce
+	sess.Values[sessionSSOVerifierKey] = flow.Verifier
+	if err := sess.Save(c.Request(), c.Response()); err != nil {
+		ctlr.logger.Error("Failed to save session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// SSOCallback finishes single sign-on when the identity provider redirects back
+func (ctlr *RTOController) SSOCallback(c echo.Context) error {
+	if ctlr.sso == nil {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	sess, err := session.Get("session", c)
+	if err != nil {
+		ctlr.logger.Error("Failed to get session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+	flow := sso.Flow{}
+	flow.State, _ = sess.Values[sessionSSOStateKey].(string)
+	flow.Nonce, _ = sess.Values[sessionSSONonceKey].(string)
+	flow.Verifier, _ = sess.Values[sessionSSOVerifierKey].(string)
+
+	// A flow is good for one callback. On success startSession replaces the
+	// whole session; on failure the flow is dropped here.
+	delete(sess.Values, sessionSSOStateKey)
+	delete(sess.Values, sessionSSONonceKey)
+	delete(sess.Values, sessionSSOVerifierKey)
+	fail := func(status int, msg string) error {
+		sess.Save(c.Request(), c.Response())
+		return c.Render(status, "login.html", ctlr.loginData(msg))
+	}
+
+	if reason := c.QueryParam("error"); reason != "" {
+		ctlr.logger.Info("Identity provider refused sign-in", "error", reason, "description", c.QueryParam("error_description"))
+		return fail(http.StatusUnauthorized, "Single sign-on was cancelled or refused.")
+	}
+
+	identity, err := ctlr.sso.Exchange(c.Request().Context(), flow, c.QueryParam("state"), c.QueryParam("code"))
+	if err != nil {
+		if errors.Is(err, sso.ErrStateMismatch) {
+			return fail(http.StatusBadRequest, "Sign-in expired. Please try again.")
+		}
+		ctlr.logger.Error("Single sign-on failed", "error", err)
+		return fail(http.StatusUnauthorized, "Single sign-on failed.")
+	}
+
+	user, err := ctlr.service.SignInExternal(identity)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrInvalidInput) {
+			ctlr.logger.Info("Single sign-on rejected", "error", err)
+			return fail(http.StatusConflict, err.Error())
+		}
+		ctlr.logger.Error("Failed to sign in external identity", "error", err)
+		return fail(http.StatusInternalServerError, "Internal server error")
+	}
+
+	if err := ctlr.startSession(c, user, authSSO); err != nil {
+		ctlr.logger.Error("Failed to save session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+
+	ctlr.logger.Info("User signed in with single sign-on", "userID", user.ID)
+	return c.Redirect(http.StatusSeeOther, "/")
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/sso"
+	"github.com/robstave/rto/internal/adapters/sso/mockidp"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newSSOController wires a controller to a mock identity provider
+func newSSOController(t *testing.T, mockService *mocks.RTOBLL) (*RTOController, *mockidp.Provider, *echo.Echo) {
+	idp, server, err := mockidp.NewServer("rto", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil)) // Assign a simple logger
+	ctlr.sso = sso.New(sso.Config{
+		IssuerURL:    idp.Issuer,
+		ClientID:     "rto",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://rto.test/auth/oidc/callback",
+	})
+
+	e := newSessionEcho(ctlr)
+	e.Renderer = &mockRenderer{}
+	e.GET("/auth/oidc/login", ctlr.SSOLogin)
+	e.GET("/auth/oidc/callback", ctlr.SSOCallback)
+	return ctlr, idp, e
+}
+
+// visitProvider follows the redirect to the provider like a browser and
+// returns the callback path and query it sends the browser back to
+func visitProvider(t *testing.T, location string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return callback.RequestURI()
+}
+
+func TestSSO_SignsInAndStartsSession(t *testing.T) {
+	mockService := new(mocks.RTOBLL)
+	mockService.On("HasUsers").Return(true, nil).Maybe()
+	user := &types.User{ID: 9, Username: "alice"}
+	mockService.On("SignInExternal", mock.MatchedBy(func(i types.ExternalIdentity) bool {
+		return i.Subject == "u-1" && i.Email == "alice@example.com" && i.EmailVerified
+	})).Return(user, nil)
+	mockService.On("GetUser", 9).Return(user, nil)
+
+	_, idp, e := newSSOController(t, mockService)
+	idp.SetClaims(map[string]interface{}{"sub": "u-1", "email": "alice@example.com", "email_verified": true})
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderLocation), idp.Issuer))
+	cookies := rec.Result().Cookies()
+
+	req = httptest.NewRequest(http.MethodGet, visitProvider(t, rec.Header().Get(echo.HeaderLocation)), nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+
+	// The session cookie opens protected pages as the signed-in user
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestSSO_CallbackWithoutLoginIsRejected(t *testing.T) {
+	mockService := new(mocks.RTOBLL)
+	mockService.On("HasUsers").Return(true, nil)
+	mockService.On("RegistrationOpen").Return(false)
+
+	_, _, e := newSSOController(t, mockService)
+
+	// A callback this browser never started, e.g. a login CSRF attempt
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?state=forged&code=stolen", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "SignInExternal", mock.Anything)
+}
+
+func TestProcessLogin_PasswordLoginDisabled(t *testing.T) {
+	mockService := new(mocks.RTOBLL)
+	mockService.On("HasUsers").Return(true, nil)
+
+	ctlr, _, e := newSSOController(t, mockService)
+	ctlr.passwordLogin = false
+
+	form := url.Values{"username": {"alice"}, "password": {"correct horse"}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"PasswordLogin":false`)
+	mockService.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything)
+}
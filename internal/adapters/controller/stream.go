@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Stream message names
+const (
+	streamChange = "change"
+	streamStats  = "stats"
+)
+
+// streamHeartbeat keeps proxies from closing idle streams
+const streamHeartbeat = 25 * time.Second
+
+// statsDelay lets a burst of changes settle before stats are recomputed
+const statsDelay = 250 * time.Millisecond
+
+// StreamChange is the payload of a "change" message on /events/stream
+type StreamChange struct {
+	Type   string    `json:"type"`
+	Event  *APIEvent `json:"event,omitempty"`
+	Status string    `json:"status,omitempty"`
+	Level  string    `json:"level,omitempty"`
+}
+
+// StreamStats is the payload of a "stats" message. It matches the numbers
+// the home page renders and /toggle-attendance returns.
+type StreamStats struct {
+	InOfficeCount int     `json:"inOfficeCount"`
+	TotalDays     int     `json:"totalDays"`
+	Average       float64 `json:"average"`
+	AverageDays   float64 `json:"averageDays"`
+	TargetDays    float64 `json:"targetDays"`
+}
+
+// streamTopic is the broker topic for a user's open tabs
+func streamTopic(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// publishChange is registered with the service and forwards changes to the
+// open tabs of the user whose calendar changed
+func (ctlr *RTOController) publishChange(change types.Change) {
+	if ctlr.stream.Clients() == 0 {
+		return
+	}
+	userID := int(change.UserID)
+	topic := streamTopic(userID)
+
+	msg := StreamChange{Type: change.Kind, Status: change.Status, Level: change.Level}
+	if change.Event != nil {
+		event := toAPIEvent(*change.Event)
+		msg.Event = &event
+	}
+	ctlr.stream.Publish(topic, streamChange, msg)
+
+	ctlr.stream.Coalesce(topic, streamStats, statsDelay, func() (interface{}, bool) {
+		stats, err := ctlr.service.CalculateAttendanceStats(userID)
+		if err != nil {
+			ctlr.logger.Error("Error calculating stats for stream", "error", err)
+			return nil, false
+		}
+		return StreamStats{
+			InOfficeCount: stats.InOfficeCount,
+			TotalDays:     stats.TotalDays,
+			Average:       stats.Average,
+			AverageDays:   stats.AverageDays,
+			TargetDays:    stats.TargetDays,
+		}, true
+	})
+}
+
+// EventStream serves Server-Sent Events with event and stats changes
+func (ctlr *RTOController) EventStream(c echo.Context) error {
+	if ctlr.stream == nil {
+		return c.String(http.StatusServiceUnavailable, "Live updates are not available.")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	// Tell the browser how long to wait before reconnecting
+	if _, err := res.Write([]byte("retry: 3000\n\n")); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	messages := ctlr.stream.Subscribe(streamTopic(currentUserID(c)))
+	defer ctlr.stream.Unsubscribe(messages)
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			if _, err := msg.WriteTo(res); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			if _, err := res.Write([]byte(": ping\n\n")); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
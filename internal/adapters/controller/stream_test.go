@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/stream"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishChange_SendsChangeThenStats(t *testing.T) {
+	mockService := new(mocks.RTOBLL)
+	mockService.On("CalculateAttendanceStats", 3).Return(&types.AttendanceStats{
+		InOfficeCount: 10,
+		TotalDays:     20,
+		Average:       50,
+		AverageDays:   3.5,
+		TargetDays:    2.5,
+	}, nil)
+
+	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
+	ctlr.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctlr.stream = stream.NewBroker(ctlr.logger)
+
+	messages := ctlr.stream.Subscribe(streamTopic(3))
+	defer ctlr.stream.Unsubscribe(messages)
+
+	date := time.Date(2024, time.October, 7, 0, 0, 0, 0, time.UTC)
+	ctlr.publishChange(types.Change{
+		Kind:   types.ChangeAttendanceToggled,
+		UserID: 3,
+		Event:  &types.Event{ID: 5, Date: date, Type: "attendance", IsInOffice: true},
+		Status: "in",
+	})
+
+	msg := <-messages
+	assert.Equal(t, streamChange, msg.Event)
+	var change StreamChange
+	assert.NoError(t, json.Unmarshal(msg.Data, &change))
+	assert.Equal(t, types.ChangeAttendanceToggled, change.Type)
+	assert.Equal(t, "in", change.Status)
+	assert.Equal(t, "2024-10-07", change.Event.Date)
+	assert.Equal(t, uint(5), change.Event.ID)
+
+	select {
+	case msg = <-messages:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stats message was never published")
+	}
+	assert.Equal(t, streamStats, msg.Event)
+	var stats StreamStats
+	assert.NoError(t, json.Unmarshal(msg.Data, &stats))
+	assert.Equal(t, 50.0, stats.Average)
+	assert.Equal(t, 2.5, stats.TargetDays)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// monthParam reads ?month=YYYY-MM, defaulting to the current month
+func monthParam(c echo.Context) time.Time {
+	if month, err := time.Parse("2006-01", c.QueryParam("month")); err == nil {
+		return month
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ShowTeam renders the attendance heatmap of the signed-in manager's team
+func (ctlr *RTOController) ShowTeam(c echo.Context) error {
+	return ctlr.renderTeam(c, http.StatusOK, map[string]interface{}{})
+}
+
+// SetAnchorDays sets the days the signed-in manager's team all come in
+func (ctlr *RTOController) SetAnchorDays(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form submission.")
+	}
+	if err := ctlr.service.SetAnchorDays(currentUserID(c), strings.Join(form["days"], ",")); err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to save the anchor days."
+		if errors.Is(err, domain.ErrInvalidInput) {
+			status, msg = http.StatusBadRequest, err.Error()
+		} else {
+			ctlr.logger.Error("Error setting anchor days", "userID", currentUserID(c), "error", err)
+		}
+		return ctlr.renderTeam(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/team?month="+monthParam(c).Format("2006-01"))
+}
+
+func (ctlr *RTOController) renderTeam(c echo.Context, status int, data map[string]interface{}) error {
+	month := monthParam(c)
+	dashboard, err := ctlr.service.TeamDashboard(currentUserID(c), month)
+	if err != nil {
+		ctlr.logger.Error("Error building team dashboard", "userID", currentUserID(c), "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load the team dashboard.")
+	}
+
+	anchors := make(map[string]bool)
+	for _, day := range strings.Split(dashboard.AnchorDays, ",") {
+		anchors[day] = true
+	}
+	data["Month"] = month
+	data["PrevMonth"] = month.AddDate(0, -1, 0).Format("2006-01")
+	data["NextMonth"] = month.AddDate(0, 1, 0).Format("2006-01")
+	data["Team"] = dashboard
+	data["Weekdays"] = []string{"M", "T", "W", "Th", "F"}
+	data["Anchors"] = anchors
+	return c.Render(status, "team.html", data)
+}
+
+// ShowTeamMember renders a read-only calendar of one team member, chosen by ForReport
+func (ctlr *RTOController) ShowTeamMember(c echo.Context) error {
+	member := c.Get(reportUserKey).(*types.User)
+	userID := calendarUserID(c)
+	month := monthParam(c)
+
+	period, err := ctlr.service.GetCurrentPeriod()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load the current period.")
+	}
+	stats, err := ctlr.service.CalculateStatsBetween(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		ctlr.logger.Error("Error calculating stats", "userID", userID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to calculate attendance.")
+	}
+
+	anchors, err := ctlr.service.AnchorCompliance(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		ctlr.logger.Error("Error calculating anchor compliance", "userID", userID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to calculate attendance.")
+	}
+	suggested, err := ctlr.service.SuggestDefaultDays(userID)
+	if err != nil {
+		ctlr.logger.Error("Error suggesting default days", "userID", userID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to suggest default days.")
+	}
+
+	weeks := utils.GetCalendarMonth(month)
+	fillCalendar(weeks, ctlr.service.GetAllEvents(userID))
+
+	offices, err := ctlr.service.GetOffices()
+	if err != nil {
+		ctlr.logger.Error("Error loading offices", "error", err)
+	}
+
+	return c.Render(http.StatusOK, "team_member.html", map[string]interface{}{
+		"Member":      member,
+		"Month":       month,
+		"PrevMonth":   month.AddDate(0, -1, 0).Format("2006-01"),
+		"NextMonth":   month.AddDate(0, 1, 0).Format("2006-01"),
+		"Weeks":       weeks,
+		"Period":      period,
+		"Stats":       stats,
+		"Anchors":     anchors,
+		"Prefs":       ctlr.service.GetPrefs(userID),
+		"Suggested":   suggested,
+		"OfficeNames": officeNames(offices),
+	})
+}
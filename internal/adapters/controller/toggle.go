@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
 )
 
 // ToggleAttendanceRequest represents the JSON payload for toggling attendance
@@ -47,7 +49,10 @@ func (ctlr *RTOController) ToggleAttendance(c echo.Context) error {
 	}
 
 	// Call domain service to toggle attendance
-	newStatus, err := ctlr.service.ToggleAttendance(eventDate)
+	newStatus, err := ctlr.service.ToggleAttendance(currentUserID(c), eventDate)
+	if errors.Is(err, domain.ErrConflict) {
+		return c.JSON(http.StatusConflict, ToggleAttendanceResponse{Success: false, Message: err.Error()})
+	}
 	if err != nil {
 		ctlr.logger.Error("Error toggling attendance", "error", err)
 		return c.JSON(http.StatusInternalServerError, ToggleAttendanceResponse{
@@ -57,7 +62,7 @@ func (ctlr *RTOController) ToggleAttendance(c echo.Context) error {
 	}
 
 	// After toggling, recalculate stats
-	stats, err := ctlr.service.CalculateAttendanceStats()
+	stats, err := ctlr.service.CalculateAttendanceStats(currentUserID(c))
 	if err != nil {
 		ctlr.logger.Error("Error calculating stats", "error", err)
 		return c.JSON(http.StatusInternalServerError, ToggleAttendanceResponse{
@@ -34,8 +34,8 @@ func TestToggleAttendance_Success(t *testing.T) {
 
 	// Mock the service methods
 	eventDate, _ := time.Parse("2006-01-02", reqBody.Date)
-	mockService.On("ToggleAttendance", eventDate).Return("in", nil)
-	mockService.On("CalculateAttendanceStats").Return(&types.AttendanceStats{
+	mockService.On("ToggleAttendance", 0, eventDate).Return("in", nil)
+	mockService.On("CalculateAttendanceStats", 0).Return(&types.AttendanceStats{
 		InOfficeCount:  10,
 		TotalDays:      20,
 		Average:        50.0,
@@ -124,7 +124,7 @@ func TestToggleAttendance_ServiceError(t *testing.T) {
 
 	// Mock the service method to return an error
 	eventDate, _ := time.Parse("2006-01-02", reqBody.Date)
-	mockService.On("ToggleAttendance", eventDate).Return("", errors.New("attendance event not found"))
+	mockService.On("ToggleAttendance", 0, eventDate).Return("", errors.New("attendance event not found"))
 
 	// Initialize the controller with the mock service
 	ctlr := NewRTOControllerWithMock("none", mockService, QuarterStart, QuarterEnd)
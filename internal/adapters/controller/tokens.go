@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ShowTokens renders the API token settings page
+func (ctlr *RTOController) ShowTokens(c echo.Context) error {
+	return ctlr.renderTokens(c, http.StatusOK, map[string]interface{}{})
+}
+
+// CreateToken handles the new token form. The plaintext token is rendered once.
+func (ctlr *RTOController) CreateToken(c echo.Context) error {
+	name := c.FormValue("name")
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form submission.")
+	}
+	scopes := form["scopes"]
+
+	var expiresAt *time.Time
+	if days := c.FormValue("expiresInDays"); days != "" && days != "0" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return ctlr.renderTokens(c, http.StatusBadRequest, map[string]interface{}{
+				"ErrorMessage": "Expiry must be a number of days.",
+			})
+		}
+		expiry := time.Now().AddDate(0, 0, n)
+		expiresAt = &expiry
+	}
+
+	plaintext, token, err := ctlr.service.CreateAPIToken(currentUserID(c), name, scopes, expiresAt)
+	if err != nil {
+		ctlr.logger.Error("Error creating API token", "error", err)
+		return ctlr.renderTokens(c, http.StatusBadRequest, map[string]interface{}{
+			"ErrorMessage": err.Error(),
+		})
+	}
+
+	return ctlr.renderTokens(c, http.StatusOK, map[string]interface{}{
+		"NewToken":       plaintext,
+		"SuccessMessage": "Token '" + token.Name + "' created. Copy it now, it will not be shown again.",
+	})
+}
+
+// RevokeToken handles the revoke button on the tokens page
+func (ctlr *RTOController) RevokeToken(c echo.Context) error {
+	tokenID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid token ID.")
+	}
+
+	if err := ctlr.service.RevokeAPIToken(currentUserID(c), tokenID); err != nil {
+		ctlr.logger.Error("Error revoking API token", "tokenID", tokenID, "error", err)
+		return ctlr.renderTokens(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to revoke token.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/tokens")
+}
+
+func (ctlr *RTOController) renderTokens(c echo.Context, status int, data map[string]interface{}) error {
+	tokens, err := ctlr.service.GetAPITokens(currentUserID(c))
+	if err != nil {
+		ctlr.logger.Error("Error listing API tokens", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load tokens.")
+	}
+	data["Tokens"] = tokens
+	data["Now"] = time.Now()
+	return c.Render(status, "tokens.html", data)
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// pendingSignInTTL is how long the code form stays valid after the password step
+const pendingSignInTTL = 5 * time.Minute
+
+// twoFactorEnrollPaths stay reachable while a user whose role requires
+// two-factor sign-in has not set it up yet
+var twoFactorEnrollPaths = map[string]bool{
+	"/account/2fa":        true,
+	"/account/2fa/enable": true,
+}
+
+// startSecondFactor remembers who passed the password step. The session is
+// not signed in until the code is checked.
+func (ctlr *RTOController) startSecondFactor(c echo.Context, user *types.User) error {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return err
+	}
+	for key := range sess.Values {
+		delete(sess.Values, key)
+	}
+	sess.Values[pendingUserKey] = int(user.ID)
+	sess.Values[pendingAtKey] = time.Now().Unix()
+	return sess.Save(c.Request(), c.Response())
+}
+
+// pendingUserID returns the user waiting for their second factor, if the
+// password step was recent enough
+func (ctlr *RTOController) pendingUserID(c echo.Context) (int, bool) {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return 0, false
+	}
+	userID, ok := sess.Values[pendingUserKey].(int)
+	at, _ := sess.Values[pendingAtKey].(int64)
+	if !ok || time.Since(time.Unix(at, 0)) > pendingSignInTTL {
+		return 0, false
+	}
+	return userID, true
+}
+
+// mustEnrollTwoFactor reports whether a password session belongs to a user
+// whose role requires two-factor sign-in that they have not set up
+func (ctlr *RTOController) mustEnrollTwoFactor(c echo.Context, user *types.User) bool {
+	return !user.TOTPEnabled && ctlr.sessionMethod(c) == authPassword && ctlr.service.TwoFactorRequired(*user)
+}
+
+// ShowSecondFactor renders the code form that follows the password step
+func (ctlr *RTOController) ShowSecondFactor(c echo.Context) error {
+	if _, ok := ctlr.pendingUserID(c); !ok {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+	return c.Render(http.StatusOK, "login_2fa.html", map[string]interface{}{})
+}
+
+// ProcessSecondFactor checks the TOTP or recovery code and signs the user in
+func (ctlr *RTOController) ProcessSecondFactor(c echo.Context) error {
+	userID, ok := ctlr.pendingUserID(c)
+	if !ok {
+		return c.Render(http.StatusUnauthorized, "login.html", ctlr.loginData("Your sign-in timed out. Enter your password again."))
+	}
+
+	user, err := ctlr.service.VerifySecondFactor(userID, c.FormValue("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountLocked):
+			return c.Render(http.StatusTooManyRequests, "login.html", ctlr.loginData(err.Error()))
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			ctlr.logger.Info("Failed second factor", "userID", userID)
+			return c.Render(http.StatusUnauthorized, "login_2fa.html", map[string]interface{}{
+				"Error": "That code is not right.",
+			})
+		default:
+			ctlr.logger.Error("Failed to verify second factor", "userID", userID, "error", err)
+			return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+		}
+	}
+
+	if err := ctlr.startSession(c, user, authPassword); err != nil {
+		ctlr.logger.Error("Failed to save session", "error", err)
+		return c.Render(http.StatusInternalServerError, "login.html", ctlr.loginData("Internal server error"))
+	}
+
+	ctlr.logger.Info("User signed in with two-factor", "userID", user.ID)
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// ShowTwoFactor renders the two-factor settings page, starting enrollment
+// when it is off
+func (ctlr *RTOController) ShowTwoFactor(c echo.Context) error {
+	return ctlr.renderTwoFactor(c, http.StatusOK, map[string]interface{}{})
+}
+
+// EnableTwoFactor confirms the first code from the user's app and shows the recovery codes
+func (ctlr *RTOController) EnableTwoFactor(c echo.Context) error {
+	userID := currentUserID(c)
+	codes, err := ctlr.service.EnableTOTP(userID, c.FormValue("code"))
+	if err != nil {
+		return ctlr.twoFactorError(c, err, "Failed to turn on two-factor sign-in.")
+	}
+	// Other sessions are now signed out; keep this one
+	if err := ctlr.renewSession(c); err != nil {
+		ctlr.logger.Error("Failed to refresh session", "userID", currentUserID(c), "error", err)
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+	return ctlr.renderTwoFactor(c, http.StatusOK, map[string]interface{}{
+		"RecoveryCodes":  codes,
+		"SuccessMessage": "Two-factor sign-in is on. Other sessions have been signed out.",
+	})
+}
+
+// DisableTwoFactor turns two-factor sign-in off after checking the password
+func (ctlr *RTOController) DisableTwoFactor(c echo.Context) error {
+	if err := ctlr.service.DisableTOTP(currentUserID(c), c.FormValue("password")); err != nil {
+		return ctlr.twoFactorError(c, err, "Failed to turn off two-factor sign-in.")
+	}
+	// Other sessions are now signed out; keep this one
+	if err := ctlr.renewSession(c); err != nil {
+		ctlr.logger.Error("Failed to refresh session", "userID", currentUserID(c), "error", err)
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+	return ctlr.renderTwoFactor(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Two-factor sign-in is off. Other sessions have been signed out.",
+	})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes and shows the new ones once
+func (ctlr *RTOController) RegenerateRecoveryCodes(c echo.Context) error {
+	codes, err := ctlr.service.RegenerateRecoveryCodes(currentUserID(c), c.FormValue("code"))
+	if err != nil {
+		return ctlr.twoFactorError(c, err, "Failed to make new recovery codes.")
+	}
+	if err := ctlr.refreshCurrentUser(c); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load your account.")
+	}
+	return ctlr.renderTwoFactor(c, http.StatusOK, map[string]interface{}{
+		"RecoveryCodes":  codes,
+		"SuccessMessage": "New recovery codes made. The old ones no longer work.",
+	})
+}
+
+// SetTwoFactorPolicy chooses the roles that must use two-factor sign-in
+func (ctlr *RTOController) SetTwoFactorPolicy(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form submission.")
+	}
+	if err := ctlr.service.SetTwoFactorRoles(form["roles"]); err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to update the two-factor policy."
+		if errors.Is(err, domain.ErrInvalidInput) {
+			status, msg = http.StatusBadRequest, err.Error()
+		}
+		return ctlr.renderAccount(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/account")
+}
+
+func (ctlr *RTOController) twoFactorError(c echo.Context, err error, fallback string) error {
+	status, msg := http.StatusInternalServerError, fallback
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrConflict):
+		status, msg = http.StatusBadRequest, err.Error()
+	default:
+		ctlr.logger.Error(fallback, "userID", currentUserID(c), "error", err)
+	}
+	return ctlr.renderTwoFactor(c, status, map[string]interface{}{"ErrorMessage": msg})
+}
+
+// refreshCurrentUser reloads the signed-in user after their account changed
+func (ctlr *RTOController) refreshCurrentUser(c echo.Context) error {
+	user, err := ctlr.service.GetUser(currentUserID(c))
+	if err != nil {
+		return err
+	}
+	c.Set(currentUserKey, user)
+	return nil
+}
+
+func (ctlr *RTOController) renderTwoFactor(c echo.Context, status int, data map[string]interface{}) error {
+	user := currentUser(c)
+	data["User"] = user
+	data["Required"] = ctlr.service.TwoFactorRequired(*user)
+	if user.TOTPEnabled {
+		data["CodesLeft"] = domain.RecoveryCodesLeft(*user)
+	} else {
+		secret, uri, err := ctlr.service.BeginTOTPEnrollment(int(user.ID))
+		if err != nil {
+			ctlr.logger.Error("Error starting two-factor enrollment", "userID", user.ID, "error", err)
+			return c.String(http.StatusInternalServerError, "Failed to start two-factor enrollment.")
+		}
+		data["Secret"] = secret
+		data["URI"] = uri
+	}
+	return c.Render(status, "account_2fa.html", data)
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// ShowRegister renders the sign-up form
+func (ctlr *RTOController) ShowRegister(c echo.Context) error {
+	data := ctlr.loginData("")
+	if !data["CanRegister"].(bool) {
+		data["Error"] = "Registration is closed. Ask an admin for an account."
+		if !ctlr.passwordLogin {
+			data["Error"] = "Accounts are created by signing in with single sign-on."
+		}
+		return c.Render(http.StatusForbidden, "login.html", data)
+	}
+	return c.Render(http.StatusOK, "register.html", data)
+}
+
+// ProcessRegister creates the account and signs it in
+func (ctlr *RTOController) ProcessRegister(c echo.Context) error {
+	if !ctlr.passwordLogin {
+		return c.Render(http.StatusForbidden, "login.html", ctlr.loginData("Accounts are created by signing in with single sign-on."))
+	}
+
+	username := c.FormValue("username")
+	password := c.FormValue("password")
+
+	render := func(status int, msg string) error {
+		data := ctlr.loginData(msg)
+		data["Username"] = username
+		return c.Render(status, "register.html", data)
+	}
+
+	if password != c.FormValue("confirm") {
+		return render(http.StatusBadRequest, "Passwords do not match.")
+	}
+
+	user, err := ctlr.service.Register(username, password)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRegistrationClosed):
+			return render(http.StatusForbidden, "Registration is closed. Ask an admin for an account.")
+		case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrConflict):
+			return render(http.StatusBadRequest, err.Error())
+		default:
+			ctlr.logger.Error("Failed to register user", "error", err)
+			return render(http.StatusInternalServerError, "Internal server error")
+		}
+	}
+
+	if err := ctlr.startSession(c, user, authPassword); err != nil {
+		ctlr.logger.Error("Failed to save session", "error", err)
+		return render(http.StatusInternalServerError, "Internal server error")
+	}
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// ShowAccount renders the account page: password change for everyone, sign-up
+// control and the user list with roles and managers for admins
+func (ctlr *RTOController) ShowAccount(c echo.Context) error {
+	return ctlr.renderAccount(c, http.StatusOK, map[string]interface{}{})
+}
+
+// ChangePassword handles the password form on the account page
+func (ctlr *RTOController) ChangePassword(c echo.Context) error {
+	user := currentUser(c)
+	password := c.FormValue("password")
+	if password != c.FormValue("confirm") {
+		return ctlr.renderAccount(c, http.StatusBadRequest, map[string]interface{}{
+			"ErrorMessage": "New passwords do not match.",
+		})
+	}
+
+	err := ctlr.service.ChangePassword(int(user.ID), c.FormValue("current"), password)
+	if err != nil {
+		status := http.StatusInternalServerError
+		msg := "Failed to change password."
+		if errors.Is(err, domain.ErrInvalidInput) {
+			status, msg = http.StatusBadRequest, err.Error()
+		} else {
+			ctlr.logger.Error("Error changing password", "userID", user.ID, "error", err)
+		}
+		return ctlr.renderAccount(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+
+	// Other sessions are now signed out; keep this one
+	if err := ctlr.renewSession(c); err != nil {
+		ctlr.logger.Error("Failed to refresh session", "userID", user.ID, "error", err)
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	return ctlr.renderAccount(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Password changed. Other sessions have been signed out.",
+	})
+}
+
+// SetUserAccess changes a user's role and manager from the account page (admins only)
+func (ctlr *RTOController) SetUserAccess(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID < 1 {
+		return c.String(http.StatusBadRequest, "Invalid user ID.")
+	}
+	managerID := 0
+	if manager := c.FormValue("manager"); manager != "" {
+		if managerID, err = strconv.Atoi(manager); err != nil || managerID < 1 {
+			return c.String(http.StatusBadRequest, "Invalid manager.")
+		}
+	}
+
+	err = ctlr.service.SetUserRole(userID, c.FormValue("role"))
+	if err == nil {
+		err = ctlr.service.SetUserManager(userID, managerID)
+	}
+	if err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to update user."
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			status, msg = http.StatusNotFound, err.Error()
+		case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrConflict):
+			status, msg = http.StatusBadRequest, err.Error()
+		default:
+			ctlr.logger.Error("Error updating user access", "userID", userID, "error", err)
+		}
+		return ctlr.renderAccount(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	return c.Redirect(http.StatusSeeOther, "/account")
+}
+
+// SetUserEmail sets the address a user's reminders go to and single sign-on
+// links by, from the account page (admins only). An empty address clears it.
+func (ctlr *RTOController) SetUserEmail(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID < 1 {
+		return c.String(http.StatusBadRequest, "Invalid user ID.")
+	}
+	user, err := ctlr.service.GetUser(userID)
+	if err != nil {
+		return ctlr.renderAccount(c, http.StatusNotFound, map[string]interface{}{"ErrorMessage": "No such user."})
+	}
+
+	if err := ctlr.service.SetUserEmail(user.Username, c.FormValue("email")); err != nil {
+		status, msg := http.StatusInternalServerError, "Failed to update the email address."
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			status, msg = http.StatusNotFound, err.Error()
+		case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrConflict):
+			status, msg = http.StatusBadRequest, err.Error()
+		default:
+			ctlr.logger.Error("Error updating email", "userID", userID, "error", err)
+		}
+		return ctlr.renderAccount(c, status, map[string]interface{}{"ErrorMessage": msg})
+	}
+	ctlr.logger.Info("Admin set user email", "adminID", currentUserID(c), "userID", userID)
+	return c.Redirect(http.StatusSeeOther, "/account")
+}
+
+// SignOutUser ends every session of another user (admins only)
+func (ctlr *RTOController) SignOutUser(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID < 1 {
+		return c.String(http.StatusBadRequest, "Invalid user ID.")
+	}
+	user, err := ctlr.service.GetUser(userID)
+	if err != nil {
+		return ctlr.renderAccount(c, http.StatusNotFound, map[string]interface{}{"ErrorMessage": "No such user."})
+	}
+
+	ended, err := ctlr.service.RevokeAllSessions(userID)
+	if err != nil {
+		return ctlr.renderAccount(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to sign the user out.",
+		})
+	}
+	ctlr.logger.Info("Admin signed user out", "adminID", currentUserID(c), "userID", userID, "sessions", ended)
+	return ctlr.renderAccount(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": fmt.Sprintf("Signed %s out of %d session(s).", user.Username, ended),
+	})
+}
+
+// SetRegistration opens or closes sign-up (admins only)
+func (ctlr *RTOController) SetRegistration(c echo.Context) error {
+	open := c.FormValue("open") == "true"
+	if err := ctlr.service.SetRegistrationOpen(open); err != nil {
+		ctlr.logger.Error("Error updating registration setting", "error", err)
+		return ctlr.renderAccount(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to update registration.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/account")
+}
+
+// SetOtherOffices decides whether days at another office count (admins only)
+func (ctlr *RTOController) SetOtherOffices(c echo.Context) error {
+	count := c.FormValue("count") == "true"
+	if err := ctlr.service.SetCountOtherOffices(count); err != nil {
+		ctlr.logger.Error("Error updating other offices setting", "error", err)
+		return ctlr.renderAccount(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to update the other offices policy.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/account")
+}
+
+func (ctlr *RTOController) renderAccount(c echo.Context, status int, data map[string]interface{}) error {
+	user := currentUser(c)
+	data["User"] = user
+	data["CanManageUsers"] = user.Can(types.PermManageUsers)
+	data["CanManageJobs"] = user.Can(types.PermManageJobs)
+	if user.Can(types.PermManageUsers) {
+		users, err := ctlr.service.GetUsers()
+		if err != nil {
+			ctlr.logger.Error("Error listing users", "error", err)
+			return c.String(http.StatusInternalServerError, "Failed to load users.")
+		}
+		data["Users"] = users
+		data["Roles"] = types.Roles
+		data["RegistrationOpen"] = ctlr.service.RegistrationOpen()
+		twoFactorRoles := map[string]bool{}
+		for _, role := range ctlr.service.TwoFactorRoles() {
+			twoFactorRoles[role] = true
+		}
+		data["TwoFactorRoles"] = twoFactorRoles
+		data["CountOtherOffices"] = ctlr.service.CountOtherOffices()
+		data["Now"] = time.Now()
+	}
+	return c.Render(status, "account.html", data)
+}
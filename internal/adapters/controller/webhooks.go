@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// recentDeliveries is how many log entries are shown per webhook
+const recentDeliveries = 10
+
+// webhookView pairs a webhook with its recent deliveries for the template
+type webhookView struct {
+	types.Webhook
+	Deliveries []types.WebhookDelivery
+}
+
+// ShowWebhooks renders the webhook settings page
+func (ctlr *RTOController) ShowWebhooks(c echo.Context) error {
+	return ctlr.renderWebhooks(c, http.StatusOK, map[string]interface{}{})
+}
+
+// CreateWebhook handles the new webhook form
+func (ctlr *RTOController) CreateWebhook(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form submission.")
+	}
+
+	webhook, err := ctlr.service.CreateWebhook(currentUserID(c), c.FormValue("url"), form["events"])
+	if err != nil {
+		ctlr.logger.Error("Error creating webhook", "error", err)
+		return ctlr.renderWebhooks(c, http.StatusBadRequest, map[string]interface{}{
+			"ErrorMessage": err.Error(),
+		})
+	}
+
+	return ctlr.renderWebhooks(c, http.StatusOK, map[string]interface{}{
+		"SuccessMessage": "Webhook created for " + webhook.URL + ".",
+	})
+}
+
+// SetWebhookActive pauses or resumes a webhook
+func (ctlr *RTOController) SetWebhookActive(c echo.Context) error {
+	webhookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid webhook ID.")
+	}
+	active := c.FormValue("active") == "true"
+
+	if err := ctlr.service.SetWebhookActive(currentUserID(c), webhookID, active); err != nil {
+		ctlr.logger.Error("Error updating webhook", "webhookID", webhookID, "error", err)
+		return ctlr.renderWebhooks(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to update webhook.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/webhooks")
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (ctlr *RTOController) DeleteWebhook(c echo.Context) error {
+	webhookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid webhook ID.")
+	}
+
+	if err := ctlr.service.DeleteWebhook(currentUserID(c), webhookID); err != nil {
+		ctlr.logger.Error("Error deleting webhook", "webhookID", webhookID, "error", err)
+		return ctlr.renderWebhooks(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to delete webhook.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/webhooks")
+}
+
+// ReplayWebhookDelivery re-sends a logged delivery as a new delivery
+func (ctlr *RTOController) ReplayWebhookDelivery(c echo.Context) error {
+	deliveryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid delivery ID.")
+	}
+	if ctlr.webhooks == nil {
+		return c.String(http.StatusServiceUnavailable, "Webhook delivery is not available.")
+	}
+
+	if _, err := ctlr.webhooks.Replay(currentUserID(c), deliveryID); err != nil {
+		ctlr.logger.Error("Error replaying webhook delivery", "deliveryID", deliveryID, "error", err)
+		return ctlr.renderWebhooks(c, http.StatusInternalServerError, map[string]interface{}{
+			"ErrorMessage": "Failed to replay delivery.",
+		})
+	}
+	return c.Redirect(http.StatusSeeOther, "/webhooks")
+}
+
+func (ctlr *RTOController) renderWebhooks(c echo.Context, status int, data map[string]interface{}) error {
+	hooks, err := ctlr.service.GetWebhooks(currentUserID(c))
+	if err != nil {
+		ctlr.logger.Error("Error listing webhooks", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load webhooks.")
+	}
+
+	views := make([]webhookView, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveries, err := ctlr.service.GetWebhookDeliveries(currentUserID(c), int(hook.ID), recentDeliveries)
+		if err != nil {
+			ctlr.logger.Error("Error listing webhook deliveries", "webhookID", hook.ID, "error", err)
+		}
+		views = append(views, webhookView{Webhook: hook, Deliveries: deliveries})
+	}
+
+	data["Webhooks"] = views
+	data["ChangeKinds"] = types.ChangeKinds
+	return c.Render(status, "webhooks.html", data)
+}
@@ -0,0 +1,158 @@
+// Package mail renders reminder emails from templates and sends them over SMTP.
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// dialTimeout bounds how long connecting to the SMTP server may take
+const dialTimeout = 10 * time.Second
+
+// Config describes the SMTP server and the sender address
+type Config struct {
+	Host     string
+	Port     int
+	Username string // no authentication when empty
+	Password string
+	From     string
+}
+
+// Mailer sends reminders through one SMTP server
+type Mailer struct {
+	config    Config
+	templates map[string]*template.Template // by reminder kind
+}
+
+var funcs = template.FuncMap{
+	"days":  func(n float64) string { return plural(n, "day") },
+	"weeks": func(n float64) string { return plural(n, "week") },
+	"date":  func(t time.Time) string { return t.Format("Mon Jan 2") },
+	"day":   func(t time.Time) string { return t.Format("Monday, Jan 2") },
+}
+
+// New parses the templates for every reminder kind
+func New(config Config) (*Mailer, error) {
+	m := &Mailer{config: config, templates: make(map[string]*template.Template)}
+	for _, kind := range []string{types.ReminderBehind, types.ReminderUnlogged, types.ReminderVacation, types.ReminderTest} {
+		tmpl, err := template.New(kind).Funcs(funcs).ParseFS(templateFiles, "templates/"+kind+".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		m.templates[kind] = tmpl
+	}
+	return m, nil
+}
+
+// Render fills in the subject and body templates for the reminder's kind
+func (m *Mailer) Render(reminder types.Reminder) (string, string, error) {
+	tmpl, ok := m.templates[reminder.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for %q reminders", reminder.Kind)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", reminder); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", reminder); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimLeft(body.String(), "\n"), nil
+}
+
+// Send delivers a plain text message, upgrading to TLS when the server offers STARTTLS
+func (m *Mailer) Send(to, subject, body string) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return err
+		}
+	}
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	from := m.config.From
+	if address, err := netmail.ParseAddress(from); err == nil {
+		from = address.Address
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.message(to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message builds the headers and body, with CRLF line endings
+func (m *Mailer) message(to, subject, body string) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", messageID(), m.config.Host)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(msg.String())
+}
+
+// plural writes 1.5 days, 1 day, 2 weeks
+func plural(n float64, unit string) string {
+	text := strconv.FormatFloat(n, 'f', -1, 64)
+	if n != 1 {
+		unit += "s"
+	}
+	return text + " " + unit
+}
+
+func messageID() string {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,140 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// sink is a minimal SMTP server that accepts one message
+type sink struct {
+	listener net.Listener
+	from     string
+	rcpt     string
+	data     chan string
+}
+
+func newSink(t *testing.T) *sink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &sink{listener: listener, data: make(chan string, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *sink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *sink) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 sink")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.rcpt = line
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data <- data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	m, err := New(Config{})
+	assert.NoError(t, err)
+	user := types.User{Username: "ann"}
+
+	subject, body, err := m.Render(types.Reminder{Kind: types.ReminderBehind, User: user, BehindDays: 1.5, TargetDays: 2.5, WeeksLeft: 4, PeriodName: "Q4 2026"})
+	assert.NoError(t, err)
+	assert.Equal(t, "You're 1.5 days behind target with 4 weeks left", subject)
+	assert.Contains(t, body, "target of 2.5 days a week in the office for Q4 2026")
+
+	subject, _, err = m.Render(types.Reminder{
+		Kind:          types.ReminderVacation,
+		User:          user,
+		VacationStart: time.Date(2026, time.October, 26, 0, 0, 0, 0, time.UTC),
+		VacationEnd:   time.Date(2026, time.October, 30, 0, 0, 0, 0, time.UTC),
+		VacationDays:  5,
+		HasPTO:        true,
+		PTOBalance:    3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Vacation starts Monday, Oct 26 — PTO balance after: 3 days", subject)
+
+	subject, body, err = m.Render(types.Reminder{Kind: types.ReminderUnlogged, User: user, Missing: []time.Time{time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "You haven't logged last week", subject)
+	assert.Contains(t, body, "1 day last week")
+	assert.Contains(t, body, "- Wed Oct 14")
+
+	_, _, err = m.Render(types.Reminder{Kind: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestSend(t *testing.T) {
+	s := newSink(t)
+	m, err := New(Config{Host: "127.0.0.1", Port: s.port(), From: "RTO <rto@example.com>"})
+	assert.NoError(t, err)
+
+	err = m.Send("ann@example.com", "Vacation starts Monday — PTO balance after: 3 days", "Hi ann,\n\nEnjoy.\n")
+
+	assert.NoError(t, err)
+	data := <-s.data
+	assert.Equal(t, "MAIL FROM:<rto@example.com>", strings.SplitN(s.from, " BODY", 2)[0])
+	assert.Equal(t, "RCPT TO:<ann@example.com>", s.rcpt)
+	assert.Contains(t, data, "To: ann@example.com\r\n")
+	assert.Contains(t, data, "Subject: =?utf-8?q?")
+	assert.Contains(t, data, "\r\n\r\nHi ann,\r\n\r\nEnjoy.\r\n")
+}
+
+func TestSend_Unreachable(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m, _ := New(Config{Host: "127.0.0.1", Port: port, From: "rto@example.com"})
+
+	assert.Error(t, m.Send("ann@example.com", "subject", "body"), "nothing listens on "+strconv.Itoa(port))
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm/clause"
+)
+
+// GetCompanyRequests returns everyone's requests for company between the dates
+func (r *CompanyRepositorySQLite) GetCompanyRequests(start, end time.Time) ([]types.CompanyRequest, error) {
+	var requests []types.CompanyRequest
+	result := r.db.Where("date BETWEEN ? AND ?", start, end).Order("date ASC").Find(&requests)
+	return requests, result.Error
+}
+
+// AddCompanyRequest stores the request; asking twice for the same day is a no-op
+func (r *CompanyRepositorySQLite) AddCompanyRequest(request types.CompanyRequest) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&request)
+	return result.Error
+}
+
+func (r *CompanyRepositorySQLite) DeleteCompanyRequest(userID int, date time.Time) error {
+	result := r.db.Where("user_id = ? AND date = ?", userID, date).Delete(&types.CompanyRequest{})
+	return result.Error
+}
+
+// DeleteCompanyRequestsBefore removes every request for a day before date
+func (r *CompanyRepositorySQLite) DeleteCompanyRequestsBefore(date time.Time) (int64, error) {
+	result := r.db.Where("date < ?", date).Delete(&types.CompanyRequest{})
+	return result.RowsAffected, result.Error
+}
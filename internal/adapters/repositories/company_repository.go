@@ -0,0 +1,24 @@
+//go:generate mockery --name CompanyRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type CompanyRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type CompanyRepository interface {
+	GetCompanyRequests(start, end time.Time) ([]types.CompanyRequest, error)
+	AddCompanyRequest(request types.CompanyRequest) error
+	DeleteCompanyRequest(userID int, date time.Time) error
+	DeleteCompanyRequestsBefore(date time.Time) (int64, error)
+}
+
+func NewCompanyRepositorySQLite(db *gorm.DB) CompanyRepository {
+	return &CompanyRepositorySQLite{db: db}
+}
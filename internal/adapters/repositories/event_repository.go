@@ -12,19 +12,23 @@ type EventRepositorySQLite struct {
 	db *gorm.DB
 }
 
+// EventRepository reads and writes one user's events. Holidays belong to no
+// one and are visible to every user.
 type EventRepository interface {
-	GetAllEvents() ([]types.Event, error)
+	GetAllEvents(userID int) ([]types.Event, error)
+	GetEventsForAllUsers() ([]types.Event, error)
 	AddEvent(event types.Event) error
 	UpdateEvent(event types.Event) error
-	DeleteEvent(eventID int) error
-	GetEventByDate(date time.Time) (types.Event, error)
-	GetEventByID(eventID int) (types.Event, error)
-	GetEventsByType(eventType string) ([]types.Event, error)
-	GetEventByDateAndType(date time.Time, eventType string) (types.Event, error)
-	GetEventsByDate(date time.Time) ([]types.Event, error)
-	GetEventsByTypeBetween(eventType string, start, end time.Time) ([]types.Event, error)
-	GetEventsBetweenDates(start, end time.Time) ([]types.Event, error)
-	GetEventByDateAndTypeBetween(eventType string, start, end time.Time) (types.Event, error)
+	DeleteEvent(userID int, eventID int) error
+	GetEventByDate(userID int, date time.Time) (types.Event, error)
+	GetEventByID(userID int, eventID int) (types.Event, error)
+	GetEventsByType(userID int, eventType string) ([]types.Event, error)
+	GetEventByDateAndType(userID int, date time.Time, eventType string) (types.Event, error)
+	GetEventsByDate(userID int, date time.Time) ([]types.Event, error)
+	GetEventsByTypeBetween(userID int, eventType string, start, end time.Time) ([]types.Event, error)
+	GetEventsBetweenDates(userID int, start, end time.Time) ([]types.Event, error)
+	GetEventByDateAndTypeBetween(userID int, eventType string, start, end time.Time) (types.Event, error)
+	QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error)
 }
 
 func NewEventRepositorySQLite(db *gorm.DB) EventRepository {
@@ -1,14 +1,28 @@
 package repository
 
 import (
+	"strings"
 	"time"
 
 	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
 )
 
-func (r *EventRepositorySQLite) GetAllEvents() ([]types.Event, error) {
+// visibleTo limits a query to the user's own events and the shared holidays
+func (r *EventRepositorySQLite) visibleTo(userID int) *gorm.DB {
+	return r.db.Where("(user_id = ? OR type = ?)", userID, "holiday")
+}
+
+func (r *EventRepositorySQLite) GetAllEvents(userID int) ([]types.Event, error) {
+	var events []types.Event
+	result := r.visibleTo(userID).Order("date ASC").Find(&events)
+	return events, result.Error
+}
+
+// GetEventsForAllUsers returns every stored event regardless of owner, for maintenance
+func (r *EventRepositorySQLite) GetEventsForAllUsers() ([]types.Event, error) {
 	var events []types.Event
-	result := r.db.Order("date ASC").Find(&events)
+	result := r.db.Order("date ASC").Order("id ASC").Find(&events)
 	return events, result.Error
 }
 
@@ -22,63 +36,124 @@ func (r *EventRepositorySQLite) UpdateEvent(event types.Event) error {
 	return result.Error
 }
 
-func (r *EventRepositorySQLite) DeleteEvent(eventID int) error {
+func (r *EventRepositorySQLite) DeleteEvent(userID int, eventID int) error {
 
-	result := r.db.Delete(&types.Event{}, eventID)
+	result := r.visibleTo(userID).Delete(&types.Event{}, eventID)
 	return result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventByDate(date time.Time) (types.Event, error) {
+func (r *EventRepositorySQLite) GetEventByDate(userID int, date time.Time) (types.Event, error) {
 	var event types.Event
-	result := r.db.Where("date = ?", date).First(&event)
+	result := r.visibleTo(userID).Where("date = ?", date).First(&event)
 	return event, result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventByID(eventID int) (types.Event, error) {
+func (r *EventRepositorySQLite) GetEventByID(userID int, eventID int) (types.Event, error) {
 	var event types.Event
-	result := r.db.First(&event, eventID)
+	result := r.visibleTo(userID).First(&event, eventID)
 	return event, result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventsByType(eventType string) ([]types.Event, error) {
+func (r *EventRepositorySQLite) GetEventsByType(userID int, eventType string) ([]types.Event, error) {
 	var events []types.Event
-	result := r.db.Where("type = ?", eventType).Order("date ASC").Find(&events)
+	result := r.visibleTo(userID).Where("type = ?", eventType).Order("date ASC").Find(&events)
 	return events, result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventsByDate(date time.Time) ([]types.Event, error) {
+func (r *EventRepositorySQLite) GetEventsByDate(userID int, date time.Time) ([]types.Event, error) {
 	var events []types.Event
-	result := r.db.Where("date = ?", date).Order("date ASC").Find(&events)
+	result := r.visibleTo(userID).Where("date = ?", date).Order("date ASC").Find(&events)
 	return events, result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventByDateAndType(date time.Time, eventType string) (types.Event, error) {
+func (r *EventRepositorySQLite) GetEventByDateAndType(userID int, date time.Time, eventType string) (types.Event, error) {
 	var event types.Event
-	result := r.db.Where("date = ? AND type = ?", date, eventType).First(&event)
+	result := r.visibleTo(userID).Where("date = ? AND type = ?", date, eventType).First(&event)
 	return event, result.Error
 }
 
-func (r *EventRepositorySQLite) GetEventsByTypeBetween(eventType string, start, end time.Time) ([]types.Event, error) {
+func (r *EventRepositorySQLite) GetEventsByTypeBetween(userID int, eventType string, start, end time.Time) ([]types.Event, error) {
 	var events []types.Event
-	result := r.db.Where("type = ? AND date BETWEEN ? AND ?", eventType, start, end).
+	result := r.visibleTo(userID).Where("type = ? AND date BETWEEN ? AND ?", eventType, start, end).
 		Order("date ASC").
 		Find(&events)
 	return events, result.Error
 }
 
 // GetEventsBetweenDates returns all events that occur between the start and stop times.
-func (r *EventRepositorySQLite) GetEventsBetweenDates(start, end time.Time) ([]types.Event, error) {
+func (r *EventRepositorySQLite) GetEventsBetweenDates(userID int, start, end time.Time) ([]types.Event, error) {
 	var events []types.Event
-	result := r.db.Where("date BETWEEN ? AND ?", start, end).
+	result := r.visibleTo(userID).Where("date BETWEEN ? AND ?", start, end).
 		Order("date ASC").
 		Find(&events)
 	return events, result.Error
 }
 
 // GetEventByDateAndTypeBetween returns the first event of the given type that occurs between the start and stop times.
-func (r *EventRepositorySQLite) GetEventByDateAndTypeBetween(eventType string, start, end time.Time) (types.Event, error) {
+func (r *EventRepositorySQLite) GetEventByDateAndTypeBetween(userID int, eventType string, start, end time.Time) (types.Event, error) {
 	var event types.Event
-	result := r.db.Where("type = ? AND date BETWEEN ? AND ?", eventType, start, end).
+	result := r.visibleTo(userID).Where("type = ? AND date BETWEEN ? AND ?", eventType, start, end).
 		First(&event)
 	return event, result.Error
 }
+
+// eventSortColumns maps EventFilter.Sort fields to columns
+var eventSortColumns = map[string]string{
+	"date":        "date",
+	"type":        "type",
+	"description": "description",
+}
+
+// likeEscaper escapes LIKE wildcards so searches match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// QueryEvents returns the page of events matching the filter along with the total
+// number of matches before paging is applied.
+func (r *EventRepositorySQLite) QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error) {
+	query := r.visibleTo(userID).Model(&types.Event{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("date <= ?", filter.To)
+	}
+	if filter.InOffice != nil {
+		query = query.Where("type = ? AND is_in_office = ?", "attendance", *filter.InOffice)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Search)) + "%"
+		query = query.Where(`LOWER(description) LIKE ? ESCAPE '\'`, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	// Sort by the requested field; date then ID keeps pages stable
+	field, direction := filter.Sort, "ASC"
+	if strings.HasPrefix(field, "-") {
+		field, direction = field[1:], "DESC"
+	}
+	tieDirection := "ASC"
+	if field == "date" {
+		tieDirection = direction
+	} else if column, ok := eventSortColumns[field]; ok {
+		query = query.Order(column + " " + direction)
+	}
+	query = query.Order("date " + tieDirection).Order("id " + tieDirection)
+
+	var events []types.Event
+	result := query.Find(&events)
+	return events, total, result.Error
+}
@@ -0,0 +1,26 @@
+//go:generate mockery --name JobRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type JobRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type JobRepository interface {
+	GetJobs() ([]types.ScheduledJob, error)
+	GetJob(name string) (types.ScheduledJob, error)
+	AddJob(job types.ScheduledJob) error
+	SetSchedule(name, schedule string, nextRunAt *time.Time) error
+	ClaimJob(name, owner string, now, staleBefore time.Time, force bool) (bool, error)
+	FinishJob(job types.ScheduledJob) error
+}
+
+func NewJobRepositorySQLite(db *gorm.DB) JobRepository {
+	return &JobRepositorySQLite{db: db}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+func (r *JobRepositorySQLite) GetJobs() ([]types.ScheduledJob, error) {
+	var jobs []types.ScheduledJob
+	result := r.db.Order("name ASC").Find(&jobs)
+	return jobs, result.Error
+}
+
+func (r *JobRepositorySQLite) GetJob(name string) (types.ScheduledJob, error) {
+	var job types.ScheduledJob
+	result := r.db.Where("name = ?", name).First(&job)
+	return job, result.Error
+}
+
+func (r *JobRepositorySQLite) AddJob(job types.ScheduledJob) error {
+	result := r.db.Create(&job)
+	return result.Error
+}
+
+// SetSchedule changes a job's schedule and when it next runs
+func (r *JobRepositorySQLite) SetSchedule(name, schedule string, nextRunAt *time.Time) error {
+	result := r.db.Model(&types.ScheduledJob{}).Where("name = ?", name).
+		Updates(map[string]interface{}{"schedule": schedule, "next_run_at": nextRunAt})
+	return result.Error
+}
+
+// ClaimJob marks the job as running for owner when it is due (or force is
+// set) and no run is in progress. A run started before staleBefore is taken
+// to have died with its server. Only one caller can win the claim.
+func (r *JobRepositorySQLite) ClaimJob(name, owner string, now, staleBefore time.Time, force bool) (bool, error) {
+	query := r.db.Model(&types.ScheduledJob{}).
+		Where("name = ?", name).
+		Where("running_since IS NULL OR running_since < ?", staleBefore)
+	if !force {
+		query = query.Where("next_run_at IS NOT NULL AND next_run_at <= ?", now)
+	}
+	result := query.Updates(map[string]interface{}{"running_since": now, "running_owner": owner})
+	return result.RowsAffected == 1, result.Error
+}
+
+// FinishJob records the outcome of a run and releases the claim, as long as
+// owner still holds it
+func (r *JobRepositorySQLite) FinishJob(job types.ScheduledJob) error {
+	failures := gorm.Expr("failures")
+	if job.LastStatus == types.JobFailed {
+		failures = gorm.Expr("failures + 1")
+	}
+	result := r.db.Model(&types.ScheduledJob{}).
+		Where("name = ? AND running_owner = ?", job.Name, job.RunningOwner).
+		Updates(map[string]interface{}{
+			"next_run_at":   job.NextRunAt,
+			"last_run_at":   job.LastRunAt,
+			"last_duration": job.LastDuration,
+			"last_status":   job.LastStatus,
+			"last_result":   job.LastResult,
+			"last_error":    job.LastError,
+			"runs":          gorm.Expr("runs + 1"),
+			"failures":      failures,
+			"running_since": nil,
+			"running_owner": "",
+		})
+	return result.Error
+}
@@ -29,13 +29,13 @@ func (_m *EventRepository) AddEvent(event types.Event) error {
 	return r0
 }
 
-// DeleteEvent provides a mock function with given fields: eventID
-func (_m *EventRepository) DeleteEvent(eventID int) error {
-	ret := _m.Called(eventID)
+// DeleteEvent provides a mock function with given fields: userID, eventID
+func (_m *EventRepository) DeleteEvent(userID int, eventID int) error {
+	ret := _m.Called(userID, eventID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(eventID)
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, eventID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -43,13 +43,13 @@ func (_m *EventRepository) DeleteEvent(eventID int) error {
 	return r0
 }
 
-// GetAllEvents provides a mock function with given fields:
-func (_m *EventRepository) GetAllEvents() ([]types.Event, error) {
-	ret := _m.Called()
+// GetAllEvents provides a mock function with given fields: userID
+func (_m *EventRepository) GetAllEvents(userID int) ([]types.Event, error) {
+	ret := _m.Called(userID)
 
 	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func() []types.Event); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(int) []types.Event); ok {
+		r0 = rf(userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Event)
@@ -57,8 +57,8 @@ func (_m *EventRepository) GetAllEvents() ([]types.Event, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -66,20 +66,20 @@ func (_m *EventRepository) GetAllEvents() ([]types.Event, error) {
 	return r0, r1
 }
 
-// GetEventByDate provides a mock function with given fields: date
-func (_m *EventRepository) GetEventByDate(date time.Time) (types.Event, error) {
-	ret := _m.Called(date)
+// GetEventByDate provides a mock function with given fields: userID, date
+func (_m *EventRepository) GetEventByDate(userID int, date time.Time) (types.Event, error) {
+	ret := _m.Called(userID, date)
 
 	var r0 types.Event
-	if rf, ok := ret.Get(0).(func(time.Time) types.Event); ok {
-		r0 = rf(date)
+	if rf, ok := ret.Get(0).(func(int, time.Time) types.Event); ok {
+		r0 = rf(userID, date)
 	} else {
 		r0 = ret.Get(0).(types.Event)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
-		r1 = rf(date)
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -87,20 +87,20 @@ func (_m *EventRepository) GetEventByDate(date time.Time) (types.Event, error) {
 	return r0, r1
 }
 
-// GetEventByDateAndType provides a mock function with given fields: date, eventType
-func (_m *EventRepository) GetEventByDateAndType(date time.Time, eventType string) (types.Event, error) {
-	ret := _m.Called(date, eventType)
+// GetEventByDateAndType provides a mock function with given fields: userID, date, eventType
+func (_m *EventRepository) GetEventByDateAndType(userID int, date time.Time, eventType string) (types.Event, error) {
+	ret := _m.Called(userID, date, eventType)
 
 	var r0 types.Event
-	if rf, ok := ret.Get(0).(func(time.Time, string) types.Event); ok {
-		r0 = rf(date, eventType)
+	if rf, ok := ret.Get(0).(func(int, time.Time, string) types.Event); ok {
+		r0 = rf(userID, date, eventType)
 	} else {
 		r0 = ret.Get(0).(types.Event)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time, string) error); ok {
-		r1 = rf(date, eventType)
+	if rf, ok := ret.Get(1).(func(int, time.Time, string) error); ok {
+		r1 = rf(userID, date, eventType)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,20 +108,20 @@ func (_m *EventRepository) GetEventByDateAndType(date time.Time, eventType strin
 	return r0, r1
 }
 
-// GetEventByDateAndTypeBetween provides a mock function with given fields: eventType, start, end
-func (_m *EventRepository) GetEventByDateAndTypeBetween(eventType string, start time.Time, end time.Time) (types.Event, error) {
-	ret := _m.Called(eventType, start, end)
+// GetEventByDateAndTypeBetween provides a mock function with given fields: userID, eventType, start, end
+func (_m *EventRepository) GetEventByDateAndTypeBetween(userID int, eventType string, start time.Time, end time.Time) (types.Event, error) {
+	ret := _m.Called(userID, eventType, start, end)
 
 	var r0 types.Event
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) types.Event); ok {
-		r0 = rf(eventType, start, end)
+	if rf, ok := ret.Get(0).(func(int, string, time.Time, time.Time) types.Event); ok {
+		r0 = rf(userID, eventType, start, end)
 	} else {
 		r0 = ret.Get(0).(types.Event)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
-		r1 = rf(eventType, start, end)
+	if rf, ok := ret.Get(1).(func(int, string, time.Time, time.Time) error); ok {
+		r1 = rf(userID, eventType, start, end)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,20 +129,43 @@ func (_m *EventRepository) GetEventByDateAndTypeBetween(eventType string, start
 	return r0, r1
 }
 
-// GetEventByID provides a mock function with given fields: eventID
-func (_m *EventRepository) GetEventByID(eventID int) (types.Event, error) {
-	ret := _m.Called(eventID)
+// GetEventByID provides a mock function with given fields: userID, eventID
+func (_m *EventRepository) GetEventByID(userID int, eventID int) (types.Event, error) {
+	ret := _m.Called(userID, eventID)
 
 	var r0 types.Event
-	if rf, ok := ret.Get(0).(func(int) types.Event); ok {
-		r0 = rf(eventID)
+	if rf, ok := ret.Get(0).(func(int, int) types.Event); ok {
+		r0 = rf(userID, eventID)
 	} else {
 		r0 = ret.Get(0).(types.Event)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(eventID)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventsBetweenDates provides a mock function with given fields: userID, start, end
+func (_m *EventRepository) GetEventsBetweenDates(userID int, start time.Time, end time.Time) ([]types.Event, error) {
+	ret := _m.Called(userID, start, end)
+
+	var r0 []types.Event
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) []types.Event); ok {
+		r0 = rf(userID, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, start, end)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -150,13 +173,13 @@ func (_m *EventRepository) GetEventByID(eventID int) (types.Event, error) {
 	return r0, r1
 }
 
-// GetEventsBetweenDates provides a mock function with given fields: start, end
-func (_m *EventRepository) GetEventsBetweenDates(start time.Time, end time.Time) ([]types.Event, error) {
-	ret := _m.Called(start, end)
+// GetEventsByDate provides a mock function with given fields: userID, date
+func (_m *EventRepository) GetEventsByDate(userID int, date time.Time) ([]types.Event, error) {
+	ret := _m.Called(userID, date)
 
 	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []types.Event); ok {
-		r0 = rf(start, end)
+	if rf, ok := ret.Get(0).(func(int, time.Time) []types.Event); ok {
+		r0 = rf(userID, date)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Event)
@@ -164,8 +187,8 @@ func (_m *EventRepository) GetEventsBetweenDates(start time.Time, end time.Time)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
-		r1 = rf(start, end)
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -173,13 +196,13 @@ func (_m *EventRepository) GetEventsBetweenDates(start time.Time, end time.Time)
 	return r0, r1
 }
 
-// GetEventsByDate provides a mock function with given fields: date
-func (_m *EventRepository) GetEventsByDate(date time.Time) ([]types.Event, error) {
-	ret := _m.Called(date)
+// GetEventsByType provides a mock function with given fields: userID, eventType
+func (_m *EventRepository) GetEventsByType(userID int, eventType string) ([]types.Event, error) {
+	ret := _m.Called(userID, eventType)
 
 	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func(time.Time) []types.Event); ok {
-		r0 = rf(date)
+	if rf, ok := ret.Get(0).(func(int, string) []types.Event); ok {
+		r0 = rf(userID, eventType)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Event)
@@ -187,8 +210,8 @@ func (_m *EventRepository) GetEventsByDate(date time.Time) ([]types.Event, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
-		r1 = rf(date)
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, eventType)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -196,13 +219,13 @@ func (_m *EventRepository) GetEventsByDate(date time.Time) ([]types.Event, error
 	return r0, r1
 }
 
-// GetEventsByType provides a mock function with given fields: eventType
-func (_m *EventRepository) GetEventsByType(eventType string) ([]types.Event, error) {
-	ret := _m.Called(eventType)
+// GetEventsByTypeBetween provides a mock function with given fields: userID, eventType, start, end
+func (_m *EventRepository) GetEventsByTypeBetween(userID int, eventType string, start time.Time, end time.Time) ([]types.Event, error) {
+	ret := _m.Called(userID, eventType, start, end)
 
 	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func(string) []types.Event); ok {
-		r0 = rf(eventType)
+	if rf, ok := ret.Get(0).(func(int, string, time.Time, time.Time) []types.Event); ok {
+		r0 = rf(userID, eventType, start, end)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Event)
@@ -210,8 +233,8 @@ func (_m *EventRepository) GetEventsByType(eventType string) ([]types.Event, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(eventType)
+	if rf, ok := ret.Get(1).(func(int, string, time.Time, time.Time) error); ok {
+		r1 = rf(userID, eventType, start, end)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -219,13 +242,13 @@ func (_m *EventRepository) GetEventsByType(eventType string) ([]types.Event, err
 	return r0, r1
 }
 
-// GetEventsByTypeBetween provides a mock function with given fields: eventType, start, end
-func (_m *EventRepository) GetEventsByTypeBetween(eventType string, start time.Time, end time.Time) ([]types.Event, error) {
-	ret := _m.Called(eventType, start, end)
+// GetEventsForAllUsers provides a mock function with given fields:
+func (_m *EventRepository) GetEventsForAllUsers() ([]types.Event, error) {
+	ret := _m.Called()
 
 	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []types.Event); ok {
-		r0 = rf(eventType, start, end)
+	if rf, ok := ret.Get(0).(func() []types.Event); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]types.Event)
@@ -233,8 +256,8 @@ func (_m *EventRepository) GetEventsByTypeBetween(eventType string, start time.T
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
-		r1 = rf(eventType, start, end)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -242,6 +265,36 @@ func (_m *EventRepository) GetEventsByTypeBetween(eventType string, start time.T
 	return r0, r1
 }
 
+// QueryEvents provides a mock function with given fields: userID, filter
+func (_m *EventRepository) QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error) {
+	ret := _m.Called(userID, filter)
+
+	var r0 []types.Event
+	if rf, ok := ret.Get(0).(func(int, types.EventFilter) []types.Event); ok {
+		r0 = rf(userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Event)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(int, types.EventFilter) int64); ok {
+		r1 = rf(userID, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int, types.EventFilter) error); ok {
+		r2 = rf(userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // UpdateEvent provides a mock function with given fields: event
 func (_m *EventRepository) UpdateEvent(event types.Event) error {
 	ret := _m.Called(event)
@@ -13,20 +13,34 @@ type PreferenceRepository struct {
 	mock.Mock
 }
 
-// GetPreferences provides a mock function with given fields:
-func (_m *PreferenceRepository) GetPreferences() (types.Preferences, error) {
-	ret := _m.Called()
+// DeletePreferenceVersion provides a mock function with given fields: userID, versionID
+func (_m *PreferenceRepository) DeletePreferenceVersion(userID int, versionID int) error {
+	ret := _m.Called(userID, versionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, versionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPreferences provides a mock function with given fields: userID
+func (_m *PreferenceRepository) GetPreferences(userID int) (types.Preferences, error) {
+	ret := _m.Called(userID)
 
 	var r0 types.Preferences
-	if rf, ok := ret.Get(0).(func() types.Preferences); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(int) types.Preferences); ok {
+		r0 = rf(userID)
 	} else {
 		r0 = ret.Get(0).(types.Preferences)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -34,6 +48,20 @@ func (_m *PreferenceRepository) GetPreferences() (types.Preferences, error) {
 	return r0, r1
 }
 
+// SavePreferenceVersion provides a mock function with given fields: version
+func (_m *PreferenceRepository) SavePreferenceVersion(version types.PreferenceVersion) error {
+	ret := _m.Called(version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.PreferenceVersion) error); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePreferences provides a mock function with given fields: prefs
 func (_m *PreferenceRepository) UpdatePreferences(prefs types.Preferences) error {
 	ret := _m.Called(prefs)
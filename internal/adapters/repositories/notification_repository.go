@@ -0,0 +1,28 @@
+//go:generate mockery --name NotificationRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type NotificationRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type NotificationRepository interface {
+	GetNotificationSettings(userID int) (types.NotificationSettings, error)
+	SaveNotificationSettings(settings types.NotificationSettings) error
+	GetSubscribers() ([]types.NotificationSettings, error)
+
+	AddEmailDelivery(delivery types.EmailDelivery) (types.EmailDelivery, error)
+	GetEmailDeliveries(userID int, limit int) ([]types.EmailDelivery, error)
+	EmailSent(userID int, kind, key string) (bool, error)
+	DeleteEmailDeliveriesBefore(before time.Time) (int64, error)
+}
+
+func NewNotificationRepositorySQLite(db *gorm.DB) NotificationRepository {
+	return &NotificationRepositorySQLite{db: db}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+func (r *NotificationRepositorySQLite) GetNotificationSettings(userID int) (types.NotificationSettings, error) {
+	var settings types.NotificationSettings
+	result := r.db.Where("user_id = ?", userID).First(&settings)
+	return settings, result.Error
+}
+
+func (r *NotificationRepositorySQLite) SaveNotificationSettings(settings types.NotificationSettings) error {
+	result := r.db.Save(&settings)
+	return result.Error
+}
+
+// GetSubscribers returns the settings of every user with a reminder turned on
+func (r *NotificationRepositorySQLite) GetSubscribers() ([]types.NotificationSettings, error) {
+	var settings []types.NotificationSettings
+	result := r.db.Where("behind OR unlogged OR vacation").Order("user_id ASC").Find(&settings)
+	return settings, result.Error
+}
+
+// AddEmailDelivery logs a send attempt and returns it with its assigned ID
+func (r *NotificationRepositorySQLite) AddEmailDelivery(delivery types.EmailDelivery) (types.EmailDelivery, error) {
+	result := r.db.Create(&delivery)
+	return delivery, result.Error
+}
+
+// GetEmailDeliveries returns the user's most recent deliveries, newest first
+func (r *NotificationRepositorySQLite) GetEmailDeliveries(userID int, limit int) ([]types.EmailDelivery, error) {
+	var deliveries []types.EmailDelivery
+	result := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// EmailSent reports whether the reminder was already delivered successfully
+func (r *NotificationRepositorySQLite) EmailSent(userID int, kind, key string) (bool, error) {
+	var count int64
+	result := r.db.Model(&types.EmailDelivery{}).
+		Where("user_id = ? AND kind = ? AND key = ? AND success", userID, kind, key).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// DeleteEmailDeliveriesBefore removes delivery records created before the given time
+func (r *NotificationRepositorySQLite) DeleteEmailDeliveriesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&types.EmailDelivery{})
+	return result.RowsAffected, result.Error
+}
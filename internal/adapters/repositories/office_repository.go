@@ -0,0 +1,36 @@
+//go:generate mockery --name OfficeRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type OfficeRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type OfficeRepository interface {
+	GetAllOffices() ([]types.Office, error)
+	GetOfficeByID(officeID int) (types.Office, error)
+	AddOffice(office types.Office) (types.Office, error)
+	UpdateOffice(office types.Office) error
+	DeleteOffice(officeID int) error
+
+	GetResources(officeID int) ([]types.Resource, error)
+	AddResource(resource types.Resource) (types.Resource, error)
+	DeleteResource(officeID, resourceID int) (int64, error)
+
+	GetBookings(officeID int, start, end time.Time) ([]types.Booking, error)
+	GetUserBookings(userID int, start, end time.Time) ([]types.Booking, error)
+	AddBooking(booking types.Booking) (types.Booking, error)
+	AddDeskBooking(booking types.Booking, capacity int) (types.Booking, error)
+	DeleteBooking(userID, bookingID int) (int64, error)
+	DeleteUserBookings(userID int, date time.Time) (int64, error)
+}
+
+func NewOfficeRepositorySQLite(db *gorm.DB) OfficeRepository {
+	return &OfficeRepositorySQLite{db: db}
+}
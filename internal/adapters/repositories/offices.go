@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyBooked is returned when the resource is booked on that day
+	ErrAlreadyBooked = errors.New("already booked")
+	// ErrOfficeFull is returned by AddDeskBooking when the office is at capacity
+	ErrOfficeFull = errors.New("office is full")
+)
+
+func (r *OfficeRepositorySQLite) GetAllOffices() ([]types.Office, error) {
+	var offices []types.Office
+	result := r.db.Order("name ASC").Find(&offices)
+	return offices, result.Error
+}
+
+func (r *OfficeRepositorySQLite) GetOfficeByID(officeID int) (types.Office, error) {
+	var office types.Office
+	result := r.db.First(&office, officeID)
+	return office, result.Error
+}
+
+// AddOffice stores a new office and returns it with its assigned ID
+func (r *OfficeRepositorySQLite) AddOffice(office types.Office) (types.Office, error) {
+	result := r.db.Create(&office)
+	return office, result.Error
+}
+
+func (r *OfficeRepositorySQLite) UpdateOffice(office types.Office) error {
+	result := r.db.Save(&office)
+	return result.Error
+}
+
+// DeleteOffice removes an office along with its desks, rooms and bookings
+func (r *OfficeRepositorySQLite) DeleteOffice(officeID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("office_id = ?", officeID).Delete(&types.Booking{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("office_id = ?", officeID).Delete(&types.Resource{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&types.Office{}, officeID).Error
+	})
+}
+
+func (r *OfficeRepositorySQLite) GetResources(officeID int) ([]types.Resource, error) {
+	var resources []types.Resource
+	result := r.db.Where("office_id = ?", officeID).Order("kind ASC, name ASC").Find(&resources)
+	return resources, result.Error
+}
+
+// AddResource stores a new desk or room and returns it with its assigned ID
+func (r *OfficeRepositorySQLite) AddResource(resource types.Resource) (types.Resource, error) {
+	result := r.db.Create(&resource)
+	return resource, result.Error
+}
+
+// DeleteResource removes a desk or room of the office along with its
+// bookings and returns how many resources went
+func (r *OfficeRepositorySQLite) DeleteResource(officeID, resourceID int) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND office_id = ?", resourceID, officeID).Delete(&types.Resource{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return tx.Where("resource_id = ?", resourceID).Delete(&types.Booking{}).Error
+	})
+	return deleted, err
+}
+
+// GetBookings returns the office's bookings between the dates
+func (r *OfficeRepositorySQLite) GetBookings(officeID int, start, end time.Time) ([]types.Booking, error) {
+	var bookings []types.Booking
+	result := r.db.Where("office_id = ? AND date BETWEEN ? AND ?", officeID, start, end).
+		Order("date ASC, id ASC").
+		Find(&bookings)
+	return bookings, result.Error
+}
+
+// GetUserBookings returns the user's bookings in any office between the dates
+func (r *OfficeRepositorySQLite) GetUserBookings(userID int, start, end time.Time) ([]types.Booking, error) {
+	var bookings []types.Booking
+	result := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, start, end).
+		Order("date ASC, id ASC").
+		Find(&bookings)
+	return bookings, result.Error
+}
+
+// AddBooking stores a new booking and returns it with its assigned ID. The
+// unique index on resource and date rejects a second booking of the same
+// resource with ErrAlreadyBooked.
+func (r *OfficeRepositorySQLite) AddBooking(booking types.Booking) (types.Booking, error) {
+	err := createBooking(r.db, &booking)
+	return booking, err
+}
+
+// AddDeskBooking is AddBooking for a desk, refused with ErrOfficeFull when the
+// office already has capacity desks booked that day. As with AddFirstUser the
+// insert comes before the count in one transaction, so concurrent bookings
+// take turns and the last one over the limit is rolled back.
+func (r *OfficeRepositorySQLite) AddDeskBooking(booking types.Booking, capacity int) (types.Booking, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createBooking(tx, &booking); err != nil {
+			return err
+		}
+		var desks int64
+		err := tx.Model(&types.Booking{}).
+			Where("office_id = ? AND date = ? AND kind = ?", booking.OfficeID, booking.Date, types.ResourceDesk).
+			Count(&desks).Error
+		if err != nil {
+			return err
+		}
+		if desks > int64(capacity) {
+			return ErrOfficeFull
+		}
+		return nil
+	})
+	return booking, err
+}
+
+// createBooking inserts the booking, reporting a clash on the resource and
+// date index as ErrAlreadyBooked
+func createBooking(db *gorm.DB, booking *types.Booking) error {
+	err := db.Create(booking).Error
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok && err != nil {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return ErrAlreadyBooked
+		}
+	}
+	return err
+}
+
+// DeleteBooking removes one of the user's bookings and returns how many rows went
+func (r *OfficeRepositorySQLite) DeleteBooking(userID, bookingID int) (int64, error) {
+	result := r.db.Where("id = ? AND user_id = ?", bookingID, userID).Delete(&types.Booking{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteUserBookings releases everything the user booked on the date and
+// returns how many bookings went
+func (r *OfficeRepositorySQLite) DeleteUserBookings(userID int, date time.Time) (int64, error) {
+	result := r.db.Where("user_id = ? AND date = ?", userID, date).Delete(&types.Booking{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,31 @@
+//go:generate mockery --name PeriodRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type PeriodRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type PeriodRepository interface {
+	GetAllPeriods() ([]types.Period, error)
+	GetPeriodByID(periodID int) (types.Period, error)
+	GetPeriodForDate(date time.Time) (types.Period, error)
+	AddPeriod(period types.Period) (types.Period, error)
+	UpdatePeriod(period types.Period) error
+	DeletePeriod(periodID int) error
+	SetPeriodClosed(periodID int, closedAt *time.Time) error
+
+	SaveArchives(periodID int, archives []types.PeriodArchive) error
+	DeleteArchives(periodID int) error
+	GetArchive(periodID, userID int) (types.PeriodArchive, error)
+}
+
+func NewPeriodRepositorySQLite(db *gorm.DB) PeriodRepository {
+	return &PeriodRepositorySQLite{db: db}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+func (r *PeriodRepositorySQLite) GetAllPeriods() ([]types.Period, error) {
+	var periods []types.Period
+	result := r.db.Order("start_date ASC").Find(&periods)
+	return periods, result.Error
+}
+
+func (r *PeriodRepositorySQLite) GetPeriodByID(periodID int) (types.Period, error) {
+	var period types.Period
+	result := r.db.First(&period, periodID)
+	return period, result.Error
+}
+
+// GetPeriodForDate returns the period that contains the given date
+func (r *PeriodRepositorySQLite) GetPeriodForDate(date time.Time) (types.Period, error) {
+	var period types.Period
+	result := r.db.Where("start_date <= ? AND end_date >= ?", date, date).
+		Order("start_date DESC").
+		First(&period)
+	return period, result.Error
+}
+
+// AddPeriod stores a new period and returns it with its assigned ID
+func (r *PeriodRepositorySQLite) AddPeriod(period types.Period) (types.Period, error) {
+	result := r.db.Create(&period)
+	return period, result.Error
+}
+
+func (r *PeriodRepositorySQLite) UpdatePeriod(period types.Period) error {
+	result := r.db.Save(&period)
+	return result.Error
+}
+
+func (r *PeriodRepositorySQLite) DeletePeriod(periodID int) error {
+	result := r.db.Delete(&types.Period{}, periodID)
+	return result.Error
+}
+
+// SetPeriodClosed closes the period at closedAt, or reopens it when nil
+func (r *PeriodRepositorySQLite) SetPeriodClosed(periodID int, closedAt *time.Time) error {
+	result := r.db.Model(&types.Period{}).Where("id = ?", periodID).Update("closed_at", closedAt)
+	return result.Error
+}
+
+// SaveArchives replaces the period's archived stats with archives
+func (r *PeriodRepositorySQLite) SaveArchives(periodID int, archives []types.PeriodArchive) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("period_id = ?", periodID).Delete(&types.PeriodArchive{}).Error; err != nil {
+			return err
+		}
+		if len(archives) == 0 {
+			return nil
+		}
+		return tx.Create(&archives).Error
+	})
+}
+
+func (r *PeriodRepositorySQLite) DeleteArchives(periodID int) error {
+	result := r.db.Where("period_id = ?", periodID).Delete(&types.PeriodArchive{})
+	return result.Error
+}
+
+func (r *PeriodRepositorySQLite) GetArchive(periodID, userID int) (types.PeriodArchive, error) {
+	var archive types.PeriodArchive
+	result := r.db.Where("period_id = ? AND user_id = ?", periodID, userID).First(&archive)
+	return archive, result.Error
+}
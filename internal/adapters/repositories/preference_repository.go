@@ -15,7 +15,9 @@ func NewPreferenceRepositorySQLite(db *gorm.DB) PreferenceRepository {
 }
 
 type PreferenceRepository interface {
-	GetPreferences() (types.Preferences, error)
+	GetPreferences(userID int) (types.Preferences, error)
 	UpdatePreferences(prefs types.Preferences) error
+	SavePreferenceVersion(version types.PreferenceVersion) error
+	DeletePreferenceVersion(userID, versionID int) error
 	// Add other methods as needed
 }
@@ -2,11 +2,19 @@ package repository
 
 import (
 	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (r *PreferenceRepositorySQLite) GetPreferences() (types.Preferences, error) {
+// GetPreferences returns the user's preferences with their dated versions,
+// or gorm.ErrRecordNotFound when none are saved yet
+func (r *PreferenceRepositorySQLite) GetPreferences(userID int) (types.Preferences, error) {
 	var prefs types.Preferences
-	result := r.db.First(&prefs)
+	result := r.db.Where("user_id = ?", userID).First(&prefs)
+	if result.Error != nil {
+		return prefs, result.Error
+	}
+	result = r.db.Where("user_id = ?", userID).Order("effective_from ASC").Find(&prefs.Versions)
 	return prefs, result.Error
 }
 
@@ -14,3 +22,23 @@ func (r *PreferenceRepositorySQLite) UpdatePreferences(prefs types.Preferences)
 	result := r.db.Save(&prefs)
 	return result.Error
 }
+
+// SavePreferenceVersion stores the version, replacing the user's version for
+// the same date if there is one
+func (r *PreferenceRepositorySQLite) SavePreferenceVersion(version types.PreferenceVersion) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "effective_from"}},
+		DoUpdates: clause.AssignmentColumns([]string{"default_days", "target_days", "calculation_method"}),
+	}).Create(&version)
+	return result.Error
+}
+
+// DeletePreferenceVersion removes one of the user's versions, returning
+// gorm.ErrRecordNotFound when the user has no such version
+func (r *PreferenceRepositorySQLite) DeletePreferenceVersion(userID, versionID int) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&types.PreferenceVersion{}, versionID)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
@@ -0,0 +1,28 @@
+//go:generate mockery --name SessionRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type SessionRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type SessionRepository interface {
+	GetSessionByHash(hash string) (types.Session, error)
+	GetSessionsByUser(userID int, now time.Time) ([]types.Session, error)
+	AddSession(session types.Session) (types.Session, error)
+	UpdateSession(session types.Session) error
+	TouchSession(sessionID uint, lastSeen time.Time) error
+	DeleteSession(userID int, sessionID uint) (int64, error)
+	DeleteSessionsByUser(userID int) (int64, error)
+	DeleteExpiredSessions(now time.Time) (int64, error)
+}
+
+func NewSessionRepositorySQLite(db *gorm.DB) SessionRepository {
+	return &SessionRepositorySQLite{db: db}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+func (r *SessionRepositorySQLite) GetSessionByHash(hash string) (types.Session, error) {
+	var session types.Session
+	result := r.db.Where("token_hash = ?", hash).First(&session)
+	return session, result.Error
+}
+
+// GetSessionsByUser returns the user's unexpired sessions, most recently used first
+func (r *SessionRepositorySQLite) GetSessionsByUser(userID int, now time.Time) ([]types.Session, error) {
+	var sessions []types.Session
+	result := r.db.Where("user_id = ? AND expires_at > ?", userID, now).Order("last_seen_at DESC").Find(&sessions)
+	return sessions, result.Error
+}
+
+// AddSession stores a new session and returns it with its assigned ID
+func (r *SessionRepositorySQLite) AddSession(session types.Session) (types.Session, error) {
+	result := r.db.Create(&session)
+	return session, result.Error
+}
+
+func (r *SessionRepositorySQLite) UpdateSession(session types.Session) error {
+	result := r.db.Save(&session)
+	return result.Error
+}
+
+// TouchSession records activity without rewriting the session values
+func (r *SessionRepositorySQLite) TouchSession(sessionID uint, lastSeen time.Time) error {
+	result := r.db.Model(&types.Session{}).Where("id = ?", sessionID).Update("last_seen_at", lastSeen)
+	return result.Error
+}
+
+// DeleteSession removes one of the user's sessions and returns how many rows went
+func (r *SessionRepositorySQLite) DeleteSession(userID int, sessionID uint) (int64, error) {
+	result := r.db.Where("id = ? AND user_id = ?", sessionID, userID).Delete(&types.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteSessionsByUser signs the user out everywhere
+func (r *SessionRepositorySQLite) DeleteSessionsByUser(userID int) (int64, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&types.Session{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *SessionRepositorySQLite) DeleteExpiredSessions(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&types.Session{})
+	return result.RowsAffected, result.Error
+}
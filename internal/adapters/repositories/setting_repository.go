@@ -0,0 +1,20 @@
+//go:generate mockery --name SettingRepository
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+type SettingRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type SettingRepository interface {
+	// GetSetting returns gorm.ErrRecordNotFound when the key has never been set
+	GetSetting(key string) (string, error)
+	SetSetting(key string, value string) error
+}
+
+func NewSettingRepositorySQLite(db *gorm.DB) SettingRepository {
+	return &SettingRepositorySQLite{db: db}
+}
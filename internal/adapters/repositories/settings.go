@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+func (r *SettingRepositorySQLite) GetSetting(key string) (string, error) {
+	var setting types.Setting
+	result := r.db.Where("key = ?", key).First(&setting)
+	return setting.Value, result.Error
+}
+
+// SetSetting creates or replaces a setting
+func (r *SettingRepositorySQLite) SetSetting(key string, value string) error {
+	result := r.db.Save(&types.Setting{Key: key, Value: value})
+	return result.Error
+}
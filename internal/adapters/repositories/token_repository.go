@@ -0,0 +1,23 @@
+//go:generate mockery --name TokenRepository
+package repository
+
+import (
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type TokenRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type TokenRepository interface {
+	GetAllTokens(userID int) ([]types.APIToken, error)
+	GetTokenByID(tokenID int) (types.APIToken, error)
+	GetTokenByHash(hash string) (types.APIToken, error)
+	AddToken(token types.APIToken) (types.APIToken, error)
+	UpdateToken(token types.APIToken) error
+}
+
+func NewTokenRepositorySQLite(db *gorm.DB) TokenRepository {
+	return &TokenRepositorySQLite{db: db}
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+func (r *TokenRepositorySQLite) GetAllTokens(userID int) ([]types.APIToken, error) {
+	var tokens []types.APIToken
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens)
+	return tokens, result.Error
+}
+
+func (r *TokenRepositorySQLite) GetTokenByID(tokenID int) (types.APIToken, error) {
+	var token types.APIToken
+	result := r.db.First(&token, tokenID)
+	return token, result.Error
+}
+
+func (r *TokenRepositorySQLite) GetTokenByHash(hash string) (types.APIToken, error) {
+	var token types.APIToken
+	result := r.db.Where("token_hash = ?", hash).First(&token)
+	return token, result.Error
+}
+
+// AddToken stores a new token and returns it with its assigned ID
+func (r *TokenRepositorySQLite) AddToken(token types.APIToken) (types.APIToken, error) {
+	result := r.db.Create(&token)
+	return token, result.Error
+}
+
+func (r *TokenRepositorySQLite) UpdateToken(token types.APIToken) error {
+	result := r.db.Save(&token)
+	return result.Error
+}
@@ -0,0 +1,34 @@
+//go:generate mockery --name UserRepository
+package repository
+
+import (
+	"errors"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// ErrNotFirstUser is returned by AddFirstUser when an account already exists
+var ErrNotFirstUser = errors.New("an account already exists")
+
+type UserRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type UserRepository interface {
+	GetAllUsers() ([]types.User, error)
+	GetUserByID(userID int) (types.User, error)
+	GetUserByUsername(username string) (types.User, error)
+	GetUserByEmail(email string) (types.User, error)
+	GetUserByExternalID(externalID string) (types.User, error)
+	GetUsersByManager(managerID int) ([]types.User, error)
+	CountUsers() (int64, error)
+	AddUser(user types.User) (types.User, error)
+	AddFirstUser(user types.User) (types.User, error)
+	UpdateUser(user types.User) error
+	AssignUnownedData(userID int) (int64, error)
+}
+
+func NewUserRepositorySQLite(db *gorm.DB) UserRepository {
+	return &UserRepositorySQLite{db: db}
+}
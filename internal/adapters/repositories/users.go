@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+func (r *UserRepositorySQLite) GetAllUsers() ([]types.User, error) {
+	var users []types.User
+	result := r.db.Order("username").Find(&users)
+	return users, result.Error
+}
+
+func (r *UserRepositorySQLite) GetUserByID(userID int) (types.User, error) {
+	var user types.User
+	result := r.db.First(&user, userID)
+	return user, result.Error
+}
+
+func (r *UserRepositorySQLite) GetUserByUsername(username string) (types.User, error) {
+	var user types.User
+	result := r.db.Where("username = ?", username).First(&user)
+	return user, result.Error
+}
+
+func (r *UserRepositorySQLite) GetUserByEmail(email string) (types.User, error) {
+	var user types.User
+	result := r.db.Where("email = ?", email).First(&user)
+	return user, result.Error
+}
+
+func (r *UserRepositorySQLite) GetUserByExternalID(externalID string) (types.User, error) {
+	var user types.User
+	result := r.db.Where("external_id = ?", externalID).First(&user)
+	return user, result.Error
+}
+
+// GetUsersByManager returns the users who report directly to the manager
+func (r *UserRepositorySQLite) GetUsersByManager(managerID int) ([]types.User, error) {
+	var users []types.User
+	result := r.db.Where("manager_id = ?", managerID).Order("username").Find(&users)
+	return users, result.Error
+}
+
+func (r *UserRepositorySQLite) CountUsers() (int64, error) {
+	var count int64
+	result := r.db.Model(&types.User{}).Count(&count)
+	return count, result.Error
+}
+
+// AddUser stores a new user and returns it with its assigned ID
+func (r *UserRepositorySQLite) AddUser(user types.User) (types.User, error) {
+	result := r.db.Create(&user)
+	return user, result.Error
+}
+
+// AddFirstUser stores the user only when there is no other account, returning
+// ErrNotFirstUser otherwise. The insert comes before the count in one
+// transaction, so SQLite holds its write lock while counting and a second
+// first user waits for the first and then finds it.
+func (r *UserRepositorySQLite) AddFirstUser(user types.User) (types.User, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&types.User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 1 {
+			return ErrNotFirstUser
+		}
+		return nil
+	})
+	return user, err
+}
+
+func (r *UserRepositorySQLite) UpdateUser(user types.User) error {
+	result := r.db.Save(&user)
+	return result.Error
+}
+
+// AssignUnownedData gives the user every event, preferences row, API token and
+// webhook that has no owner, as left behind by single-user installs. Holidays
+// stay shared. It returns the number of rows assigned.
+func (r *UserRepositorySQLite) AssignUnownedData(userID int) (int64, error) {
+	var assigned int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&types.Event{}).
+			Where("user_id = ? AND type <> ?", 0, "holiday").
+			Update("user_id", userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		assigned += result.RowsAffected
+
+		// Keep the user's own preferences if they already saved some
+		var prefs int64
+		if err := tx.Model(&types.Preferences{}).Where("user_id = ?", userID).Count(&prefs).Error; err != nil {
+			return err
+		}
+		if prefs == 0 {
+			var orphan types.Preferences
+			err := tx.Where("user_id = ?", 0).Order("id").First(&orphan).Error
+			if err == nil {
+				if err := tx.Model(&orphan).Update("user_id", userID).Error; err != nil {
+					return err
+				}
+				assigned++
+
+				// Their dated history goes with them
+				result := tx.Model(&types.PreferenceVersion{}).Where("user_id = ?", 0).Update("user_id", userID)
+				if result.Error != nil {
+					return result.Error
+				}
+				assigned += result.RowsAffected
+			} else if err != gorm.ErrRecordNotFound {
+				return err
+			}
+		}
+
+		for _, model := range []interface{}{&types.APIToken{}, &types.Webhook{}} {
+			result := tx.Model(model).Where("user_id = ?", 0).Update("user_id", userID)
+			if result.Error != nil {
+				return result.Error
+			}
+			assigned += result.RowsAffected
+		}
+		return nil
+	})
+	return assigned, err
+}
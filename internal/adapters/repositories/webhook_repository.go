@@ -0,0 +1,32 @@
+//go:generate mockery --name WebhookRepository
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositorySQLite struct {
+	db *gorm.DB
+}
+
+type WebhookRepository interface {
+	GetAllWebhooks(userID int) ([]types.Webhook, error)
+	GetActiveWebhooks(userID int) ([]types.Webhook, error)
+	GetWebhookByID(webhookID int) (types.Webhook, error)
+	AddWebhook(webhook types.Webhook) (types.Webhook, error)
+	UpdateWebhook(webhook types.Webhook) error
+	DeleteWebhook(webhookID int) error
+
+	AddDelivery(delivery types.WebhookDelivery) (types.WebhookDelivery, error)
+	UpdateDelivery(delivery types.WebhookDelivery) error
+	GetDeliveryByID(deliveryID int) (types.WebhookDelivery, error)
+	GetDeliveries(webhookID int, limit int) ([]types.WebhookDelivery, error)
+	DeleteDeliveriesBefore(before time.Time) (int64, error)
+}
+
+func NewWebhookRepositorySQLite(db *gorm.DB) WebhookRepository {
+	return &WebhookRepositorySQLite{db: db}
+}
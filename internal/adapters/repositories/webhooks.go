@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+func (r *WebhookRepositorySQLite) GetAllWebhooks(userID int) ([]types.Webhook, error) {
+	var webhooks []types.Webhook
+	result := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r *WebhookRepositorySQLite) GetActiveWebhooks(userID int) ([]types.Webhook, error) {
+	var webhooks []types.Webhook
+	result := r.db.Where("user_id = ? AND active = ?", userID, true).Order("id ASC").Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r *WebhookRepositorySQLite) GetWebhookByID(webhookID int) (types.Webhook, error) {
+	var webhook types.Webhook
+	result := r.db.First(&webhook, webhookID)
+	return webhook, result.Error
+}
+
+// AddWebhook stores a new webhook and returns it with its assigned ID
+func (r *WebhookRepositorySQLite) AddWebhook(webhook types.Webhook) (types.Webhook, error) {
+	result := r.db.Create(&webhook)
+	return webhook, result.Error
+}
+
+func (r *WebhookRepositorySQLite) UpdateWebhook(webhook types.Webhook) error {
+	result := r.db.Save(&webhook)
+	return result.Error
+}
+
+// DeleteWebhook removes a webhook along with its delivery log
+func (r *WebhookRepositorySQLite) DeleteWebhook(webhookID int) error {
+	if err := r.db.Where("webhook_id = ?", webhookID).Delete(&types.WebhookDelivery{}).Error; err != nil {
+		return err
+	}
+	result := r.db.Delete(&types.Webhook{}, webhookID)
+	return result.Error
+}
+
+// AddDelivery stores a new delivery record and returns it with its assigned ID
+func (r *WebhookRepositorySQLite) AddDelivery(delivery types.WebhookDelivery) (types.WebhookDelivery, error) {
+	result := r.db.Create(&delivery)
+	return delivery, result.Error
+}
+
+func (r *WebhookRepositorySQLite) UpdateDelivery(delivery types.WebhookDelivery) error {
+	result := r.db.Save(&delivery)
+	return result.Error
+}
+
+func (r *WebhookRepositorySQLite) GetDeliveryByID(deliveryID int) (types.WebhookDelivery, error) {
+	var delivery types.WebhookDelivery
+	result := r.db.First(&delivery, deliveryID)
+	return delivery, result.Error
+}
+
+// GetDeliveries returns the most recent deliveries for a webhook, newest first
+func (r *WebhookRepositorySQLite) GetDeliveries(webhookID int, limit int) ([]types.WebhookDelivery, error) {
+	var deliveries []types.WebhookDelivery
+	result := r.db.Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// DeleteDeliveriesBefore removes delivery records created before the given time
+func (r *WebhookRepositorySQLite) DeleteDeliveriesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&types.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_RejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@often"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Monday, October 19 2026 at 10:17
+	from := time.Date(2026, time.October, 19, 10, 17, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", at(time.October, 19, 10, 18)},
+		{"*/15 * * * *", at(time.October, 19, 10, 30)},
+		{"0 2 * * *", at(time.October, 20, 2, 0)},
+		{"@daily", at(time.October, 20, 0, 0)},
+		{"0 8 * * 1-5", at(time.October, 20, 8, 0)},
+		{"0 9 * * 6,7", at(time.October, 24, 9, 0)},
+		{"30 3 1 * *", at(time.November, 1, 3, 30)},
+		{"0 0 1 1 *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields set: either one matches, as in cron
+		{"0 12 25 * 3", at(time.October, 21, 12, 0)},
+	}
+	for _, test := range tests {
+		schedule, err := Parse(test.spec)
+		if assert.NoError(t, err, test.spec) {
+			assert.Equal(t, test.want, schedule.Next(from), test.spec)
+		}
+	}
+}
+
+func TestNext_LeapDay(t *testing.T) {
+	schedule, err := Parse("0 0 29 2 *")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		schedule.Next(time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)))
+}
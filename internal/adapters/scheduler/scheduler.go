@@ -0,0 +1,297 @@
+// Package scheduler runs recurring jobs on cron schedules. Each job's state
+// is kept in the database, so a restart picks up where it left off, a run
+// missed while the server was down happens once on start, and servers sharing
+// one database never run the same job twice.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownJob and ErrRunning are returned by RunNow
+var (
+	ErrUnknownJob = errors.New("no such job")
+	ErrRunning    = errors.New("the job is already running")
+)
+
+const (
+	// tickInterval is how often due jobs are looked for
+	tickInterval = 30 * time.Second
+	// staleAfter is how long a run may hold its claim before it is taken to
+	// have died with its server
+	staleAfter = time.Hour
+	// maxResult bounds the stored result and error text
+	maxResult = 500
+)
+
+// Func does a job's work and returns a short summary for the status page
+type Func func(ctx context.Context) (string, error)
+
+// Job is a registered job
+type Job struct {
+	Name        string
+	Description string
+	Spec        string // cron expression, or Off
+	schedule    *Schedule
+	run         Func
+}
+
+// Status is a job with its persisted state, for the status page
+type Status struct {
+	Name        string
+	Description string
+	Spec        string
+	State       types.ScheduledJob
+}
+
+// Scheduler runs the registered jobs when they are due
+type Scheduler struct {
+	repo   repository.JobRepository
+	logger *slog.Logger
+	owner  string // tells this process's claims apart from other servers'
+	now    func() time.Time
+	ctx    context.Context // ends the runs started by RunNow; set by Start
+
+	mu   sync.Mutex
+	jobs []*Job
+	wg   sync.WaitGroup
+}
+
+// New returns a scheduler with no jobs
+func New(repo repository.JobRepository, logger *slog.Logger) *Scheduler {
+	host, _ := os.Hostname()
+	raw := make([]byte, 4)
+	rand.Read(raw)
+	return &Scheduler{
+		repo:   repo,
+		logger: logger,
+		owner:  fmt.Sprintf("%s/%d/%s", host, os.Getpid(), hex.EncodeToString(raw)),
+		now:    time.Now,
+		ctx:    context.Background(),
+	}
+}
+
+// Add registers a job. spec is a cron expression, or Off for a job that only
+// runs when started by hand.
+func (s *Scheduler) Add(name, description, spec string, run Func) error {
+	job := &Job{Name: name, Description: description, Spec: spec, run: run}
+	if spec != Off {
+		schedule, err := Parse(spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		job.schedule = &schedule
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.jobs {
+		if existing.Name == name {
+			return fmt.Errorf("job %s is already registered", name)
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Start stores the registered schedules and runs due jobs until ctx ends
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.ctx = ctx
+	if err := s.sync(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			s.RunDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// sync adds a row for each new job and updates the schedules that changed.
+// Jobs whose row says they are overdue keep that, so they run once on start.
+func (s *Scheduler) sync() error {
+	now := s.now()
+	for _, job := range s.registered() {
+		next := job.next(now)
+		stored, err := s.repo.GetJob(job.Name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := s.repo.AddJob(types.ScheduledJob{Name: job.Name, Schedule: job.Spec, NextRunAt: next}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if stored.Schedule != job.Spec {
+			if err := s.repo.SetSchedule(job.Name, job.Spec, next); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RunDue runs every job that is due and waits for them to finish
+func (s *Scheduler) RunDue(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.registered() {
+		if job.schedule == nil {
+			continue
+		}
+		now := s.now()
+		claimed, err := s.repo.ClaimJob(job.Name, s.owner, now, now.Add(-staleAfter), false)
+		if err != nil {
+			s.logger.Error("Failed to claim job", "job", job.Name, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		wg.Add(1)
+		go func(job *Job) {
+			defer wg.Done()
+			s.execute(ctx, job, now)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// RunNow starts a job outside its schedule. It returns once the job has
+// started; the outcome shows up in Status.
+func (s *Scheduler) RunNow(name string) error {
+	job := s.job(name)
+	if job == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	now := s.now()
+	claimed, err := s.repo.ClaimJob(name, s.owner, now, now.Add(-staleAfter), true)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrRunning
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(s.ctx, job, now)
+	}()
+	return nil
+}
+
+// Wait blocks until the runs started by RunNow are done
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// execute runs a claimed job and records how it went
+func (s *Scheduler) execute(ctx context.Context, job *Job, started time.Time) {
+	s.logger.Info("Running job", "job", job.Name)
+	result, err := s.safeRun(ctx, job)
+	finished := s.now()
+
+	record := types.ScheduledJob{
+		Name:         job.Name,
+		NextRunAt:    job.next(started),
+		LastRunAt:    &started,
+		LastDuration: finished.Sub(started).Milliseconds(),
+		LastStatus:   types.JobOK,
+		LastResult:   truncate(result),
+		RunningOwner: s.owner,
+	}
+	if err != nil {
+		record.LastStatus = types.JobFailed
+		record.LastError = truncate(err.Error())
+		s.logger.Error("Job failed", "job", job.Name, "error", err)
+	} else {
+		s.logger.Info("Job finished", "job", job.Name, "result", result, "duration", finished.Sub(started))
+	}
+	if err := s.repo.FinishJob(record); err != nil {
+		s.logger.Error("Failed to record job run", "job", job.Name, "error", err)
+	}
+}
+
+// safeRun turns a panicking job into a failed run
+func (s *Scheduler) safeRun(ctx context.Context, job *Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.run(ctx)
+}
+
+// Status lists the registered jobs with their stored state
+func (s *Scheduler) Status() ([]Status, error) {
+	stored, err := s.repo.GetJobs()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]types.ScheduledJob, len(stored))
+	for _, job := range stored {
+		byName[job.Name] = job
+	}
+
+	var statuses []Status
+	for _, job := range s.registered() {
+		statuses = append(statuses, Status{
+			Name:        job.Name,
+			Description: job.Description,
+			Spec:        job.Spec,
+			State:       byName[job.Name],
+		})
+	}
+	return statuses, nil
+}
+
+func (s *Scheduler) registered() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Job(nil), s.jobs...)
+}
+
+func (s *Scheduler) job(name string) *Job {
+	for _, job := range s.registered() {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// next is when the job runs after t, or nil when it has no schedule
+func (j *Job) next(t time.Time) *time.Time {
+	if j.schedule == nil {
+		return nil
+	}
+	next := j.schedule.Next(t)
+	return &next
+}
+
+func truncate(s string) string {
+	if len(s) <= maxResult {
+		return s
+	}
+	return s[:maxResult]
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/database"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestScheduler(t *testing.T) (*Scheduler, repository.JobRepository) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := database.OpenQuiet(filepath.Join(t.TempDir(), "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewJobRepositorySQLite(db)
+	return New(repo, logger), repo
+}
+
+func TestRunDue_RunsOverdueJobsOnce(t *testing.T) {
+	s, repo := newTestScheduler(t)
+	start := time.Date(2026, time.October, 19, 1, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+
+	var runs int32
+	assert.NoError(t, s.Add("backup", "Back up", "0 2 * * *", func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "done", nil
+	}))
+	assert.NoError(t, s.sync())
+
+	// Not due yet
+	s.RunDue(context.Background())
+	assert.Equal(t, int32(0), runs)
+
+	// Due: a second scheduler on the same database loses the claim
+	s.now = func() time.Time { return start.Add(90 * time.Minute) }
+	other := New(repo, s.logger)
+	other.now = s.now
+	assert.NoError(t, other.Add("backup", "Back up", "0 2 * * *", func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&runs, 1)
+		return "done", nil
+	}))
+	s.RunDue(context.Background())
+	other.RunDue(context.Background())
+	assert.Equal(t, int32(1), runs)
+
+	job, err := repo.GetJob("backup")
+	assert.NoError(t, err)
+	assert.Equal(t, types.JobOK, job.LastStatus)
+	assert.Equal(t, "done", job.LastResult)
+	assert.Equal(t, 1, job.Runs)
+	assert.Nil(t, job.RunningSince)
+	assert.Equal(t, time.Date(2026, time.October, 20, 2, 0, 0, 0, time.UTC), job.NextRunAt.UTC())
+}
+
+func TestRunNow_RecordsFailures(t *testing.T) {
+	s, repo := newTestScheduler(t)
+	assert.NoError(t, s.Add("purge", "Purge", Off, func(ctx context.Context) (string, error) {
+		panic("boom")
+	}))
+	assert.NoError(t, s.Add("rollover", "Roll over", Off, func(ctx context.Context) (string, error) {
+		return "", errors.New("no period")
+	}))
+	assert.NoError(t, s.sync())
+
+	assert.NoError(t, s.RunNow("purge"))
+	assert.NoError(t, s.RunNow("rollover"))
+	assert.True(t, errors.Is(s.RunNow("missing"), ErrUnknownJob))
+	s.Wait()
+
+	statuses, err := s.Status()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.Equal(t, types.JobFailed, status.State.LastStatus, status.Name)
+		assert.Equal(t, 1, status.State.Failures, status.Name)
+		assert.Nil(t, status.State.NextRunAt, status.Name)
+	}
+	job, _ := repo.GetJob("purge")
+	assert.Equal(t, "panic: boom", job.LastError)
+}
+
+func TestRunNow_OneRunAtATime(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	release := make(chan struct{})
+	assert.NoError(t, s.Add("backup", "Back up", Off, func(ctx context.Context) (string, error) {
+		<-release
+		return "", nil
+	}))
+	assert.NoError(t, s.sync())
+
+	assert.NoError(t, s.RunNow("backup"))
+	assert.True(t, errors.Is(s.RunNow("backup"), ErrRunning))
+	close(release)
+	s.Wait()
+	assert.NoError(t, s.RunNow("backup"))
+	s.Wait()
+}
@@ -0,0 +1,236 @@
+// Package sessionstore keeps browser sessions in the database so they can be
+// listed and revoked. It implements the gorilla sessions.Store interface;
+// the cookie only carries a signed, random session ID.
+package sessionstore
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// How often last-seen is written for a session that is only read, and how
+// often expired sessions are purged
+const (
+	touchInterval = time.Minute
+	purgeInterval = time.Hour
+)
+
+// Store is a sessions.Store backed by a SessionRepository
+type Store struct {
+	Codecs  []securecookie.Codec
+	Options *sessions.Options
+
+	repo      repository.SessionRepository
+	logger    *slog.Logger
+	userIDKey string // the session value holding the signed-in user's ID
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// New returns a store signing session IDs with keyPairs. userIDKey names the
+// int session value recorded as the session's owner.
+func New(repo repository.SessionRepository, logger *slog.Logger, userIDKey string, keyPairs ...[]byte) *Store {
+	return &Store{
+		Codecs:    securecookie.CodecsFromPairs(keyPairs...),
+		Options:   &sessions.Options{Path: "/", MaxAge: 86400 * 30},
+		repo:      repo,
+		logger:    logger,
+		userIDKey: userIDKey,
+	}
+}
+
+// Hash is the value stored in Session.TokenHash for a session ID
+func Hash(id string) string {
+	if id == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the named session, cached for the rest of the request
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named by the request's cookie. A missing, expired or
+// revoked session comes back as a new, empty one.
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	var id string
+	if err := securecookie.DecodeMulti(name, cookie.Value, &id, s.Codecs...); err != nil {
+		return session, err
+	}
+
+	now := time.Now()
+	row, err := s.repo.GetSessionByHash(Hash(id))
+	if err != nil || !row.ExpiresAt.After(now) {
+		return session, nil
+	}
+	if err := securecookie.DecodeMulti(name, row.Data, &session.Values, s.Codecs...); err != nil {
+		return session, err
+	}
+	session.ID = id
+	session.IsNew = false
+
+	if now.Sub(row.LastSeenAt) > touchInterval {
+		if err := s.repo.TouchSession(row.ID, now); err != nil {
+			s.logger.Error("Failed to update session last seen", "sessionID", row.ID, "error", err)
+		}
+	}
+	return session, nil
+}
+
+// Save writes the session and sets its cookie. A negative MaxAge deletes it.
+// The ID is replaced whenever the signed-in user changes, so an ID handed out
+// before sign-in is never the one used after it.
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	name := session.Name()
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if row, err := s.repo.GetSessionByHash(Hash(session.ID)); err == nil {
+				if _, err := s.repo.DeleteSession(int(row.UserID), row.ID); err != nil {
+					return err
+				}
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(name, "", session.Options))
+		return nil
+	}
+
+	now := time.Now()
+	userID, _ := session.Values[s.userIDKey].(int)
+	data, err := securecookie.EncodeMulti(name, session.Values, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	var row types.Session
+	found := false
+	if session.ID != "" {
+		if row, err = s.repo.GetSessionByHash(Hash(session.ID)); err == nil {
+			found = true
+			if int(row.UserID) != userID {
+				if _, err := s.repo.DeleteSession(int(row.UserID), row.ID); err != nil {
+					return err
+				}
+				found = false
+			}
+		}
+	}
+	if !found {
+		session.ID = newID()
+		if session.ID == "" {
+			return errors.New("sessionstore: could not generate a session ID")
+		}
+		row = types.Session{
+			TokenHash: Hash(session.ID),
+			UserID:    uint(userID),
+			UserAgent: truncate(r.UserAgent(), 255),
+			IP:        clientIP(r),
+			CreatedAt: now,
+		}
+	}
+	row.Data = data
+	row.LastSeenAt = now
+	row.ExpiresAt = now.Add(lifetime(session.Options))
+	if found {
+		err = s.repo.UpdateSession(row)
+	} else {
+		_, err = s.repo.AddSession(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(name, session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(name, encoded, session.Options))
+
+	s.purgeExpired(now)
+	return nil
+}
+
+// purgeExpired drops expired sessions at most once per purgeInterval
+func (s *Store) purgeExpired(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPurge) < purgeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurge = now
+	s.mu.Unlock()
+
+	purged, err := s.repo.DeleteExpiredSessions(now)
+	if err != nil {
+		s.logger.Error("Failed to purge expired sessions", "error", err)
+		return
+	}
+	if purged > 0 {
+		s.logger.Info("Purged expired sessions", "count", purged)
+	}
+}
+
+// lifetime is how long the server keeps a session; browser-session cookies
+// (MaxAge 0) are kept for a day
+func lifetime(opts *sessions.Options) time.Duration {
+	if opts.MaxAge > 0 {
+		return time.Duration(opts.MaxAge) * time.Second
+	}
+	return 24 * time.Hour
+}
+
+func newID() string {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return ""
+	}
+	return strings.TrimRight(base32.StdEncoding.EncodeToString(raw), "=")
+}
+
+// clientIP prefers the proxy headers echo also trusts, then the peer address
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		return truncate(strings.TrimSpace(ip), 64)
+	}
+	if ip := r.Header.Get("X-Real-Ip"); ip != "" {
+		return truncate(ip, 64)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return truncate(r.RemoteAddr, 64)
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
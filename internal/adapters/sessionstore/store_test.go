@@ -0,0 +1,120 @@
+package sessionstore
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) (*Store, repository.SessionRepository) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := database.OpenQuiet(filepath.Join(t.TempDir(), "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewSessionRepositorySQLite(db)
+	return New(repo, logger, "user_id", []byte("0123456789abcdef0123456789abcdef")), repo
+}
+
+// save stores the session and returns the request a browser would send next
+func save(t *testing.T, store *Store, req *http.Request, sess *sessions.Session) *http.Request {
+	rec := httptest.NewRecorder()
+	if err := store.Save(req, rec, sess); err != nil {
+		t.Fatal(err)
+	}
+	next := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		next.AddCookie(cookie)
+	}
+	return next
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store, repo := newTestStore(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
+	req.RemoteAddr = "192.0.2.7:51234"
+	sess, err := store.New(req, "session")
+	assert.NoError(t, err)
+	assert.True(t, sess.IsNew)
+	sess.Values["user_id"] = 2
+
+	next := save(t, store, req, sess)
+
+	loaded, err := store.New(next, "session")
+	assert.NoError(t, err)
+	assert.False(t, loaded.IsNew)
+	assert.Equal(t, 2, loaded.Values["user_id"])
+
+	rows, err := repo.GetSessionsByUser(2, time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, Hash(sess.ID), rows[0].TokenHash)
+		assert.Equal(t, "192.0.2.7", rows[0].IP)
+		assert.Equal(t, "Firefox on Linux", rows[0].Device())
+	}
+}
+
+func TestStore_SignInReplacesID(t *testing.T) {
+	store, repo := newTestStore(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+	sess, _ := store.New(req, "session")
+	sess.Values["state"] = "abc"
+	next := save(t, store, req, sess)
+	before := sess.ID
+
+	sess, _ = store.New(next, "session")
+	assert.Equal(t, before, sess.ID)
+	sess.Values["user_id"] = 2
+	save(t, store, next, sess)
+
+	assert.NotEqual(t, before, sess.ID)
+	_, err := repo.GetSessionByHash(Hash(before))
+	assert.Error(t, err, "the pre-sign-in session is gone")
+}
+
+func TestStore_RevokedSessionIsSignedOut(t *testing.T) {
+	store, repo := newTestStore(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	sess, _ := store.New(req, "session")
+	sess.Values["user_id"] = 2
+	next := save(t, store, req, sess)
+
+	deleted, err := repo.DeleteSessionsByUser(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	loaded, err := store.New(next, "session")
+	assert.NoError(t, err)
+	assert.True(t, loaded.IsNew)
+	assert.Empty(t, loaded.Values)
+}
+
+func TestStore_NegativeMaxAgeDeletes(t *testing.T) {
+	store, repo := newTestStore(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	sess, _ := store.New(req, "session")
+	sess.Values["user_id"] = 2
+	next := save(t, store, req, sess)
+
+	sess, _ = store.New(next, "session")
+	sess.Options.MaxAge = -1
+	save(t, store, next, sess)
+
+	rows, err := repo.GetSessionsByUser(2, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+}
@@ -0,0 +1,213 @@
+// Package sso signs users in with an OpenID Connect provider, using the
+// authorization code flow with PKCE.
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/robstave/rto/internal/domain/types"
+	"golang.org/x/oauth2"
+)
+
+// ErrStateMismatch means the callback does not belong to the sign-in this
+// browser started, e.g. a replayed or forged redirect
+var ErrStateMismatch = errors.New("sign-in state does not match")
+
+// Config describes the provider and this app's client registration
+type Config struct {
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string   // callback URL registered with the provider
+	Scopes        []string // requested along with "openid"
+	GroupsClaim   string   // ID token claim listing the user's groups
+	AdminGroups   []string // members of any of these groups are admins
+	ManagerGroups []string // members of any of these groups are managers
+}
+
+// Flow is what a sign-in remembers between the redirect to the provider and the callback
+type Flow struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// Provider talks to one OpenID Connect provider. Discovery happens on first
+// use and is retried until it succeeds, so the server starts while the
+// provider is down.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+}
+
+func New(config Config) *Provider {
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *Provider) discover(ctx context.Context) (*oidc.Provider, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, p.verifier, nil
+	}
+	// Keys are fetched later with the same client, outside this request's lifetime
+	provider, err := oidc.NewProvider(oidc.ClientContext(context.Background(), p.client), p.config.IssuerURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("discover %s: %w", p.config.IssuerURL, err)
+	}
+	p.provider = provider
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	return p.provider, p.verifier, nil
+}
+
+func (p *Provider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       append([]string{oidc.ScopeOpenID}, p.config.Scopes...),
+	}
+}
+
+// AuthURL starts a sign-in. The caller keeps the flow (in the session) and
+// sends the browser to the returned URL.
+func (p *Provider) AuthURL(ctx context.Context) (string, Flow, error) {
+	provider, _, err := p.discover(ctx)
+	if err != nil {
+		return "", Flow{}, err
+	}
+	state, err := randomString()
+	if err != nil {
+		return "", Flow{}, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", Flow{}, err
+	}
+	flow := Flow{State: state, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}
+
+	url := p.oauth2Config(provider).AuthCodeURL(flow.State,
+		oidc.Nonce(flow.Nonce),
+		oauth2.S256ChallengeOption(flow.Verifier),
+	)
+	return url, flow, nil
+}
+
+// Exchange finishes a sign-in: it checks the state, redeems the code with the
+// PKCE verifier and verifies the ID token
+func (p *Provider) Exchange(ctx context.Context, flow Flow, state, code string) (types.ExternalIdentity, error) {
+	if flow.State == "" || state != flow.State {
+		return types.ExternalIdentity{}, ErrStateMismatch
+	}
+	provider, verifier, err := p.discover(ctx)
+	if err != nil {
+		return types.ExternalIdentity{}, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := p.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		return types.ExternalIdentity{}, fmt.Errorf("redeem code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return types.ExternalIdentity{}, errors.New("provider returned no ID token")
+	}
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return types.ExternalIdentity{}, fmt.Errorf("verify ID token: %w", err)
+	}
+	if idToken.Nonce != flow.Nonce {
+		return types.ExternalIdentity{}, errors.New("ID token nonce does not match")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return types.ExternalIdentity{}, fmt.Errorf("read ID token claims: %w", err)
+	}
+	return p.identity(idToken.Issuer, idToken.Subject, claims), nil
+}
+
+// identity maps ID token claims onto an ExternalIdentity
+func (p *Provider) identity(issuer, subject string, claims map[string]interface{}) types.ExternalIdentity {
+	identity := types.ExternalIdentity{Issuer: issuer, Subject: subject}
+	identity.Email, _ = claims["email"].(string)
+	identity.Username, _ = claims["preferred_username"].(string)
+
+	// Some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	mapsRoles := len(p.config.AdminGroups) > 0 || len(p.config.ManagerGroups) > 0
+	if groups, ok := stringList(claims[p.config.GroupsClaim]); ok && mapsRoles {
+		switch {
+		case memberOf(groups, p.config.AdminGroups):
+			identity.Role = types.RoleAdmin
+		case memberOf(groups, p.config.ManagerGroups):
+			identity.Role = types.RoleManager
+		default:
+			identity.Role = types.RoleEmployee
+		}
+	}
+	return identity
+}
+
+func memberOf(groups, wanted []string) bool {
+	for _, group := range groups {
+		for _, w := range wanted {
+			if group == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringList reads a claim holding a list of strings, or a single string
+func stringList(claim interface{}) ([]string, bool) {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(strings.ReplaceAll(v, ",", " ")), true
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
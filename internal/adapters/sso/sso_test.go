@@ -0,0 +1,152 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/robstave/rto/internal/adapters/sso/mockidp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRedirect = "http://rto.test/auth/oidc/callback"
+
+func newTestProvider(t *testing.T) (*Provider, *mockidp.Provider) {
+	idp, server, err := mockidp.NewServer("rto", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	return New(Config{
+		IssuerURL:     idp.Issuer,
+		ClientID:      "rto",
+		ClientSecret:  "s3cret",
+		RedirectURL:   testRedirect,
+		Scopes:        []string{"email", "profile"},
+		AdminGroups:   []string{"rto-admins"},
+		ManagerGroups: []string{"rto-managers"},
+	}), idp
+}
+
+// authorize follows the provider's redirect the way a browser would and
+// returns the callback's state and code
+func authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize answered %d", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return callback.Query().Get("state"), callback.Query().Get("code")
+}
+
+func TestSignIn_CodeFlowWithPKCE(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	idp.SetClaims(map[string]interface{}{
+		"sub":                "u-123",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"preferred_username": "alice",
+		"groups":             []string{"staff", "rto-admins"},
+	})
+
+	authURL, flow, err := provider.AuthURL(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, authURL, "code_challenge_method=S256")
+	assert.NotContains(t, authURL, flow.Verifier)
+
+	state, code := authorize(t, authURL)
+	identity, err := provider.Exchange(context.Background(), flow, state, code)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, idp.Issuer, identity.Issuer)
+		assert.Equal(t, "u-123", identity.Subject)
+		assert.Equal(t, "alice@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "alice", identity.Username)
+		assert.Equal(t, "admin", identity.Role)
+	}
+}
+
+func TestSignIn_NoGroupsClaimLeavesRole(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	idp.SetClaims(map[string]interface{}{"sub": "u-456", "email": "bob@example.com", "email_verified": "false"})
+
+	authURL, flow, err := provider.AuthURL(context.Background())
+	assert.NoError(t, err)
+	state, code := authorize(t, authURL)
+	identity, err := provider.Exchange(context.Background(), flow, state, code)
+
+	if assert.NoError(t, err) {
+		assert.False(t, identity.EmailVerified)
+		assert.Empty(t, identity.Role)
+	}
+}
+
+func TestSignIn_GroupsMapToRoles(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	for groups, role := range map[string]string{
+		"rto-managers": "manager",
+		"staff":        "employee",
+	} {
+		idp.SetClaims(map[string]interface{}{"sub": "u-789", "groups": groups})
+
+		authURL, flow, err := provider.AuthURL(context.Background())
+		assert.NoError(t, err)
+		state, code := authorize(t, authURL)
+		identity, err := provider.Exchange(context.Background(), flow, state, code)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, role, identity.Role, groups)
+		}
+	}
+}
+
+func TestSignIn_StateMismatch(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	authURL, flow, err := provider.AuthURL(context.Background())
+	assert.NoError(t, err)
+	_, code := authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), flow, "forged", code)
+	assert.ErrorIs(t, err, ErrStateMismatch)
+}
+
+func TestSignIn_WrongVerifier(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	authURL, flow, err := provider.AuthURL(context.Background())
+	assert.NoError(t, err)
+	state, code := authorize(t, authURL)
+
+	// A stolen code is useless without the verifier kept in the session
+	flow.Verifier = "not-the-verifier-not-the-verifier-not-the-verifier"
+	_, err = provider.Exchange(context.Background(), flow, state, code)
+	assert.Error(t, err)
+}
+
+func TestSignIn_CodeIsSingleUse(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	authURL, flow, err := provider.AuthURL(context.Background())
+	assert.NoError(t, err)
+	state, code := authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), flow, state, code)
+	assert.NoError(t, err)
+	_, err = provider.Exchange(context.Background(), flow, state, code)
+	assert.Error(t, err)
+}
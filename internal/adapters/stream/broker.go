@@ -0,0 +1,141 @@
+// Package stream fans messages out to Server-Sent Events clients such as
+// open browser tabs.
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientBuffer is how many messages a slow client may fall behind before
+// messages to it are dropped
+const clientBuffer = 32
+
+// Message is a single SSE message
+type Message struct {
+	Event string
+	Data  []byte
+}
+
+// WriteTo writes the message in text/event-stream framing
+func (m Message) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if m.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", m.Event)
+	}
+	for _, line := range strings.Split(string(m.Data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Broker keeps track of connected clients, each listening on one topic, and
+// publishes messages to the clients of a topic
+type Broker struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	clients map[chan Message]string // client to topic
+	pending map[string]bool         // topic/event pairs with a coalesced publish scheduled
+}
+
+func NewBroker(logger *slog.Logger) *Broker {
+	return &Broker{
+		logger:  logger,
+		clients: make(map[chan Message]string),
+		pending: make(map[string]bool),
+	}
+}
+
+// Subscribe registers a new client for a topic. Call Unsubscribe when it disconnects.
+func (b *Broker) Subscribe(topic string) chan Message {
+	ch := make(chan Message, clientBuffer)
+	b.mu.Lock()
+	b.clients[ch] = topic
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *Broker) Unsubscribe(ch chan Message) {
+	b.mu.Lock()
+	if _, ok := b.clients[ch]; ok {
+		delete(b.clients, ch)
+		close(ch)
+	}
+	b.mu.Unlock()
+}
+
+// Clients returns the number of connected clients across all topics
+func (b *Broker) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// hasClients reports whether anyone listens on the topic; callers hold mu
+func (b *Broker) hasClients(topic string) bool {
+	for _, t := range b.clients {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish encodes v as JSON and sends it to every client of the topic. It
+// never blocks: clients that are too far behind miss the message.
+func (b *Broker) Publish(topic, event string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.logger.Error("Error encoding stream message", "event", event, "error", err)
+		return
+	}
+	msg := Message{Event: event, Data: data}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, t := range b.clients {
+		if t != topic {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+			b.logger.Warn("Dropping stream message for slow client", "event", event)
+		}
+	}
+}
+
+// Coalesce publishes the result of build to the topic after delay. Further
+// calls for the same topic and event before then are folded into that one
+// publish, which keeps bursts of changes from recomputing expensive messages.
+// Nothing is scheduled while the topic has no clients; build returning
+// ok=false skips the publish.
+func (b *Broker) Coalesce(topic, event string, delay time.Duration, build func() (v interface{}, ok bool)) {
+	key := topic + "/" + event
+
+	b.mu.Lock()
+	if !b.hasClients(topic) || b.pending[key] {
+		b.mu.Unlock()
+		return
+	}
+	b.pending[key] = true
+	b.mu.Unlock()
+
+	time.AfterFunc(delay, func() {
+		b.mu.Lock()
+		delete(b.pending, key)
+		b.mu.Unlock()
+
+		if v, ok := build(); ok {
+			b.Publish(topic, event, v)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package stream
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBroker() *Broker {
+	return NewBroker(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
+
+func TestMessage_WriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Message{Event: "change", Data: []byte("{\"a\":1}\nsecond")}.WriteTo(&buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "event: change\ndata: {\"a\":1}\ndata: second\n\n", buf.String())
+}
+
+func TestBroker_PublishToTopicClients(t *testing.T) {
+	b := newTestBroker()
+	one := b.Subscribe("user:1")
+	two := b.Subscribe("user:1")
+	other := b.Subscribe("user:2")
+	assert.Equal(t, 3, b.Clients())
+
+	b.Publish("user:1", "change", map[string]string{"type": "event.created"})
+
+	for _, ch := range []chan Message{one, two} {
+		msg := <-ch
+		assert.Equal(t, "change", msg.Event)
+		assert.JSONEq(t, `{"type":"event.created"}`, string(msg.Data))
+	}
+	assert.Len(t, other, 0)
+	b.Unsubscribe(other)
+
+	b.Unsubscribe(one)
+	b.Unsubscribe(one) // safe to call twice
+	assert.Equal(t, 1, b.Clients())
+	_, open := <-one
+	assert.False(t, open)
+}
+
+func TestBroker_SlowClientDoesNotBlock(t *testing.T) {
+	b := newTestBroker()
+	ch := b.Subscribe("user:1")
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < clientBuffer*2; i++ {
+			b.Publish("user:1", "change", i)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow client")
+	}
+	assert.Len(t, ch, clientBuffer)
+}
+
+func TestBroker_Coalesce(t *testing.T) {
+	b := newTestBroker()
+
+	// Nothing is built while no one is listening
+	var builds int32
+	build := func() (interface{}, bool) {
+		atomic.AddInt32(&builds, 1)
+		return "stats", true
+	}
+	b.Coalesce("user:1", "stats", time.Millisecond, build)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&builds))
+
+	// Nor while only other topics are
+	other := b.Subscribe("user:2")
+	b.Coalesce("user:1", "stats", time.Millisecond, build)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&builds))
+
+	ch := b.Subscribe("user:1")
+	for i := 0; i < 5; i++ {
+		b.Coalesce("user:1", "stats", 20*time.Millisecond, build)
+	}
+
+	select {
+	case msg := <-ch:
+		assert.Equal(t, "stats", msg.Event)
+	case <-time.After(time.Second):
+		t.Fatal("coalesced message was never published")
+	}
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&builds))
+	assert.Len(t, ch, 0)
+	assert.Len(t, other, 0)
+}
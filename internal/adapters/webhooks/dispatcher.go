@@ -0,0 +1,237 @@
+// Package webhooks delivers signed JSON payloads for service changes to
+// user-registered URLs, retrying with backoff and logging every delivery.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-RTO-Event"
+	HeaderDelivery  = "X-RTO-Delivery"
+	HeaderTimestamp = "X-RTO-Timestamp"
+	HeaderSignature = "X-RTO-Signature"
+)
+
+// DefaultBackoff is the wait before each retry. A delivery is attempted
+// len(DefaultBackoff)+1 times in total.
+var DefaultBackoff = []time.Duration{
+	10 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+}
+
+// Dispatcher turns service changes into webhook deliveries
+type Dispatcher struct {
+	repo    repository.WebhookRepository
+	logger  *slog.Logger
+	client  *http.Client
+	backoff []time.Duration
+
+	wg sync.WaitGroup
+}
+
+func NewDispatcher(repo repository.WebhookRepository, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:    repo,
+		logger:  logger,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		backoff: DefaultBackoff,
+	}
+}
+
+// SetBackoff overrides the retry schedule, mainly for tests
+func (d *Dispatcher) SetBackoff(backoff []time.Duration) {
+	d.backoff = backoff
+}
+
+// Sign returns the value of the signature header for a payload. Receivers
+// should recompute it from the timestamp header and the raw body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HandleChange is a types.ChangeListener. It records a delivery for every
+// active webhook of the change's user that wants the change and sends them in
+// the background.
+func (d *Dispatcher) HandleChange(change types.Change) {
+	hooks, err := d.repo.GetActiveWebhooks(int(change.UserID))
+	if err != nil {
+		d.logger.Error("Error loading webhooks", "error", err)
+		return
+	}
+
+	var body []byte
+	for _, hook := range hooks {
+		if !hook.Wants(change.Kind) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(newPayload(change))
+			if err != nil {
+				d.logger.Error("Error encoding webhook payload", "kind", change.Kind, "error", err)
+				return
+			}
+		}
+
+		delivery, err := d.repo.AddDelivery(types.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventType: change.Kind,
+			Payload:   string(body),
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			d.logger.Error("Error recording webhook delivery", "webhookID", hook.ID, "error", err)
+			continue
+		}
+		d.start(hook, delivery)
+	}
+}
+
+// Replay sends the payload of an earlier delivery again as a new delivery.
+// Deliveries of another user's webhooks are reported as not found.
+func (d *Dispatcher) Replay(userID int, deliveryID int) (*types.WebhookDelivery, error) {
+	original, err := d.repo.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := d.repo.GetWebhookByID(int(original.WebhookID))
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != uint(userID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	delivery, err := d.repo.AddDelivery(types.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventType: original.EventType,
+		Payload:   original.Payload,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Info("Replaying webhook delivery", "deliveryID", deliveryID, "newDeliveryID", delivery.ID)
+	d.start(hook, delivery)
+	return &delivery, nil
+}
+
+// Wait blocks until every in-flight delivery has finished retrying
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) start(hook types.Webhook, delivery types.WebhookDelivery) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.deliver(hook, delivery)
+	}()
+}
+
+// deliver attempts a delivery until it succeeds, the retries run out, or the
+// webhook is paused or deleted
+func (d *Dispatcher) deliver(hook types.Webhook, delivery types.WebhookDelivery) {
+	for attempt := 0; ; attempt++ {
+		status, err := d.send(hook, delivery)
+
+		now := time.Now()
+		delivery.Attempts++
+		delivery.LastAttemptAt = &now
+		delivery.StatusCode = status
+		delivery.Success = err == nil
+		delivery.Error = ""
+		if err != nil {
+			delivery.Error = truncate(err.Error(), 500)
+		}
+		if uerr := d.repo.UpdateDelivery(delivery); uerr != nil {
+			d.logger.Error("Error updating webhook delivery", "deliveryID", delivery.ID, "error", uerr)
+		}
+
+		if err == nil {
+			return
+		}
+		d.logger.Warn("Webhook delivery failed", "webhookID", hook.ID, "deliveryID", delivery.ID,
+			"attempt", delivery.Attempts, "error", err)
+		if attempt >= len(d.backoff) {
+			return
+		}
+
+		time.Sleep(d.backoff[attempt])
+
+		// Stop retrying if the webhook was paused or removed in the meantime
+		current, err := d.repo.GetWebhookByID(int(hook.ID))
+		if err != nil || !current.Active {
+			return
+		}
+		hook = current
+	}
+}
+
+func (d *Dispatcher) send(hook types.Webhook, delivery types.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rto-webhooks/1")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// newID returns a random identifier for a payload so receivers can de-duplicate
+func newID() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(raw)
+}
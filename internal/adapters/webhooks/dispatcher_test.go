@@ -0,0 +1,240 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// receiver is a local HTTP endpoint that records what it was sent
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int // responses to give, in order; 200 once exhausted
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestDispatcher(repo *mocks.WebhookRepository) *Dispatcher {
+	d := NewDispatcher(repo, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	d.SetBackoff([]time.Duration{time.Millisecond, time.Millisecond})
+	return d
+}
+
+func toggledChange() types.Change {
+	return types.Change{
+		Kind:       types.ChangeAttendanceToggled,
+		UserID:     2,
+		OccurredAt: time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC),
+		Event: &types.Event{
+			ID:         7,
+			Date:       time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC),
+			Type:       "attendance",
+			IsInOffice: true,
+		},
+		Status: "in",
+	}
+}
+
+func TestHandleChange_SignsAndDelivers(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	hook := types.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true, UserID: 2}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetActiveWebhooks", 2).Return([]types.Webhook{hook}, nil)
+	mockRepo.On("AddDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Return(func(delivery types.WebhookDelivery) types.WebhookDelivery {
+			delivery.ID = 42
+			return delivery
+		}, nil)
+
+	var final types.WebhookDelivery
+	mockRepo.On("UpdateDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Run(func(args mock.Arguments) { final = args.Get(0).(types.WebhookDelivery) }).
+		Return(nil)
+
+	d := newTestDispatcher(mockRepo)
+	d.HandleChange(toggledChange())
+	d.Wait()
+
+	if !assert.Len(t, recv.requests, 1) {
+		return
+	}
+	req, body := recv.requests[0], recv.bodies[0]
+
+	assert.Equal(t, types.ChangeAttendanceToggled, req.Header.Get(HeaderEvent))
+	assert.Equal(t, "42", req.Header.Get(HeaderDelivery))
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign("whsec_test", timestamp, body), req.Header.Get(HeaderSignature))
+	assert.NotEqual(t, Sign("wrong", timestamp, body), req.Header.Get(HeaderSignature))
+
+	var payload Payload
+	assert.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, types.ChangeAttendanceToggled, payload.Type)
+	assert.Equal(t, "in", payload.Data.Status)
+	assert.Equal(t, "2025-01-06", payload.Data.Event.Date)
+	assert.Equal(t, uint(7), payload.Data.Event.ID)
+
+	assert.True(t, final.Success)
+	assert.Equal(t, 1, final.Attempts)
+	assert.Equal(t, http.StatusOK, final.StatusCode)
+}
+
+func TestHandleChange_RetriesWithBackoff(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	hook := types.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true, UserID: 2}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetActiveWebhooks", 2).Return([]types.Webhook{hook}, nil)
+	mockRepo.On("GetWebhookByID", 1).Return(hook, nil)
+	mockRepo.On("AddDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Return(func(delivery types.WebhookDelivery) types.WebhookDelivery {
+			delivery.ID = 1
+			return delivery
+		}, nil)
+
+	var updates []types.WebhookDelivery
+	mockRepo.On("UpdateDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Run(func(args mock.Arguments) { updates = append(updates, args.Get(0).(types.WebhookDelivery)) }).
+		Return(nil)
+
+	d := newTestDispatcher(mockRepo)
+	d.HandleChange(toggledChange())
+	d.Wait()
+
+	assert.Len(t, recv.requests, 3)
+	if assert.Len(t, updates, 3) {
+		assert.False(t, updates[0].Success)
+		assert.Equal(t, http.StatusInternalServerError, updates[0].StatusCode)
+		assert.NotEmpty(t, updates[0].Error)
+
+		assert.True(t, updates[2].Success)
+		assert.Equal(t, 3, updates[2].Attempts)
+		assert.Empty(t, updates[2].Error)
+	}
+}
+
+func TestHandleChange_GivesUpAfterBackoff(t *testing.T) {
+	recv := &receiver{statuses: []int{500, 500, 500, 500}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	hook := types.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true, UserID: 2}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetActiveWebhooks", 2).Return([]types.Webhook{hook}, nil)
+	mockRepo.On("GetWebhookByID", 1).Return(hook, nil)
+	mockRepo.On("AddDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Return(types.WebhookDelivery{ID: 1, WebhookID: 1, EventType: types.ChangeEventCreated, Payload: "{}"}, nil)
+
+	var final types.WebhookDelivery
+	mockRepo.On("UpdateDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Run(func(args mock.Arguments) { final = args.Get(0).(types.WebhookDelivery) }).
+		Return(nil)
+
+	d := newTestDispatcher(mockRepo)
+	d.HandleChange(types.Change{Kind: types.ChangeEventCreated, UserID: 2})
+	d.Wait()
+
+	// One attempt plus one per backoff step
+	assert.Len(t, recv.requests, 3)
+	assert.False(t, final.Success)
+	assert.Equal(t, 3, final.Attempts)
+}
+
+func TestHandleChange_SkipsUnsubscribedKinds(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	hook := types.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test", Active: true, UserID: 2,
+		Events: types.ChangeEventDeleted}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetActiveWebhooks", 2).Return([]types.Webhook{hook}, nil)
+
+	d := newTestDispatcher(mockRepo)
+	d.HandleChange(toggledChange())
+	d.Wait()
+
+	assert.Empty(t, recv.requests)
+	mockRepo.AssertNotCalled(t, "AddDelivery", mock.Anything)
+}
+
+func TestReplay_SendsStoredPayload(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	hook := types.Webhook{ID: 3, URL: server.URL, Secret: "whsec_test", Active: true, UserID: 2}
+	original := types.WebhookDelivery{ID: 9, WebhookID: 3, EventType: types.ChangeEventDeleted,
+		Payload: `{"id":"abc","type":"event.deleted"}`, Attempts: 5}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetDeliveryByID", 9).Return(original, nil)
+	mockRepo.On("GetWebhookByID", 3).Return(hook, nil)
+	mockRepo.On("AddDelivery", mock.AnythingOfType("types.WebhookDelivery")).
+		Return(func(delivery types.WebhookDelivery) types.WebhookDelivery {
+			delivery.ID = 10
+			return delivery
+		}, nil)
+	mockRepo.On("UpdateDelivery", mock.AnythingOfType("types.WebhookDelivery")).Return(nil)
+
+	d := newTestDispatcher(mockRepo)
+	delivery, err := d.Replay(2, 9)
+	d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), delivery.ID)
+	if assert.Len(t, recv.requests, 1) {
+		assert.Equal(t, original.Payload, string(recv.bodies[0]))
+		assert.Equal(t, "10", recv.requests[0].Header.Get(HeaderDelivery))
+	}
+}
+
+func TestReplay_OtherUsersWebhook(t *testing.T) {
+	hook := types.Webhook{ID: 3, URL: "http://example.invalid", Secret: "whsec_test", Active: true, UserID: 2}
+
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("GetDeliveryByID", 9).Return(types.WebhookDelivery{ID: 9, WebhookID: 3}, nil)
+	mockRepo.On("GetWebhookByID", 3).Return(hook, nil)
+
+	d := newTestDispatcher(mockRepo)
+	_, err := d.Replay(5, 9)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "AddDelivery", mock.Anything)
+}
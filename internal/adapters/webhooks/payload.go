@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Payload is the JSON body posted to webhooks
+type Payload struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       PayloadData `json:"data"`
+}
+
+type PayloadData struct {
+	Event         *PayloadEvent `json:"event,omitempty"`
+	Status        string        `json:"status,omitempty"`
+	Stats         *PayloadStats `json:"stats,omitempty"`
+	Level         string        `json:"level,omitempty"`
+	PreviousLevel string        `json:"previousLevel,omitempty"`
+}
+
+type PayloadEvent struct {
+	ID          uint   `json:"id"`
+	Date        string `json:"date"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsInOffice  bool   `json:"isInOffice"`
+}
+
+type PayloadStats struct {
+	InOfficeCount  int     `json:"inOfficeCount"`
+	TotalDays      int     `json:"totalDays"`
+	AverageDays    float64 `json:"averageDays"`
+	TargetDays     float64 `json:"targetDays"`
+	AveragePercent float64 `json:"averagePercent"`
+}
+
+func newPayload(change types.Change) Payload {
+	payload := Payload{
+		ID:         newID(),
+		Type:       change.Kind,
+		OccurredAt: change.OccurredAt,
+		Data: PayloadData{
+			Status:        change.Status,
+			Level:         change.Level,
+			PreviousLevel: change.PrevLevel,
+		},
+	}
+	if change.Event != nil {
+		payload.Data.Event = &PayloadEvent{
+			ID:          change.Event.ID,
+			Date:        change.Event.Date.Format("2006-01-02"),
+			Type:        change.Event.Type,
+			Description: change.Event.Description,
+			IsInOffice:  change.Event.IsInOffice,
+		}
+	}
+	if change.Stats != nil {
+		payload.Data.Stats = &PayloadStats{
+			InOfficeCount:  change.Stats.InOfficeCount,
+			TotalDays:      change.Stats.TotalDays,
+			AverageDays:    change.Stats.AverageDays,
+			TargetDays:     change.Stats.TargetDays,
+			AveragePercent: change.Stats.AveragePercent,
+		}
+	}
+	return payload
+}
@@ -0,0 +1,77 @@
+//go:generate mockery --name Backend
+
+// Package client gives the command-line tools one interface over the two ways
+// of reaching the calendar: the local SQLite database through the domain
+// service, or a running server through the REST API with a token.
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Backend is implemented by Local and Remote
+type Backend interface {
+	// SetAttendance marks a day as in office or remote
+	SetAttendance(date time.Time, inOffice bool) (*types.Event, error)
+	// ToggleAttendance flips a day between in office and remote
+	ToggleAttendance(date time.Time) (string, error)
+	// AddVacation books the weekdays from start to end as vacation
+	AddVacation(start, end time.Time, description string) (*types.BulkAddResponse, error)
+	// Stats returns the current period's stats
+	Stats() (*Report, error)
+	// Plan returns what it takes to reach the target in the current period
+	Plan() (*types.Plan, error)
+	// ListEvents returns a page of events and the total number of matches
+	ListEvents(filter types.EventFilter) ([]types.Event, int64, error)
+	// Close releases the backend's resources
+	Close() error
+}
+
+// Report is a period's stats together with the range they cover
+type Report struct {
+	From  time.Time
+	To    time.Time
+	Stats types.AttendanceStats
+}
+
+// ParseDate accepts YYYY-MM-DD or one of today, tomorrow and yesterday
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, today, tomorrow or yesterday", s)
+	}
+	return date, nil
+}
+
+// ParseRange accepts a single date or FROM..TO
+func ParseRange(s string, now time.Time) (time.Time, time.Time, error) {
+	fromStr, toStr, isRange := strings.Cut(s, "..")
+	from, err := ParseDate(fromStr, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err := ParseDate(toStr, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range %q: end is before start", s)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 1, 15, 18, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"today", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"Tomorrow", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"2025-02-03", time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		date, err := ParseDate(tt.input, now)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, date, tt.input)
+	}
+
+	_, err := ParseDate("next week", now)
+	assert.Error(t, err)
+}
+
+func TestParseRange(t *testing.T) {
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	from, to, err := ParseRange("2025-03-03..2025-03-07", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ParseRange("tomorrow", now)
+	assert.NoError(t, err)
+	assert.Equal(t, from, to)
+
+	_, _, err = ParseRange("2025-03-07..2025-03-03", now)
+	assert.Error(t, err)
+}
+
+func TestRemote_SetAttendance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/attendance/2025-01-15", r.URL.Path)
+		assert.Equal(t, "Bearer rto_test", r.Header.Get("Authorization"))
+
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "in", body["status"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 9, "date": "2025-01-15", "type": "attendance", "description": "", "isInOffice": true}`))
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL+"/", "rto_test")
+	event, err := remote.SetAttendance(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), event.ID)
+	assert.True(t, event.IsInOffice)
+}
+
+func TestRemote_ListEventsQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/events", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "attendance", q.Get("type"))
+		assert.Equal(t, "remote", q.Get("status"))
+		assert.Equal(t, "-date", q.Get("sort"))
+		assert.Equal(t, "5", q.Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"id": 3, "date": "2025-01-14", "type": "attendance", "description": "", "isInOffice": false}], "total": 12, "limit": 5, "offset": 0}`))
+	}))
+	defer server.Close()
+
+	inOffice := false
+	remote := NewRemote(server.URL, "rto_test")
+	events, total, err := remote.ListEvents(types.EventFilter{
+		Type:     "attendance",
+		InOffice: &inOffice,
+		Sort:     "-date",
+		Limit:    5,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	assert.Len(t, events, 1)
+	assert.Equal(t, time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), events[0].Date)
+}
+
+func TestRemote_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "forbidden", "message": "token lacks the write:events scope"}}`))
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL, "rto_test")
+	_, err := remote.ToggleAttendance(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusForbidden, apiErr.Status)
+		assert.Equal(t, "forbidden", apiErr.Code)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"log/slog"
+	"time"
+
+	repo "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/database"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// Local works directly against the SQLite database through the domain service,
+// acting as one account
+type Local struct {
+	service domain.RTOBLL
+	userID  int
+	db      *gorm.DB
+}
+
+// NewLocal opens the database at dbPath and acts as the named account, or the
+// only account when username is empty. The quarter is used when no stored
+// period covers today, the same as in the server.
+func NewLocal(dbPath, username string, logger *slog.Logger, quarterStart, quarterEnd time.Time) (*Local, error) {
+	db, err := database.OpenQuiet(dbPath, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	service := domain.NewService(
+		logger,
+		repo.NewEventRepositorySQLite(db),
+		repo.NewPreferenceRepositorySQLite(db),
+		repo.NewPeriodRepositorySQLite(db),
+		repo.NewTokenRepositorySQLite(db),
+		repo.NewWebhookRepositorySQLite(db),
+		repo.NewUserRepositorySQLite(db),
+		repo.NewSettingRepositorySQLite(db),
+		repo.NewSessionRepositorySQLite(db),
+		repo.NewCompanyRepositorySQLite(db),
+		repo.NewOfficeRepositorySQLite(db),
+		repo.NewNotificationRepositorySQLite(db),
+		quarterStart,
+		quarterEnd,
+	)
+
+	userID, err := service.ResolveUser(username)
+	if err != nil {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			sqlDB.Close()
+		}
+		return nil, err
+	}
+	return &Local{service: service, userID: userID, db: db}, nil
+}
+
+// NewLocalWithService wraps an existing service, mainly for tests
+func NewLocalWithService(service domain.RTOBLL, userID int) *Local {
+	return &Local{service: service, userID: userID}
+}
+
+func (l *Local) SetAttendance(date time.Time, inOffice bool) (*types.Event, error) {
+	return l.service.SetAttendance(l.userID, date, inOffice)
+}
+
+func (l *Local) ToggleAttendance(date time.Time) (string, error) {
+	return l.service.ToggleAttendance(l.userID, date)
+}
+
+func (l *Local) AddVacation(start, end time.Time, description string) (*types.BulkAddResponse, error) {
+	return l.service.AddVacation(l.userID, start, end, description)
+}
+
+func (l *Local) Stats() (*Report, error) {
+	period, err := l.service.GetCurrentPeriod()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := l.service.CalculateStatsBetween(l.userID, period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{From: period.StartDate, To: period.EndDate, Stats: *stats}, nil
+}
+
+func (l *Local) Plan() (*types.Plan, error) {
+	period, err := l.service.GetCurrentPeriod()
+	if err != nil {
+		return nil, err
+	}
+	return l.service.CalculatePlan(l.userID, period.StartDate, period.EndDate)
+}
+
+func (l *Local) ListEvents(filter types.EventFilter) ([]types.Event, int64, error) {
+	return l.service.QueryEvents(l.userID, filter)
+}
+
+func (l *Local) Close() error {
+	if l.db == nil {
+		return nil
+	}
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
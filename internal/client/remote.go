@@ -0,0 +1,244 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/controller"
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Remote talks to a running server's /api/v1 with a personal access token
+type Remote struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// APIError is returned when the server answers with an error body
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.Status, e.Code)
+}
+
+// NewRemote returns a backend for the server at baseURL, e.g. http://localhost:8761
+func NewRemote(baseURL, token string) *Remote {
+	return &Remote{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v1",
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (r *Remote) SetAttendance(date time.Time, inOffice bool) (*types.Event, error) {
+	status := "remote"
+	if inOffice {
+		status = "in"
+	}
+	var event controller.APIEvent
+	err := r.do(http.MethodPut, "/attendance/"+date.Format("2006-01-02"),
+		controller.APIAttendanceRequest{Status: status}, &event)
+	if err != nil {
+		return nil, err
+	}
+	return fromAPIEvent(event)
+}
+
+func (r *Remote) ToggleAttendance(date time.Time) (string, error) {
+	var resp controller.APIToggleResponse
+	if err := r.do(http.MethodPost, "/attendance/"+date.Format("2006-01-02")+"/toggle", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Status, nil
+}
+
+func (r *Remote) AddVacation(start, end time.Time, description string) (*types.BulkAddResponse, error) {
+	var resp types.BulkAddResponse
+	err := r.do(http.MethodPost, "/vacations", controller.APIVacationRequest{
+		From:        start.Format("2006-01-02"),
+		To:          end.Format("2006-01-02"),
+		Description: description,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r *Remote) Stats() (*Report, error) {
+	var stats controller.APIStats
+	if err := r.do(http.MethodGet, "/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	from, err := time.Parse("2006-01-02", stats.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.Parse("2006-01-02", stats.To)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		From: from,
+		To:   to,
+		Stats: types.AttendanceStats{
+			InOfficeCount:  stats.InOfficeCount,
+			TotalDays:      stats.TotalDays,
+			Average:        stats.Average,
+			AverageDays:    stats.AverageDays,
+			TargetDays:     stats.TargetDays,
+			AveragePercent: stats.AveragePercent,
+		},
+	}, nil
+}
+
+func (r *Remote) Plan() (*types.Plan, error) {
+	var plan controller.APIPlan
+	if err := r.do(http.MethodGet, "/plan", nil, &plan); err != nil {
+		return nil, err
+	}
+	from, err := time.Parse("2006-01-02", plan.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.Parse("2006-01-02", plan.To)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Plan{
+		From:          from,
+		To:            to,
+		TargetDays:    plan.TargetDays,
+		TotalDays:     plan.TotalDays,
+		RequiredDays:  plan.RequiredDays,
+		LoggedDays:    plan.LoggedDays,
+		PlannedDays:   plan.PlannedDays,
+		RemainingDays: plan.RemainingDays,
+		OpenDays:      plan.OpenDays,
+		WeeksLeft:     plan.WeeksLeft,
+		PerWeek:       plan.PerWeek,
+		Achievable:    plan.Achievable,
+	}, nil
+}
+
+func (r *Remote) ListEvents(filter types.EventFilter) ([]types.Event, int64, error) {
+	q := url.Values{}
+	if filter.Type != "" {
+		q.Set("type", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		q.Set("from", filter.From.Format("2006-01-02"))
+	}
+	if !filter.To.IsZero() {
+		q.Set("to", filter.To.Format("2006-01-02"))
+	}
+	if filter.InOffice != nil {
+		if *filter.InOffice {
+			q.Set("status", "in")
+		} else {
+			q.Set("status", "remote")
+		}
+	}
+	if filter.Search != "" {
+		q.Set("q", filter.Search)
+	}
+	if filter.Sort != "" {
+		q.Set("sort", filter.Sort)
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		q.Set("offset", strconv.Itoa(filter.Offset))
+	}
+
+	var page struct {
+		Data  []controller.APIEvent `json:"data"`
+		Total int64                 `json:"total"`
+	}
+	if err := r.do(http.MethodGet, "/events?"+q.Encode(), nil, &page); err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]types.Event, 0, len(page.Data))
+	for _, e := range page.Data {
+		event, err := fromAPIEvent(e)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, *event)
+	}
+	return events, page.Total, nil
+}
+
+func (r *Remote) Close() error {
+	return nil
+}
+
+// do sends a JSON request and decodes a JSON response into out
+func (r *Remote) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, r.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr controller.APIErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error.Code == "" {
+			return &APIError{Status: resp.StatusCode, Code: "http_error", Message: resp.Status}
+		}
+		return &APIError{Status: resp.StatusCode, Code: apiErr.Error.Code, Message: apiErr.Error.Message}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func fromAPIEvent(e controller.APIEvent) (*types.Event, error) {
+	date, err := time.Parse("2006-01-02", e.Date)
+	if err != nil {
+		return nil, fmt.Errorf("server returned invalid date %q", e.Date)
+	}
+	return &types.Event{
+		ID:          e.ID,
+		Date:        date,
+		Type:        e.Type,
+		Description: e.Description,
+		IsInOffice:  e.IsInOffice,
+	}, nil
+}
@@ -0,0 +1,159 @@
+// Package config holds settings shared by the server and the command-line tools.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Quarter configuration – centralizing start/end dates for the quarter
+var (
+	QuarterStart = time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC)
+	QuarterEnd   = time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// DefaultDBPath is used when DB_PATH is not set
+const DefaultDBPath = "./data/db.sqlite3"
+
+// DBPath reads DB_PATH from the environment, falling back to DefaultDBPath
+func DBPath() string {
+	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
+		return dbPath
+	}
+	return DefaultDBPath
+}
+
+// SessionSecret returns SESSION_SECRET, the key that signs session cookies.
+// When it is empty the server generates a key and keeps it in the database.
+func SessionSecret() string {
+	return os.Getenv("SESSION_SECRET")
+}
+
+// TLSFiles returns TLS_CERT_FILE and TLS_KEY_FILE. The server speaks HTTPS
+// when both are set.
+func TLSFiles() (certFile, keyFile string, ok bool) {
+	certFile, keyFile = os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	return certFile, keyFile, certFile != "" && keyFile != ""
+}
+
+// SecureCookies reports whether cookies are only sent over HTTPS. COOKIE_SECURE
+// overrides the default, which is on when the server itself serves TLS. Set
+// it to true behind a proxy that terminates TLS.
+func SecureCookies() bool {
+	if secure, err := strconv.ParseBool(os.Getenv("COOKIE_SECURE")); err == nil {
+		return secure
+	}
+	_, _, tls := TLSFiles()
+	return tls
+}
+
+// CORSOrigins reads CORS_ORIGINS, the other sites whose pages may call the API
+// with a token, e.g. "https://dash.example.com". Empty allows none.
+func CORSOrigins() []string {
+	return list(os.Getenv("CORS_ORIGINS"))
+}
+
+// OIDC holds the OpenID Connect single sign-on settings
+type OIDC struct {
+	IssuerURL     string   // OIDC_ISSUER; sign-on is off when empty
+	ClientID      string   // OIDC_CLIENT_ID
+	ClientSecret  string   // OIDC_CLIENT_SECRET
+	RedirectURL   string   // OIDC_REDIRECT_URL, e.g. https://rto.example.com/auth/oidc/callback
+	Scopes        []string // OIDC_SCOPES, default "email profile"
+	GroupsClaim   string   // OIDC_GROUPS_CLAIM, default "groups"
+	AdminGroups   []string // OIDC_ADMIN_GROUPS; members are admins
+	ManagerGroups []string // OIDC_MANAGER_GROUPS; members are managers unless also admins
+}
+
+// OIDCSettings reads the OIDC_* variables
+func OIDCSettings() OIDC {
+	scopes := list(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{"email", "profile"}
+	}
+	return OIDC{
+		IssuerURL:     strings.TrimRight(os.Getenv("OIDC_ISSUER"), "/"),
+		ClientID:      os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:  os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:   os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:        scopes,
+		GroupsClaim:   os.Getenv("OIDC_GROUPS_CLAIM"),
+		AdminGroups:   list(os.Getenv("OIDC_ADMIN_GROUPS")),
+		ManagerGroups: list(os.Getenv("OIDC_MANAGER_GROUPS")),
+	}
+}
+
+// SMTP holds the mail server settings for email reminders
+type SMTP struct {
+	Host     string // SMTP_HOST; email is off when empty
+	Port     int    // SMTP_PORT, default 587
+	Username string // SMTP_USERNAME; no authentication when empty
+	Password string // SMTP_PASSWORD
+	From     string // SMTP_FROM, default "rto@" + SMTP_HOST
+}
+
+// SMTPSettings reads the SMTP_* variables
+func SMTPSettings() SMTP {
+	settings := SMTP{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     587,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if port, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil {
+		settings.Port = port
+	}
+	if settings.From == "" {
+		settings.From = "rto@" + settings.Host
+	}
+	return settings
+}
+
+// JobSchedule reads JOB_<NAME>, the cron schedule of a scheduled job such as
+// JOB_BACKUP="0 2 * * *". "off" leaves the job to be run by hand.
+func JobSchedule(name, fallback string) string {
+	if spec := strings.TrimSpace(os.Getenv("JOB_" + strings.ToUpper(name))); spec != "" {
+		return spec
+	}
+	return fallback
+}
+
+// SchedulerEnabled reports whether the server runs scheduled jobs.
+// SCHEDULER=false turns them off, e.g. for a read-only replica.
+func SchedulerEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("SCHEDULER"))
+	return err != nil || enabled
+}
+
+// BackupDir reads BACKUP_DIR, where nightly backups go; by default a
+// backups directory next to the database
+func BackupDir(dbPath string) string {
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// BackupKeep reads BACKUP_KEEP, how many nightly backups to keep (default 7)
+func BackupKeep() int {
+	if keep, err := strconv.Atoi(os.Getenv("BACKUP_KEEP")); err == nil && keep > 0 {
+		return keep
+	}
+	return 7
+}
+
+// PasswordLogin reports whether sign-in with a username and password is allowed.
+// PASSWORD_LOGIN=false turns it off; the server ignores that unless single sign-on is set up.
+func PasswordLogin() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("PASSWORD_LOGIN"))
+	return err != nil || enabled
+}
+
+// list splits a comma or space separated value
+func list(value string) []string {
+	return strings.Fields(strings.ReplaceAll(value, ",", " "))
+}
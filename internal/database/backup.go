@@ -0,0 +1,148 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Backup writes a consistent copy of the open database to dest using
+// VACUUM INTO, which is safe while the server is running. dest must not exist.
+func Backup(db *gorm.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup: %s already exists", dest)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("backup: %w", err)
+	}
+	if err := db.Exec("VACUUM INTO ?", dest).Error; err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	return nil
+}
+
+// RotatingBackup writes a timestamped backup into dir, creating it if
+// needed, and removes the oldest ones so that keep remain. It returns the
+// new backup's path.
+func RotatingBackup(db *gorm.DB, dir string, keep int, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("backup: %w", err)
+	}
+	dest := filepath.Join(dir, "rto-"+now.Format("2006-01-02T150405")+".sqlite3")
+	if err := Backup(db, dest); err != nil {
+		return "", err
+	}
+
+	// The names sort by time
+	old, err := filepath.Glob(filepath.Join(dir, "rto-*.sqlite3"))
+	if err != nil {
+		return dest, err
+	}
+	sort.Strings(old)
+	for len(old) > keep {
+		if err := os.Remove(old[0]); err != nil {
+			return dest, fmt.Errorf("backup: %w", err)
+		}
+		old = old[1:]
+	}
+	return dest, nil
+}
+
+// IntegrityCheck runs SQLite's integrity check and returns the problems it
+// reports, or nothing when the file is sound
+func IntegrityCheck(db *gorm.DB) ([]string, error) {
+	var rows []string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	if len(rows) == 1 && rows[0] == "ok" {
+		return nil, nil
+	}
+	return rows, nil
+}
+
+// Restore replaces the database at dbPath with the backup at src. The backup
+// is checked first, and the current database is saved next to dbPath; the
+// path of that copy is returned ("" when there was no database yet). The
+// server must be stopped while restoring.
+func Restore(src, dbPath string) (string, error) {
+	if err := checkBackup(src); err != nil {
+		return "", err
+	}
+
+	saved := ""
+	if _, err := os.Stat(dbPath); err == nil {
+		saved = fmt.Sprintf("%s.%s.bak", dbPath, time.Now().Format("20060102-150405"))
+		if err := copyFile(dbPath, saved); err != nil {
+			return "", fmt.Errorf("restore: save current database: %w", err)
+		}
+	}
+
+	// Copy next to the target first so a failed copy never leaves a half-written database
+	tmp := dbPath + ".restore"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("restore: %w", err)
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("restore: %w", err)
+	}
+	return saved, nil
+}
+
+// checkBackup makes sure src is a sound SQLite file with the events table
+func checkBackup(src string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	db, err := gorm.Open(sqlite.Open(src), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		return fmt.Errorf("restore: open %s: %w", src, err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	problems, err := IntegrityCheck(db)
+	if err != nil {
+		return fmt.Errorf("restore: %s is not a usable database: %w", src, err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("restore: %s failed the integrity check: %s", src, problems[0])
+	}
+	if !db.Migrator().HasTable(&types.Event{}) {
+		return fmt.Errorf("restore: %s has no events table", src)
+	}
+	return nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,87 @@
+// Package database opens the SQLite database and keeps its schema current.
+package database
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Models lists every table managed by AutoMigrate
+var Models = []interface{}{
+	&types.Event{},
+	&types.Preferences{},
+	&types.Period{},
+	&types.APIToken{},
+	&types.Webhook{},
+	&types.WebhookDelivery{},
+	&types.User{},
+	&types.Setting{},
+	&types.Session{},
+	&types.CompanyRequest{},
+	&types.Office{},
+	&types.Resource{},
+	&types.Booking{},
+	&types.NotificationSettings{},
+	&types.EmailDelivery{},
+	&types.ScheduledJob{},
+	&types.PeriodArchive{},
+	&types.PreferenceVersion{},
+}
+
+// Open connects to the SQLite database at dbPath and migrates the schema
+func Open(dbPath string, logger *slog.Logger) (*gorm.DB, error) {
+	return open(dbPath, logger, &gorm.Config{})
+}
+
+// OpenQuiet is Open without GORM's query logging, which goes to stdout and
+// would mix with command-line output. Errors are still returned as usual.
+func OpenQuiet(dbPath string, logger *slog.Logger) (*gorm.DB, error) {
+	return open(dbPath, logger, &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+}
+
+func open(dbPath string, logger *slog.Logger, config *gorm.Config) (*gorm.DB, error) {
+	logger.Info("opening database", "db", dbPath)
+	db, err := gorm.Open(sqlite.Open(dbPath), config)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Migrate brings the schema up to date
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(Models...); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+	if err := migrateAdminFlag(db); err != nil {
+		return fmt.Errorf("migrate user roles: %w", err)
+	}
+	return nil
+}
+
+// migrateAdminFlag turns the is_admin column of databases from before roles
+// into the admin role. SQLite drops a column by rebuilding the table, which
+// loses its indexes, so they are migrated again afterwards.
+func migrateAdminFlag(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&types.User{}, "is_admin") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE users SET role = ? WHERE is_admin", types.RoleAdmin).Error; err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(&types.User{}, "is_admin"); err != nil {
+			return err
+		}
+		return tx.AutoMigrate(&types.User{})
+	})
+}
@@ -0,0 +1,287 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testQuarterStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	testQuarterEnd   = time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func TestSeed_IsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	holidays := filepath.Join(dir, "holidays.json")
+	assert.NoError(t, os.WriteFile(holidays, []byte(`[
+		{"date": "2025-01-01", "description": "New Year", "type": "holiday"},
+		{"date": "2025-01-20", "description": "MLK Day", "type": "holiday"}
+	]`), 0o644))
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(dir, "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, SeedPeriods(db, logger, testQuarterStart, testQuarterEnd))
+	}
+	added, err := SeedHolidays(db, logger, holidays)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	added, err = SeedHolidays(db, logger, holidays)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, added)
+
+	var periods, events int64
+	db.Model(&types.Period{}).Count(&periods)
+	db.Model(&types.Event{}).Count(&events)
+	assert.Equal(t, int64(1), periods)
+	assert.Equal(t, int64(2), events)
+}
+
+// Holidays never change a closed period behind its archived stats
+func TestSeedHolidays_SkipsClosedPeriods(t *testing.T) {
+	dir := t.TempDir()
+	holidays := filepath.Join(dir, "holidays.json")
+	assert.NoError(t, os.WriteFile(holidays, []byte(`[
+		{"date": "2025-01-20", "description": "MLK Day", "type": "holiday"},
+		{"date": "2025-05-26", "description": "Memorial Day", "type": "holiday"}
+	]`), 0o644))
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(dir, "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAt := testQuarterEnd.AddDate(0, 0, 1)
+	assert.NoError(t, db.Create(&types.Period{Name: "Q1 2025", StartDate: testQuarterStart, EndDate: testQuarterEnd, ClosedAt: &closedAt}).Error)
+
+	added, err := SeedHolidays(db, logger, holidays)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	var events []types.Event
+	db.Find(&events)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "Memorial Day", events[0].Description)
+	}
+
+	// Once the period is reopened the holiday goes in
+	assert.NoError(t, db.Model(&types.Period{}).Where("1 = 1").Update("closed_at", nil).Error)
+	added, err = SeedHolidays(db, logger, holidays)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "rto.db")
+	backupPath := filepath.Join(dir, "backup.db")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	db, err := OpenQuiet(dbPath, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, db.Create(&types.Event{Date: testQuarterStart, Type: "holiday", Description: "New Year"}).Error)
+
+	assert.NoError(t, Backup(db, backupPath))
+	assert.Error(t, Backup(db, backupPath), "existing destination")
+
+	problems, err := IntegrityCheck(db)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// Change the live database, then restore the backup over it
+	assert.NoError(t, db.Create(&types.Event{Date: testQuarterEnd, Type: "vacation", Description: "Trip"}).Error)
+	sqlDB, _ := db.DB()
+	assert.NoError(t, sqlDB.Close())
+
+	saved, err := Restore(backupPath, dbPath)
+	assert.NoError(t, err)
+	assert.FileExists(t, saved)
+
+	db, err = OpenQuiet(dbPath, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Model(&types.Event{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRotatingBackup_KeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	backups := filepath.Join(dir, "backups")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(dir, "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for i := 0; i < 4; i++ {
+		path, err := RotatingBackup(db, backups, 2, testQuarterStart.Add(time.Duration(i)*time.Hour))
+		assert.NoError(t, err)
+		paths = append(paths, path)
+	}
+
+	kept, _ := filepath.Glob(filepath.Join(backups, "*"))
+	assert.Equal(t, paths[2:], kept)
+}
+
+func TestRestore_RejectsNonDatabase(t *testing.T) {
+	dir := t.TempDir()
+	bogus := filepath.Join(dir, "notes.txt")
+	assert.NoError(t, os.WriteFile(bogus, []byte("not a database"), 0o644))
+
+	_, err := Restore(bogus, filepath.Join(dir, "rto.db"))
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "rto.db"))
+}
+
+func TestMigrate_AdminFlagBecomesRole(t *testing.T) {
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	db, err := OpenQuiet(filepath.Join(dir, "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The users table as it was before roles
+	type legacyUser struct {
+		types.User
+		IsAdmin bool `gorm:"not null;default:false"`
+	}
+	legacy := db.Table("users")
+	assert.NoError(t, legacy.AutoMigrate(&legacyUser{}))
+	assert.NoError(t, legacy.Create(&legacyUser{User: types.User{Username: "alice", PasswordHash: "x"}, IsAdmin: true}).Error)
+	assert.NoError(t, legacy.Create(&legacyUser{User: types.User{Username: "bob", PasswordHash: "x"}}).Error)
+
+	assert.NoError(t, Migrate(db))
+
+	var users []types.User
+	assert.NoError(t, db.Order("username").Find(&users).Error)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, types.RoleAdmin, users[0].Role)
+		assert.Equal(t, types.RoleEmployee, users[1].Role)
+	}
+	assert.False(t, db.Migrator().HasColumn(&types.User{}, "is_admin"))
+	// Usernames are still unique after the table was rebuilt
+	assert.Error(t, db.Create(&types.User{Username: "alice", PasswordHash: "x"}).Error)
+}
+
+func TestAddFirstUser_OnlyOneWins(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(t.TempDir(), "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewUserRepositorySQLite(db)
+
+	// Registrations racing on an empty database: exactly one becomes the admin
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.AddFirstUser(types.User{Username: fmt.Sprintf("user%d", i), PasswordHash: "x", Role: types.RoleAdmin})
+		}(i)
+	}
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		if err == nil {
+			won++
+		} else {
+			assert.True(t, errors.Is(err, repository.ErrNotFirstUser), err.Error())
+		}
+	}
+	var users int64
+	db.Model(&types.User{}).Count(&users)
+	assert.Equal(t, 1, won)
+	assert.Equal(t, int64(1), users)
+}
+
+func TestAddDeskBooking_KeepsToCapacity(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(t.TempDir(), "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewOfficeRepositorySQLite(db)
+	desk := func(userID, resourceID uint) types.Booking {
+		return types.Booking{UserID: userID, OfficeID: 1, ResourceID: resourceID, Kind: types.ResourceDesk, Date: testQuarterStart}
+	}
+
+	// Six people race for the free desks of an office that lets in two a day
+	errs := make([]error, 6)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.AddDeskBooking(desk(uint(i+1), uint(i+1)), 2)
+		}(i)
+	}
+	wg.Wait()
+
+	booked := 0
+	for _, err := range errs {
+		if err == nil {
+			booked++
+		} else {
+			assert.True(t, errors.Is(err, repository.ErrOfficeFull), err.Error())
+		}
+	}
+	var bookings int64
+	db.Model(&types.Booking{}).Count(&bookings)
+	assert.Equal(t, 2, booked)
+	assert.Equal(t, int64(2), bookings)
+
+	// The same desk twice is caught by its unique index
+	_, err = repo.AddDeskBooking(desk(7, 10), 5)
+	assert.NoError(t, err)
+	_, err = repo.AddDeskBooking(desk(8, 10), 5)
+	assert.ErrorIs(t, err, repository.ErrAlreadyBooked)
+	_, err = repo.AddBooking(desk(8, 10))
+	assert.ErrorIs(t, err, repository.ErrAlreadyBooked)
+}
+
+func TestAssignUnownedData_MovesPreferenceHistory(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	db, err := OpenQuiet(filepath.Join(t.TempDir(), "rto.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A database from before accounts keeps everything under user 0
+	assert.NoError(t, db.Create(&types.Preferences{UserID: 0, DefaultDays: "M,T", TargetDays: "2"}).Error)
+	for _, from := range []time.Time{testQuarterStart, testQuarterStart.AddDate(0, 1, 0)} {
+		version := types.PreferenceVersion{UserID: 0, EffectiveFrom: from, DefaultDays: "M,T", TargetDays: "3", CalculationMethod: types.MethodWorkdays}
+		assert.NoError(t, db.Create(&version).Error)
+	}
+
+	assigned, err := repository.NewUserRepositorySQLite(db).AssignUnownedData(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), assigned)
+
+	prefs, err := repository.NewPreferenceRepositorySQLite(db).GetPreferences(1)
+	assert.NoError(t, err)
+	assert.Len(t, prefs.Versions, 2)
+}
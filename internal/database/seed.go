@@ -0,0 +1,143 @@
+package database
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// HolidaysFile is the holiday list loaded at startup, relative to the working directory
+var HolidaysFile = filepath.Join("static", "holidays.json")
+
+// Seed fills in what a fresh database needs: the holidays from HolidaysFile
+// and the configured quarter as the first period. Each step leaves existing
+// data alone, so it is safe to run on every start. Preferences are created per
+// user by the service.
+func Seed(db *gorm.DB, logger *slog.Logger, quarterStart, quarterEnd time.Time) error {
+	// Initialize holidays
+	if _, err := SeedHolidays(db, logger, HolidaysFile); err != nil {
+		return err
+	}
+
+	// Seed the configured quarter as the first period
+	return SeedPeriods(db, logger, quarterStart, quarterEnd)
+}
+
+// SeedHolidays inserts the holidays listed in a JSON file that are not in the
+// database yet and returns how many were added. Dates inside closed periods
+// are skipped, as the service would refuse them, so archived stats keep
+// matching the calendar; reopen the period and run it again to add them.
+func SeedHolidays(db *gorm.DB, logger *slog.Logger, path string) (int, error) {
+	// Load holidays from JSON file
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		logger.Error("Failed to read holidays file", "path", path, "error", err)
+		return 0, err
+	}
+
+	// Define a temporary struct for unmarshaling
+	type RawHoliday struct {
+		Date        string `json:"date"`
+		Description string `json:"description"`
+		Type        string `json:"type"`       // e.g., "holiday", "vacation"
+		IsInOffice  bool   `json:"isInOffice"` // Optional
+	}
+
+	var rawHolidays []RawHoliday
+	if err := json.Unmarshal(byteValue, &rawHolidays); err != nil {
+		logger.Error("Failed to parse holidays file", "path", path, "error", err)
+		return 0, err
+	}
+
+	var closed []types.Period
+	if err := db.Where("closed_at IS NOT NULL").Find(&closed).Error; err != nil {
+		logger.Error("Failed to load closed periods", "error", err)
+		return 0, err
+	}
+
+	// Insert holidays into the database if they don't already exist
+	added := 0
+	for _, rawHoliday := range rawHolidays {
+		// Parse date
+		date, err := utils.ParseDate(rawHoliday.Date)
+		if err != nil {
+			logger.Error("Invalid date in holidays file", "date", rawHoliday.Date, "error", err)
+			continue
+		}
+
+		if inClosedPeriod(closed, date) {
+			logger.Info("Skipping holiday in a closed period", "date", rawHoliday.Date, "name", rawHoliday.Description)
+			continue
+		}
+
+		// Create an Event
+		holiday := types.Event{
+			Date:        date,
+			Description: rawHoliday.Description,
+			Type:        rawHoliday.Type,
+			IsInOffice:  false, // Holidays override attendance
+		}
+
+		// Check if the holiday already exists
+		var count int64
+		db.Model(&types.Event{}).
+			Where("date = ? AND type = ?", date, holiday.Type).
+			Count(&count)
+
+		if count == 0 {
+			// Insert holiday
+			if err := db.Create(&holiday).Error; err != nil {
+				logger.Error("Failed to insert holiday", "date", date, "error", err)
+			} else {
+				added++
+				logger.Info("Inserted holiday", "date", date, "name", holiday.Description)
+			}
+		}
+	}
+
+	return added, nil
+}
+
+// inClosedPeriod reports whether the date falls in one of the closed periods
+func inClosedPeriod(closed []types.Period, date time.Time) bool {
+	for _, period := range closed {
+		if period.Contains(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// SeedPeriods stores the configured quarter as a period when no periods exist yet
+func SeedPeriods(db *gorm.DB, logger *slog.Logger, quarterStart, quarterEnd time.Time) error {
+	var count int64
+	if err := db.Model(&types.Period{}).Count(&count).Error; err != nil {
+		logger.Error("Failed to count periods", "error", err)
+		return err
+	}
+
+	if count > 0 {
+		logger.Info("Periods already exist")
+		return nil
+	}
+
+	period := types.Period{
+		Name:      domain.PeriodName(quarterStart, quarterEnd),
+		StartDate: quarterStart,
+		EndDate:   quarterEnd,
+	}
+	if err := db.Create(&period).Error; err != nil {
+		logger.Error("Failed to create default period", "error", err)
+		return err
+	}
+	logger.Info("Default period created", "name", period.Name)
+
+	return nil
+}
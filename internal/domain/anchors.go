@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// dayAbbrevs are the day names used in default days and anchor days, in week order
+var dayAbbrevs = []struct {
+	abbrev string
+	day    time.Weekday
+}{
+	{"M", time.Monday},
+	{"T", time.Tuesday},
+	{"W", time.Wednesday},
+	{"Th", time.Thursday},
+	{"F", time.Friday},
+	{"Sat", time.Saturday},
+	{"Sun", time.Sunday},
+}
+
+// parseDays reads a list like "M,W,Th"
+func parseDays(list string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		found := false
+		for _, d := range dayAbbrevs {
+			if strings.EqualFold(part, d.abbrev) {
+				days[d.day] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: unknown day %q", ErrInvalidInput, part)
+		}
+	}
+	return days, nil
+}
+
+// formatDays writes the days back as a list in week order
+func formatDays(days map[time.Weekday]bool) string {
+	var parts []string
+	for _, d := range dayAbbrevs {
+		if days[d.day] {
+			parts = append(parts, d.abbrev)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// SetAnchorDays sets the weekdays a manager's whole team is expected in. An
+// empty list clears them.
+func (s *Service) SetAnchorDays(managerID int, days string) error {
+	parsed, err := parseDays(days)
+	if err != nil {
+		return err
+	}
+	if parsed[time.Saturday] || parsed[time.Sunday] {
+		return fmt.Errorf("%w: anchor days must be weekdays", ErrInvalidInput)
+	}
+
+	manager, err := s.getUser(managerID)
+	if err != nil {
+		return err
+	}
+	manager.AnchorDays = formatDays(parsed)
+	if err := s.userRepo.UpdateUser(manager); err != nil {
+		s.logger.Error("Error saving anchor days", "userID", managerID, "error", err)
+		return err
+	}
+	s.logger.Info("Anchor days set", "userID", managerID, "anchorDays", manager.AnchorDays)
+	return nil
+}
+
+// teamAnchorDays returns the anchor days the user follows: their manager's,
+// or their own when they report to nobody
+func (s *Service) teamAnchorDays(user types.User) (string, error) {
+	if user.ManagerID == nil {
+		return user.AnchorDays, nil
+	}
+	manager, err := s.getUser(int(*user.ManagerID))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return manager.AnchorDays, nil
+}
+
+// AnchorCompliance reports how often the user came in on their team's anchor
+// days between start and end, up to today
+func (s *Service) AnchorCompliance(userID int, start, end time.Time) (*types.AnchorCompliance, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	anchorDays, err := s.teamAnchorDays(user)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.GetEventsBetweenDates(userID, utils.NormalizeDate(start), utils.NormalizeDate(end))
+	if err != nil {
+		s.logger.Error("Error fetching events", "userID", userID, "error", err)
+		return nil, err
+	}
+	compliance := anchorCompliance(anchorDays, events, start, end, time.Now())
+	return &compliance, nil
+}
+
+// anchorCompliance counts the anchor days between start and the earlier of
+// end and now. Holidays and vacation are not held against anyone.
+func anchorCompliance(anchorDays string, events []types.Event, start, end, now time.Time) types.AnchorCompliance {
+	compliance := types.AnchorCompliance{AnchorDays: anchorDays}
+	anchors, err := parseDays(anchorDays)
+	if err != nil || len(anchors) == 0 {
+		return compliance
+	}
+
+	start, end = utils.NormalizeDate(start), utils.NormalizeDate(end)
+	if today := utils.NormalizeDate(now); end.After(today) {
+		end = today
+	}
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if anchors[d.Weekday()] {
+			dates = append(dates, d)
+		}
+	}
+
+	for _, status := range dayStatuses(events, dates) {
+		switch status {
+		case types.DayHoliday, types.DayVacation:
+			continue
+		case types.DayInOffice:
+			compliance.Attended++
+		}
+		compliance.Days++
+	}
+	if compliance.Days > 0 {
+		compliance.Percent = float64(compliance.Attended) / float64(compliance.Days) * 100
+	}
+	return compliance
+}
+
+// SuggestDefaultDays proposes a default-day pattern for the user: their
+// team's anchor days, topped up to their weekly target with the weekdays
+// most common in their teammates' default days, so office days overlap
+func (s *Service) SuggestDefaultDays(userID int) (string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return "", err
+	}
+	anchorDays, err := s.teamAnchorDays(user)
+	if err != nil {
+		return "", err
+	}
+	suggested, _ := parseDays(anchorDays)
+
+	team, err := s.Teammates(userID)
+	if err != nil {
+		return "", err
+	}
+	votes := make(map[time.Weekday]int)
+	for _, mate := range team {
+		days := s.GetPrefs(int(mate.ID)).DefaultDays
+		if strings.EqualFold(days, types.TeamDefaultDays) {
+			// Their own suggestion would depend on this one; count their anchors
+			if days, err = s.teamAnchorDays(mate); err != nil {
+				return "", err
+			}
+		}
+		parsed, err := parseDays(days)
+		if err != nil {
+			continue
+		}
+		for day := range parsed {
+			votes[day]++
+		}
+	}
+
+	var candidates []time.Weekday
+	for _, d := range dayAbbrevs[:5] {
+		if !suggested[d.day] {
+			candidates = append(candidates, d.day)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return votes[candidates[i]] > votes[candidates[j]]
+	})
+
+	want := int(math.Min(math.Ceil(targetDays(s.GetPrefs(userID))), 5))
+	for _, day := range candidates {
+		if len(suggested) >= want {
+			break
+		}
+		suggested[day] = true
+	}
+	return formatDays(suggested), nil
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseDays(t *testing.T) {
+	days, err := parseDays(" th, m ,W")
+	assert.NoError(t, err)
+	assert.Equal(t, "M,W,Th", formatDays(days))
+
+	_, err = parseDays("M,Tu")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestAnchorCompliance(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+	events := []types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true},  // Tue
+		{Date: day(2), Type: "attendance", IsInOffice: false}, // Wed
+		{Date: day(8), Type: "vacation"},                      // Tue
+		{Date: day(9), Type: "attendance", IsInOffice: true},  // Wed
+		{Date: day(10), Type: "attendance", IsInOffice: true}, // Thu, not an anchor
+	}
+
+	// Days after now are not counted yet
+	compliance := anchorCompliance("T,W", events, day(1), day(31), day(16))
+
+	assert.Equal(t, "T,W", compliance.AnchorDays)
+	// Oct 1, 2, 9, 15, 16; the 8th was vacation
+	assert.Equal(t, 5, compliance.Days)
+	assert.Equal(t, 2, compliance.Attended)
+	assert.InDelta(t, 40.0, compliance.Percent, 0.001)
+
+	assert.Zero(t, anchorCompliance("", events, day(1), day(31), day(16)).Days)
+}
+
+func TestSetAnchorDays(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Role: types.RoleManager}, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.AnchorDays == "T,W"
+	})).Return(nil).Once()
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	err := service.SetAnchorDays(1, "W,Sat")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	assert.NoError(t, service.SetAnchorDays(1, "w,t"))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSuggestDefaultDays(t *testing.T) {
+	manager := uint(1)
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "mgr", AnchorDays: "T"}, nil)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "ann", ManagerID: &manager}, nil)
+	mockUserRepo.On("GetUsersByManager", 1).Return([]types.User{
+		{ID: 2, Username: "ann", ManagerID: &manager},
+		{ID: 3, Username: "bob", ManagerID: &manager},
+		{ID: 4, Username: "cy", ManagerID: &manager},
+	}, nil)
+	mockUserRepo.On("GetUsersByManager", 2).Return([]types.User{}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 2).Return(types.Preferences{DefaultDays: "M,F", TargetDays: "2.5"}, nil)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{DefaultDays: "W,Th"}, nil)
+	mockPrefsRepo.On("GetPreferences", 3).Return(types.Preferences{DefaultDays: "W,Th,F"}, nil)
+	// Following the team counts as the anchors, not a suggestion of its own
+	mockPrefsRepo.On("GetPreferences", 4).Return(types.Preferences{DefaultDays: types.TeamDefaultDays}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:       mockUserRepo,
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	days, err := service.SuggestDefaultDays(2)
+
+	// The anchor Tuesday, then Wednesday and Thursday, which two teammates
+	// share, up to three days for a 2.5 target
+	assert.NoError(t, err)
+	assert.Equal(t, "T,W,Th", days)
+}
+
+func TestFillDefaultDays_FollowsTeam(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "mgr", AnchorDays: "W"}, nil)
+	mockUserRepo.On("GetUsersByManager", 1).Return([]types.User{}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{DefaultDays: types.TeamDefaultDays, TargetDays: "1"}, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{}, nil)
+	mockEventRepo.On("AddEvent", mock.Anything).Return(nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:       mockUserRepo,
+		preferenceRepo: mockPrefsRepo,
+		eventRepo:      mockEventRepo,
+		periodRepo:     openPeriods(),
+	}
+
+	// Mon Oct 7 to Wed Oct 9
+	added, err := service.FillDefaultDays(1, time.Date(2024, time.October, 7, 0, 0, 0, 0, time.UTC), time.Date(2024, time.October, 9, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, added)
+	for _, call := range mockEventRepo.Calls {
+		if call.Method != "AddEvent" {
+			continue
+		}
+		event := call.Arguments.Get(0).(types.Event)
+		assert.Equal(t, event.Date.Weekday() == time.Wednesday, event.IsInOffice, event.Date.String())
+	}
+}
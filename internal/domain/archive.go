@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ClosePeriod archives every user's final stats and weekly breakdown for the
+// period and closes it, which locks the days inside it. Only a period that
+// has ended can be closed.
+func (s *Service) ClosePeriod(periodID int) (*types.Period, error) {
+	return s.closePeriod(periodID, time.Now())
+}
+
+func (s *Service) closePeriod(periodID int, now time.Time) (*types.Period, error) {
+	period, err := s.GetPeriod(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Closed() {
+		return nil, fmt.Errorf("%w: %s is already closed", ErrConflict, period.Name)
+	}
+	if !period.EndDate.Before(utils.NormalizeDate(now)) {
+		return nil, fmt.Errorf("%w: %s has not ended yet", ErrConflict, period.Name)
+	}
+
+	users, err := s.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	var archives []types.PeriodArchive
+	for _, user := range users {
+		events, err := s.eventRepo.GetAllEvents(int(user.ID))
+		if err != nil {
+			s.logger.Error("Error fetching events to archive", "userID", user.ID, "error", err)
+			return nil, err
+		}
+		// Nothing to keep for accounts that joined later with no days in the period
+		if utils.NormalizeDate(user.CreatedAt).After(period.EndDate) && !loggedIn(events, *period) {
+			continue
+		}
+		stats := s.attendanceStats(int(user.ID), events, period.StartDate, period.EndDate)
+		archives = append(archives, types.PeriodArchive{
+			PeriodID:       period.ID,
+			UserID:         user.ID,
+			InOfficeCount:  stats.InOfficeCount,
+			TotalDays:      stats.TotalDays,
+			Average:        stats.Average,
+			AverageDays:    stats.AverageDays,
+			TargetDays:     stats.TargetDays,
+			AveragePercent: stats.AveragePercent,
+			ByOffice:       stats.ByOffice,
+			Uncounted:      stats.Uncounted,
+			Method:         stats.Method,
+			Weeks:          weeklyBreakdown(events, period.StartDate, period.EndDate),
+		})
+	}
+	if err := s.periodRepo.SaveArchives(periodID, archives); err != nil {
+		s.logger.Error("Error archiving period", "periodID", periodID, "error", err)
+		return nil, err
+	}
+
+	closedAt := now
+	if err := s.periodRepo.SetPeriodClosed(periodID, &closedAt); err != nil {
+		s.logger.Error("Error closing period", "periodID", periodID, "error", err)
+		return nil, err
+	}
+	period.ClosedAt = &closedAt
+	s.logger.Info("Period closed", "period", period.Name, "archived", len(archives))
+	return period, nil
+}
+
+// ReopenPeriod unlocks a closed period. Its archive is dropped, so stats are
+// worked out from the days again until it is closed once more.
+func (s *Service) ReopenPeriod(periodID int) (*types.Period, error) {
+	period, err := s.GetPeriod(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if !period.Closed() {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrConflict, period.Name)
+	}
+	if err := s.periodRepo.SetPeriodClosed(periodID, nil); err != nil {
+		s.logger.Error("Error reopening period", "periodID", periodID, "error", err)
+		return nil, err
+	}
+	if err := s.periodRepo.DeleteArchives(periodID); err != nil {
+		s.logger.Error("Error dropping period archive", "periodID", periodID, "error", err)
+		return nil, err
+	}
+	period.ClosedAt = nil
+	s.logger.Info("Period reopened", "period", period.Name)
+	return period, nil
+}
+
+// CloseEndedPeriods closes every period that has ended and is still open
+func (s *Service) CloseEndedPeriods() ([]types.Period, error) {
+	return s.closeEndedPeriods(time.Now())
+}
+
+func (s *Service) closeEndedPeriods(now time.Time) ([]types.Period, error) {
+	periods, err := s.GetPeriods()
+	if err != nil {
+		return nil, err
+	}
+	today := utils.NormalizeDate(now)
+	var closed []types.Period
+	for _, period := range periods {
+		if period.Closed() || !period.EndDate.Before(today) {
+			continue
+		}
+		done, err := s.closePeriod(int(period.ID), now)
+		if err != nil {
+			return closed, err
+		}
+		closed = append(closed, *done)
+	}
+	return closed, nil
+}
+
+// GetPeriodArchive returns the user's archived stats for a closed period
+func (s *Service) GetPeriodArchive(userID, periodID int) (*types.PeriodArchive, error) {
+	period, err := s.GetPeriod(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if !period.Closed() {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrNotFound, period.Name)
+	}
+	archive, err := s.periodRepo.GetArchive(periodID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no archived stats for %s", ErrNotFound, period.Name)
+	}
+	if err != nil {
+		s.logger.Error("Error fetching period archive", "periodID", periodID, "userID", userID, "error", err)
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// PeriodStats returns the user's stats for a period: the archived ones once
+// it is closed, otherwise worked out from the days
+func (s *Service) PeriodStats(userID, periodID int) (*types.AttendanceStats, error) {
+	period, err := s.GetPeriod(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Closed() {
+		archive, err := s.GetPeriodArchive(userID, periodID)
+		if err == nil {
+			stats := archive.Stats()
+			return &stats, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+	return s.CalculateStatsBetween(userID, period.StartDate, period.EndDate)
+}
+
+// checkOpen refuses changes to a day inside a closed period
+func (s *Service) checkOpen(date time.Time) error {
+	period, err := s.periodRepo.GetPeriodForDate(utils.NormalizeDate(date))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("Error fetching period", "date", date, "error", err)
+		return err
+	}
+	if period.Closed() {
+		return fmt.Errorf("%w: %s is in %s, which is closed; reopen it to make changes",
+			ErrConflict, date.Format("2006-01-02"), period.Name)
+	}
+	return nil
+}
+
+// closedPeriods returns the periods that are closed
+func (s *Service) closedPeriods() ([]types.Period, error) {
+	periods, err := s.GetPeriods()
+	if err != nil {
+		return nil, err
+	}
+	var closed []types.Period
+	for _, period := range periods {
+		if period.Closed() {
+			closed = append(closed, period)
+		}
+	}
+	return closed, nil
+}
+
+// loggedIn reports whether any of the user's own events fall in the period
+func loggedIn(events []types.Event, period types.Period) bool {
+	for _, event := range events {
+		if event.UserID != 0 && period.Contains(utils.NormalizeDate(event.Date)) {
+			return true
+		}
+	}
+	return false
+}
+
+func inPeriods(periods []types.Period, date time.Time) bool {
+	for _, period := range periods {
+		if period.Contains(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// weeklyBreakdown counts the weekdays of each week from start to end by status
+func weeklyBreakdown(events []types.Event, start, end time.Time) []types.WeekStats {
+	var days []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !utils.IsWeekend(d) {
+			days = append(days, d)
+		}
+	}
+	statuses := dayStatuses(events, days)
+
+	var weeks []types.WeekStats
+	for i, d := range days {
+		if len(weeks) == 0 || d.Weekday() == time.Monday {
+			weeks = append(weeks, types.WeekStats{Start: d})
+		}
+		week := &weeks[len(weeks)-1]
+		switch statuses[i] {
+		case types.DayInOffice:
+			week.InOffice++
+		case types.DayRemote:
+			week.Remote++
+		case types.DayVacation:
+			week.Vacation++
+		case types.DayHoliday:
+			week.Holiday++
+		default:
+			week.Unlogged++
+		}
+	}
+	return weeks
+}
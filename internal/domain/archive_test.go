@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// openPeriods is a period repository with no periods, so no day is locked
+func openPeriods() *mocks.PeriodRepository {
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", mock.Anything).Return(types.Period{}, gorm.ErrRecordNotFound)
+	mockPeriodRepo.On("GetAllPeriods").Return([]types.Period{}, nil)
+	return mockPeriodRepo
+}
+
+func TestWeeklyBreakdown(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+	events := []types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true},
+		{Date: day(2), Type: "attendance"},
+		{Date: day(5), Type: "vacation"},
+		{Date: day(5), Type: "attendance", IsInOffice: true},
+		{Date: day(6), Type: "holiday"},
+	}
+
+	// Thursday the 1st to Tuesday the 6th
+	weeks := weeklyBreakdown(events, day(1), day(6))
+
+	assert.Equal(t, []types.WeekStats{
+		{Start: day(1), InOffice: 1, Remote: 1},
+		{Start: day(5), Vacation: 1, Holiday: 1},
+	}, weeks)
+}
+
+func newArchiveTestService(period types.Period) (*Service, *mocks.PeriodRepository) {
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodByID", int(period.ID)).Return(period, nil)
+	mockPeriodRepo.On("GetPeriodForDate", mock.Anything).Return(period, nil)
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{
+		{ID: 1, CreatedAt: period.StartDate},
+		{ID: 2, CreatedAt: period.EndDate.AddDate(0, 0, 1)}, // joined afterwards
+	}, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{
+		{Date: period.StartDate, Type: "attendance", IsInOffice: true},
+	}, nil)
+	mockEventRepo.On("GetAllEvents", 2).Return([]types.Event{
+		{Date: period.StartDate.AddDate(0, 0, 3), Type: "holiday"},
+	}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{TargetDays: "2"}, nil)
+
+	service := &Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		periodRepo:     mockPeriodRepo,
+		userRepo:       mockUserRepo,
+		eventRepo:      mockEventRepo,
+		preferenceRepo: mockPrefsRepo,
+	}
+	return service, mockPeriodRepo
+}
+
+func TestClosePeriod(t *testing.T) {
+	period := types.Period{ID: 3, Name: "Q3 2026", StartDate: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC)}
+	service, mockPeriodRepo := newArchiveTestService(period)
+	mockPeriodRepo.On("SaveArchives", 3, mock.Anything).Return(nil)
+	mockPeriodRepo.On("SetPeriodClosed", 3, mock.Anything).Return(nil)
+
+	// Not before the period is over
+	_, err := service.closePeriod(3, time.Date(2026, time.September, 30, 12, 0, 0, 0, time.UTC))
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	closed, err := service.closePeriod(3, time.Date(2026, time.October, 1, 1, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.True(t, closed.Closed())
+	mockPeriodRepo.AssertCalled(t, "SaveArchives", 3, mock.MatchedBy(func(archives []types.PeriodArchive) bool {
+		return len(archives) == 1 && archives[0].UserID == 1 && archives[0].InOfficeCount == 1 &&
+			archives[0].TargetDays == 2 && len(archives[0].Weeks) == 14
+	}))
+}
+
+func TestClosedPeriod_LocksDays(t *testing.T) {
+	closedAt := time.Date(2026, time.October, 1, 1, 0, 0, 0, time.UTC)
+	period := types.Period{ID: 3, Name: "Q3 2026", StartDate: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC), ClosedAt: &closedAt}
+	service, mockPeriodRepo := newArchiveTestService(period)
+	day := time.Date(2026, time.August, 3, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.SetAttendance(1, day, true)
+	assert.True(t, errors.Is(err, ErrConflict))
+	err = service.AddEvent(1, types.Event{Date: day, Type: "vacation"})
+	assert.True(t, errors.Is(err, ErrConflict))
+	err = service.ClearEventsForDate(1, day)
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.True(t, errors.Is(service.DeletePeriod(3), ErrConflict))
+
+	// Stats come from the archive
+	mockPeriodRepo.On("GetArchive", 3, 1).Return(types.PeriodArchive{InOfficeCount: 20, TotalDays: 92}, nil)
+	stats, err := service.PeriodStats(1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, stats.InOfficeCount)
+}
+
+func TestFillDefaultDays_SkipsClosedPeriods(t *testing.T) {
+	closedAt := time.Date(2026, time.October, 1, 1, 0, 0, 0, time.UTC)
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetAllPeriods").Return([]types.Period{
+		{ID: 3, StartDate: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC), ClosedAt: &closedAt},
+	}, nil)
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{DefaultDays: "M,T,W,Th,F"}, nil)
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{}, nil)
+	mockEventRepo.On("AddEvent", mock.Anything).Return(nil)
+	service := &Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		periodRepo:     mockPeriodRepo,
+		preferenceRepo: mockPrefsRepo,
+		eventRepo:      mockEventRepo,
+	}
+
+	// Wednesday Sept 30 to Friday Oct 2: only October is open
+	added, err := service.FillDefaultDays(1, time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC), time.Date(2026, time.October, 2, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// maxVacationDays bounds a single vacation range
+const maxVacationDays = 366
+
+// SetAttendance marks a day as in office or remote, creating the attendance
+// event when the day has none. Setting the status a day already has is a no-op.
+// A day marked in office is spent at the user's default office.
+func (s *Service) SetAttendance(userID int, date time.Time, inOffice bool) (*types.Event, error) {
+	return s.setAttendance(userID, date, inOffice, 0)
+}
+
+// setAttendance is SetAttendance with the office an in-office day is spent
+// at; 0 keeps the office already recorded, or uses the default office
+func (s *Service) setAttendance(userID int, date time.Time, inOffice bool, officeID uint) (*types.Event, error) {
+	if date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+	date = utils.NormalizeDate(date)
+	if err := s.checkOpen(date); err != nil {
+		return nil, err
+	}
+
+	status := "remote"
+	if inOffice {
+		status = "in"
+	}
+
+	existing, err := s.eventRepo.GetEventByDateAndType(userID, date, "attendance")
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error fetching attendance event", "date", date, "error", err)
+		return nil, err
+	}
+
+	if err == nil {
+		if existing.IsInOffice == inOffice && (officeID == 0 || existing.OfficeID == officeID) {
+			return &existing, nil
+		}
+		existing.IsInOffice = inOffice
+		existing.OfficeID = officeID
+		if existing, err = s.locateEvent(userID, existing); err != nil {
+			return nil, err
+		}
+		booking, err := s.claimDesk(userID, existing)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.eventRepo.UpdateEvent(existing); err != nil {
+			s.logger.Error("Error updating attendance event", "eventID", existing.ID, "error", err)
+			s.undoBooking(userID, booking)
+			return nil, err
+		}
+		s.releaseIfAway(userID, existing)
+		s.notify(userID, types.Change{Kind: types.ChangeAttendanceToggled, Event: &existing, Status: status})
+		return &existing, nil
+	}
+
+	event, err := s.locateEvent(userID, types.Event{
+		UserID:     uint(userID),
+		Date:       date,
+		Type:       "attendance",
+		IsInOffice: inOffice,
+		OfficeID:   officeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.claimDesk(userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.AddEvent(event); err != nil {
+		s.logger.Error("Error adding attendance event", "date", date, "error", err)
+		s.undoBooking(userID, booking)
+		return nil, err
+	}
+	created, err := s.eventRepo.GetEventByDateAndType(userID, date, "attendance")
+	if err != nil {
+		s.logger.Error("Error reading back attendance event", "date", date, "error", err)
+		return nil, err
+	}
+
+	s.releaseIfAway(userID, created)
+	s.logger.Info("Attendance set", "date", date.Format("2006-01-02"), "status", status)
+	s.notify(userID, eventChange(types.ChangeEventCreated, created))
+	return &created, nil
+}
+
+// AddVacation books every weekday from start to end (inclusive) as vacation,
+// using the same rules as BulkAddEvents: holidays are skipped and attendance
+// days are turned into vacation.
+func (s *Service) AddVacation(userID int, start, end time.Time, description string) (*types.BulkAddResponse, error) {
+	if description == "" {
+		return nil, fmt.Errorf("%w: description is required", ErrInvalidInput)
+	}
+	start, end = utils.NormalizeDate(start), utils.NormalizeDate(end)
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+	if end.Sub(start) > maxVacationDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: vacation ranges are limited to %d days", ErrInvalidInput, maxVacationDays)
+	}
+
+	var events []types.Event
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if utils.IsWeekend(d) {
+			continue
+		}
+		events = append(events, types.Event{Date: d, Type: "vacation", Description: description})
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: no weekdays between %s and %s", ErrInvalidInput,
+			start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	return s.BulkAddEvents(userID, events)
+}
@@ -0,0 +1,518 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetOffices returns every office, by name
+func (s *Service) GetOffices() ([]types.Office, error) {
+	offices, err := s.officeRepo.GetAllOffices()
+	if err != nil {
+		s.logger.Error("Error fetching offices", "error", err)
+		return nil, err
+	}
+	return offices, nil
+}
+
+// GetOffice returns an office by ID
+func (s *Service) GetOffice(officeID int) (*types.Office, error) {
+	office, err := s.getOffice(officeID)
+	if err != nil {
+		return nil, err
+	}
+	return &office, nil
+}
+
+func (s *Service) getOffice(officeID int) (types.Office, error) {
+	office, err := s.officeRepo.GetOfficeByID(officeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return office, fmt.Errorf("%w: office %d", ErrNotFound, officeID)
+		}
+		s.logger.Error("Error fetching office", "officeID", officeID, "error", err)
+		return office, err
+	}
+	return office, nil
+}
+
+// CreateOffice adds an office. A capacity of 0 lets in one person per desk.
+func (s *Service) CreateOffice(name string, capacity int) (*types.Office, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: office name is required", ErrInvalidInput)
+	}
+	if capacity < 0 {
+		return nil, fmt.Errorf("%w: capacity cannot be negative", ErrInvalidInput)
+	}
+
+	offices, err := s.GetOffices()
+	if err != nil {
+		return nil, err
+	}
+	for _, office := range offices {
+		if strings.EqualFold(office.Name, name) {
+			return nil, fmt.Errorf("%w: an office named %q already exists", ErrConflict, office.Name)
+		}
+	}
+
+	office, err := s.officeRepo.AddOffice(types.Office{Name: name, Capacity: capacity})
+	if err != nil {
+		s.logger.Error("Error creating office", "name", name, "error", err)
+		return nil, err
+	}
+	s.logger.Info("Office created", "officeID", office.ID, "name", name)
+	return &office, nil
+}
+
+// SetOfficeCapacity changes how many people the office lets in per day.
+// Bookings already made are kept even if the office is now over capacity.
+func (s *Service) SetOfficeCapacity(officeID, capacity int) error {
+	if capacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidInput)
+	}
+	office, err := s.getOffice(officeID)
+	if err != nil {
+		return err
+	}
+	office.Capacity = capacity
+	if err := s.officeRepo.UpdateOffice(office); err != nil {
+		s.logger.Error("Error updating office", "officeID", officeID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteOffice removes an office with its desks, rooms and bookings
+func (s *Service) DeleteOffice(officeID int) error {
+	if _, err := s.getOffice(officeID); err != nil {
+		return err
+	}
+	if err := s.officeRepo.DeleteOffice(officeID); err != nil {
+		s.logger.Error("Error deleting office", "officeID", officeID, "error", err)
+		return err
+	}
+	s.logger.Info("Office deleted", "officeID", officeID)
+	return nil
+}
+
+// GetResources returns the office's desks and rooms
+func (s *Service) GetResources(officeID int) ([]types.Resource, error) {
+	if _, err := s.getOffice(officeID); err != nil {
+		return nil, err
+	}
+	resources, err := s.officeRepo.GetResources(officeID)
+	if err != nil {
+		s.logger.Error("Error fetching resources", "officeID", officeID, "error", err)
+		return nil, err
+	}
+	return resources, nil
+}
+
+// AddResource adds a desk or room to the office. Names are unique per office.
+func (s *Service) AddResource(officeID int, name, kind string) (*types.Resource, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if kind != types.ResourceDesk && kind != types.ResourceRoom {
+		return nil, fmt.Errorf("%w: kind must be %q or %q", ErrInvalidInput, types.ResourceDesk, types.ResourceRoom)
+	}
+
+	resources, err := s.GetResources(officeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		if strings.EqualFold(resource.Name, name) {
+			return nil, fmt.Errorf("%w: the office already has a %s named %q", ErrConflict, resource.Kind, resource.Name)
+		}
+	}
+
+	resource, err := s.officeRepo.AddResource(types.Resource{OfficeID: uint(officeID), Name: name, Kind: kind})
+	if err != nil {
+		s.logger.Error("Error adding resource", "officeID", officeID, "name", name, "error", err)
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// DeleteResource removes a desk or room from the office, cancelling its bookings
+func (s *Service) DeleteResource(officeID, resourceID int) error {
+	deleted, err := s.officeRepo.DeleteResource(officeID, resourceID)
+	if err != nil {
+		s.logger.Error("Error deleting resource", "officeID", officeID, "resourceID", resourceID, "error", err)
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: resource %d", ErrNotFound, resourceID)
+	}
+	return nil
+}
+
+// BookDesk books a desk in the office for the day and marks the day in
+// office there. A resourceID of 0 takes any free desk. Booking a second desk the
+// same day, a taken desk or a full office is a conflict.
+func (s *Service) BookDesk(userID, officeID, resourceID int, date time.Time) (*types.Booking, error) {
+	date, err := bookableDate(date)
+	if err != nil {
+		return nil, err
+	}
+	office, err := s.getOffice(officeID)
+	if err != nil {
+		return nil, err
+	}
+
+	mine, err := s.officeRepo.GetUserBookings(userID, date, date)
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "userID", userID, "date", date, "error", err)
+		return nil, err
+	}
+	for _, booking := range mine {
+		if booking.Kind != types.ResourceDesk {
+			continue
+		}
+		if int(booking.OfficeID) == officeID && (resourceID == 0 || int(booking.ResourceID) == resourceID) {
+			return &booking, nil
+		}
+		return nil, fmt.Errorf("%w: you already have a desk booked on %s; cancel it first", ErrConflict, date.Format("2006-01-02"))
+	}
+	if err := s.checkNotAway(userID, date); err != nil {
+		return nil, err
+	}
+
+	resources, err := s.GetResources(officeID)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.bookFreeDesk(userID, office, resources, resourceID, date)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.setAttendance(userID, date, true, office.ID); err != nil {
+		s.undoBooking(userID, booking)
+		return nil, err
+	}
+	return booking, nil
+}
+
+// bookFreeDesk books the desk, or with a resourceID of 0 any free desk, in the
+// office for the day. The office's capacity is checked again as the booking
+// is stored, so concurrent bookings cannot overfill it.
+func (s *Service) bookFreeDesk(userID int, office types.Office, resources []types.Resource, resourceID int, date time.Time) (*types.Booking, error) {
+	bookings, err := s.officeRepo.GetBookings(int(office.ID), date, date)
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "officeID", office.ID, "date", date, "error", err)
+		return nil, err
+	}
+	taken := make(map[uint]bool)
+	for _, booking := range bookings {
+		taken[booking.ResourceID] = true
+	}
+	day := bookingDay(office, resources, date, bookings)
+	if day.Free() == 0 {
+		return nil, fmt.Errorf("%w: %s is full on %s", ErrConflict, office.Name, date.Format("2006-01-02"))
+	}
+
+	var desk *types.Resource
+	for i, resource := range resources {
+		if resource.Kind != types.ResourceDesk || (resourceID != 0 && int(resource.ID) != resourceID) {
+			continue
+		}
+		if taken[resource.ID] {
+			if resourceID != 0 {
+				return nil, fmt.Errorf("%w: %s is already booked on %s", ErrConflict, resource.Name, date.Format("2006-01-02"))
+			}
+			continue
+		}
+		desk = &resources[i]
+		break
+	}
+	if desk == nil {
+		if resourceID != 0 {
+			return nil, fmt.Errorf("%w: desk %d in %s", ErrNotFound, resourceID, office.Name)
+		}
+		return nil, fmt.Errorf("%w: %s is full on %s", ErrConflict, office.Name, date.Format("2006-01-02"))
+	}
+
+	booking, err := s.addBooking(userID, *desk, date, day.Capacity)
+	if errors.Is(err, repository.ErrOfficeFull) {
+		return nil, fmt.Errorf("%w: %s is full on %s", ErrConflict, office.Name, date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// claimDesk books a desk for an in-office day from today on, when the office
+// has desks and the user holds none there yet. A full office is a conflict,
+// so the day cannot be marked in. Past days are records rather than plans and
+// book nothing. It returns the new booking, if any, for callers to undo when
+// saving the day fails.
+func (s *Service) claimDesk(userID int, event types.Event) (*types.Booking, error) {
+	if event.Type != "attendance" || !event.IsInOffice || event.OfficeID == 0 {
+		return nil, nil
+	}
+	date, err := bookableDate(event.Date)
+	if err != nil {
+		return nil, nil
+	}
+
+	resources, err := s.GetResources(int(event.OfficeID))
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(resources, func(r types.Resource) bool { return r.Kind == types.ResourceDesk }) {
+		return nil, nil
+	}
+	mine, err := s.officeRepo.GetUserBookings(userID, date, date)
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "userID", userID, "date", date, "error", err)
+		return nil, err
+	}
+	for _, booking := range mine {
+		if booking.Kind != types.ResourceDesk {
+			continue
+		}
+		if booking.OfficeID == event.OfficeID {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: you already have a desk booked elsewhere on %s; cancel it first", ErrConflict, date.Format("2006-01-02"))
+	}
+
+	office, err := s.getOffice(int(event.OfficeID))
+	if err != nil {
+		return nil, err
+	}
+	return s.bookFreeDesk(userID, office, resources, 0, date)
+}
+
+// undoBooking removes a booking made for a change that then failed
+func (s *Service) undoBooking(userID int, booking *types.Booking) {
+	if booking == nil {
+		return
+	}
+	if _, err := s.officeRepo.DeleteBooking(userID, int(booking.ID)); err != nil {
+		s.logger.Error("Error undoing desk booking", "bookingID", booking.ID, "error", err)
+	}
+}
+
+// BookRoom books a meeting room in the office for the day
+func (s *Service) BookRoom(userID, officeID, resourceID int, date time.Time) (*types.Booking, error) {
+	date, err := bookableDate(date)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.GetResources(officeID)
+	if err != nil {
+		return nil, err
+	}
+	var room *types.Resource
+	for i, resource := range resources {
+		if resource.Kind == types.ResourceRoom && int(resource.ID) == resourceID {
+			room = &resources[i]
+		}
+	}
+	if room == nil {
+		return nil, fmt.Errorf("%w: room %d", ErrNotFound, resourceID)
+	}
+	if err := s.checkNotAway(userID, date); err != nil {
+		return nil, err
+	}
+
+	bookings, err := s.officeRepo.GetBookings(officeID, date, date)
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "officeID", officeID, "date", date, "error", err)
+		return nil, err
+	}
+	for _, booking := range bookings {
+		if booking.ResourceID == room.ID {
+			if int(booking.UserID) == userID {
+				return &booking, nil
+			}
+			return nil, fmt.Errorf("%w: %s is already booked on %s", ErrConflict, room.Name, date.Format("2006-01-02"))
+		}
+	}
+
+	booking, err := s.addBooking(userID, *room, date, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// addBooking stores a booking of the resource. Desks also count against the
+// office's capacity for the day; rooms ignore it. Someone else booking the
+// resource first is a conflict.
+func (s *Service) addBooking(userID int, resource types.Resource, date time.Time, capacity int) (types.Booking, error) {
+	booking := types.Booking{
+		UserID:     uint(userID),
+		OfficeID:   resource.OfficeID,
+		ResourceID: resource.ID,
+		Kind:       resource.Kind,
+		Date:       date,
+	}
+	var err error
+	if resource.Kind == types.ResourceDesk {
+		booking, err = s.officeRepo.AddDeskBooking(booking, capacity)
+	} else {
+		booking, err = s.officeRepo.AddBooking(booking)
+	}
+	if errors.Is(err, repository.ErrAlreadyBooked) {
+		return booking, fmt.Errorf("%w: %s is already booked on %s", ErrConflict, resource.Name, date.Format("2006-01-02"))
+	}
+	if errors.Is(err, repository.ErrOfficeFull) {
+		return booking, err
+	}
+	if err != nil {
+		s.logger.Error("Error saving booking", "userID", userID, "resourceID", resource.ID, "date", date, "error", err)
+		return booking, err
+	}
+	s.logger.Info("Booked", "userID", userID, "resource", resource.Name, "date", date.Format("2006-01-02"))
+	return booking, nil
+}
+
+// CancelBooking releases one of the user's bookings. The day stays marked in office.
+func (s *Service) CancelBooking(userID, bookingID int) error {
+	deleted, err := s.officeRepo.DeleteBooking(userID, bookingID)
+	if err != nil {
+		s.logger.Error("Error cancelling booking", "bookingID", bookingID, "error", err)
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: booking %d", ErrNotFound, bookingID)
+	}
+	return nil
+}
+
+// GetUserBookings returns the user's bookings in every office between the dates
+func (s *Service) GetUserBookings(userID int, start, end time.Time) ([]types.Booking, error) {
+	bookings, err := s.officeRepo.GetUserBookings(userID, utils.NormalizeDate(start), utils.NormalizeDate(end))
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "userID", userID, "error", err)
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// OfficeCalendar returns the office's bookings for the weekdays of the month
+// containing the date
+func (s *Service) OfficeCalendar(officeID int, month time.Time) (*types.OfficeCalendar, error) {
+	office, err := s.getOffice(officeID)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.officeRepo.GetResources(officeID)
+	if err != nil {
+		s.logger.Error("Error fetching resources", "officeID", officeID, "error", err)
+		return nil, err
+	}
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	bookings, err := s.officeRepo.GetBookings(officeID, first, last)
+	if err != nil {
+		s.logger.Error("Error fetching bookings", "officeID", officeID, "error", err)
+		return nil, err
+	}
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		s.logger.Error("Error fetching users", "error", err)
+		return nil, err
+	}
+
+	calendar := &types.OfficeCalendar{Office: office, Resources: resources, Usernames: make(map[uint]string)}
+	for _, user := range users {
+		calendar.Usernames[user.ID] = user.Username
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if utils.IsWeekend(day) {
+			continue
+		}
+		var booked []types.Booking
+		for _, booking := range bookings {
+			if utils.SameDay(booking.Date, day) {
+				booked = append(booked, booking)
+			}
+		}
+		calendar.Days = append(calendar.Days, bookingDay(office, resources, day, booked))
+	}
+	return calendar, nil
+}
+
+// bookingDay sorts the day's bookings into desks and rooms. The office lets
+// in one person per desk, or fewer when it has a lower capacity set.
+func bookingDay(office types.Office, resources []types.Resource, date time.Time, bookings []types.Booking) types.BookingDay {
+	day := types.BookingDay{Date: date}
+	for _, resource := range resources {
+		if resource.Kind == types.ResourceDesk {
+			day.Capacity++
+		}
+	}
+	if office.Capacity > 0 && office.Capacity < day.Capacity {
+		day.Capacity = office.Capacity
+	}
+	for _, booking := range bookings {
+		if booking.Kind == types.ResourceDesk {
+			day.Desks = append(day.Desks, booking)
+		} else {
+			day.Rooms = append(day.Rooms, booking)
+		}
+	}
+	return day
+}
+
+// bookableDate normalizes the date and checks it is a weekday from today on
+func bookableDate(date time.Time) (time.Time, error) {
+	if date.IsZero() {
+		return date, fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+	date = utils.NormalizeDate(date)
+	if date.Before(utils.NormalizeDate(time.Now())) {
+		return date, fmt.Errorf("%w: bookings can only be made for today or later", ErrInvalidInput)
+	}
+	if utils.IsWeekend(date) {
+		return date, fmt.Errorf("%w: bookings can only be made for a weekday", ErrInvalidInput)
+	}
+	return date, nil
+}
+
+// checkNotAway refuses bookings on the user's vacation days and holidays
+func (s *Service) checkNotAway(userID int, date time.Time) error {
+	events, err := s.eventRepo.GetEventsByDate(userID, date)
+	if err != nil {
+		s.logger.Error("Error fetching events for date", "date", date, "error", err)
+		return err
+	}
+	for _, event := range events {
+		if event.Type == "vacation" || event.Type == "holiday" {
+			return fmt.Errorf("%w: %s is a %s day", ErrConflict, date.Format("2006-01-02"), event.Type)
+		}
+	}
+	return nil
+}
+
+// releaseIfAway frees the user's desk and rooms when an event says they are
+// not coming in that day. Failures are logged; the event change still stands.
+func (s *Service) releaseIfAway(userID int, event types.Event) {
+	if event.Type != "vacation" && (event.Type != "attendance" || event.IsInOffice) {
+		return
+	}
+	released, err := s.officeRepo.DeleteUserBookings(userID, utils.NormalizeDate(event.Date))
+	if err != nil {
+		s.logger.Error("Error releasing bookings", "userID", userID, "date", event.Date, "error", err)
+		return
+	}
+	if released > 0 {
+		s.logger.Info("Bookings released", "userID", userID, "date", event.Date.Format("2006-01-02"), "count", released)
+	}
+}
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// nextMonday is a bookable weekday at least a week out
+func nextMonday() time.Time {
+	day := utils.NormalizeDate(time.Now()).AddDate(0, 0, 7)
+	for day.Weekday() != time.Monday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+func TestBookingDay_Capacity(t *testing.T) {
+	resources := []types.Resource{
+		{ID: 1, Kind: types.ResourceDesk},
+		{ID: 2, Kind: types.ResourceDesk},
+		{ID: 3, Kind: types.ResourceDesk},
+		{ID: 4, Kind: types.ResourceRoom},
+	}
+	bookings := []types.Booking{
+		{ResourceID: 1, Kind: types.ResourceDesk},
+		{ResourceID: 4, Kind: types.ResourceRoom},
+	}
+
+	// One per desk by default; rooms are not counted
+	day := bookingDay(types.Office{}, resources, nextMonday(), bookings)
+	assert.Equal(t, 3, day.Capacity)
+	assert.Equal(t, 2, day.Free())
+	assert.Len(t, day.Rooms, 1)
+
+	// A lower capacity caps the desks
+	day = bookingDay(types.Office{Capacity: 1}, resources, nextMonday(), bookings)
+	assert.Equal(t, 1, day.Capacity)
+	assert.Equal(t, 0, day.Free())
+}
+
+func newBookingTestService(date time.Time, booked []types.Booking) (*Service, *mocks.OfficeRepository, *mocks.EventRepository) {
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("GetOfficeByID", 1).Return(types.Office{ID: 1, Name: "HQ", Capacity: 2}, nil)
+	mockOfficeRepo.On("GetResources", 1).Return([]types.Resource{
+		{ID: 1, OfficeID: 1, Name: "Desk 1", Kind: types.ResourceDesk},
+		{ID: 2, OfficeID: 1, Name: "Desk 2", Kind: types.ResourceDesk},
+		{ID: 3, OfficeID: 1, Name: "Desk 3", Kind: types.ResourceDesk},
+	}, nil)
+	mockOfficeRepo.On("GetUserBookings", 1, date, date).Return([]types.Booking{}, nil).Once()
+	mockOfficeRepo.On("GetBookings", 1, date, date).Return(booked, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventsByDate", 1, date).Return([]types.Event{}, nil)
+
+	service := &Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockEventRepo,
+		officeRepo: mockOfficeRepo,
+		periodRepo: openPeriods(),
+	}
+	return service, mockOfficeRepo, mockEventRepo
+}
+
+func TestBookDesk_TakesFreeDeskAndMarksInOffice(t *testing.T) {
+	date := nextMonday()
+	service, mockOfficeRepo, mockEventRepo := newBookingTestService(date, []types.Booking{
+		{ID: 7, UserID: 2, OfficeID: 1, ResourceID: 1, Kind: types.ResourceDesk, Date: date},
+	})
+	booked := types.Booking{ID: 8, UserID: 1, OfficeID: 1, ResourceID: 2, Kind: types.ResourceDesk, Date: date}
+	mockOfficeRepo.On("AddDeskBooking", mock.MatchedBy(func(b types.Booking) bool {
+		return b.ResourceID == 2 && b.UserID == 1 && b.Kind == types.ResourceDesk
+	}), 2).Return(booked, nil).Once()
+	// Marking the day in then finds the desk already held
+	mockOfficeRepo.On("GetUserBookings", 1, date, date).Return([]types.Booking{booked}, nil)
+
+	remote := types.Event{ID: 4, UserID: 1, Date: date, Type: "attendance"}
+	mockEventRepo.On("GetEventByDateAndType", 1, date, "attendance").Return(remote, nil)
+	mockEventRepo.On("UpdateEvent", mock.MatchedBy(func(e types.Event) bool { return e.ID == 4 && e.IsInOffice })).Return(nil).Once()
+
+	booking, err := service.BookDesk(1, 1, 0, date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), booking.ResourceID)
+	mockOfficeRepo.AssertExpectations(t)
+	mockEventRepo.AssertExpectations(t)
+}
+
+func TestBookDesk_FullOfficeIsConflict(t *testing.T) {
+	date := nextMonday()
+	// Three desks, but the office only lets in two people a day
+	service, mockOfficeRepo, _ := newBookingTestService(date, []types.Booking{
+		{ID: 7, UserID: 2, OfficeID: 1, ResourceID: 1, Kind: types.ResourceDesk, Date: date},
+		{ID: 8, UserID: 3, OfficeID: 1, ResourceID: 2, Kind: types.ResourceDesk, Date: date},
+	})
+
+	_, err := service.BookDesk(1, 1, 3, date)
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockOfficeRepo.AssertNotCalled(t, "AddDeskBooking", mock.Anything, mock.Anything)
+}
+
+func TestBookDesk_TakenDeskIsConflict(t *testing.T) {
+	date := nextMonday()
+	service, mockOfficeRepo, _ := newBookingTestService(date, []types.Booking{
+		{ID: 7, UserID: 2, OfficeID: 1, ResourceID: 1, Kind: types.ResourceDesk, Date: date},
+	})
+
+	_, err := service.BookDesk(1, 1, 1, date)
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockOfficeRepo.AssertNotCalled(t, "AddDeskBooking", mock.Anything, mock.Anything)
+}
+
+// Someone else can book between the capacity check and the insert; the
+// repository catches it and the user gets a conflict rather than an error
+func TestBookDesk_LosingRaceIsConflict(t *testing.T) {
+	date := nextMonday()
+	for _, lost := range []error{repository.ErrOfficeFull, repository.ErrAlreadyBooked} {
+		service, mockOfficeRepo, _ := newBookingTestService(date, nil)
+		mockOfficeRepo.On("AddDeskBooking", mock.Anything, 2).Return(types.Booking{}, lost)
+
+		_, err := service.BookDesk(1, 1, 0, date)
+
+		assert.ErrorIs(t, err, ErrConflict, lost.Error())
+	}
+}
+
+// Toggling a day to in office books a desk at the default office, or is
+// refused when the office is full
+func TestToggleAttendance_InOfficeBooksDesk(t *testing.T) {
+	date := nextMonday()
+	for _, full := range []bool{false, true} {
+		var booked []types.Booking
+		if full {
+			booked = []types.Booking{
+				{ID: 7, UserID: 2, OfficeID: 1, ResourceID: 1, Kind: types.ResourceDesk, Date: date},
+				{ID: 8, UserID: 3, OfficeID: 1, ResourceID: 2, Kind: types.ResourceDesk, Date: date},
+			}
+		}
+		service, mockOfficeRepo, mockEventRepo := newBookingTestService(date, booked)
+		mockOfficeRepo.On("AddDeskBooking", mock.Anything, 2).Return(types.Booking{ID: 9, UserID: 1, OfficeID: 1, ResourceID: 1, Kind: types.ResourceDesk, Date: date}, nil)
+		mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{{ID: 4, UserID: 1, Date: date, Type: "attendance"}}, nil)
+		mockEventRepo.On("UpdateEvent", mock.Anything).Return(nil)
+		mockPrefsRepo := new(mocks.PreferenceRepository)
+		mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{UserID: 1, DefaultOfficeID: 1}, nil)
+		service.preferenceRepo = mockPrefsRepo
+
+		status, err := service.ToggleAttendance(1, date)
+
+		if full {
+			assert.ErrorIs(t, err, ErrConflict)
+			mockOfficeRepo.AssertNotCalled(t, "AddDeskBooking", mock.Anything, mock.Anything)
+			mockEventRepo.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, "in", status)
+			mockOfficeRepo.AssertCalled(t, "AddDeskBooking", mock.MatchedBy(func(b types.Booking) bool {
+				return b.UserID == 1 && b.ResourceID == 1 && b.Date.Equal(date)
+			}), 2)
+			mockEventRepo.AssertCalled(t, "UpdateEvent", mock.MatchedBy(func(e types.Event) bool {
+				return e.ID == 4 && e.IsInOffice && e.OfficeID == 1
+			}))
+		}
+	}
+}
+
+func TestSetAttendance_RemoteReleasesBookings(t *testing.T) {
+	date := nextMonday()
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventByDateAndType", 1, date, "attendance").Return(types.Event{
+		ID: 4, UserID: 1, Date: date, Type: "attendance", IsInOffice: true,
+	}, nil)
+	mockEventRepo.On("UpdateEvent", mock.Anything).Return(nil)
+
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("DeleteUserBookings", 1, date).Return(int64(1), nil).Once()
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockEventRepo,
+		officeRepo: mockOfficeRepo,
+		periodRepo: openPeriods(),
+	}
+
+	_, err := service.SetAttendance(1, date, false)
+
+	assert.NoError(t, err)
+	mockOfficeRepo.AssertExpectations(t)
+}
+
+func TestAddEvent_VacationReleasesBookings(t *testing.T) {
+	date := nextMonday()
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventByDateAndType", 1, date, "vacation").Return(types.Event{}, nil)
+	mockEventRepo.On("AddEvent", mock.Anything).Return(nil)
+
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("DeleteUserBookings", 1, date).Return(int64(1), nil).Once()
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockEventRepo,
+		officeRepo: mockOfficeRepo,
+		periodRepo: openPeriods(),
+	}
+
+	err := service.AddEvent(1, types.Event{Date: date, Type: "vacation", Description: "Trip"})
+
+	assert.NoError(t, err)
+	mockOfficeRepo.AssertExpectations(t)
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // BulkAddEvents processes a list of vacation events, adding or updating them based on existing events
-func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, error) {
+func (s *Service) BulkAddEvents(userID int, events []types.Event) (*types.BulkAddResponse, error) {
 	// Initialize counters and result list
 	var addedCount, updatedCount, skippedCount int
 	var failedEvents []string
@@ -19,7 +19,7 @@ func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 	for _, event := range events {
 		date := event.Date
 		dateStr := date.Format("2006-01-02")
-		eventsOnDate, err := s.eventRepo.GetEventsByDate(date)
+		eventsOnDate, err := s.eventRepo.GetEventsByDate(userID, date)
 		if err != nil && !s.IsRecordNotFoundError(err) {
 			s.logger.Error("--Error fetching events by date", "date", date, "error", err)
 			failedEvents = append(failedEvents, dateStr)
@@ -56,7 +56,7 @@ func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 
 		if vacationExists {
 			// Update the existing vacation event
-			existingVacation, err := s.GetEventByDateAndType(date, "vacation")
+			existingVacation, err := s.GetEventByDateAndType(userID, date, "vacation")
 			if err != nil {
 				s.logger.Error("Error fetching existing vacation event", "date", date, "error", err)
 				failedEvents = append(failedEvents, dateStr)
@@ -68,7 +68,7 @@ func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 			}
 
 			existingVacation.Description = event.Description
-			err = s.UpdateEvent(*existingVacation)
+			err = s.UpdateEvent(userID, *existingVacation)
 			if err != nil {
 				s.logger.Error("Failed to update existing vacation event", "event", existingVacation, "error", err)
 				failedEvents = append(failedEvents, dateStr)
@@ -93,7 +93,7 @@ func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 
 			attendanceEvent.Type = "vacation"
 			attendanceEvent.Description = event.Description
-			err = s.UpdateEvent(*attendanceEvent)
+			err = s.UpdateEvent(userID, *attendanceEvent)
 			if err != nil {
 				s.logger.Error("Failed to update attendance event to vacation", "event", attendanceEvent, "error", err)
 				failedEvents = append(failedEvents, dateStr)
@@ -113,7 +113,7 @@ func (s *Service) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 		}
 
 		// If no events exist on that date, add the vacation event
-		err = s.AddEvent(event)
+		err = s.AddEvent(userID, event)
 		if err != nil {
 			s.logger.Error("Failed to add vacation event", "event", event, "error", err)
 			failedEvents = append(failedEvents, dateStr)
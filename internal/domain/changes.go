@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// Stats levels mirror the colours of the progress bar on the home page
+const (
+	StatsBelowMinimum = "below_minimum"
+	StatsBelowTarget  = "below_target"
+	StatsOnTarget     = "on_target"
+
+	// minimumDays is the red line on the progress bar
+	minimumDays = 2.0
+)
+
+// StatsLevel buckets stats into the same bands the progress bar uses
+func StatsLevel(stats types.AttendanceStats) string {
+	switch {
+	case stats.AverageDays >= stats.TargetDays:
+		return StatsOnTarget
+	case stats.AverageDays >= minimumDays:
+		return StatsBelowTarget
+	default:
+		return StatsBelowMinimum
+	}
+}
+
+// Subscribe registers a listener for every change the service makes
+func (s *Service) Subscribe(listener types.ChangeListener) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *Service) hasListeners() bool {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	return len(s.listeners) > 0
+}
+
+// notify hands the user's changes to every listener and then checks whether
+// the user's stats for the current period moved into a different band
+func (s *Service) notify(userID int, changes ...types.Change) {
+	if len(changes) == 0 || !s.hasListeners() {
+		return
+	}
+
+	s.changeMu.Lock()
+	listeners := append([]types.ChangeListener(nil), s.listeners...)
+	s.changeMu.Unlock()
+
+	now := time.Now()
+	for _, change := range changes {
+		if change.OccurredAt.IsZero() {
+			change.OccurredAt = now
+		}
+		change.UserID = uint(userID)
+		for _, listener := range listeners {
+			listener(change)
+		}
+	}
+
+	s.checkStatsThreshold(userID, listeners)
+}
+
+// eventChange builds an event.* change for a copy of the event
+func eventChange(kind string, event types.Event) types.Change {
+	return types.Change{Kind: kind, Event: &event}
+}
+
+// checkStatsThreshold publishes a stats change when the user's average for the
+// current period crosses into another band. The first check only records a baseline.
+func (s *Service) checkStatsThreshold(userID int, listeners []types.ChangeListener) {
+	period, err := s.GetCurrentPeriod()
+	if err != nil {
+		s.logger.Error("Error getting current period for threshold check", "error", err)
+		return
+	}
+	stats, err := s.CalculateStatsBetween(userID, period.StartDate, period.EndDate)
+	if err != nil {
+		s.logger.Error("Error calculating stats for threshold check", "error", err)
+		return
+	}
+
+	level := StatsLevel(*stats)
+
+	s.changeMu.Lock()
+	if s.statsLevels == nil {
+		s.statsLevels = make(map[int]string)
+	}
+	prev := s.statsLevels[userID]
+	s.statsLevels[userID] = level
+	s.changeMu.Unlock()
+
+	if prev == "" || prev == level {
+		return
+	}
+
+	change := types.Change{
+		Kind:       types.ChangeStatsThreshold,
+		UserID:     uint(userID),
+		OccurredAt: time.Now(),
+		Stats:      stats,
+		Level:      level,
+		PrevLevel:  prev,
+	}
+	for _, listener := range listeners {
+		listener(change)
+	}
+}
+
+// storedEvent reads back an event that was just added so the published change
+// carries its ID. The repository is only consulted when someone is listening.
+func (s *Service) storedEvent(userID int, event types.Event) types.Event {
+	if !s.hasListeners() {
+		return event
+	}
+	stored, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, event.Type)
+	if err != nil {
+		return event
+	}
+	return stored
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newChangeTestService builds a service over a week of attendance where the
+// first two days are in-office, so the week starts below target
+func newChangeTestService(t *testing.T) (*Service, *[]types.Event) {
+	start := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC) // Monday
+	events := []types.Event{}
+	for i := 0; i < 5; i++ {
+		events = append(events, types.Event{
+			ID:         uint(i + 1),
+			UserID:     1,
+			Date:       start.AddDate(0, 0, i),
+			Type:       "attendance",
+			IsInOffice: i < 2,
+		})
+	}
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return(func(int) []types.Event {
+		return append([]types.Event(nil), events...)
+	}, nil)
+	mockEventRepo.On("UpdateEvent", mock.AnythingOfType("types.Event")).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(0).(types.Event)
+			for i := range events {
+				if events[i].ID == updated.ID {
+					events[i] = updated
+				}
+			}
+		}).
+		Return(nil)
+
+	mockPrefRepo := new(mocks.PreferenceRepository)
+	mockPrefRepo.On("GetPreferences", 1).Return(types.Preferences{UserID: 1, TargetDays: "2.5"}, nil)
+
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", mock.AnythingOfType("time.Time")).
+		Return(types.Period{}, gorm.ErrRecordNotFound)
+
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("DeleteUserBookings", 1, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+
+	service := &Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockEventRepo,
+		preferenceRepo: mockPrefRepo,
+		periodRepo:     mockPeriodRepo,
+		officeRepo:     mockOfficeRepo,
+		quarterStart:   start,
+		quarterEnd:     start.AddDate(0, 0, 6),
+	}
+	return service, &events
+}
+
+func TestToggleAttendance_PublishesChanges(t *testing.T) {
+	service, events := newChangeTestService(t)
+	service.statsLevels = map[int]string{1: StatsBelowTarget}
+
+	var changes []types.Change
+	service.Subscribe(func(change types.Change) { changes = append(changes, change) })
+
+	status, err := service.ToggleAttendance(1, (*events)[2].Date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in", status)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, types.ChangeAttendanceToggled, changes[0].Kind)
+		assert.Equal(t, uint(1), changes[0].UserID)
+		assert.Equal(t, "in", changes[0].Status)
+		assert.Equal(t, uint(3), changes[0].Event.ID)
+		assert.False(t, changes[0].OccurredAt.IsZero())
+
+		// Three days in a seven day week puts the average at 3.0, over the 2.5 target
+		assert.Equal(t, types.ChangeStatsThreshold, changes[1].Kind)
+		assert.Equal(t, uint(1), changes[1].UserID)
+		assert.Equal(t, StatsOnTarget, changes[1].Level)
+		assert.Equal(t, StatsBelowTarget, changes[1].PrevLevel)
+		assert.InDelta(t, 3.0, changes[1].Stats.AverageDays, 0.001)
+	}
+}
+
+func TestToggleAttendance_NoThresholdChangeWithinBand(t *testing.T) {
+	service, events := newChangeTestService(t)
+
+	var changes []types.Change
+	service.Subscribe(func(change types.Change) { changes = append(changes, change) })
+
+	// The first check only records a baseline of 3.0 days, on target
+	_, err := service.ToggleAttendance(1, (*events)[2].Date)
+	assert.NoError(t, err)
+	// Going to 4.0 and back to 3.0 never leaves the band
+	_, err = service.ToggleAttendance(1, (*events)[3].Date)
+	assert.NoError(t, err)
+	_, err = service.ToggleAttendance(1, (*events)[3].Date)
+	assert.NoError(t, err)
+
+	for _, change := range changes {
+		assert.NotEqual(t, types.ChangeStatsThreshold, change.Kind)
+	}
+	assert.Len(t, changes, 3)
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, err := service.CreateWebhook(1, "ftp://example.com/hook", nil)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = service.CreateWebhook(1, "/relative", nil)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = service.CreateWebhook(1, "https://example.com/hook", []string{"event.exploded"})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestCreateWebhook_GeneratesSecret(t *testing.T) {
+	mockRepo := new(mocks.WebhookRepository)
+	mockRepo.On("AddWebhook", mock.AnythingOfType("types.Webhook")).
+		Return(func(webhook types.Webhook) types.Webhook {
+			webhook.ID = 1
+			return webhook
+		}, nil)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		webhookRepo: mockRepo,
+	}
+
+	webhook, err := service.CreateWebhook(3, "https://example.com/hook",
+		[]string{types.ChangeEventCreated, types.ChangeAttendanceToggled})
+
+	assert.NoError(t, err)
+	assert.Contains(t, webhook.Secret, webhookSecretPrefix)
+	assert.True(t, webhook.Active)
+	assert.Equal(t, uint(3), webhook.UserID)
+	assert.True(t, webhook.Wants(types.ChangeAttendanceToggled))
+	assert.False(t, webhook.Wants(types.ChangeEventDeleted))
+	mockRepo.AssertExpectations(t)
+}
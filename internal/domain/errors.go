@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// Sentinel errors returned by the service so adapters can map them to
+// the right response without inspecting messages.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrConflict     = errors.New("conflict")
+)
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,31 +12,44 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *Service) GetAllEvents() []types.Event {
-	events, err := s.eventRepo.GetAllEvents()
+func (s *Service) GetAllEvents(userID int) []types.Event {
+	events, err := s.eventRepo.GetAllEvents(userID)
 	if err != nil {
 		s.logger.Error("Error getting events", "error", err)
 		return []types.Event{}
 	}
 	return events
 }
-func (s *Service) GetPrefs() types.Preferences {
-	prefs, err := s.preferenceRepo.GetPreferences()
-	if err != nil {
-		s.logger.Error("Error getting preferences", "error", err)
-		// Return default preferences or handle error as needed
+func (s *Service) GetPrefs(userID int) types.Preferences {
+	return s.preferencesAt(userID, time.Now())
+}
+
+// ownEvent stamps the event with its owner. Holidays are shared by everyone.
+func ownEvent(userID int, event types.Event) types.Event {
+	if event.Type == "holiday" {
+		event.UserID = 0
+	} else {
+		event.UserID = uint(userID)
 	}
-	return prefs
+	return event
 }
 
 // AddEvent adds a new event or updates an existing one based on the event type and date
-func (s *Service) AddEvent(event types.Event) error {
+func (s *Service) AddEvent(userID int, event types.Event) error {
 
+	event = ownEvent(userID, event)
 	event.Date = utils.NormalizeDate(event.Date)
+	if err := s.checkOpen(event.Date); err != nil {
+		return err
+	}
+	event, err := s.locateEvent(userID, event)
+	if err != nil {
+		return err
+	}
 
 	if event.Type == "vacation" {
 		// Check if a vacation event already exists on the given date
-		existingEvent, err := s.eventRepo.GetEventByDateAndType(event.Date, "vacation")
+		existingEvent, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, "vacation")
 		if err != nil && err != gorm.ErrRecordNotFound {
 			s.logger.Error("Error fetching existing vacation event", "error", err)
 			return err
@@ -50,13 +64,14 @@ func (s *Service) AddEvent(event types.Event) error {
 				return err
 			}
 			s.logger.Info("Vacation event updated", "date", event.Date)
+			s.notify(userID, eventChange(types.ChangeEventUpdated, existingEvent))
 			return nil
 		}
 	} else if event.Type == "attendance" {
 		s.logger.Info("ADding Attendence", "date", event.Date, "type", event.Type)
 
 		// Check if an attendance event already exists on the given date
-		existingEvent, err := s.eventRepo.GetEventByDateAndType(event.Date, "attendance")
+		existingEvent, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, "attendance")
 		if err != nil && err != gorm.ErrRecordNotFound {
 			s.logger.Error("Error fetching existing attendance event", "error", err)
 			return err
@@ -73,19 +88,31 @@ func (s *Service) AddEvent(event types.Event) error {
 
 	s.logger.Info("doing add", "date", event.Date, "type", event.Type)
 
+	booking, err := s.claimDesk(userID, event)
+	if err != nil {
+		return err
+	}
+
 	// No existing event; proceed to add the new event
-	err := s.eventRepo.AddEvent(event)
+	err = s.eventRepo.AddEvent(event)
 	if err != nil {
 		s.logger.Error("Error adding event", "error", err)
+		s.undoBooking(userID, booking)
 		return err
 	}
 	s.logger.Info("Event added", "date", event.Date.Format("2006-01-02"), "type", event.Type)
+	s.releaseIfAway(userID, event)
+	s.notify(userID, eventChange(types.ChangeEventCreated, s.storedEvent(userID, event)))
 	return nil
 }
 
-// ClearEventsForDate clears all events for a specific date
-func (s *Service) ClearEventsForDate(date time.Time) error {
-	events, err := s.eventRepo.GetEventsByDate(date)
+// ClearEventsForDate clears all of the user's events for a specific date.
+// Shared holidays are left in place.
+func (s *Service) ClearEventsForDate(userID int, date time.Time) error {
+	if err := s.checkOpen(date); err != nil {
+		return err
+	}
+	events, err := s.eventRepo.GetEventsByDate(userID, date)
 	s.logger.Info("0000-----ClearEventsForDate------", "date", date, "len", len(events))
 
 	if err != nil {
@@ -93,38 +120,30 @@ func (s *Service) ClearEventsForDate(date time.Time) error {
 		return err
 	}
 
+	var changes []types.Change
 	for _, event := range events {
+		if event.UserID != uint(userID) {
+			continue
+		}
 		s.logger.Info("000bbbb0-----deletin------", "len", int(event.ID))
 
-		err := s.eventRepo.DeleteEvent(int(event.ID))
+		err := s.eventRepo.DeleteEvent(userID, int(event.ID))
 		if err != nil {
 			s.logger.Error("Error deleting event", "eventID", event.ID, "error", err)
+			s.notify(userID, changes...)
 			return err
 		}
+		changes = append(changes, eventChange(types.ChangeEventDeleted, event))
 	}
+	s.notify(userID, changes...)
 
 	s.logger.Info("All events cleared for date", "date", date.Format("2006-01-02"))
 	return nil
 }
 
-func (s *Service) AddDefaultDays() error {
+func (s *Service) AddDefaultDays(userID int) error {
 	s.logger.Info("===================AddDefaultDays triggered")
 
-	// Get current preferences
-	prefs, err := s.preferenceRepo.GetPreferences()
-	if err != nil {
-		s.logger.Error("Failed to get preferences", "error", err)
-		return err
-	}
-
-	// Parse default days
-	defaultDays := strings.Split(prefs.DefaultDays, ",")
-	defaultDaysMap := make(map[string]bool)
-	for _, day := range defaultDays {
-		day = strings.TrimSpace(strings.ToLower(day))
-		defaultDaysMap[day] = true
-	}
-
 	// Define the date range
 	//currentYear := time.Now().Year()
 	startDate := time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC)
@@ -132,11 +151,34 @@ func (s *Service) AddDefaultDays() error {
 	//endDate := s.quarterEnd
 	endDate := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
 
+	_, err := s.FillDefaultDays(userID, startDate, endDate)
+	return err
+}
+
+// FillDefaultDays adds an attendance event, in office or remote according to
+// the default days preference, to every weekday between start and end that has
+// no event yet, leaving out closed periods and days the office is full. It
+// returns the number of events added.
+func (s *Service) FillDefaultDays(userID int, startDate, endDate time.Time) (int, error) {
+	startDate, endDate = utils.NormalizeDate(startDate), utils.NormalizeDate(endDate)
+	if endDate.Before(startDate) {
+		return 0, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+	closed, err := s.closedPeriods()
+	if err != nil {
+		return 0, err
+	}
+
+	prefs, pattern, err := s.defaultPattern(userID)
+	if err != nil {
+		return 0, err
+	}
+
 	// Retrieve existing events
-	existingEvents, err := s.eventRepo.GetAllEvents()
+	existingEvents, err := s.eventRepo.GetAllEvents(userID)
 	if err != nil {
 		s.logger.Error("Failed to retrieve events", "error", err)
-		return err
+		return 0, err
 	}
 
 	// Create a map of existing event dates
@@ -148,60 +190,104 @@ func (s *Service) AddDefaultDays() error {
 
 	// Add default attendance events
 	addedCount := 0
+	var changes []types.Change
 	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-		// Map Go's Weekday to user's day abbreviations
-		var dayAbbrev string
-		switch d.Weekday() {
-		case time.Monday:
-			dayAbbrev = "M"
-		case time.Tuesday:
-			dayAbbrev = "T"
-		case time.Wednesday:
-			dayAbbrev = "W"
-		case time.Thursday:
-			dayAbbrev = "Th"
-		case time.Friday:
-			dayAbbrev = "F"
-		case time.Saturday:
-			dayAbbrev = "Sat"
-		case time.Sunday:
-			dayAbbrev = "Sun"
-		}
-		dayAbbrevLower := strings.ToLower(dayAbbrev)
-
 		// Skip weekends
-		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		if utils.IsWeekend(d) {
 			continue
 		}
 
-		// Determine if it's a default in-office day
-		isInOffice := defaultDaysMap[dayAbbrevLower]
+		// Determine if it's a default in-office day under the preferences of the time
+		isInOffice := pattern(d)[d.Weekday()]
 
 		dateStr := d.Format("2006-01-02")
-		if !existingEventDates[dateStr] {
+		if !existingEventDates[dateStr] && !inPeriods(closed, d) {
 			// Create a new attendance event
 			newEvent := types.Event{
+				UserID:      uint(userID),
 				Date:        d,
 				Description: "",
 				IsInOffice:  isInOffice,
 				Type:        "attendance",
 			}
-			err := s.eventRepo.AddEvent(newEvent)
+			if isInOffice {
+				newEvent.OfficeID = prefs.DefaultOfficeID
+			}
+			// Days the office is already full for are left for the user to decide
+			booking, err := s.claimDesk(userID, newEvent)
+			if err != nil {
+				s.logger.Info("Skipping default day", "date", dateStr, "error", err)
+				continue
+			}
+			err = s.eventRepo.AddEvent(newEvent)
 			if err != nil {
 				s.logger.Error("Failed to add event", "date", dateStr, "error", err)
+				s.undoBooking(userID, booking)
 				continue
 			}
 			addedCount++
+			changes = append(changes, eventChange(types.ChangeEventCreated, s.storedEvent(userID, newEvent)))
 		}
 	}
 
-	s.logger.Info("AddDefaultDays completed", "events_added", addedCount)
-	return nil
+	s.notify(userID, changes...)
+	s.logger.Info("FillDefaultDays completed", "events_added", addedCount)
+	return addedCount, nil
+}
+
+// defaultPattern loads the user's preferences and gives, for any day, the
+// weekdays they are in by default under the preferences in force that day,
+// following the team's suggested pattern when asked to
+func (s *Service) defaultPattern(userID int) (types.Preferences, func(time.Time) map[time.Weekday]bool, error) {
+	prefs, err := s.preferencesFor(userID)
+	if err != nil {
+		s.logger.Error("Failed to get preferences", "error", err)
+		return prefs, nil, err
+	}
+
+	patterns := make(map[string]map[time.Weekday]bool)
+	all := []string{prefs.DefaultDays}
+	for _, version := range prefs.Versions {
+		all = append(all, version.DefaultDays)
+	}
+	for _, days := range all {
+		if _, ok := patterns[days]; ok {
+			continue
+		}
+		patterns[days], err = s.parsePattern(userID, days)
+		if err != nil {
+			return prefs, nil, err
+		}
+	}
+	return prefs, func(day time.Time) map[time.Weekday]bool {
+		return patterns[prefs.At(day).DefaultDays]
+	}, nil
+}
+
+// parsePattern turns default days into the weekdays they name
+func (s *Service) parsePattern(userID int, days string) (map[time.Weekday]bool, error) {
+	if strings.EqualFold(days, types.TeamDefaultDays) {
+		var err error
+		if days, err = s.SuggestDefaultDays(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Unknown days are skipped rather than refused; the form does not check them
+	pattern := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(days, ",") {
+		for _, d := range dayAbbrevs {
+			if strings.EqualFold(strings.TrimSpace(part), d.abbrev) {
+				pattern[d.day] = true
+			}
+		}
+	}
+	return pattern, nil
 }
 
 // GetEventByID retrieves a single event by its ID
-func (s *Service) GetEventByID(eventID int) (types.Event, error) {
-	event, err := s.eventRepo.GetEventByID(eventID)
+func (s *Service) GetEventByID(userID int, eventID int) (types.Event, error) {
+	event, err := s.eventRepo.GetEventByID(userID, eventID)
 	if err != nil {
 		s.logger.Error("Error fetching event by ID", "error", err)
 		return types.Event{}, err
@@ -210,43 +296,93 @@ func (s *Service) GetEventByID(eventID int) (types.Event, error) {
 }
 
 // DeleteEvent deletes an event by its ID
-func (s *Service) DeleteEvent(eventID int) error {
+func (s *Service) DeleteEvent(userID int, eventID int) error {
 	// First, retrieve the event to ensure it exists and is deletable
-	_, err := s.GetEventByID(eventID)
+	event, err := s.GetEventByID(userID, eventID)
 	if err != nil {
 		return err
 	}
 
+	if err := s.checkOpen(event.Date); err != nil {
+		return err
+	}
+
 	////if event.Type != "vacation" {
 	//return errors.New("only vacation events can be deleted")
 	//}
 
 	// Proceed to delete the event
-	err = s.eventRepo.DeleteEvent(eventID)
+	err = s.eventRepo.DeleteEvent(userID, eventID)
 	if err != nil {
 		s.logger.Error("Error deleting event", "error", err)
 		return err
 	}
 
+	s.notify(userID, eventChange(types.ChangeEventDeleted, event))
 	return nil
 }
 
-// UpdateEvent updates an existing event in the database
-func (s *Service) UpdateEvent(event types.Event) error {
+// UpdateEvent updates one of the user's events in the database
+func (s *Service) UpdateEvent(userID int, event types.Event) error {
 	if event.ID == 0 {
 		return errors.New("event ID is required for update")
 	}
-	err := s.eventRepo.UpdateEvent(event)
+	if !eventTypes[event.Type] {
+		return fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, event.Type)
+	}
+	stored, err := s.eventRepo.GetEventByID(userID, int(event.ID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: event %d", ErrNotFound, event.ID)
+		}
+		s.logger.Error("Failed to fetch event for update", "eventID", event.ID, "error", err)
+		return err
+	}
+	event = ownEvent(userID, event)
+	event.Date = utils.NormalizeDate(event.Date)
+	// A sign-off covers the day and type it was given for
+	event.ApprovedBy, event.ApprovedAt = nil, nil
+	if event.Date.Equal(stored.Date) && event.Type == stored.Type {
+		event.ApprovedBy, event.ApprovedAt = stored.ApprovedBy, stored.ApprovedAt
+	}
+	// Neither the day it was on nor the day it moves to may be in a closed period
+	if err := s.checkOpen(stored.Date); err != nil {
+		return err
+	}
+	if err := s.checkOpen(event.Date); err != nil {
+		return err
+	}
+	// Moving it onto a day that already has an event of its type would duplicate it
+	other, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, event.Type)
+	if err == nil && other.ID != event.ID {
+		return fmt.Errorf("%w: a %s event already exists on %s", ErrConflict, event.Type, event.Date.Format("2006-01-02"))
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error checking for existing event", "date", event.Date, "error", err)
+		return err
+	}
+	event, err = s.locateEvent(userID, event)
+	if err != nil {
+		return err
+	}
+	booking, err := s.claimDesk(userID, event)
+	if err != nil {
+		return err
+	}
+	err = s.eventRepo.UpdateEvent(event)
 	if err != nil {
 		s.logger.Error("Failed to update event", "eventID", event.ID, "error", err)
+		s.undoBooking(userID, booking)
 		return err
 	}
+	s.releaseIfAway(userID, event)
+	s.notify(userID, eventChange(types.ChangeEventUpdated, event))
 	return nil
 }
 
 // GetEventByDateAndType retrieves an event by date and type
-func (s *Service) GetEventByDateAndType(date time.Time, eventType string) (*types.Event, error) {
-	event, err := s.eventRepo.GetEventByDateAndType(date, eventType)
+func (s *Service) GetEventByDateAndType(userID int, date time.Time, eventType string) (*types.Event, error) {
+	event, err := s.eventRepo.GetEventByDateAndType(userID, date, eventType)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("record not found")
@@ -257,11 +393,90 @@ func (s *Service) GetEventByDateAndType(date time.Time, eventType string) (*type
 }
 
 // GetEventsByDate retrieves all events for a specific date
-func (s *Service) GetEventsByDate(date time.Time) ([]types.Event, error) {
-	events, err := s.eventRepo.GetEventsByDate(date)
+func (s *Service) GetEventsByDate(userID int, date time.Time) ([]types.Event, error) {
+	events, err := s.eventRepo.GetEventsByDate(userID, date)
 	if err != nil {
 		s.logger.Error("Error fetching events by date", "date", date, "error", err)
 		return nil, err
 	}
 	return events, nil
 }
+
+// eventTypes lists the event types the service knows how to handle
+var eventTypes = map[string]bool{
+	"holiday":    true,
+	"vacation":   true,
+	"attendance": true,
+}
+
+// QueryEvents returns a filtered page of events and the total number of matches
+func (s *Service) QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error) {
+	if filter.Type != "" && !eventTypes[filter.Type] {
+		return nil, 0, fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, filter.Type)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, 0, fmt.Errorf("%w: 'to' is before 'from'", ErrInvalidInput)
+	}
+	if filter.Sort != "" && !slices.Contains(types.EventSortFields, strings.TrimPrefix(filter.Sort, "-")) {
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q", ErrInvalidInput, filter.Sort)
+	}
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	events, total, err := s.eventRepo.QueryEvents(userID, filter)
+	if err != nil {
+		s.logger.Error("Error querying events", "filter", filter, "error", err)
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// CreateEvent adds a single event and returns it as stored. Unlike AddEvent it
+// refuses to silently merge with an existing event of the same type on that date.
+func (s *Service) CreateEvent(userID int, event types.Event) (*types.Event, error) {
+	if !eventTypes[event.Type] {
+		return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, event.Type)
+	}
+	if event.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidInput)
+	}
+	if event.Type != "attendance" {
+		event.IsInOffice = false
+	}
+	event = ownEvent(userID, event)
+	event.Date = utils.NormalizeDate(event.Date)
+	if err := s.checkOpen(event.Date); err != nil {
+		return nil, err
+	}
+	event, err := s.locateEvent(userID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.eventRepo.GetEventByDateAndType(userID, event.Date, event.Type)
+	if err == nil {
+		return nil, fmt.Errorf("%w: a %s event already exists on %s", ErrConflict, event.Type, event.Date.Format("2006-01-02"))
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error checking for existing event", "date", event.Date, "error", err)
+		return nil, err
+	}
+
+	booking, err := s.claimDesk(userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.AddEvent(event); err != nil {
+		s.logger.Error("Error creating event", "event", event, "error", err)
+		s.undoBooking(userID, booking)
+		return nil, err
+	}
+
+	created, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, event.Type)
+	if err != nil {
+		s.logger.Error("Error reading back created event", "event", event, "error", err)
+		return nil, err
+	}
+	s.releaseIfAway(userID, created)
+	s.notify(userID, eventChange(types.ChangeEventCreated, created))
+	return &created, nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newUpdateEventTestService(stored types.Event) (*Service, *mocks.EventRepository) {
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventByID", 1, int(stored.ID)).Return(stored, nil)
+	// Vacation releases any desk booked for the day
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("DeleteUserBookings", 1, mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	service := &Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockEventRepo,
+		periodRepo: openPeriods(),
+		officeRepo: mockOfficeRepo,
+	}
+	return service, mockEventRepo
+}
+
+func TestUpdateEvent_MovingOntoTakenDayConflicts(t *testing.T) {
+	from := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)
+	service, mockEventRepo := newUpdateEventTestService(types.Event{ID: 3, UserID: 1, Date: from, Type: "vacation"})
+	mockEventRepo.On("GetEventByDateAndType", 1, to, "vacation").Return(types.Event{ID: 8, UserID: 1, Date: to, Type: "vacation"}, nil)
+
+	err := service.UpdateEvent(1, types.Event{ID: 3, Date: to, Type: "vacation"})
+
+	assert.ErrorIs(t, err, ErrConflict)
+	mockEventRepo.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+}
+
+func TestUpdateEvent_SameDayOrFreeDay(t *testing.T) {
+	from := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)
+	service, mockEventRepo := newUpdateEventTestService(types.Event{ID: 3, UserID: 1, Date: from, Type: "vacation"})
+	// The event itself is found on its own day; the other day is free
+	mockEventRepo.On("GetEventByDateAndType", 1, from, "vacation").Return(types.Event{ID: 3, UserID: 1, Date: from, Type: "vacation"}, nil)
+	mockEventRepo.On("GetEventByDateAndType", 1, to, "vacation").Return(types.Event{}, gorm.ErrRecordNotFound)
+	mockEventRepo.On("UpdateEvent", mock.Anything).Return(nil)
+
+	assert.NoError(t, service.UpdateEvent(1, types.Event{ID: 3, Date: from, Type: "vacation", Description: "Trip"}))
+	assert.NoError(t, service.UpdateEvent(1, types.Event{ID: 3, Date: to, Type: "vacation"}))
+	mockEventRepo.AssertNumberOfCalls(t, "UpdateEvent", 2)
+}
+
+func TestUpdateEvent_MovingWithdrawsApproval(t *testing.T) {
+	from := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)
+	approvedAt := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	service, mockEventRepo := newUpdateEventTestService(types.Event{ID: 3, UserID: 1, Date: from, Type: "vacation", ApprovedBy: uintPtr(2), ApprovedAt: &approvedAt})
+	mockEventRepo.On("GetEventByDateAndType", 1, mock.Anything, "vacation").Return(types.Event{}, gorm.ErrRecordNotFound)
+	mockEventRepo.On("UpdateEvent", mock.Anything).Return(nil)
+
+	// Renaming keeps the sign-off; moving to another day needs a new one
+	assert.NoError(t, service.UpdateEvent(1, types.Event{ID: 3, Date: from, Type: "vacation", Description: "Trip"}))
+	mockEventRepo.AssertCalled(t, "UpdateEvent", mock.MatchedBy(func(e types.Event) bool {
+		return e.Date.Equal(from) && e.ApprovedBy != nil && *e.ApprovedBy == 2
+	}))
+	assert.NoError(t, service.UpdateEvent(1, types.Event{ID: 3, Date: to, Type: "vacation"}))
+	mockEventRepo.AssertCalled(t, "UpdateEvent", mock.MatchedBy(func(e types.Event) bool {
+		return e.Date.Equal(to) && e.ApprovedBy == nil && e.ApprovedAt == nil
+	}))
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// rolloverLead is how long before a period ends the next one is set up
+	rolloverLead = 14 * 24 * time.Hour
+	// logRetention is how long delivery logs and past company requests are kept
+	logRetention = 90 * 24 * time.Hour
+)
+
+// RollOverPeriod sets up the next period once the current one is within two
+// weeks of ending: it creates the period when nobody has yet, then fills in
+// every user's default days. Default days go in once per period, so days
+// people clear afterwards stay clear. It returns the next period, or nil when
+// it is not time yet, and how many days were filled in.
+func (s *Service) RollOverPeriod() (*types.Period, int, error) {
+	return s.rollOverPeriod(time.Now())
+}
+
+func (s *Service) rollOverPeriod(now time.Time) (*types.Period, int, error) {
+	today := utils.NormalizeDate(now)
+	current, err := s.periodFor(today)
+	if err != nil {
+		return nil, 0, err
+	}
+	if current.EndDate.Sub(today) > rolloverLead {
+		return nil, 0, nil
+	}
+
+	next, err := s.periodRepo.GetPeriodForDate(current.EndDate.AddDate(0, 0, 1))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		start, end := followingPeriod(*current)
+		created, err := s.CreatePeriod(types.Period{StartDate: start, EndDate: end})
+		if err != nil {
+			return nil, 0, err
+		}
+		next = *created
+	} else if err != nil {
+		s.logger.Error("Error fetching the next period", "error", err)
+		return nil, 0, err
+	}
+
+	marker := strconv.Itoa(int(next.ID))
+	if filled, err := s.settingRepo.GetSetting(types.SettingRolledOver); err == nil && filled == marker {
+		return &next, 0, nil
+	}
+	users, err := s.GetUsers()
+	if err != nil {
+		return nil, 0, err
+	}
+	added := 0
+	for _, user := range users {
+		n, err := s.FillDefaultDays(int(user.ID), next.StartDate, next.EndDate)
+		if err != nil {
+			return &next, added, err
+		}
+		added += n
+	}
+	if err := s.settingRepo.SetSetting(types.SettingRolledOver, marker); err != nil {
+		s.logger.Error("Error recording the rollover", "periodID", next.ID, "error", err)
+		return &next, added, err
+	}
+	return &next, added, nil
+}
+
+// followingPeriod is the same length as the period and starts the day after
+// it ends. Whole calendar months stay whole months, so a quarter is followed
+// by the next quarter.
+func followingPeriod(period types.Period) (time.Time, time.Time) {
+	start := period.EndDate.AddDate(0, 0, 1)
+	if period.StartDate.Day() == 1 && start.Day() == 1 {
+		months := (start.Year()-period.StartDate.Year())*12 + int(start.Month()-period.StartDate.Month())
+		return start, start.AddDate(0, months, -1)
+	}
+	days := int(period.EndDate.Sub(period.StartDate).Hours() / 24)
+	return start, start.AddDate(0, 0, days)
+}
+
+// PurgeStaleData removes expired sessions, delivery logs older than 90 days
+// and requests for company on days more than 90 days gone
+func (s *Service) PurgeStaleData() (types.PurgeResult, error) {
+	return s.purgeStaleData(time.Now())
+}
+
+func (s *Service) purgeStaleData(now time.Time) (types.PurgeResult, error) {
+	var result types.PurgeResult
+	var err error
+	cutoff := now.Add(-logRetention)
+
+	if result.Sessions, err = s.sessionRepo.DeleteExpiredSessions(now); err != nil {
+		s.logger.Error("Error purging sessions", "error", err)
+		return result, err
+	}
+	if result.WebhookDeliveries, err = s.webhookRepo.DeleteDeliveriesBefore(cutoff); err != nil {
+		s.logger.Error("Error purging webhook deliveries", "error", err)
+		return result, err
+	}
+	if result.EmailDeliveries, err = s.notificationRepo.DeleteEmailDeliveriesBefore(cutoff); err != nil {
+		s.logger.Error("Error purging email deliveries", "error", err)
+		return result, err
+	}
+	if result.CompanyRequests, err = s.companyRepo.DeleteCompanyRequestsBefore(utils.NormalizeDate(cutoff)); err != nil {
+		s.logger.Error("Error purging company requests", "error", err)
+		return result, err
+	}
+	return result, nil
+}
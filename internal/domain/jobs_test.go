@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFollowingPeriod(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	start, end := followingPeriod(types.Period{StartDate: date(2026, time.October, 1), EndDate: date(2026, time.December, 31)})
+	assert.Equal(t, date(2027, time.January, 1), start)
+	assert.Equal(t, date(2027, time.March, 31), end)
+
+	start, end = followingPeriod(types.Period{StartDate: date(2026, time.January, 15), EndDate: date(2026, time.February, 14)})
+	assert.Equal(t, date(2026, time.February, 15), start)
+	assert.Equal(t, date(2026, time.March, 17), end)
+}
+
+func newRolloverTestService() (*Service, *mocks.PeriodRepository, *mocks.SettingRepository) {
+	q4 := types.Period{ID: 4, Name: "Q4 2026", StartDate: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)}
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", mock.Anything).Return(q4, nil).Once()
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{}, nil)
+
+	service := &Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		periodRepo:  mockPeriodRepo,
+		settingRepo: mockSettingRepo,
+		userRepo:    mockUserRepo,
+	}
+	return service, mockPeriodRepo, mockSettingRepo
+}
+
+func TestRollOverPeriod_NotYet(t *testing.T) {
+	service, _, _ := newRolloverTestService()
+
+	next, added, err := service.rollOverPeriod(time.Date(2026, time.December, 1, 1, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, 0, added)
+}
+
+func TestRollOverPeriod_CreatesNextQuarter(t *testing.T) {
+	service, mockPeriodRepo, mockSettingRepo := newRolloverTestService()
+	q1 := types.Period{ID: 5, Name: "Q1 2027", StartDate: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2027, time.March, 31, 0, 0, 0, 0, time.UTC)}
+	mockPeriodRepo.On("GetPeriodForDate", q1.StartDate).Return(types.Period{}, gorm.ErrRecordNotFound)
+	mockPeriodRepo.On("GetAllPeriods").Return([]types.Period{}, nil)
+	mockPeriodRepo.On("AddPeriod", mock.MatchedBy(func(p types.Period) bool {
+		return p.StartDate.Equal(q1.StartDate) && p.EndDate.Equal(q1.EndDate)
+	})).Return(q1, nil)
+	mockSettingRepo.On("GetSetting", types.SettingRolledOver).Return("", gorm.ErrRecordNotFound)
+	mockSettingRepo.On("SetSetting", types.SettingRolledOver, "5").Return(nil)
+
+	next, _, err := service.rollOverPeriod(time.Date(2026, time.December, 20, 1, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Q1 2027", next.Name)
+	mockSettingRepo.AssertCalled(t, "SetSetting", types.SettingRolledOver, "5")
+}
+
+func TestRollOverPeriod_FillsOnce(t *testing.T) {
+	service, mockPeriodRepo, mockSettingRepo := newRolloverTestService()
+	q1 := types.Period{ID: 5, Name: "Q1 2027"}
+	mockPeriodRepo.On("GetPeriodForDate", mock.Anything).Return(q1, nil)
+	mockSettingRepo.On("GetSetting", types.SettingRolledOver).Return("5", nil)
+
+	next, added, err := service.rollOverPeriod(time.Date(2026, time.December, 20, 1, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), next.ID)
+	assert.Equal(t, 0, added)
+	mockSettingRepo.AssertNotCalled(t, "SetSetting", mock.Anything, mock.Anything)
+}
+
+func TestPurgeStaleData(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 3, 30, 0, 0, time.UTC)
+	cutoff := now.AddDate(0, 0, -90)
+	mockSessionRepo := new(mocks.SessionRepository)
+	mockSessionRepo.On("DeleteExpiredSessions", now).Return(int64(3), nil)
+	mockWebhookRepo := new(mocks.WebhookRepository)
+	mockWebhookRepo.On("DeleteDeliveriesBefore", cutoff).Return(int64(2), nil)
+	mockNotificationRepo := new(mocks.NotificationRepository)
+	mockNotificationRepo.On("DeleteEmailDeliveriesBefore", cutoff).Return(int64(1), nil)
+	mockCompanyRepo := new(mocks.CompanyRepository)
+	mockCompanyRepo.On("DeleteCompanyRequestsBefore", time.Date(2026, time.July, 21, 0, 0, 0, 0, time.UTC)).Return(int64(4), nil)
+	service := &Service{
+		logger:           slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		sessionRepo:      mockSessionRepo,
+		webhookRepo:      mockWebhookRepo,
+		notificationRepo: mockNotificationRepo,
+		companyRepo:      mockCompanyRepo,
+	}
+
+	result, err := service.purgeStaleData(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, types.PurgeResult{Sessions: 3, WebhookDeliveries: 2, EmailDeliveries: 1, CompanyRequests: 4}, result)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// CountOtherOffices reports whether a day in at an office other than the
+// user's default counts towards their attendance. It does until an admin
+// turns it off.
+func (s *Service) CountOtherOffices() bool {
+	value, err := s.settingRepo.GetSetting(types.SettingCountOtherOffices)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Error("Error reading other offices setting", "error", err)
+		}
+		return true
+	}
+	count, err := strconv.ParseBool(value)
+	return err != nil || count
+}
+
+// SetCountOtherOffices decides whether visits to other offices count
+func (s *Service) SetCountOtherOffices(count bool) error {
+	if err := s.settingRepo.SetSetting(types.SettingCountOtherOffices, strconv.FormatBool(count)); err != nil {
+		s.logger.Error("Error saving other offices setting", "error", err)
+		return err
+	}
+	s.logger.Info("Other offices setting changed", "count", count)
+	return nil
+}
+
+// SetDefaultOffice sets the office the user normally works from; 0 clears it.
+// Days already logged keep the office they were recorded with.
+func (s *Service) SetDefaultOffice(userID, officeID int) error {
+	if officeID != 0 {
+		if _, err := s.getOffice(officeID); err != nil {
+			return err
+		}
+	}
+	prefs, err := s.preferencesFor(userID)
+	if err != nil {
+		return err
+	}
+	prefs.DefaultOfficeID = uint(officeID)
+	if err := s.preferenceRepo.UpdatePreferences(prefs); err != nil {
+		s.logger.Error("Error updating default office", "userID", userID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// SetAttendanceAt marks the day in office at the given office, or at the
+// user's default office when officeID is 0
+func (s *Service) SetAttendanceAt(userID int, date time.Time, officeID int) (*types.Event, error) {
+	if officeID != 0 {
+		if _, err := s.getOffice(officeID); err != nil {
+			return nil, err
+		}
+	}
+	return s.setAttendance(userID, date, true, uint(officeID))
+}
+
+// locateEvent records where an in-office day is spent: the office given, or
+// the user's default office. Other events have no office.
+func (s *Service) locateEvent(userID int, event types.Event) (types.Event, error) {
+	if event.Type != "attendance" || !event.IsInOffice {
+		event.OfficeID = 0
+		return event, nil
+	}
+	if event.OfficeID == 0 {
+		event.OfficeID = s.GetPrefs(userID).DefaultOfficeID
+		return event, nil
+	}
+	if _, err := s.getOffice(int(event.OfficeID)); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// officeSplit counts the in-office days between the dates per office
+func officeSplit(events []types.Event, start, end time.Time) map[uint]int {
+	split := make(map[uint]int)
+	for _, event := range events {
+		if event.Type != "attendance" || !event.IsInOffice || event.Date.Before(start) || event.Date.After(end) {
+			continue
+		}
+		split[event.OfficeID]++
+	}
+	return split
+}
+
+// uncountedDays is how many of the split's days were at an office other than
+// home. Days with no office recorded are taken to be at home.
+func uncountedDays(split map[uint]int, home uint) int {
+	if home == 0 {
+		return 0
+	}
+	uncounted := 0
+	for officeID, days := range split {
+		if officeID != 0 && officeID != home {
+			uncounted += days
+		}
+	}
+	return uncounted
+}
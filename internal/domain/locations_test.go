@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOfficeSplit(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+	events := []types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true, OfficeID: 1},
+		{Date: day(2), Type: "attendance", IsInOffice: true, OfficeID: 2},
+		{Date: day(3), Type: "attendance", IsInOffice: true},
+		{Date: day(4), Type: "attendance", IsInOffice: false},
+		{Date: day(7), Type: "attendance", IsInOffice: true, OfficeID: 2},
+		{Date: day(31), Type: "attendance", IsInOffice: true, OfficeID: 1}, // outside the range
+	}
+
+	split := officeSplit(events, day(1), day(30))
+
+	assert.Equal(t, map[uint]int{0: 1, 1: 1, 2: 2}, split)
+	// Days with no office are taken to be at home
+	assert.Equal(t, 2, uncountedDays(split, 1))
+	// Without a default office nothing is another office
+	assert.Equal(t, 0, uncountedDays(split, 0))
+}
+
+func newLocationTestService(countOthers string) *Service {
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true, OfficeID: 1},
+		{Date: day(2), Type: "attendance", IsInOffice: true, OfficeID: 2},
+		{Date: day(3), Type: "attendance", IsInOffice: true, OfficeID: 1},
+	}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{UserID: 1, TargetDays: "2.5", DefaultOfficeID: 1}, nil)
+
+	mockSettingRepo := new(mocks.SettingRepository)
+	if countOthers == "" {
+		mockSettingRepo.On("GetSetting", types.SettingCountOtherOffices).Return("", gorm.ErrRecordNotFound)
+	} else {
+		mockSettingRepo.On("GetSetting", types.SettingCountOtherOffices).Return(countOthers, nil)
+	}
+
+	return &Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockEventRepo,
+		preferenceRepo: mockPrefsRepo,
+		settingRepo:    mockSettingRepo,
+	}
+}
+
+func TestCalculateStatsBetween_OtherOffices(t *testing.T) {
+	start := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+	// Visits count unless the policy says otherwise
+	stats, err := newLocationTestService("").CalculateStatsBetween(1, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.InOfficeCount)
+	assert.Equal(t, 0, stats.Uncounted)
+	assert.Equal(t, map[uint]int{1: 2, 2: 1}, stats.ByOffice)
+
+	stats, err = newLocationTestService("false").CalculateStatsBetween(1, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.InOfficeCount)
+	assert.Equal(t, 1, stats.Uncounted)
+	assert.Equal(t, map[uint]int{1: 2, 2: 1}, stats.ByOffice)
+}
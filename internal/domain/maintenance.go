@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// knownEventTypes are the event types the calendar knows how to show
+var knownEventTypes = map[string]bool{
+	"attendance": true,
+	"holiday":    true,
+	"vacation":   true,
+}
+
+// checkDay groups events by owner and day for CheckData
+type checkDay struct {
+	userID uint
+	date   string
+}
+
+// CheckData looks for calendar data the app can't make sense of: unknown
+// event types, the same type twice on one user's day, attendance recorded on
+// a holiday or vacation day, and overlapping periods. Every user's events are
+// checked. Issues are sorted by date.
+func (s *Service) CheckData() ([]types.DataIssue, error) {
+	events, err := s.eventRepo.GetEventsForAllUsers()
+	if err != nil {
+		s.logger.Error("Error fetching events for check", "error", err)
+		return nil, err
+	}
+
+	var issues []types.DataIssue
+	byDay := make(map[checkDay]map[string][]types.Event)
+	for _, event := range events {
+		if !knownEventTypes[event.Type] {
+			issues = append(issues, types.DataIssue{
+				Check:   "unknown-type",
+				Date:    event.Date,
+				EventID: event.ID,
+				Message: fmt.Sprintf("event %d has unknown type %q", event.ID, event.Type),
+			})
+			continue
+		}
+		key := checkDay{event.UserID, event.Date.Format("2006-01-02")}
+		if byDay[key] == nil {
+			byDay[key] = make(map[string][]types.Event)
+		}
+		byDay[key][event.Type] = append(byDay[key][event.Type], event)
+	}
+
+	for key, dayTypes := range byDay {
+		owner := ""
+		if key.userID != 0 {
+			owner = fmt.Sprintf(" for user %d", key.userID)
+		}
+		for eventType, dayEvents := range dayTypes {
+			for _, extra := range dayEvents[1:] {
+				issues = append(issues, types.DataIssue{
+					Check:   "duplicate",
+					Date:    extra.Date,
+					EventID: extra.ID,
+					Message: fmt.Sprintf("%s has more than one %s event%s (also event %d)", key.date, eventType, owner, dayEvents[0].ID),
+				})
+			}
+		}
+		attendance := dayTypes["attendance"]
+		if len(attendance) == 0 {
+			continue
+		}
+		// Holidays are shared, so they clash with everyone's attendance
+		blocked := map[string]bool{
+			"holiday":  len(dayTypes["holiday"]) > 0 || len(byDay[checkDay{0, key.date}]["holiday"]) > 0,
+			"vacation": len(dayTypes["vacation"]) > 0,
+		}
+		for _, blocking := range []string{"holiday", "vacation"} {
+			if blocked[blocking] {
+				issues = append(issues, types.DataIssue{
+					Check:   "conflict",
+					Date:    attendance[0].Date,
+					EventID: attendance[0].ID,
+					Message: fmt.Sprintf("%s has attendance and a %s%s", key.date, blocking, owner),
+				})
+			}
+		}
+	}
+
+	periods, err := s.periodRepo.GetAllPeriods()
+	if err != nil {
+		s.logger.Error("Error fetching periods for check", "error", err)
+		return nil, err
+	}
+	for i, a := range periods {
+		for _, b := range periods[i+1:] {
+			if !a.EndDate.Before(b.StartDate) && !b.EndDate.Before(a.StartDate) {
+				overlapStart := a.StartDate
+				if b.StartDate.After(overlapStart) {
+					overlapStart = b.StartDate
+				}
+				issues = append(issues, types.DataIssue{
+					Check:   "period-overlap",
+					Date:    overlapStart,
+					Message: fmt.Sprintf("periods %q and %q overlap", a.Name, b.Name),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if !issues[i].Date.Equal(issues[j].Date) {
+			return issues[i].Date.Before(issues[j].Date)
+		}
+		return issues[i].EventID < issues[j].EventID
+	})
+	return issues, nil
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckData_FindsProblems(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventsForAllUsers").Return([]types.Event{
+		{ID: 1, UserID: 1, Date: day(6), Type: "attendance", IsInOffice: true},
+		{ID: 2, UserID: 1, Date: day(6), Type: "attendance", IsInOffice: false},
+		{ID: 3, UserID: 1, Date: day(7), Type: "attendance", IsInOffice: true},
+		{ID: 4, Date: day(7), Type: "holiday", Description: "Founders day"},
+		{ID: 5, UserID: 1, Date: day(8), Type: "meeting"},
+		{ID: 6, UserID: 1, Date: day(9), Type: "attendance", IsInOffice: true},
+		// Another user's day is not a duplicate
+		{ID: 7, UserID: 2, Date: day(6), Type: "attendance", IsInOffice: true},
+	}, nil)
+
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetAllPeriods").Return([]types.Period{
+		{ID: 1, Name: "Q1 2025", StartDate: day(1), EndDate: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "Sprint", StartDate: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockEventRepo,
+		periodRepo: mockPeriodRepo,
+	}
+
+	issues, err := service.CheckData()
+
+	assert.NoError(t, err)
+	var checks []string
+	for _, issue := range issues {
+		checks = append(checks, issue.Check)
+	}
+	assert.Equal(t, []string{"duplicate", "conflict", "unknown-type", "period-overlap"}, checks)
+	assert.Equal(t, uint(2), issues[0].EventID)
+	assert.Equal(t, uint(3), issues[1].EventID)
+	assert.Contains(t, issues[1].Message, "holiday for user 1")
+}
+
+func TestFillDefaultDays_SkipsExistingDays(t *testing.T) {
+	mockEventRepo := new(mocks.EventRepository)
+	mockPrefRepo := new(mocks.PreferenceRepository)
+
+	mockPrefRepo.On("GetPreferences", 2).Return(types.Preferences{UserID: 2, DefaultDays: "M,W", TargetDays: "2.5"}, nil)
+	mockEventRepo.On("GetAllEvents", 2).Return([]types.Event{
+		{Date: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), Type: "vacation"},
+	}, nil)
+	mockEventRepo.On("AddEvent", mock.AnythingOfType("types.Event")).Return(nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockEventRepo,
+		preferenceRepo: mockPrefRepo,
+		periodRepo:     openPeriods(),
+	}
+
+	// Mon 6th to Sun 12th; Tuesday already has a vacation
+	added, err := service.FillDefaultDays(2,
+		time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, added)
+	mockEventRepo.AssertCalled(t, "AddEvent", types.Event{
+		UserID: 2, Date: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: true,
+	})
+	mockEventRepo.AssertCalled(t, "AddEvent", types.Event{
+		UserID: 2, Date: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: false,
+	})
+	mockEventRepo.AssertNumberOfCalls(t, "AddEvent", 4)
+}
+
+func TestFillDefaultDays_EndBeforeStart(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, err := service.FillDefaultDays(2,
+		time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+	)
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
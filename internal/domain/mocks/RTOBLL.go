@@ -3,25 +3,1755 @@
 package mocks
 
 import (
+	mock "github.com/stretchr/testify/mock"
+
 	time "time"
 
-	mock "github.com/stretchr/testify/mock"
+	types "github.com/robstave/rto/internal/domain/types"
+
+	domain "github.com/robstave/rto/internal/domain"
+)
+
+// RTOBLL is an autogenerated mock type for the RTOBLL type
+type RTOBLL struct {
+	mock.Mock
+}
+
+// AddDefaultDays provides a mock function with given fields: userID
+func (_m *RTOBLL) AddDefaultDays(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddEvent provides a mock function with given fields: userID, event
+func (_m *RTOBLL) AddEvent(userID int, event types.Event) error {
+	ret := _m.Called(userID, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, types.Event) error); ok {
+		r0 = rf(userID, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddResource provides a mock function with given fields: officeID, name, kind
+func (_m *RTOBLL) AddResource(officeID int, name string, kind string) (*types.Resource, error) {
+	ret := _m.Called(officeID, name, kind)
+
+	var r0 *types.Resource
+	if rf, ok := ret.Get(0).(func(int, string, string) *types.Resource); ok {
+		r0 = rf(officeID, name, kind)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Resource)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, string) error); ok {
+		r1 = rf(officeID, name, kind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddVacation provides a mock function with given fields: userID, start, end, description
+func (_m *RTOBLL) AddVacation(userID int, start time.Time, end time.Time, description string) (*types.BulkAddResponse, error) {
+	ret := _m.Called(userID, start, end, description)
+
+	var r0 *types.BulkAddResponse
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time, string) *types.BulkAddResponse); ok {
+		r0 = rf(userID, start, end, description)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.BulkAddResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time, string) error); ok {
+		r1 = rf(userID, start, end, description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnchorCompliance provides a mock function with given fields: userID, start, end
+func (_m *RTOBLL) AnchorCompliance(userID int, start time.Time, end time.Time) (*types.AnchorCompliance, error) {
+	ret := _m.Called(userID, start, end)
+
+	var r0 *types.AnchorCompliance
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) *types.AnchorCompliance); ok {
+		r0 = rf(userID, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AnchorCompliance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApproveVacation provides a mock function with given fields: approverID, userID, eventID
+func (_m *RTOBLL) ApproveVacation(approverID int, userID int, eventID int) (types.Event, error) {
+	ret := _m.Called(approverID, userID, eventID)
+
+	var r0 types.Event
+	if rf, ok := ret.Get(0).(func(int, int, int) types.Event); ok {
+		r0 = rf(approverID, userID, eventID)
+	} else {
+		r0 = ret.Get(0).(types.Event)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(approverID, userID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authenticate provides a mock function with given fields: username, password
+func (_m *RTOBLL) Authenticate(username string, password string) (*types.User, error) {
+	ret := _m.Called(username, password)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(string, string) *types.User); ok {
+		r0 = rf(username, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(username, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthenticateAPIToken provides a mock function with given fields: plaintext
+func (_m *RTOBLL) AuthenticateAPIToken(plaintext string) (*types.APIToken, error) {
+	ret := _m.Called(plaintext)
+
+	var r0 *types.APIToken
+	if rf, ok := ret.Get(0).(func(string) *types.APIToken); ok {
+		r0 = rf(plaintext)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(plaintext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginTOTPEnrollment provides a mock function with given fields: userID
+func (_m *RTOBLL) BeginTOTPEnrollment(userID int) (string, string, error) {
+	ret := _m.Called(userID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int) string); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(int) string); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int) error); ok {
+		r2 = rf(userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// BookDesk provides a mock function with given fields: userID, officeID, resourceID, date
+func (_m *RTOBLL) BookDesk(userID int, officeID int, resourceID int, date time.Time) (*types.Booking, error) {
+	ret := _m.Called(userID, officeID, resourceID, date)
+
+	var r0 *types.Booking
+	if rf, ok := ret.Get(0).(func(int, int, int, time.Time) *types.Booking); ok {
+		r0 = rf(userID, officeID, resourceID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, int, time.Time) error); ok {
+		r1 = rf(userID, officeID, resourceID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BookRoom provides a mock function with given fields: userID, officeID, resourceID, date
+func (_m *RTOBLL) BookRoom(userID int, officeID int, resourceID int, date time.Time) (*types.Booking, error) {
+	ret := _m.Called(userID, officeID, resourceID, date)
+
+	var r0 *types.Booking
+	if rf, ok := ret.Get(0).(func(int, int, int, time.Time) *types.Booking); ok {
+		r0 = rf(userID, officeID, resourceID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, int, time.Time) error); ok {
+		r1 = rf(userID, officeID, resourceID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BulkAddEvents provides a mock function with given fields: userID, events
+func (_m *RTOBLL) BulkAddEvents(userID int, events []types.Event) (*types.BulkAddResponse, error) {
+	ret := _m.Called(userID, events)
+
+	var r0 *types.BulkAddResponse
+	if rf, ok := ret.Get(0).(func(int, []types.Event) *types.BulkAddResponse); ok {
+		r0 = rf(userID, events)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.BulkAddResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, []types.Event) error); ok {
+		r1 = rf(userID, events)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalculateAttendanceStats provides a mock function with given fields: userID
+func (_m *RTOBLL) CalculateAttendanceStats(userID int) (*types.AttendanceStats, error) {
+	ret := _m.Called(userID)
+
+	var r0 *types.AttendanceStats
+	if rf, ok := ret.Get(0).(func(int) *types.AttendanceStats); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AttendanceStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalculatePlan provides a mock function with given fields: userID, start, end
+func (_m *RTOBLL) CalculatePlan(userID int, start time.Time, end time.Time) (*types.Plan, error) {
+	ret := _m.Called(userID, start, end)
+
+	var r0 *types.Plan
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) *types.Plan); ok {
+		r0 = rf(userID, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalculateStatsBetween provides a mock function with given fields: userID, start, end
+func (_m *RTOBLL) CalculateStatsBetween(userID int, start time.Time, end time.Time) (*types.AttendanceStats, error) {
+	ret := _m.Called(userID, start, end)
+
+	var r0 *types.AttendanceStats
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) *types.AttendanceStats); ok {
+		r0 = rf(userID, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AttendanceStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelBooking provides a mock function with given fields: userID, bookingID
+func (_m *RTOBLL) CancelBooking(userID int, bookingID int) error {
+	ret := _m.Called(userID, bookingID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, bookingID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: userID, current, password
+func (_m *RTOBLL) ChangePassword(userID int, current string, password string) error {
+	ret := _m.Called(userID, current, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string) error); ok {
+		r0 = rf(userID, current, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckData provides a mock function with given fields:
+func (_m *RTOBLL) CheckData() ([]types.DataIssue, error) {
+	ret := _m.Called()
+
+	var r0 []types.DataIssue
+	if rf, ok := ret.Get(0).(func() []types.DataIssue); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.DataIssue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimUnownedData provides a mock function with given fields:
+func (_m *RTOBLL) ClaimUnownedData() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClearEventsForDate provides a mock function with given fields: userID, date
+func (_m *RTOBLL) ClearEventsForDate(userID int, date time.Time) error {
+	ret := _m.Called(userID, date)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(userID, date)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseEndedPeriods provides a mock function with given fields:
+func (_m *RTOBLL) CloseEndedPeriods() ([]types.Period, error) {
+	ret := _m.Called()
+
+	var r0 []types.Period
+	if rf, ok := ret.Get(0).(func() []types.Period); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClosePeriod provides a mock function with given fields: periodID
+func (_m *RTOBLL) ClosePeriod(periodID int) (*types.Period, error) {
+	ret := _m.Called(periodID)
+
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func(int) *types.Period); ok {
+		r0 = rf(periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountOtherOffices provides a mock function with given fields:
+func (_m *RTOBLL) CountOtherOffices() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// CreateAPIToken provides a mock function with given fields: userID, name, scopes, expiresAt
+func (_m *RTOBLL) CreateAPIToken(userID int, name string, scopes []string, expiresAt *time.Time) (string, *types.APIToken, error) {
+	ret := _m.Called(userID, name, scopes, expiresAt)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int, string, []string, *time.Time) string); ok {
+		r0 = rf(userID, name, scopes, expiresAt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *types.APIToken
+	if rf, ok := ret.Get(1).(func(int, string, []string, *time.Time) *types.APIToken); ok {
+		r1 = rf(userID, name, scopes, expiresAt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*types.APIToken)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int, string, []string, *time.Time) error); ok {
+		r2 = rf(userID, name, scopes, expiresAt)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateEvent provides a mock function with given fields: userID, event
+func (_m *RTOBLL) CreateEvent(userID int, event types.Event) (*types.Event, error) {
+	ret := _m.Called(userID, event)
+
+	var r0 *types.Event
+	if rf, ok := ret.Get(0).(func(int, types.Event) *types.Event); ok {
+		r0 = rf(userID, event)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, types.Event) error); ok {
+		r1 = rf(userID, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateOffice provides a mock function with given fields: name, capacity
+func (_m *RTOBLL) CreateOffice(name string, capacity int) (*types.Office, error) {
+	ret := _m.Called(name, capacity)
+
+	var r0 *types.Office
+	if rf, ok := ret.Get(0).(func(string, int) *types.Office); ok {
+		r0 = rf(name, capacity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Office)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(name, capacity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePeriod provides a mock function with given fields: period
+func (_m *RTOBLL) CreatePeriod(period types.Period) (*types.Period, error) {
+	ret := _m.Called(period)
+
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func(types.Period) *types.Period); ok {
+		r0 = rf(period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.Period) error); ok {
+		r1 = rf(period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: username, password, role
+func (_m *RTOBLL) CreateUser(username string, password string, role string) (*types.User, error) {
+	ret := _m.Called(username, password, role)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(string, string, string) *types.User); ok {
+		r0 = rf(username, password, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(username, password, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhook provides a mock function with given fields: userID, url, events
+func (_m *RTOBLL) CreateWebhook(userID int, url string, events []string) (*types.Webhook, error) {
+	ret := _m.Called(userID, url, events)
+
+	var r0 *types.Webhook
+	if rf, ok := ret.Get(0).(func(int, string, []string) *types.Webhook); ok {
+		r0 = rf(userID, url, events)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, []string) error); ok {
+		r1 = rf(userID, url, events)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEvent provides a mock function with given fields: userID, eventID
+func (_m *RTOBLL) DeleteEvent(userID int, eventID int) error {
+	ret := _m.Called(userID, eventID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOffice provides a mock function with given fields: officeID
+func (_m *RTOBLL) DeleteOffice(officeID int) error {
+	ret := _m.Called(officeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(officeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePeriod provides a mock function with given fields: periodID
+func (_m *RTOBLL) DeletePeriod(periodID int) error {
+	ret := _m.Called(periodID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(periodID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePreferenceVersion provides a mock function with given fields: userID, versionID
+func (_m *RTOBLL) DeletePreferenceVersion(userID int, versionID int) error {
+	ret := _m.Called(userID, versionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, versionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteResource provides a mock function with given fields: officeID, resourceID
+func (_m *RTOBLL) DeleteResource(officeID int, resourceID int) error {
+	ret := _m.Called(officeID, resourceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(officeID, resourceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhook provides a mock function with given fields: userID, webhookID
+func (_m *RTOBLL) DeleteWebhook(userID int, webhookID int) error {
+	ret := _m.Called(userID, webhookID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: userID, password
+func (_m *RTOBLL) DisableTOTP(userID int, password string) error {
+	ret := _m.Called(userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EmailEnabled provides a mock function with given fields:
+func (_m *RTOBLL) EmailEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EnableTOTP provides a mock function with given fields: userID, code
+func (_m *RTOBLL) EnableTOTP(userID int, code string) ([]string, error) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(int, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FillDefaultDays provides a mock function with given fields: userID, startDate, endDate
+func (_m *RTOBLL) FillDefaultDays(userID int, startDate time.Time, endDate time.Time) (int, error) {
+	ret := _m.Called(userID, startDate, endDate)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) int); ok {
+		r0 = rf(userID, startDate, endDate)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPITokens provides a mock function with given fields: userID
+func (_m *RTOBLL) GetAPITokens(userID int) ([]types.APIToken, error) {
+	ret := _m.Called(userID)
+
+	var r0 []types.APIToken
+	if rf, ok := ret.Get(0).(func(int) []types.APIToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllEvents provides a mock function with given fields: userID
+func (_m *RTOBLL) GetAllEvents(userID int) []types.Event {
+	ret := _m.Called(userID)
+
+	var r0 []types.Event
+	if rf, ok := ret.Get(0).(func(int) []types.Event); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Event)
+		}
+	}
+
+	return r0
+}
+
+// GetCurrentPeriod provides a mock function with given fields:
+func (_m *RTOBLL) GetCurrentPeriod() (*types.Period, error) {
+	ret := _m.Called()
+
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func() *types.Period); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEmailDeliveries provides a mock function with given fields: userID, limit
+func (_m *RTOBLL) GetEmailDeliveries(userID int, limit int) ([]types.EmailDelivery, error) {
+	ret := _m.Called(userID, limit)
+
+	var r0 []types.EmailDelivery
+	if rf, ok := ret.Get(0).(func(int, int) []types.EmailDelivery); ok {
+		r0 = rf(userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.EmailDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventByDateAndType provides a mock function with given fields: userID, date, eventType
+func (_m *RTOBLL) GetEventByDateAndType(userID int, date time.Time, eventType string) (*types.Event, error) {
+	ret := _m.Called(userID, date, eventType)
+
+	var r0 *types.Event
+	if rf, ok := ret.Get(0).(func(int, time.Time, string) *types.Event); ok {
+		r0 = rf(userID, date, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, string) error); ok {
+		r1 = rf(userID, date, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventByID provides a mock function with given fields: userID, eventID
+func (_m *RTOBLL) GetEventByID(userID int, eventID int) (types.Event, error) {
+	ret := _m.Called(userID, eventID)
+
+	var r0 types.Event
+	if rf, ok := ret.Get(0).(func(int, int) types.Event); ok {
+		r0 = rf(userID, eventID)
+	} else {
+		r0 = ret.Get(0).(types.Event)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventsByDate provides a mock function with given fields: userID, date
+func (_m *RTOBLL) GetEventsByDate(userID int, date time.Time) ([]types.Event, error) {
+	ret := _m.Called(userID, date)
+
+	var r0 []types.Event
+	if rf, ok := ret.Get(0).(func(int, time.Time) []types.Event); ok {
+		r0 = rf(userID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNotificationSettings provides a mock function with given fields: userID
+func (_m *RTOBLL) GetNotificationSettings(userID int) (types.NotificationSettings, error) {
+	ret := _m.Called(userID)
+
+	var r0 types.NotificationSettings
+	if rf, ok := ret.Get(0).(func(int) types.NotificationSettings); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(types.NotificationSettings)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOffice provides a mock function with given fields: officeID
+func (_m *RTOBLL) GetOffice(officeID int) (*types.Office, error) {
+	ret := _m.Called(officeID)
+
+	var r0 *types.Office
+	if rf, ok := ret.Get(0).(func(int) *types.Office); ok {
+		r0 = rf(officeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Office)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(officeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOffices provides a mock function with given fields:
+func (_m *RTOBLL) GetOffices() ([]types.Office, error) {
+	ret := _m.Called()
+
+	var r0 []types.Office
+	if rf, ok := ret.Get(0).(func() []types.Office); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Office)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPeriod provides a mock function with given fields: periodID
+func (_m *RTOBLL) GetPeriod(periodID int) (*types.Period, error) {
+	ret := _m.Called(periodID)
+
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func(int) *types.Period); ok {
+		r0 = rf(periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPeriodArchive provides a mock function with given fields: userID, periodID
+func (_m *RTOBLL) GetPeriodArchive(userID int, periodID int) (*types.PeriodArchive, error) {
+	ret := _m.Called(userID, periodID)
+
+	var r0 *types.PeriodArchive
+	if rf, ok := ret.Get(0).(func(int, int) *types.PeriodArchive); ok {
+		r0 = rf(userID, periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.PeriodArchive)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPeriods provides a mock function with given fields:
+func (_m *RTOBLL) GetPeriods() ([]types.Period, error) {
+	ret := _m.Called()
+
+	var r0 []types.Period
+	if rf, ok := ret.Get(0).(func() []types.Period); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreferenceHistory provides a mock function with given fields: userID
+func (_m *RTOBLL) GetPreferenceHistory(userID int) ([]types.PreferenceVersion, error) {
+	ret := _m.Called(userID)
+
+	var r0 []types.PreferenceVersion
+	if rf, ok := ret.Get(0).(func(int) []types.PreferenceVersion); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.PreferenceVersion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrefs provides a mock function with given fields: userID
+func (_m *RTOBLL) GetPrefs(userID int) types.Preferences {
+	ret := _m.Called(userID)
+
+	var r0 types.Preferences
+	if rf, ok := ret.Get(0).(func(int) types.Preferences); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(types.Preferences)
+	}
+
+	return r0
+}
+
+// GetReport provides a mock function with given fields: viewerID, userID
+func (_m *RTOBLL) GetReport(viewerID int, userID int) (*types.User, error) {
+	ret := _m.Called(viewerID, userID)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(int, int) *types.User); ok {
+		r0 = rf(viewerID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(viewerID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReports provides a mock function with given fields: managerID
+func (_m *RTOBLL) GetReports(managerID int) ([]types.User, error) {
+	ret := _m.Called(managerID)
+
+	var r0 []types.User
+	if rf, ok := ret.Get(0).(func(int) []types.User); ok {
+		r0 = rf(managerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResources provides a mock function with given fields: officeID
+func (_m *RTOBLL) GetResources(officeID int) ([]types.Resource, error) {
+	ret := _m.Called(officeID)
+
+	var r0 []types.Resource
+	if rf, ok := ret.Get(0).(func(int) []types.Resource); ok {
+		r0 = rf(officeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Resource)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(officeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: userID
+func (_m *RTOBLL) GetSessions(userID int) ([]types.Session, error) {
+	ret := _m.Called(userID)
+
+	var r0 []types.Session
+	if rf, ok := ret.Get(0).(func(int) []types.Session); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeam provides a mock function with given fields: viewerID
+func (_m *RTOBLL) GetTeam(viewerID int) ([]types.User, error) {
+	ret := _m.Called(viewerID)
+
+	var r0 []types.User
+	if rf, ok := ret.Get(0).(func(int) []types.User); ok {
+		r0 = rf(viewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: userID
+func (_m *RTOBLL) GetUser(userID int) (*types.User, error) {
+	ret := _m.Called(userID)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(int) *types.User); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserBookings provides a mock function with given fields: userID, start, end
+func (_m *RTOBLL) GetUserBookings(userID int, start time.Time, end time.Time) ([]types.Booking, error) {
+	ret := _m.Called(userID, start, end)
+
+	var r0 []types.Booking
+	if rf, ok := ret.Get(0).(func(int, time.Time, time.Time) []types.Booking); ok {
+		r0 = rf(userID, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time, time.Time) error); ok {
+		r1 = rf(userID, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields:
+func (_m *RTOBLL) GetUsers() ([]types.User, error) {
+	ret := _m.Called()
+
+	var r0 []types.User
+	if rf, ok := ret.Get(0).(func() []types.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhook provides a mock function with given fields: userID, webhookID
+func (_m *RTOBLL) GetWebhook(userID int, webhookID int) (*types.Webhook, error) {
+	ret := _m.Called(userID, webhookID)
+
+	var r0 *types.Webhook
+	if rf, ok := ret.Get(0).(func(int, int) *types.Webhook); ok {
+		r0 = rf(userID, webhookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: userID, webhookID, limit
+func (_m *RTOBLL) GetWebhookDeliveries(userID int, webhookID int, limit int) ([]types.WebhookDelivery, error) {
+	ret := _m.Called(userID, webhookID, limit)
+
+	var r0 []types.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(int, int, int) []types.WebhookDelivery); ok {
+		r0 = rf(userID, webhookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(userID, webhookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields: userID
+func (_m *RTOBLL) GetWebhooks(userID int) ([]types.Webhook, error) {
+	ret := _m.Called(userID)
+
+	var r0 []types.Webhook
+	if rf, ok := ret.Get(0).(func(int) []types.Webhook); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasUsers provides a mock function with given fields:
+func (_m *RTOBLL) HasUsers() (bool, error) {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OccupancyForecast provides a mock function with given fields: from, to
+func (_m *RTOBLL) OccupancyForecast(from time.Time, to time.Time) (*types.OccupancyForecast, error) {
+	ret := _m.Called(from, to)
+
+	var r0 *types.OccupancyForecast
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) *types.OccupancyForecast); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OccupancyForecast)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OfficeCalendar provides a mock function with given fields: officeID, month
+func (_m *RTOBLL) OfficeCalendar(officeID int, month time.Time) (*types.OfficeCalendar, error) {
+	ret := _m.Called(officeID, month)
+
+	var r0 *types.OfficeCalendar
+	if rf, ok := ret.Get(0).(func(int, time.Time) *types.OfficeCalendar); ok {
+		r0 = rf(officeID, month)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OfficeCalendar)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(officeID, month)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PeriodStats provides a mock function with given fields: userID, periodID
+func (_m *RTOBLL) PeriodStats(userID int, periodID int) (*types.AttendanceStats, error) {
+	ret := _m.Called(userID, periodID)
+
+	var r0 *types.AttendanceStats
+	if rf, ok := ret.Get(0).(func(int, int) *types.AttendanceStats); ok {
+		r0 = rf(userID, periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AttendanceStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeStaleData provides a mock function with given fields:
+func (_m *RTOBLL) PurgeStaleData() (types.PurgeResult, error) {
+	ret := _m.Called()
+
+	var r0 types.PurgeResult
+	if rf, ok := ret.Get(0).(func() types.PurgeResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.PurgeResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueryEvents provides a mock function with given fields: userID, filter
+func (_m *RTOBLL) QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error) {
+	ret := _m.Called(userID, filter)
+
+	var r0 []types.Event
+	if rf, ok := ret.Get(0).(func(int, types.EventFilter) []types.Event); ok {
+		r0 = rf(userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Event)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(int, types.EventFilter) int64); ok {
+		r1 = rf(userID, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int, types.EventFilter) error); ok {
+		r2 = rf(userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: userID, code
+func (_m *RTOBLL) RegenerateRecoveryCodes(userID int, code string) ([]string, error) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(int, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: username, password
+func (_m *RTOBLL) Register(username string, password string) (*types.User, error) {
+	ret := _m.Called(username, password)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(string, string) *types.User); ok {
+		r0 = rf(username, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(username, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegistrationOpen provides a mock function with given fields:
+func (_m *RTOBLL) RegistrationOpen() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ReopenPeriod provides a mock function with given fields: periodID
+func (_m *RTOBLL) ReopenPeriod(periodID int) (*types.Period, error) {
+	ret := _m.Called(periodID)
+
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func(int) *types.Period); ok {
+		r0 = rf(periodID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(periodID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: username, password
+func (_m *RTOBLL) ResetPassword(username string, password string) error {
+	ret := _m.Called(username, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetTOTP provides a mock function with given fields: username
+func (_m *RTOBLL) ResetTOTP(username string) error {
+	ret := _m.Called(username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveUser provides a mock function with given fields: username
+func (_m *RTOBLL) ResolveUser(username string) (int, error) {
+	ret := _m.Called(username)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIToken provides a mock function with given fields: userID, tokenID
+func (_m *RTOBLL) RevokeAPIToken(userID int, tokenID int) error {
+	ret := _m.Called(userID, tokenID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllSessions provides a mock function with given fields: userID
+func (_m *RTOBLL) RevokeAllSessions(userID int) (int64, error) {
+	ret := _m.Called(userID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(int) int64); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: userID, sessionID
+func (_m *RTOBLL) RevokeSession(userID int, sessionID int) error {
+	ret := _m.Called(userID, sessionID)
 
-	types "github.com/robstave/rto/internal/domain/types"
-)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
 
-// RTOBLL is an autogenerated mock type for the RTOBLL type
-type RTOBLL struct {
-	mock.Mock
+	return r0
 }
 
-// AddDefaultDays provides a mock function with given fields:
-func (_m *RTOBLL) AddDefaultDays() error {
+// RollOverPeriod provides a mock function with given fields:
+func (_m *RTOBLL) RollOverPeriod() (*types.Period, int, error) {
 	ret := _m.Called()
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
+	var r0 *types.Period
+	if rf, ok := ret.Get(0).(func() *types.Period); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Period)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SendReminders provides a mock function with given fields:
+func (_m *RTOBLL) SendReminders() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
 		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendTestEmail provides a mock function with given fields: userID
+func (_m *RTOBLL) SendTestEmail(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,13 +1759,36 @@ func (_m *RTOBLL) AddDefaultDays() error {
 	return r0
 }
 
-// AddEvent provides a mock function with given fields: event
-func (_m *RTOBLL) AddEvent(event types.Event) error {
-	ret := _m.Called(event)
+// SessionKey provides a mock function with given fields:
+func (_m *RTOBLL) SessionKey() ([]byte, error) {
+	ret := _m.Called()
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetAnchorDays provides a mock function with given fields: managerID, days
+func (_m *RTOBLL) SetAnchorDays(managerID int, days string) error {
+	ret := _m.Called(managerID, days)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(types.Event) error); ok {
-		r0 = rf(event)
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(managerID, days)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -43,22 +1796,22 @@ func (_m *RTOBLL) AddEvent(event types.Event) error {
 	return r0
 }
 
-// BulkAddEvents provides a mock function with given fields: events
-func (_m *RTOBLL) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, error) {
-	ret := _m.Called(events)
+// SetAttendance provides a mock function with given fields: userID, date, inOffice
+func (_m *RTOBLL) SetAttendance(userID int, date time.Time, inOffice bool) (*types.Event, error) {
+	ret := _m.Called(userID, date, inOffice)
 
-	var r0 *types.BulkAddResponse
-	if rf, ok := ret.Get(0).(func([]types.Event) *types.BulkAddResponse); ok {
-		r0 = rf(events)
+	var r0 *types.Event
+	if rf, ok := ret.Get(0).(func(int, time.Time, bool) *types.Event); ok {
+		r0 = rf(userID, date, inOffice)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.BulkAddResponse)
+			r0 = ret.Get(0).(*types.Event)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]types.Event) error); ok {
-		r1 = rf(events)
+	if rf, ok := ret.Get(1).(func(int, time.Time, bool) error); ok {
+		r1 = rf(userID, date, inOffice)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -66,22 +1819,22 @@ func (_m *RTOBLL) BulkAddEvents(events []types.Event) (*types.BulkAddResponse, e
 	return r0, r1
 }
 
-// CalculateAttendanceStats provides a mock function with given fields:
-func (_m *RTOBLL) CalculateAttendanceStats() (*types.AttendanceStats, error) {
-	ret := _m.Called()
+// SetAttendanceAt provides a mock function with given fields: userID, date, officeID
+func (_m *RTOBLL) SetAttendanceAt(userID int, date time.Time, officeID int) (*types.Event, error) {
+	ret := _m.Called(userID, date, officeID)
 
-	var r0 *types.AttendanceStats
-	if rf, ok := ret.Get(0).(func() *types.AttendanceStats); ok {
-		r0 = rf()
+	var r0 *types.Event
+	if rf, ok := ret.Get(0).(func(int, time.Time, int) *types.Event); ok {
+		r0 = rf(userID, date, officeID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.AttendanceStats)
+			r0 = ret.Get(0).(*types.Event)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(int, time.Time, int) error); ok {
+		r1 = rf(userID, date, officeID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,13 +1842,13 @@ func (_m *RTOBLL) CalculateAttendanceStats() (*types.AttendanceStats, error) {
 	return r0, r1
 }
 
-// ClearEventsForDate provides a mock function with given fields: date
-func (_m *RTOBLL) ClearEventsForDate(date time.Time) error {
-	ret := _m.Called(date)
+// SetCompanyRequest provides a mock function with given fields: userID, date, want
+func (_m *RTOBLL) SetCompanyRequest(userID int, date time.Time, want bool) error {
+	ret := _m.Called(userID, date, want)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
-		r0 = rf(date)
+	if rf, ok := ret.Get(0).(func(int, time.Time, bool) error); ok {
+		r0 = rf(userID, date, want)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -103,13 +1856,13 @@ func (_m *RTOBLL) ClearEventsForDate(date time.Time) error {
 	return r0
 }
 
-// DeleteEvent provides a mock function with given fields: eventID
-func (_m *RTOBLL) DeleteEvent(eventID int) error {
-	ret := _m.Called(eventID)
+// SetCountOtherOffices provides a mock function with given fields: count
+func (_m *RTOBLL) SetCountOtherOffices(count bool) error {
+	ret := _m.Called(count)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(eventID)
+	if rf, ok := ret.Get(0).(func(bool) error); ok {
+		r0 = rf(count)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -117,38 +1870,153 @@ func (_m *RTOBLL) DeleteEvent(eventID int) error {
 	return r0
 }
 
-// GetAllEvents provides a mock function with given fields:
-func (_m *RTOBLL) GetAllEvents() []types.Event {
-	ret := _m.Called()
+// SetDefaultOffice provides a mock function with given fields: userID, officeID
+func (_m *RTOBLL) SetDefaultOffice(userID int, officeID int) error {
+	ret := _m.Called(userID, officeID)
 
-	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func() []types.Event); ok {
-		r0 = rf()
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, officeID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]types.Event)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// GetEventByDateAndType provides a mock function with given fields: date, eventType
-func (_m *RTOBLL) GetEventByDateAndType(date time.Time, eventType string) (*types.Event, error) {
-	ret := _m.Called(date, eventType)
+// SetMailer provides a mock function with given fields: mailer
+func (_m *RTOBLL) SetMailer(mailer domain.Mailer) {
+	_m.Called(mailer)
+}
 
-	var r0 *types.Event
-	if rf, ok := ret.Get(0).(func(time.Time, string) *types.Event); ok {
-		r0 = rf(date, eventType)
+// SetOfficeCapacity provides a mock function with given fields: officeID, capacity
+func (_m *RTOBLL) SetOfficeCapacity(officeID int, capacity int) error {
+	ret := _m.Called(officeID, capacity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(officeID, capacity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPreferences provides a mock function with given fields: userID, version
+func (_m *RTOBLL) SetPreferences(userID int, version types.PreferenceVersion) error {
+	ret := _m.Called(userID, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, types.PreferenceVersion) error); ok {
+		r0 = rf(userID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRegistrationOpen provides a mock function with given fields: open
+func (_m *RTOBLL) SetRegistrationOpen(open bool) error {
+	ret := _m.Called(open)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(bool) error); ok {
+		r0 = rf(open)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTwoFactorRoles provides a mock function with given fields: roles
+func (_m *RTOBLL) SetTwoFactorRoles(roles []string) error {
+	ret := _m.Called(roles)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserEmail provides a mock function with given fields: username, email
+func (_m *RTOBLL) SetUserEmail(username string, email string) error {
+	ret := _m.Called(username, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(username, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserManager provides a mock function with given fields: userID, managerID
+func (_m *RTOBLL) SetUserManager(userID int, managerID int) error {
+	ret := _m.Called(userID, managerID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, managerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserRole provides a mock function with given fields: userID, role
+func (_m *RTOBLL) SetUserRole(userID int, role string) error {
+	ret := _m.Called(userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWebhookActive provides a mock function with given fields: userID, webhookID, active
+func (_m *RTOBLL) SetWebhookActive(userID int, webhookID int, active bool) error {
+	ret := _m.Called(userID, webhookID, active)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, bool) error); ok {
+		r0 = rf(userID, webhookID, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignInExternal provides a mock function with given fields: identity
+func (_m *RTOBLL) SignInExternal(identity types.ExternalIdentity) (*types.User, error) {
+	ret := _m.Called(identity)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(types.ExternalIdentity) *types.User); ok {
+		r0 = rf(identity)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.Event)
+			r0 = ret.Get(0).(*types.User)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time, string) error); ok {
-		r1 = rf(date, eventType)
+	if rf, ok := ret.Get(1).(func(types.ExternalIdentity) error); ok {
+		r1 = rf(identity)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -156,20 +2024,25 @@ func (_m *RTOBLL) GetEventByDateAndType(date time.Time, eventType string) (*type
 	return r0, r1
 }
 
-// GetEventByID provides a mock function with given fields: eventID
-func (_m *RTOBLL) GetEventByID(eventID int) (types.Event, error) {
-	ret := _m.Called(eventID)
+// Subscribe provides a mock function with given fields: listener
+func (_m *RTOBLL) Subscribe(listener types.ChangeListener) {
+	_m.Called(listener)
+}
+
+// SuggestDefaultDays provides a mock function with given fields: userID
+func (_m *RTOBLL) SuggestDefaultDays(userID int) (string, error) {
+	ret := _m.Called(userID)
 
-	var r0 types.Event
-	if rf, ok := ret.Get(0).(func(int) types.Event); ok {
-		r0 = rf(eventID)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int) string); ok {
+		r0 = rf(userID)
 	} else {
-		r0 = ret.Get(0).(types.Event)
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(eventID)
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -177,22 +2050,22 @@ func (_m *RTOBLL) GetEventByID(eventID int) (types.Event, error) {
 	return r0, r1
 }
 
-// GetEventsByDate provides a mock function with given fields: date
-func (_m *RTOBLL) GetEventsByDate(date time.Time) ([]types.Event, error) {
-	ret := _m.Called(date)
+// SuggestOfficeDays provides a mock function with given fields: userID, from
+func (_m *RTOBLL) SuggestOfficeDays(userID int, from time.Time) ([]types.OfficeDay, error) {
+	ret := _m.Called(userID, from)
 
-	var r0 []types.Event
-	if rf, ok := ret.Get(0).(func(time.Time) []types.Event); ok {
-		r0 = rf(date)
+	var r0 []types.OfficeDay
+	if rf, ok := ret.Get(0).(func(int, time.Time) []types.OfficeDay); ok {
+		r0 = rf(userID, from)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]types.Event)
+			r0 = ret.Get(0).([]types.OfficeDay)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
-		r1 = rf(date)
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, from)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -200,34 +2073,66 @@ func (_m *RTOBLL) GetEventsByDate(date time.Time) ([]types.Event, error) {
 	return r0, r1
 }
 
-// GetPrefs provides a mock function with given fields:
-func (_m *RTOBLL) GetPrefs() types.Preferences {
-	ret := _m.Called()
+// TeamDashboard provides a mock function with given fields: viewerID, month
+func (_m *RTOBLL) TeamDashboard(viewerID int, month time.Time) (*types.TeamDashboard, error) {
+	ret := _m.Called(viewerID, month)
 
-	var r0 types.Preferences
-	if rf, ok := ret.Get(0).(func() types.Preferences); ok {
-		r0 = rf()
+	var r0 *types.TeamDashboard
+	if rf, ok := ret.Get(0).(func(int, time.Time) *types.TeamDashboard); ok {
+		r0 = rf(viewerID, month)
 	} else {
-		r0 = ret.Get(0).(types.Preferences)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TeamDashboard)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(viewerID, month)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Teammates provides a mock function with given fields: userID
+func (_m *RTOBLL) Teammates(userID int) ([]types.User, error) {
+	ret := _m.Called(userID)
+
+	var r0 []types.User
+	if rf, ok := ret.Get(0).(func(int) []types.User); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// ToggleAttendance provides a mock function with given fields: eventDate
-func (_m *RTOBLL) ToggleAttendance(eventDate time.Time) (string, error) {
-	ret := _m.Called(eventDate)
+// ToggleAttendance provides a mock function with given fields: userID, eventDate
+func (_m *RTOBLL) ToggleAttendance(userID int, eventDate time.Time) (string, error) {
+	ret := _m.Called(userID, eventDate)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(time.Time) string); ok {
-		r0 = rf(eventDate)
+	if rf, ok := ret.Get(0).(func(int, time.Time) string); ok {
+		r0 = rf(userID, eventDate)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
-		r1 = rf(eventDate)
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, eventDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -235,13 +2140,13 @@ func (_m *RTOBLL) ToggleAttendance(eventDate time.Time) (string, error) {
 	return r0, r1
 }
 
-// TransformVacationToRemote provides a mock function with given fields: eventID
-func (_m *RTOBLL) TransformVacationToRemote(eventID int) error {
-	ret := _m.Called(eventID)
+// TransformVacationToRemote provides a mock function with given fields: userID, eventID
+func (_m *RTOBLL) TransformVacationToRemote(userID int, eventID int) error {
+	ret := _m.Called(userID, eventID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(eventID)
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, eventID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -249,13 +2154,43 @@ func (_m *RTOBLL) TransformVacationToRemote(eventID int) error {
 	return r0
 }
 
-// UpdateEvent provides a mock function with given fields: event
-func (_m *RTOBLL) UpdateEvent(event types.Event) error {
-	ret := _m.Called(event)
+// TwoFactorRequired provides a mock function with given fields: user
+func (_m *RTOBLL) TwoFactorRequired(user types.User) bool {
+	ret := _m.Called(user)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(types.User) bool); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// TwoFactorRoles provides a mock function with given fields:
+func (_m *RTOBLL) TwoFactorRoles() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// UpdateEvent provides a mock function with given fields: userID, event
+func (_m *RTOBLL) UpdateEvent(userID int, event types.Event) error {
+	ret := _m.Called(userID, event)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(types.Event) error); ok {
-		r0 = rf(event)
+	if rf, ok := ret.Get(0).(func(int, types.Event) error); ok {
+		r0 = rf(userID, event)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -263,13 +2198,27 @@ func (_m *RTOBLL) UpdateEvent(event types.Event) error {
 	return r0
 }
 
-// UpdatePreferences provides a mock function with given fields: defaultDays, targetDays
-func (_m *RTOBLL) UpdatePreferences(defaultDays string, targetDays string) error {
-	ret := _m.Called(defaultDays, targetDays)
+// UpdateNotificationSettings provides a mock function with given fields: userID, settings
+func (_m *RTOBLL) UpdateNotificationSettings(userID int, settings types.NotificationSettings) error {
+	ret := _m.Called(userID, settings)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(defaultDays, targetDays)
+	if rf, ok := ret.Get(0).(func(int, types.NotificationSettings) error); ok {
+		r0 = rf(userID, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePreferences provides a mock function with given fields: userID, defaultDays, targetDays
+func (_m *RTOBLL) UpdatePreferences(userID int, defaultDays string, targetDays string) error {
+	ret := _m.Called(userID, defaultDays, targetDays)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, string) error); ok {
+		r0 = rf(userID, defaultDays, targetDays)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -277,6 +2226,52 @@ func (_m *RTOBLL) UpdatePreferences(defaultDays string, targetDays string) error
 	return r0
 }
 
+// VerifySecondFactor provides a mock function with given fields: userID, code
+func (_m *RTOBLL) VerifySecondFactor(userID int, code string) (*types.User, error) {
+	ret := _m.Called(userID, code)
+
+	var r0 *types.User
+	if rf, ok := ret.Get(0).(func(int, string) *types.User); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WhoIsIn provides a mock function with given fields: userID, date
+func (_m *RTOBLL) WhoIsIn(userID int, date time.Time) (*types.OfficeDay, error) {
+	ret := _m.Called(userID, date)
+
+	var r0 *types.OfficeDay
+	if rf, ok := ret.Get(0).(func(int, time.Time) *types.OfficeDay); ok {
+		r0 = rf(userID, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OfficeDay)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(userID, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewRTOBLL interface {
 	mock.TestingT
 	Cleanup(func())
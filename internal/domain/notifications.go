@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Mailer renders reminders and sends them by email
+type Mailer interface {
+	Render(reminder types.Reminder) (subject, body string, err error)
+	Send(to, subject, body string) error
+}
+
+// Limits on the reminder settings
+const (
+	maxBehindDays   = 20
+	maxVacationLead = 14
+	maxPTODays      = 366
+)
+
+// SetMailer turns on email reminders. Without a mailer nothing is sent.
+func (s *Service) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// EmailEnabled reports whether a mailer is configured
+func (s *Service) EmailEnabled() bool {
+	return s.mailer != nil
+}
+
+// GetNotificationSettings returns the user's reminder choices, all off by default
+func (s *Service) GetNotificationSettings(userID int) (types.NotificationSettings, error) {
+	settings, err := s.notificationRepo.GetNotificationSettings(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return types.DefaultNotificationSettings(uint(userID)), nil
+	}
+	if err != nil {
+		s.logger.Error("Error fetching notification settings", "userID", userID, "error", err)
+	}
+	return settings, err
+}
+
+// UpdateNotificationSettings validates and stores the user's reminder choices
+func (s *Service) UpdateNotificationSettings(userID int, settings types.NotificationSettings) error {
+	if settings.BehindDays <= 0 || settings.BehindDays > maxBehindDays {
+		return fmt.Errorf("%w: the behind threshold must be more than 0 and at most %d days", ErrInvalidInput, maxBehindDays)
+	}
+	if settings.VacationLead < 1 || settings.VacationLead > maxVacationLead {
+		return fmt.Errorf("%w: vacation notice must be between 1 and %d days", ErrInvalidInput, maxVacationLead)
+	}
+	if settings.PTODays < 0 || settings.PTODays > maxPTODays {
+		return fmt.Errorf("%w: the vacation allowance must be between 0 and %d days", ErrInvalidInput, maxPTODays)
+	}
+
+	settings.UserID = uint(userID)
+	if err := s.notificationRepo.SaveNotificationSettings(settings); err != nil {
+		s.logger.Error("Error saving notification settings", "userID", userID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetEmailDeliveries returns the user's most recent reminder emails, newest first
+func (s *Service) GetEmailDeliveries(userID int, limit int) ([]types.EmailDelivery, error) {
+	return s.notificationRepo.GetEmailDeliveries(userID, limit)
+}
+
+// SendTestEmail sends the user a test message to check the mail setup
+func (s *Service) SendTestEmail(userID int) error {
+	if s.mailer == nil {
+		return fmt.Errorf("%w: email is not configured", ErrConflict)
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return fmt.Errorf("%w: the account has no email address", ErrInvalidInput)
+	}
+	return s.deliver(types.Reminder{Kind: types.ReminderTest, User: user})
+}
+
+// SendReminders emails every opted-in user the reminders that apply to them
+// today. Each reminder goes out once per key, so running it more than once a
+// day is harmless. It returns how many emails were sent.
+func (s *Service) SendReminders() (int, error) {
+	return s.sendReminders(time.Now())
+}
+
+func (s *Service) sendReminders(now time.Time) (int, error) {
+	if s.mailer == nil {
+		return 0, fmt.Errorf("%w: email is not configured", ErrConflict)
+	}
+	subscribers, err := s.notificationRepo.GetSubscribers()
+	if err != nil {
+		s.logger.Error("Error fetching reminder subscribers", "error", err)
+		return 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, settings := range subscribers {
+		user, err := s.getUser(int(settings.UserID))
+		if err != nil || user.Email == "" {
+			continue
+		}
+		reminders, err := s.remindersFor(user, settings, now)
+		if err != nil {
+			return sent, err
+		}
+		for _, reminder := range reminders {
+			done, err := s.notificationRepo.EmailSent(int(user.ID), reminder.Kind, reminder.Key)
+			if err != nil {
+				return sent, err
+			}
+			if done {
+				continue
+			}
+			if err := s.deliver(reminder); err != nil {
+				failed++
+				continue
+			}
+			sent++
+		}
+	}
+	if failed > 0 {
+		return sent, fmt.Errorf("%d of %d reminder emails failed", failed, sent+failed)
+	}
+	return sent, nil
+}
+
+// deliver renders and sends one reminder, logging the attempt
+func (s *Service) deliver(reminder types.Reminder) error {
+	subject, body, err := s.mailer.Render(reminder)
+	if err != nil {
+		s.logger.Error("Error rendering reminder", "kind", reminder.Kind, "error", err)
+		return err
+	}
+
+	delivery := types.EmailDelivery{
+		UserID:  reminder.User.ID,
+		Kind:    reminder.Kind,
+		Key:     reminder.Key,
+		To:      reminder.User.Email,
+		Subject: subject,
+		Body:    body,
+	}
+	sendErr := s.mailer.Send(reminder.User.Email, subject, body)
+	if sendErr != nil {
+		s.logger.Warn("Reminder email failed", "userID", reminder.User.ID, "kind", reminder.Kind, "error", sendErr)
+		delivery.Error = truncate(sendErr.Error(), 500)
+	}
+	delivery.Success = sendErr == nil
+
+	if _, err := s.notificationRepo.AddEmailDelivery(delivery); err != nil {
+		s.logger.Error("Error logging email delivery", "userID", reminder.User.ID, "error", err)
+	}
+	return sendErr
+}
+
+// remindersFor works out which of the user's reminders apply on the day
+func (s *Service) remindersFor(user types.User, settings types.NotificationSettings, now time.Time) ([]types.Reminder, error) {
+	userID := int(user.ID)
+	today := utils.NormalizeDate(now)
+	events, err := s.eventRepo.GetAllEvents(userID)
+	if err != nil {
+		s.logger.Error("Error fetching events for reminders", "userID", userID, "error", err)
+		return nil, err
+	}
+
+	var reminders []types.Reminder
+	if settings.Behind {
+		period, err := s.periodFor(today)
+		if err != nil {
+			return nil, err
+		}
+		if reminder, ok := s.behindReminder(user, events, *period, settings.BehindDays, today); ok {
+			reminders = append(reminders, reminder)
+		}
+	}
+	if settings.Unlogged {
+		if reminder, ok := unloggedReminder(user, events, today); ok {
+			reminders = append(reminders, reminder)
+		}
+	}
+	if settings.Vacation {
+		reminders = append(reminders, vacationReminders(user, events, settings, today)...)
+	}
+	return reminders, nil
+}
+
+// behindReminder nudges the user when their in-office days so far this
+// period trail the target by at least threshold days. It goes out at most
+// once a week.
+func (s *Service) behindReminder(user types.User, events []types.Event, period types.Period, threshold float64, today time.Time) (types.Reminder, bool) {
+	if today.Before(period.StartDate) || today.After(period.EndDate) {
+		return types.Reminder{}, false
+	}
+	stats := s.attendanceStats(int(user.ID), events, period.StartDate, today)
+	behind := stats.TargetDays*float64(stats.TotalDays)/types.DaysPerWeek(stats.Method) - float64(stats.InOfficeCount)
+	// Round to half days, as people count them
+	behind = math.Round(behind*2) / 2
+	if behind < threshold {
+		return types.Reminder{}, false
+	}
+
+	year, week := today.ISOWeek()
+	return types.Reminder{
+		Kind:       types.ReminderBehind,
+		Key:        fmt.Sprintf("%d-W%02d", year, week),
+		User:       user,
+		BehindDays: behind,
+		TargetDays: stats.TargetDays,
+		WeeksLeft:  math.Round(period.EndDate.Sub(today).Hours()/24/7*10) / 10,
+		PeriodName: period.Name,
+	}, true
+}
+
+// unloggedReminder lists the weekdays of last week with nothing logged,
+// leaving out days before the account existed
+func unloggedReminder(user types.User, events []types.Event, today time.Time) (types.Reminder, bool) {
+	monday := today.AddDate(0, 0, -((int(today.Weekday())+6)%7)-7)
+	created := utils.NormalizeDate(user.CreatedAt)
+
+	var days []time.Time
+	for d := monday; d.Before(monday.AddDate(0, 0, 5)); d = d.AddDate(0, 0, 1) {
+		if !d.Before(created) {
+			days = append(days, d)
+		}
+	}
+	var missing []time.Time
+	for i, status := range dayStatuses(events, days) {
+		if status == types.DayUnknown {
+			missing = append(missing, days[i])
+		}
+	}
+	if len(missing) == 0 {
+		return types.Reminder{}, false
+	}
+	return types.Reminder{
+		Kind:    types.ReminderUnlogged,
+		Key:     monday.Format("2006-01-02"),
+		User:    user,
+		Missing: missing,
+	}, true
+}
+
+// vacationReminders announces each vacation starting within the lead days,
+// with the allowance left once it is taken when one is set
+func vacationReminders(user types.User, events []types.Event, settings types.NotificationSettings, today time.Time) []types.Reminder {
+	vacation := make(map[string]bool)
+	var dates []time.Time
+	for _, event := range events {
+		if event.Type != "vacation" {
+			continue
+		}
+		date := utils.NormalizeDate(event.Date)
+		if !vacation[date.Format("2006-01-02")] {
+			vacation[date.Format("2006-01-02")] = true
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	isVacation := func(d time.Time) bool { return vacation[d.Format("2006-01-02")] }
+
+	var reminders []types.Reminder
+	last := today.AddDate(0, 0, settings.VacationLead)
+	for _, start := range dates {
+		if !start.After(today) || start.After(last) || isVacation(prevWeekday(start)) {
+			continue
+		}
+		reminder := types.Reminder{
+			Kind:          types.ReminderVacation,
+			Key:           start.Format("2006-01-02"),
+			User:          user,
+			VacationStart: start,
+			VacationEnd:   start,
+		}
+		// A vacation runs over weekends until a weekday without one
+		for d := start; isVacation(d); d = nextWeekday(d) {
+			reminder.VacationEnd = d
+			reminder.VacationDays++
+		}
+
+		if settings.PTODays > 0 {
+			used := 0
+			for _, d := range dates {
+				if d.Year() == start.Year() && !d.After(reminder.VacationEnd) && !utils.IsWeekend(d) {
+					used++
+				}
+			}
+			reminder.HasPTO = true
+			reminder.PTOBalance = settings.PTODays - used
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders
+}
+
+func prevWeekday(d time.Time) time.Time {
+	for d = d.AddDate(0, 0, -1); utils.IsWeekend(d); d = d.AddDate(0, 0, -1) {
+	}
+	return d
+}
+
+func nextWeekday(d time.Time) time.Time {
+	for d = d.AddDate(0, 0, 1); utils.IsWeekend(d); d = d.AddDate(0, 0, 1) {
+	}
+	return d
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// fakeMailer records what it is asked to send
+type fakeMailer struct {
+	sent []types.Reminder
+	fail error
+}
+
+func (m *fakeMailer) Render(reminder types.Reminder) (string, string, error) {
+	m.sent = append(m.sent, reminder)
+	return reminder.Kind + " subject", reminder.Kind + " body", nil
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	return m.fail
+}
+
+func TestUnloggedReminder(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+	user := types.User{ID: 1, CreatedAt: day(1)}
+	events := []types.Event{
+		{Date: day(12), Type: "attendance", IsInOffice: true},
+		{Date: day(13), Type: "attendance"},
+		{Date: day(14), Type: "vacation"},
+		{Date: day(16), Type: "holiday"},
+	}
+
+	// Monday the 19th looks back at the 12th to the 16th
+	reminder, ok := unloggedReminder(user, events, day(19))
+
+	assert.True(t, ok)
+	assert.Equal(t, "2026-10-12", reminder.Key)
+	assert.Equal(t, []time.Time{day(15)}, reminder.Missing)
+
+	// Nothing is missing before the account existed
+	user.CreatedAt = day(16)
+	_, ok = unloggedReminder(user, events, day(21))
+	assert.False(t, ok)
+}
+
+func TestVacationReminders(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+	events := []types.Event{
+		{Date: day(2), Type: "vacation"},
+		{Date: day(23), Type: "vacation"}, // Friday to Tuesday
+		{Date: day(26), Type: "vacation"},
+		{Date: day(27), Type: "vacation"},
+		{Date: day(30), Type: "vacation"}, // too far off
+	}
+	settings := types.NotificationSettings{Vacation: true, VacationLead: 5, PTODays: 10}
+
+	reminders := vacationReminders(types.User{ID: 1}, events, settings, day(19))
+
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, "2026-10-23", reminders[0].Key)
+	assert.Equal(t, day(27), reminders[0].VacationEnd)
+	assert.Equal(t, 3, reminders[0].VacationDays)
+	assert.True(t, reminders[0].HasPTO)
+	assert.Equal(t, 6, reminders[0].PTOBalance) // the 2nd plus this trip
+}
+
+func newReminderTestService(mailer Mailer) (*Service, *mocks.NotificationRepository) {
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+
+	mockNotificationRepo := new(mocks.NotificationRepository)
+	mockNotificationRepo.On("GetSubscribers").Return([]types.NotificationSettings{
+		{UserID: 1, Behind: true, BehindDays: 1},
+		{UserID: 2, Behind: true, BehindDays: 1}, // no address
+	}, nil)
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "ann", Email: "ann@example.com"}, nil)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "bo"}, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{
+		{Date: day(5), Type: "attendance", IsInOffice: true},
+	}, nil)
+
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", day(19)).Return(types.Period{Name: "Q4 2026", StartDate: day(1), EndDate: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{TargetDays: "2"}, nil)
+
+	service := &Service{
+		logger:           slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:        mockEventRepo,
+		periodRepo:       mockPeriodRepo,
+		preferenceRepo:   mockPrefsRepo,
+		userRepo:         mockUserRepo,
+		notificationRepo: mockNotificationRepo,
+		mailer:           mailer,
+	}
+	return service, mockNotificationRepo
+}
+
+func TestSendReminders_Behind(t *testing.T) {
+	mailer := &fakeMailer{}
+	service, mockNotificationRepo := newReminderTestService(mailer)
+	mockNotificationRepo.On("EmailSent", 1, types.ReminderBehind, "2026-W43").Return(false, nil)
+	mockNotificationRepo.On("AddEmailDelivery", mock.Anything).Return(types.EmailDelivery{}, nil)
+
+	sent, err := service.sendReminders(time.Date(2026, time.October, 19, 7, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	// 2 days a week over 19 days is 5.4 days; 1 was logged
+	assert.Equal(t, 4.5, mailer.sent[0].BehindDays)
+	assert.Equal(t, "Q4 2026", mailer.sent[0].PeriodName)
+	mockNotificationRepo.AssertCalled(t, "AddEmailDelivery", mock.MatchedBy(func(d types.EmailDelivery) bool {
+		return d.UserID == 1 && d.To == "ann@example.com" && d.Subject == "behind subject" && d.Success
+	}))
+}
+
+func TestSendReminders_SentOnce(t *testing.T) {
+	mailer := &fakeMailer{}
+	service, mockNotificationRepo := newReminderTestService(mailer)
+	mockNotificationRepo.On("EmailSent", 1, types.ReminderBehind, "2026-W43").Return(true, nil)
+
+	sent, err := service.sendReminders(time.Date(2026, time.October, 19, 7, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, mailer.sent)
+}
+
+func TestSendReminders_LogsFailures(t *testing.T) {
+	mailer := &fakeMailer{fail: errors.New("connection refused")}
+	service, mockNotificationRepo := newReminderTestService(mailer)
+	mockNotificationRepo.On("EmailSent", 1, types.ReminderBehind, "2026-W43").Return(false, nil)
+	mockNotificationRepo.On("AddEmailDelivery", mock.Anything).Return(types.EmailDelivery{}, nil)
+
+	sent, err := service.sendReminders(time.Date(2026, time.October, 19, 7, 0, 0, 0, time.UTC))
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+	mockNotificationRepo.AssertCalled(t, "AddEmailDelivery", mock.MatchedBy(func(d types.EmailDelivery) bool {
+		return !d.Success && d.Error == "connection refused"
+	}))
+}
+
+func TestSendReminders_NoMailer(t *testing.T) {
+	service := &Service{logger: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	_, err := service.SendReminders()
+
+	assert.True(t, errors.Is(err, ErrConflict))
+}
+
+func TestNotificationSettings(t *testing.T) {
+	mockNotificationRepo := new(mocks.NotificationRepository)
+	mockNotificationRepo.On("GetNotificationSettings", 1).Return(types.NotificationSettings{}, gorm.ErrRecordNotFound)
+	service := &Service{logger: slog.New(slog.NewTextHandler(os.Stdout, nil)), notificationRepo: mockNotificationRepo}
+
+	settings, err := service.GetNotificationSettings(1)
+	assert.NoError(t, err)
+	assert.Equal(t, types.DefaultNotificationSettings(1), settings)
+
+	settings.VacationLead = 30
+	err = service.UpdateNotificationSettings(1, settings)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
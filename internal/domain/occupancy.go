@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// maxForecastDays is the longest range an occupancy forecast covers
+const maxForecastDays = 366
+
+// OccupancyForecast counts how many people each office expects on every
+// weekday between from and to. Days still to come follow the attendance people
+// have logged, falling back to the default days AddDefaultDays would fill in;
+// past days set that default pattern against what was logged.
+func (s *Service) OccupancyForecast(from, to time.Time) (*types.OccupancyForecast, error) {
+	return s.occupancyForecast(from, to, time.Now())
+}
+
+func (s *Service) occupancyForecast(from, to, now time.Time) (*types.OccupancyForecast, error) {
+	from, to = utils.NormalizeDate(from), utils.NormalizeDate(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+	if to.Sub(from) > maxForecastDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a forecast covers at most %d days", ErrInvalidInput, maxForecastDays)
+	}
+
+	offices, err := s.GetOffices()
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint]bool)
+	officeIDs := make([]uint, 0, len(offices))
+	for _, office := range offices {
+		known[office.ID] = true
+		officeIDs = append(officeIDs, office.ID)
+	}
+	var days []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !utils.IsWeekend(d) {
+			days = append(days, d)
+		}
+	}
+
+	// Head counts by day, then office; days at a deleted office have no office
+	planned := make([]map[uint]int, len(days))
+	actual := make([]map[uint]int, len(days))
+	for i := range days {
+		planned[i], actual[i] = make(map[uint]int), make(map[uint]int)
+	}
+	for _, user := range users {
+		prefs, pattern, err := s.defaultPattern(int(user.ID))
+		if err != nil {
+			return nil, err
+		}
+		events, err := s.eventRepo.GetEventsBetweenDates(int(user.ID), from, to)
+		if err != nil {
+			s.logger.Error("Error fetching events", "userID", user.ID, "error", err)
+			return nil, err
+		}
+
+		for i, day := range days {
+			past := day.Before(utils.NormalizeDate(now))
+			if officeID, ok := plannedOffice(events, pattern(day), prefs.DefaultOfficeID, day, past); ok {
+				if !known[officeID] {
+					officeID = 0
+				}
+				planned[i][officeID]++
+			}
+			if officeID, ok := loggedOffice(events, prefs.DefaultOfficeID, day); ok && past {
+				if !known[officeID] {
+					officeID = 0
+				}
+				actual[i][officeID]++
+			}
+		}
+	}
+
+	forecast := &types.OccupancyForecast{From: from, To: to, Offices: offices}
+	miss, anyPast := 0, false
+	for i, day := range days {
+		past := day.Before(utils.NormalizeDate(now))
+		anyPast = anyPast || past
+		dayOffices := officeIDs
+		if planned[i][0] > 0 || actual[i][0] > 0 {
+			dayOffices = append(dayOffices[:len(dayOffices):len(dayOffices)], 0)
+		}
+
+		for _, officeID := range dayOffices {
+			forecast.Days = append(forecast.Days, types.OccupancyDay{
+				Date:     day,
+				OfficeID: officeID,
+				Planned:  planned[i][officeID],
+				Actual:   actual[i][officeID],
+				Past:     past,
+			})
+			if past {
+				forecast.Planned += planned[i][officeID]
+				forecast.Actual += actual[i][officeID]
+				miss += int(math.Abs(float64(planned[i][officeID] - actual[i][officeID])))
+			}
+		}
+	}
+	if anyPast {
+		forecast.Accuracy = forecastAccuracy(forecast.Actual, miss)
+	}
+	return forecast, nil
+}
+
+// plannedOffice is the office the user is expected at on the day, if any.
+// Holidays and vacation keep them out. Logged attendance decides days still
+// to come; past days are planned from the default pattern alone.
+func plannedOffice(events []types.Event, pattern map[time.Weekday]bool, home uint, day time.Time, past bool) (uint, bool) {
+	switch dayStatuses(events, []time.Time{day})[0] {
+	case types.DayHoliday, types.DayVacation:
+		return 0, false
+	case types.DayInOffice:
+		if !past {
+			return loggedOffice(events, home, day)
+		}
+	case types.DayRemote:
+		if !past {
+			return 0, false
+		}
+	}
+	return home, pattern[day.Weekday()]
+}
+
+// loggedOffice is the office the user logged being in at on the day, if any.
+// Days with no office recorded are taken to be at the default office.
+func loggedOffice(events []types.Event, home uint, day time.Time) (uint, bool) {
+	if dayStatuses(events, []time.Time{day})[0] != types.DayInOffice {
+		return 0, false
+	}
+	for _, event := range events {
+		if event.Type == "attendance" && event.IsInOffice && utils.SameDay(event.Date, day) {
+			if event.OfficeID == 0 {
+				return home, true
+			}
+			return event.OfficeID, true
+		}
+	}
+	return 0, false
+}
+
+// forecastAccuracy is 100 less the total miss as a percentage of the actual
+// head count, never below 0
+func forecastAccuracy(actual, miss int) float64 {
+	if actual == 0 {
+		if miss == 0 {
+			return 100
+		}
+		return 0
+	}
+	return math.Max(0, 100*(1-float64(miss)/float64(actual)))
+}
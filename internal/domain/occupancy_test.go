@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccupancyForecast(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+	from, to := day(7), day(11) // Monday to Friday
+
+	mockOfficeRepo := new(mocks.OfficeRepository)
+	mockOfficeRepo.On("GetAllOffices").Return([]types.Office{{ID: 1, Name: "HQ"}, {ID: 2, Name: "Annex"}}, nil)
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 1, Username: "ann"}, {ID: 2, Username: "bo"}}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(types.Preferences{DefaultDays: "M,W", DefaultOfficeID: 1}, nil)
+	mockPrefsRepo.On("GetPreferences", 2).Return(types.Preferences{DefaultDays: "T"}, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventsBetweenDates", 1, from, to).Return([]types.Event{
+		{Date: day(7), Type: "attendance", IsInOffice: true, OfficeID: 1},
+		{Date: day(8), Type: "attendance", IsInOffice: true},
+		{Date: day(9), Type: "vacation"},
+		{Date: day(10), Type: "attendance", IsInOffice: true, OfficeID: 2},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 2, from, to).Return([]types.Event{
+		{Date: day(8), Type: "attendance", IsInOffice: true},
+	}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:       mockUserRepo,
+		eventRepo:      mockEventRepo,
+		officeRepo:     mockOfficeRepo,
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	// Wednesday: Monday and Tuesday are past
+	forecast, err := service.occupancyForecast(from, to, day(9))
+
+	assert.NoError(t, err)
+	count := func(d int, officeID uint) types.OccupancyDay {
+		for _, o := range forecast.Days {
+			if o.Date.Equal(day(d)) && o.OfficeID == officeID {
+				return o
+			}
+		}
+		t.Fatalf("no row for October %d, office %d", d, officeID)
+		return types.OccupancyDay{}
+	}
+
+	// Every office every day, plus the no-office row on Tuesday
+	assert.Len(t, forecast.Days, 11)
+	assert.Equal(t, types.OccupancyDay{Date: day(7), OfficeID: 1, Planned: 1, Actual: 1, Past: true}, count(7, 1))
+	assert.Equal(t, types.OccupancyDay{Date: day(8), OfficeID: 1, Planned: 0, Actual: 1, Past: true}, count(8, 1))
+	assert.Equal(t, types.OccupancyDay{Date: day(8), OfficeID: 0, Planned: 1, Actual: 1, Past: true}, count(8, 0))
+	// Vacation wins over the default day; logged plans win over the pattern
+	assert.Equal(t, 0, count(9, 1).Planned)
+	assert.Equal(t, 1, count(10, 2).Planned)
+	assert.Equal(t, 0, count(10, 1).Planned)
+
+	assert.Equal(t, 2, forecast.Planned)
+	assert.Equal(t, 3, forecast.Actual)
+	assert.InDelta(t, 100*(1-1.0/3), forecast.Accuracy, 0.0001)
+}
+
+func TestOccupancyForecast_RangeTooLong(t *testing.T) {
+	service := Service{logger: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	_, err := service.OccupancyForecast(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
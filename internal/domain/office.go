@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// How far ahead SuggestOfficeDays looks, and how many days it offers
+const (
+	suggestionWindow = 14
+	maxSuggestions   = 3
+)
+
+// Teammates returns the people a user coordinates office days with: their
+// manager, the others who report to that manager, and their own reports
+func (s *Service) Teammates(userID int) ([]types.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var team []types.User
+	seen := map[uint]bool{user.ID: true}
+	add := func(users ...types.User) {
+		for _, u := range users {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				team = append(team, u)
+			}
+		}
+	}
+
+	if user.ManagerID != nil {
+		manager, err := s.getUser(int(*user.ManagerID))
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			add(manager)
+		}
+		peers, err := s.GetReports(int(*user.ManagerID))
+		if err != nil {
+			return nil, err
+		}
+		add(peers...)
+	}
+	reports, err := s.GetReports(userID)
+	if err != nil {
+		return nil, err
+	}
+	add(reports...)
+	return team, nil
+}
+
+// WhoIsIn lists the teammates planning to be in the office on a date, or who
+// were, and who asked for company
+func (s *Service) WhoIsIn(userID int, date time.Time) (*types.OfficeDay, error) {
+	date = utils.NormalizeDate(date)
+	days, err := s.officeDays(userID, []time.Time{date})
+	if err != nil {
+		return nil, err
+	}
+	return &days[0], nil
+}
+
+// SuggestOfficeDays picks upcoming weekdays worth coming in for: days the user
+// is not already in, ranked by teammates asking for company, then by how many
+// teammates will be there
+func (s *Service) SuggestOfficeDays(userID int, from time.Time) ([]types.OfficeDay, error) {
+	from = utils.NormalizeDate(from)
+	var dates []time.Time
+	for i := 0; i < suggestionWindow; i++ {
+		if day := from.AddDate(0, 0, i); !utils.IsWeekend(day) {
+			dates = append(dates, day)
+		}
+	}
+
+	days, err := s.officeDays(userID, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []types.OfficeDay
+	for _, day := range days {
+		if len(day.InOffice) > 0 && (day.Status == types.DayUnknown || day.Status == types.DayRemote) {
+			suggestions = append(suggestions, day)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if len(suggestions[i].WantCompany) != len(suggestions[j].WantCompany) {
+			return len(suggestions[i].WantCompany) > len(suggestions[j].WantCompany)
+		}
+		return len(suggestions[i].InOffice) > len(suggestions[j].InOffice)
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions, nil
+}
+
+// SetCompanyRequest marks or clears "I'd like company" for one of the user's
+// upcoming days
+func (s *Service) SetCompanyRequest(userID int, date time.Time, want bool) error {
+	date = utils.NormalizeDate(date)
+	if !want {
+		if err := s.companyRepo.DeleteCompanyRequest(userID, date); err != nil {
+			s.logger.Error("Error clearing company request", "userID", userID, "date", date, "error", err)
+			return err
+		}
+		return nil
+	}
+
+	if date.Before(utils.NormalizeDate(time.Now())) {
+		return fmt.Errorf("%w: company can only be asked for today or later", ErrInvalidInput)
+	}
+	if utils.IsWeekend(date) {
+		return fmt.Errorf("%w: company can only be asked for on a weekday", ErrInvalidInput)
+	}
+	if err := s.companyRepo.AddCompanyRequest(types.CompanyRequest{UserID: uint(userID), Date: date}); err != nil {
+		s.logger.Error("Error saving company request", "userID", userID, "date", date, "error", err)
+		return err
+	}
+	s.logger.Info("Company requested", "userID", userID, "date", date.Format("2006-01-02"))
+	return nil
+}
+
+// officeDays works out the user's and their teammates' statuses on each date,
+// which must be in order
+func (s *Service) officeDays(userID int, dates []time.Time) ([]types.OfficeDay, error) {
+	days := make([]types.OfficeDay, len(dates))
+	if len(dates) == 0 {
+		return days, nil
+	}
+	start, end := dates[0], dates[len(dates)-1]
+
+	team, err := s.Teammates(userID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.companyRepo.GetCompanyRequests(start, end)
+	if err != nil {
+		s.logger.Error("Error fetching company requests", "error", err)
+		return nil, err
+	}
+	asked := make(map[string]bool)
+	for _, request := range requests {
+		asked[fmt.Sprint(request.UserID, request.Date.Format("2006-01-02"))] = true
+	}
+
+	events, err := s.eventRepo.GetEventsBetweenDates(userID, start, end)
+	if err != nil {
+		s.logger.Error("Error fetching events", "userID", userID, "error", err)
+		return nil, err
+	}
+	for i, status := range dayStatuses(events, dates) {
+		days[i].Date = dates[i]
+		days[i].Status = status
+		days[i].AskedForCompany = asked[fmt.Sprint(userID, dates[i].Format("2006-01-02"))]
+	}
+
+	for _, mate := range team {
+		events, err := s.eventRepo.GetEventsBetweenDates(int(mate.ID), start, end)
+		if err != nil {
+			s.logger.Error("Error fetching events", "userID", mate.ID, "error", err)
+			return nil, err
+		}
+		for i, status := range dayStatuses(events, dates) {
+			if status != types.DayInOffice {
+				continue
+			}
+			days[i].InOffice = append(days[i].InOffice, mate)
+			if asked[fmt.Sprint(mate.ID, dates[i].Format("2006-01-02"))] {
+				days[i].WantCompany = append(days[i].WantCompany, mate)
+			}
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newOfficeTestService(t *testing.T) (*Service, *mocks.EventRepository, *mocks.CompanyRepository) {
+	manager := uint(1)
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "ann", ManagerID: &manager}, nil)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "mgr", Role: types.RoleManager}, nil)
+	mockUserRepo.On("GetUsersByManager", 1).Return([]types.User{
+		{ID: 2, Username: "ann", ManagerID: &manager},
+		{ID: 3, Username: "bob", ManagerID: &manager},
+	}, nil)
+	mockUserRepo.On("GetUsersByManager", 2).Return([]types.User{}, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockCompanyRepo := new(mocks.CompanyRepository)
+
+	return &Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:    mockUserRepo,
+		eventRepo:   mockEventRepo,
+		companyRepo: mockCompanyRepo,
+	}, mockEventRepo, mockCompanyRepo
+}
+
+func TestTeammates(t *testing.T) {
+	service, _, _ := newOfficeTestService(t)
+
+	team, err := service.Teammates(2)
+
+	assert.NoError(t, err)
+	var names []string
+	for _, user := range team {
+		names = append(names, user.Username)
+	}
+	assert.Equal(t, []string{"mgr", "bob"}, names)
+}
+
+func TestWhoIsIn(t *testing.T) {
+	service, mockEventRepo, mockCompanyRepo := newOfficeTestService(t)
+	date := time.Date(2024, time.October, 9, 0, 0, 0, 0, time.UTC)
+
+	mockCompanyRepo.On("GetCompanyRequests", date, date).Return([]types.CompanyRequest{
+		{UserID: 2, Date: date},
+		{UserID: 3, Date: date},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 2, date, date).Return([]types.Event{
+		{Date: date, Type: "attendance", IsInOffice: true},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 1, date, date).Return([]types.Event{
+		{Date: date, Type: "attendance", IsInOffice: true},
+		{Date: date, Type: "vacation"},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 3, date, date).Return([]types.Event{
+		{Date: date, Type: "attendance", IsInOffice: true},
+	}, nil)
+
+	day, err := service.WhoIsIn(2, date.Add(15*time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, date, day.Date)
+	assert.Equal(t, types.DayInOffice, day.Status)
+	assert.True(t, day.AskedForCompany)
+	// The manager is on vacation, so only bob is in
+	assert.Len(t, day.InOffice, 1)
+	assert.Equal(t, "bob", day.InOffice[0].Username)
+	assert.Len(t, day.WantCompany, 1)
+	assert.Equal(t, 2, day.Headcount())
+}
+
+func TestSuggestOfficeDays(t *testing.T) {
+	service, mockEventRepo, mockCompanyRepo := newOfficeTestService(t)
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+	from, to := day(7), day(18) // two working weeks
+
+	mockCompanyRepo.On("GetCompanyRequests", from, to).Return([]types.CompanyRequest{
+		{UserID: 3, Date: day(10)},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 2, from, to).Return([]types.Event{
+		{Date: day(8), Type: "attendance", IsInOffice: true},
+		{Date: day(9), Type: "attendance", IsInOffice: false},
+		{Date: day(11), Type: "vacation"},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 1, from, to).Return([]types.Event{
+		{Date: day(8), Type: "attendance", IsInOffice: true},
+		{Date: day(9), Type: "attendance", IsInOffice: true},
+		{Date: day(11), Type: "attendance", IsInOffice: true},
+		{Date: day(15), Type: "attendance", IsInOffice: true},
+	}, nil)
+	mockEventRepo.On("GetEventsBetweenDates", 3, from, to).Return([]types.Event{
+		{Date: day(8), Type: "attendance", IsInOffice: true},
+		{Date: day(9), Type: "attendance", IsInOffice: true},
+		{Date: day(10), Type: "attendance", IsInOffice: true},
+		{Date: day(11), Type: "attendance", IsInOffice: true},
+	}, nil)
+
+	days, err := service.SuggestOfficeDays(2, from)
+
+	assert.NoError(t, err)
+	var dates []time.Time
+	for _, d := range days {
+		dates = append(dates, d.Date)
+	}
+	// Bob asking for company comes first, then the most teammates. Ann is
+	// already in on the 8th and away on the 11th.
+	assert.Equal(t, []time.Time{day(10), day(9), day(15)}, dates)
+}
+
+func TestSetCompanyRequest(t *testing.T) {
+	service, _, mockCompanyRepo := newOfficeTestService(t)
+	today := time.Now()
+	for today.Weekday() == time.Saturday || today.Weekday() == time.Sunday {
+		today = today.AddDate(0, 0, 1)
+	}
+
+	err := service.SetCompanyRequest(2, today.AddDate(0, 0, -7), true)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	mockCompanyRepo.On("AddCompanyRequest", mock.MatchedBy(func(r types.CompanyRequest) bool {
+		return r.UserID == 2 && r.Date.Hour() == 0
+	})).Return(nil).Once()
+	assert.NoError(t, service.SetCompanyRequest(2, today, true))
+
+	// Clearing works for any day
+	mockCompanyRepo.On("DeleteCompanyRequest", 2, mock.Anything).Return(nil).Once()
+	assert.NoError(t, service.SetCompanyRequest(2, today.AddDate(0, 0, -7), false))
+	mockCompanyRepo.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
+)
+
+// GetPeriods returns every configured period ordered by start date
+func (s *Service) GetPeriods() ([]types.Period, error) {
+	periods, err := s.periodRepo.GetAllPeriods()
+	if err != nil {
+		s.logger.Error("Error fetching periods", "error", err)
+		return nil, err
+	}
+	return periods, nil
+}
+
+// GetPeriod returns a single period by ID
+func (s *Service) GetPeriod(periodID int) (*types.Period, error) {
+	period, err := s.periodRepo.GetPeriodByID(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: period %d", ErrNotFound, periodID)
+		}
+		s.logger.Error("Error fetching period", "periodID", periodID, "error", err)
+		return nil, err
+	}
+	return &period, nil
+}
+
+// GetCurrentPeriod returns the period containing today. When no stored period
+// covers today, the configured quarter is used instead.
+func (s *Service) GetCurrentPeriod() (*types.Period, error) {
+	return s.periodFor(time.Now())
+}
+
+// periodFor returns the period containing the date, falling back to the
+// configured quarter like GetCurrentPeriod
+func (s *Service) periodFor(date time.Time) (*types.Period, error) {
+	period, err := s.periodRepo.GetPeriodForDate(utils.NormalizeDate(date))
+	if err == nil {
+		return &period, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error fetching period", "date", date, "error", err)
+		return nil, err
+	}
+
+	return &types.Period{
+		Name:      PeriodName(s.quarterStart, s.quarterEnd),
+		StartDate: s.quarterStart,
+		EndDate:   s.quarterEnd,
+	}, nil
+}
+
+// CreatePeriod validates and stores a new period. Periods may not overlap.
+func (s *Service) CreatePeriod(period types.Period) (*types.Period, error) {
+	period.StartDate = utils.NormalizeDate(period.StartDate)
+	period.EndDate = utils.NormalizeDate(period.EndDate)
+	period.Name = strings.TrimSpace(period.Name)
+
+	if period.StartDate.IsZero() || period.EndDate.IsZero() {
+		return nil, fmt.Errorf("%w: start and end dates are required", ErrInvalidInput)
+	}
+	if period.EndDate.Before(period.StartDate) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+	if period.Name == "" {
+		period.Name = PeriodName(period.StartDate, period.EndDate)
+	}
+
+	existing, err := s.periodRepo.GetAllPeriods()
+	if err != nil {
+		s.logger.Error("Error fetching periods", "error", err)
+		return nil, err
+	}
+	for _, p := range existing {
+		if !period.StartDate.After(p.EndDate) && !period.EndDate.Before(p.StartDate) {
+			return nil, fmt.Errorf("%w: overlaps period %q", ErrConflict, p.Name)
+		}
+	}
+
+	created, err := s.periodRepo.AddPeriod(period)
+	if err != nil {
+		s.logger.Error("Error creating period", "period", period, "error", err)
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeletePeriod removes a period. Events inside it are left untouched. A
+// closed period has to be reopened first, so its archive is not lost by accident.
+func (s *Service) DeletePeriod(periodID int) error {
+	period, err := s.GetPeriod(periodID)
+	if err != nil {
+		return err
+	}
+	if period.Closed() {
+		return fmt.Errorf("%w: %s is closed; reopen it before deleting it", ErrConflict, period.Name)
+	}
+	if err := s.periodRepo.DeletePeriod(periodID); err != nil {
+		s.logger.Error("Error deleting period", "periodID", periodID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// PeriodName builds a display name for a date range, e.g. "Q1 2025" when the
+// range lines up with a calendar quarter and "2025-01-06 to 2025-03-28" otherwise.
+func PeriodName(start, end time.Time) string {
+	quarter := (int(end.Month())-1)/3 + 1
+	quarterStart := time.Date(end.Year(), time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+	if start.Sub(quarterStart) < 7*24*time.Hour && quarterStart.Sub(start) < 7*24*time.Hour {
+		return fmt.Sprintf("Q%d %d", quarter, end.Year())
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCreatePeriod_Overlap(t *testing.T) {
+	// Initialize the mock repository
+	mockRepo := new(mocks.PeriodRepository)
+
+	existing := []types.Period{
+		{
+			ID:        1,
+			Name:      "Q1 2025",
+			StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	mockRepo.On("GetAllPeriods").Return(existing, nil)
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		periodRepo: mockRepo,
+	}
+
+	_, err := service.CreatePeriod(types.Period{
+		StartDate: time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockRepo.AssertNotCalled(t, "AddPeriod")
+}
+
+func TestCreatePeriod_DefaultName(t *testing.T) {
+	// Initialize the mock repository
+	mockRepo := new(mocks.PeriodRepository)
+
+	period := types.Period{
+		Name:      "Q2 2025",
+		StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	stored := period
+	stored.ID = 2
+
+	mockRepo.On("GetAllPeriods").Return([]types.Period{}, nil)
+	mockRepo.On("AddPeriod", period).Return(stored, nil)
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		periodRepo: mockRepo,
+	}
+
+	created, err := service.CreatePeriod(types.Period{
+		StartDate: period.StartDate,
+		EndDate:   period.EndDate,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, *created)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreatePeriod_EndBeforeStart(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, err := service.CreatePeriod(types.Period{
+		StartDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestPeriodName(t *testing.T) {
+	// The default quarter starts on the Monday before January 1st
+	assert.Equal(t, "Q1 2025", PeriodName(
+		time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	))
+	assert.Equal(t, "2025-02-01 to 2025-03-31", PeriodName(
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	))
+}
+
+func TestCreateEvent_Conflict(t *testing.T) {
+	// Initialize the mock repository
+	mockRepo := new(mocks.EventRepository)
+
+	date := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetEventByDateAndType", 1, date, "vacation").Return(types.Event{ID: 4, UserID: 1, Date: date, Type: "vacation"}, nil)
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockRepo,
+		periodRepo: openPeriods(),
+	}
+
+	_, err := service.CreateEvent(1, types.Event{Date: date, Type: "vacation", Description: "Again"})
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockRepo.AssertNotCalled(t, "AddEvent")
+}
+
+func TestCreateEvent_UnknownType(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, err := service.CreateEvent(1, types.Event{Date: time.Now(), Type: "sabbatical"})
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestCreateEvent_HolidaysAreShared(t *testing.T) {
+	mockRepo := new(mocks.EventRepository)
+
+	date := time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC)
+	holiday := types.Event{Date: date, Type: "holiday", Description: "Independence Day"}
+	mockRepo.On("GetEventByDateAndType", 1, date, "holiday").Return(types.Event{}, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("AddEvent", holiday).Return(nil)
+	mockRepo.On("GetEventByDateAndType", 1, date, "holiday").Return(types.Event{ID: 8, Date: date, Type: "holiday"}, nil).Once()
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockRepo,
+		periodRepo: openPeriods(),
+	}
+
+	// The creator's ID is not stored on a holiday
+	holiday.UserID = 1
+	created, err := service.CreateEvent(1, holiday)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), created.UserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQueryEvents_Validation(t *testing.T) {
+	// Initialize the mock repository
+	mockRepo := new(mocks.EventRepository)
+	mockRepo.On("QueryEvents", 1, types.EventFilter{Search: "dentist", Sort: "-description"}).
+		Return([]types.Event{{ID: 1}}, int64(1), nil)
+
+	service := Service{
+		logger:    slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo: mockRepo,
+	}
+
+	_, _, err := service.QueryEvents(1, types.EventFilter{Sort: "is_in_office"})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, _, err = service.QueryEvents(1, types.EventFilter{Type: "sabbatical"})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	// Search text is trimmed before it reaches the repository
+	events, total, err := service.QueryEvents(1, types.EventFilter{Search: "  dentist ", Sort: "-description"})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(1), total)
+	mockRepo.AssertExpectations(t)
+}
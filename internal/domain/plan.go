@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// targetDays parses the target from preferences, falling back to 2.5
+func targetDays(prefs types.Preferences) float64 {
+	targetDays, err := strconv.ParseFloat(prefs.TargetDays, 64)
+	if err != nil {
+		return 2.5
+	}
+	return targetDays
+}
+
+// CalculatePlan works out how many more in-office days are needed between
+// start and end to meet the target, using the same days-per-week measure as
+// CalculateStatsBetween.
+func (s *Service) CalculatePlan(userID int, start, end time.Time) (*types.Plan, error) {
+	return s.calculatePlan(userID, start, end, time.Now())
+}
+
+func (s *Service) calculatePlan(userID int, start, end, now time.Time) (*types.Plan, error) {
+	start, end = utils.NormalizeDate(start), utils.NormalizeDate(end)
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+
+	events, err := s.eventRepo.GetAllEvents(userID)
+	if err != nil {
+		s.logger.Error("Error fetching events for plan", "error", err)
+		return nil, err
+	}
+
+	today := utils.NormalizeDate(now)
+	prefs := s.preferencesBetween(userID, start, end, now)
+	plan := &types.Plan{
+		From:       start,
+		To:         end,
+		TargetDays: targetDays(prefs),
+		TotalDays:  int(end.Sub(start).Hours()/24) + 1,
+		Method:     calculationMethod(prefs),
+	}
+	if plan.Method == types.MethodWorkdays {
+		plan.TotalDays = workdays(events, start, end)
+	}
+	// Small epsilon so 2.5 days/week over exactly 14 days needs 5, not 6
+	plan.RequiredDays = int(math.Ceil(plan.TargetDays*float64(plan.TotalDays)/types.DaysPerWeek(plan.Method) - 1e-9))
+
+	// Days that can't take another in-office day
+	blocked := make(map[string]bool)
+	for _, event := range events {
+		date := utils.NormalizeDate(event.Date)
+		if date.Before(start) || date.After(end) {
+			continue
+		}
+		key := date.Format("2006-01-02")
+		switch {
+		case event.Type == "attendance" && event.IsInOffice:
+			blocked[key] = true
+			if date.After(today) {
+				plan.PlannedDays++
+			} else {
+				plan.LoggedDays++
+			}
+		case event.Type == "holiday" || event.Type == "vacation":
+			blocked[key] = true
+		}
+	}
+
+	first := today.AddDate(0, 0, 1)
+	if first.Before(start) {
+		first = start
+	}
+	for d := first; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !utils.IsWeekend(d) && !blocked[d.Format("2006-01-02")] {
+			plan.OpenDays++
+		}
+	}
+	if !first.After(end) {
+		plan.WeeksLeft = (end.Sub(first).Hours()/24 + 1) / 7
+	}
+
+	plan.RemainingDays = plan.RequiredDays - plan.LoggedDays - plan.PlannedDays
+	if plan.RemainingDays < 0 {
+		plan.RemainingDays = 0
+	}
+	if plan.WeeksLeft > 0 {
+		plan.PerWeek = float64(plan.RemainingDays) / plan.WeeksLeft
+	}
+	plan.Achievable = plan.RemainingDays <= plan.OpenDays
+
+	return plan, nil
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// targetPrefs returns a preference repository holding the user's target
+func targetPrefs(userID int, target string) *mocks.PreferenceRepository {
+	repo := new(mocks.PreferenceRepository)
+	repo.On("GetPreferences", userID).Return(types.Preferences{UserID: uint(userID), TargetDays: target}, nil)
+	return repo
+}
+
+func TestCalculatePlan_CountsLoggedPlannedAndOpenDays(t *testing.T) {
+	// Two weeks, Mon 2025-01-06 to Sun 2025-01-19, looked at on Sun 2025-01-12
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 12, 15, 0, 0, 0, time.UTC)
+
+	mockRepo := new(mocks.EventRepository)
+	mockRepo.On("GetAllEvents", 1).Return([]types.Event{
+		{Date: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: true},
+		{Date: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: true},
+		{Date: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: false},
+		{Date: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: true},
+		{Date: time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), Type: "vacation", Description: "Day off"},
+		{Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Type: "attendance", IsInOffice: true}, // outside
+	}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockRepo,
+		preferenceRepo: targetPrefs(1, "2.5"),
+	}
+
+	plan, err := service.calculatePlan(1, start, end, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 14, plan.TotalDays)
+	assert.Equal(t, 5, plan.RequiredDays) // 2.5 * 14 / 7
+	assert.Equal(t, 2, plan.LoggedDays)
+	assert.Equal(t, 1, plan.PlannedDays)
+	assert.Equal(t, 2, plan.RemainingDays)
+	assert.Equal(t, 3, plan.OpenDays) // 15th, 16th, 17th
+	assert.Equal(t, 1.0, plan.WeeksLeft)
+	assert.Equal(t, 2.0, plan.PerWeek)
+	assert.True(t, plan.Achievable)
+}
+
+func TestCalculatePlan_NotAchievable(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := new(mocks.EventRepository)
+	mockRepo.On("GetAllEvents", 1).Return([]types.Event{}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockRepo,
+		preferenceRepo: targetPrefs(1, "3"),
+	}
+
+	plan, err := service.calculatePlan(1, start, end, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, plan.RemainingDays)
+	assert.Equal(t, 1, plan.OpenDays) // only Friday is left
+	assert.False(t, plan.Achievable)
+}
+
+func TestCalculatePlan_EndBeforeStart(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, err := service.CalculatePlan(1,
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	)
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestSetAttendance_CreatesEvent(t *testing.T) {
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := new(mocks.EventRepository)
+	mockRepo.On("GetEventByDateAndType", 1, date, "attendance").Return(types.Event{}, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("AddEvent", types.Event{UserID: 1, Date: date, Type: "attendance", IsInOffice: true, OfficeID: 2}).Return(nil)
+	mockRepo.On("GetEventByDateAndType", 1, date, "attendance").Return(types.Event{
+		ID: 4, UserID: 1, Date: date, Type: "attendance", IsInOffice: true, OfficeID: 2,
+	}, nil).Once()
+
+	// The day is spent at the default office
+	mockPrefRepo := new(mocks.PreferenceRepository)
+	mockPrefRepo.On("GetPreferences", 1).Return(types.Preferences{UserID: 1, DefaultOfficeID: 2}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockRepo,
+		preferenceRepo: mockPrefRepo,
+		periodRepo:     openPeriods(),
+	}
+
+	event, err := service.SetAttendance(1, date, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), event.ID)
+	assert.True(t, event.IsInOffice)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetAttendance_SameStatusIsNoop(t *testing.T) {
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := new(mocks.EventRepository)
+	mockRepo.On("GetEventByDateAndType", 1, date, "attendance").Return(types.Event{
+		ID: 4, UserID: 1, Date: date, Type: "attendance", IsInOffice: false,
+	}, nil)
+
+	service := Service{
+		logger:     slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:  mockRepo,
+		periodRepo: openPeriods(),
+	}
+
+	event, err := service.SetAttendance(1, date, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), event.ID)
+	mockRepo.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+	mockRepo.AssertNotCalled(t, "AddEvent", mock.Anything)
+}
+
+func TestAddVacation_Validation(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+	saturday := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.AddVacation(1, saturday, saturday, "")
+	assert.True(t, errors.Is(err, ErrInvalidInput), "missing description")
+
+	_, err = service.AddVacation(1, saturday, saturday.AddDate(0, 0, -3), "Trip")
+	assert.True(t, errors.Is(err, ErrInvalidInput), "end before start")
+
+	_, err = service.AddVacation(1, saturday, saturday.AddDate(0, 0, 1), "Trip")
+	assert.True(t, errors.Is(err, ErrInvalidInput), "weekend only")
+
+	_, err = service.AddVacation(1, saturday, saturday.AddDate(2, 0, 0), "Trip")
+	assert.True(t, errors.Is(err, ErrInvalidInput), "range too long")
+}
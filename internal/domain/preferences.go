@@ -2,72 +2,235 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+	"gorm.io/gorm"
 )
 
-func (s *Service) UpdatePreferences(defaultDays string, targetDays string) error {
-	// Fetch current preferences from the database
-	prefs, err := s.preferenceRepo.GetPreferences()
+// defaultPreferences are what a user starts with: in office Monday to Thursday
+func defaultPreferences(userID int) types.Preferences {
+	return types.Preferences{
+		UserID:      uint(userID),
+		DefaultDays: "M,T,W,Th",
+		TargetDays:  "2.5",
+	}
+}
+
+// preferencesFor loads the user's preferences, saving the defaults the first
+// time the user needs them
+func (s *Service) preferencesFor(userID int) (types.Preferences, error) {
+	prefs, err := s.preferenceRepo.GetPreferences(userID)
+	if err == nil {
+		return prefs, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error loading preferences", "userID", userID, "error", err)
+		return types.Preferences{}, err
+	}
+
+	if err := s.preferenceRepo.UpdatePreferences(defaultPreferences(userID)); err != nil {
+		s.logger.Error("Error saving default preferences", "userID", userID, "error", err)
+		return types.Preferences{}, err
+	}
+	s.logger.Info("Default preferences set", "userID", userID)
+
+	// Read back so the row's ID is known to later updates
+	return s.preferenceRepo.GetPreferences(userID)
+}
+
+// UpdatePreferences changes the default days and target from today, keeping
+// the calculation method
+func (s *Service) UpdatePreferences(userID int, defaultDays string, targetDays string) error {
+	return s.SetPreferences(userID, types.PreferenceVersion{DefaultDays: defaultDays, TargetDays: targetDays})
+}
+
+// SetPreferences records the default days, target and calculation method in
+// force from version.EffectiveFrom, or from today when it is zero. Stats are
+// measured with the version in force at the time, so a new target does not
+// re-judge earlier periods. An empty method keeps the one in force.
+func (s *Service) SetPreferences(userID int, version types.PreferenceVersion) error {
+	return s.setPreferences(userID, version, time.Now())
+}
+
+func (s *Service) setPreferences(userID int, version types.PreferenceVersion, now time.Time) error {
+	if strings.TrimSpace(version.DefaultDays) == "" {
+		return fmt.Errorf("%w: default days are required", ErrInvalidInput)
+	}
+	if target, err := strconv.ParseFloat(version.TargetDays, 64); err != nil || target <= 0 || target > 7 {
+		return fmt.Errorf("%w: target days must be greater than 0 and at most 7", ErrInvalidInput)
+	}
+	switch version.CalculationMethod {
+	case "", types.MethodCalendar, types.MethodWorkdays:
+	default:
+		return fmt.Errorf("%w: unknown calculation method %q", ErrInvalidInput, version.CalculationMethod)
+	}
+
+	prefs, err := s.preferencesFor(userID)
 	if err != nil {
-		s.logger.Error("Error fetching preferences", "error", err)
 		return err
 	}
 
-	// Update preferences fields
-	prefs.DefaultDays = defaultDays
-	prefs.TargetDays = targetDays
+	version.ID = 0
+	version.UserID = uint(userID)
+	if version.EffectiveFrom.IsZero() {
+		version.EffectiveFrom = now
+	}
+	version.EffectiveFrom = utils.NormalizeDate(version.EffectiveFrom)
+	if version.CalculationMethod == "" {
+		version.CalculationMethod = calculationMethod(prefs.At(version.EffectiveFrom))
+	}
 
-	// Save updated preferences back to the database
-	if err := s.preferenceRepo.UpdatePreferences(prefs); err != nil {
-		s.logger.Error("Error updating preferences in repository", "error", err)
-		return err
+	// The first change keeps what was there before as the version from the start
+	if len(prefs.Versions) == 0 {
+		first := types.PreferenceVersion{
+			UserID:            uint(userID),
+			DefaultDays:       prefs.DefaultDays,
+			TargetDays:        prefs.TargetDays,
+			CalculationMethod: calculationMethod(prefs),
+		}
+		if err := s.preferenceRepo.SavePreferenceVersion(first); err != nil {
+			s.logger.Error("Error saving first preference version", "userID", userID, "error", err)
+			return err
+		}
+		prefs.Versions = append(prefs.Versions, first)
 	}
 
-	// Update the service's local copy if necessary
-	s.preferences = prefs
+	if err := s.preferenceRepo.SavePreferenceVersion(version); err != nil {
+		s.logger.Error("Error saving preference version", "userID", userID, "error", err)
+		return err
+	}
+	versions := []types.PreferenceVersion{version}
+	for _, v := range prefs.Versions {
+		if !v.EffectiveFrom.Equal(version.EffectiveFrom) {
+			versions = append(versions, v)
+		}
+	}
+	prefs.Versions = versions
+	s.logger.Info("Preferences changed", "userID", userID, "from", version.EffectiveFrom.Format("2006-01-02"))
+	return s.refreshPreferences(prefs, now)
+}
 
-	return nil
+// GetPreferenceHistory returns the user's preference versions, oldest first.
+// A user who never changed their preferences has just the one from the start.
+func (s *Service) GetPreferenceHistory(userID int) ([]types.PreferenceVersion, error) {
+	prefs, err := s.preferencesFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefs.Versions) == 0 {
+		return []types.PreferenceVersion{{
+			UserID:            uint(userID),
+			DefaultDays:       prefs.DefaultDays,
+			TargetDays:        prefs.TargetDays,
+			CalculationMethod: calculationMethod(prefs),
+		}}, nil
+	}
+	return prefs.Versions, nil
 }
 
-func (s *Service) SavePreferences(filePath string) error {
+// DeletePreferenceVersion removes a version entered by mistake; the days it
+// covered fall back to the version before it. The version from the start
+// stays.
+func (s *Service) DeletePreferenceVersion(userID, versionID int) error {
+	return s.deletePreferenceVersion(userID, versionID, time.Now())
+}
 
-	data, err := json.MarshalIndent(s.preferences, "", "    ")
+func (s *Service) deletePreferenceVersion(userID, versionID int, now time.Time) error {
+	prefs, err := s.preferencesFor(userID)
 	if err != nil {
 		return err
 	}
+	var versions []types.PreferenceVersion
+	found := false
+	for _, version := range prefs.Versions {
+		if version.ID != uint(versionID) {
+			versions = append(versions, version)
+			continue
+		}
+		if version.EffectiveFrom.IsZero() {
+			return fmt.Errorf("%w: the preferences from the start cannot be removed", ErrConflict)
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("%w: preference version %d", ErrNotFound, versionID)
+	}
 
-	err = ioutil.WriteFile(filePath, data, 0644)
+	err = s.preferenceRepo.DeletePreferenceVersion(userID, versionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: preference version %d", ErrNotFound, versionID)
+	}
 	if err != nil {
+		s.logger.Error("Error deleting preference version", "userID", userID, "versionID", versionID, "error", err)
 		return err
 	}
+	prefs.Versions = versions
+	return s.refreshPreferences(prefs, now)
+}
 
+// refreshPreferences saves the preferences with the default days, target and
+// method of the version in force today
+func (s *Service) refreshPreferences(prefs types.Preferences, now time.Time) error {
+	prefs = prefs.At(now)
+	prefs.CalculationMethod = calculationMethod(prefs)
+	prefs.Versions = nil
+	if err := s.preferenceRepo.UpdatePreferences(prefs); err != nil {
+		s.logger.Error("Error updating preferences in repository", "error", err)
+		return err
+	}
 	return nil
 }
 
-// InitializePreferences initializes preferences by loading the JSON file
-func initializePreferences(s *Service) types.Preferences {
-	prefs, err := s.preferenceRepo.GetPreferences()
+// preferencesAt returns the user's preferences with the default days, target
+// and method in force on the date
+func (s *Service) preferencesAt(userID int, date time.Time) types.Preferences {
+	prefs, err := s.preferencesFor(userID)
 	if err != nil {
-		s.logger.Error("Error loading preferences from database", "error", err)
+		s.logger.Error("Error getting preferences", "error", err)
+		return defaultPreferences(userID)
+	}
+	return prefs.At(date)
+}
 
-		// Set default preferences if loading fails or no preferences exist
-		defaultPrefs := types.Preferences{
-			DefaultDays: "M,T,W,Th,F", // Adjusted to include Monday by default
-			TargetDays:  "2.5",
-		}
+// preferencesBetween returns the preferences a date range is measured with:
+// those in force on its last day, or today while the range is still running
+func (s *Service) preferencesBetween(userID int, start, end, now time.Time) types.Preferences {
+	at := utils.NormalizeDate(now)
+	if end.Before(at) {
+		at = end
+	}
+	if at.Before(start) {
+		at = start
+	}
+	return s.preferencesAt(userID, at)
+}
 
-		if err := s.preferenceRepo.UpdatePreferences(defaultPrefs); err != nil {
-			s.logger.Error("Error setting default preferences in database", "error", err)
-			// Handle as needed, possibly panic or return
-		} else {
-			s.logger.Info("Default preferences set in database.")
-		}
+// calculationMethod is the preferences' method, MethodCalendar when unset
+func calculationMethod(prefs types.Preferences) string {
+	if prefs.CalculationMethod == "" {
+		return types.MethodCalendar
+	}
+	return prefs.CalculationMethod
+}
+
+func (s *Service) SavePreferences(userID int, filePath string) error {
 
-		return defaultPrefs
+	data, err := json.MarshalIndent(s.GetPrefs(userID), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filePath, data, 0644)
+	if err != nil {
+		return err
 	}
 
-	s.logger.Info("Preferences loaded successfully from database.")
-	return prefs
+	return nil
 }
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ymd(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// versionedPrefs has a 2.5 target until July 2025 and 3 from then on, when
+// the method also switched to workdays
+func versionedPrefs() types.Preferences {
+	return types.Preferences{
+		ID: 1, UserID: 1, DefaultDays: "T,W,Th", TargetDays: "3", CalculationMethod: types.MethodWorkdays,
+		Versions: []types.PreferenceVersion{
+			{ID: 1, UserID: 1, DefaultDays: "M,T", TargetDays: "2.5", CalculationMethod: types.MethodCalendar},
+			{ID: 2, UserID: 1, EffectiveFrom: ymd(2025, time.July, 1), DefaultDays: "T,W,Th", TargetDays: "3", CalculationMethod: types.MethodWorkdays},
+		},
+	}
+}
+
+func TestPreferencesAt(t *testing.T) {
+	prefs := versionedPrefs()
+
+	assert.Equal(t, "2.5", prefs.At(ymd(2025, time.June, 30)).TargetDays)
+	assert.Equal(t, "M,T", prefs.At(ymd(2025, time.June, 30)).DefaultDays)
+	assert.Equal(t, "3", prefs.At(ymd(2025, time.July, 1)).TargetDays)
+	assert.Equal(t, types.MethodWorkdays, prefs.At(ymd(2026, time.January, 1)).CalculationMethod)
+
+	// Without versions the saved values apply throughout
+	plain := types.Preferences{TargetDays: "2"}
+	assert.Equal(t, "2", plain.At(ymd(2020, time.January, 1)).TargetDays)
+}
+
+func TestAttendanceStats_UsesPreferencesInForce(t *testing.T) {
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(versionedPrefs(), nil)
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	// Two weeks in each half of 2025, in on four days, one day of vacation
+	events := func(monday time.Time) []types.Event {
+		return []types.Event{
+			{UserID: 1, Date: monday, Type: "attendance", IsInOffice: true},
+			{UserID: 1, Date: monday.AddDate(0, 0, 1), Type: "attendance", IsInOffice: true},
+			{UserID: 1, Date: monday.AddDate(0, 0, 7), Type: "attendance", IsInOffice: true},
+			{UserID: 1, Date: monday.AddDate(0, 0, 8), Type: "attendance", IsInOffice: true},
+			{UserID: 1, Date: monday.AddDate(0, 0, 9), Type: "vacation"},
+		}
+	}
+
+	// Before the change: 4 in over 14 calendar days against 2.5
+	first := ymd(2025, time.March, 3)
+	stats := service.attendanceStats(1, events(first), first, first.AddDate(0, 0, 13))
+	assert.Equal(t, types.MethodCalendar, stats.Method)
+	assert.Equal(t, 2.5, stats.TargetDays)
+	assert.Equal(t, 14, stats.TotalDays)
+	assert.InDelta(t, 2.0, stats.AverageDays, 0.001)
+
+	// After it: 4 in over 9 working days against 3
+	second := ymd(2025, time.September, 1)
+	stats = service.attendanceStats(1, events(second), second, second.AddDate(0, 0, 13))
+	assert.Equal(t, types.MethodWorkdays, stats.Method)
+	assert.Equal(t, 3.0, stats.TargetDays)
+	assert.Equal(t, 9, stats.TotalDays)
+	assert.InDelta(t, 4.0/9*5, stats.AverageDays, 0.001)
+}
+
+func TestSetPreferences_FromDate(t *testing.T) {
+	now := ymd(2025, time.August, 15)
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(versionedPrefs(), nil)
+	mockPrefsRepo.On("SavePreferenceVersion", types.PreferenceVersion{
+		UserID: 1, EffectiveFrom: ymd(2025, time.October, 1), DefaultDays: "M,T,W", TargetDays: "3.5",
+		CalculationMethod: types.MethodWorkdays,
+	}).Return(nil)
+	// A change from later on leaves today's values as they are
+	mockPrefsRepo.On("UpdatePreferences", mock.MatchedBy(func(p types.Preferences) bool {
+		return p.TargetDays == "3" && p.DefaultDays == "T,W,Th" && p.Versions == nil
+	})).Return(nil)
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	err := service.setPreferences(1, types.PreferenceVersion{
+		EffectiveFrom: time.Date(2025, time.October, 1, 9, 30, 0, 0, time.UTC),
+		DefaultDays:   "M,T,W",
+		TargetDays:    "3.5",
+	}, now)
+
+	assert.NoError(t, err)
+	mockPrefsRepo.AssertExpectations(t)
+}
+
+func TestSetPreferences_Invalid(t *testing.T) {
+	service := Service{logger: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	err := service.SetPreferences(1, types.PreferenceVersion{DefaultDays: "M", TargetDays: "8"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	err = service.SetPreferences(1, types.PreferenceVersion{DefaultDays: "M", TargetDays: "2", CalculationMethod: "hours"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestDeletePreferenceVersion(t *testing.T) {
+	now := ymd(2025, time.August, 15)
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(versionedPrefs(), nil)
+	mockPrefsRepo.On("DeletePreferenceVersion", 1, 2).Return(nil)
+	// Today falls back to the version from the start
+	mockPrefsRepo.On("UpdatePreferences", mock.MatchedBy(func(p types.Preferences) bool {
+		return p.TargetDays == "2.5" && p.CalculationMethod == types.MethodCalendar
+	})).Return(nil)
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	assert.ErrorIs(t, service.deletePreferenceVersion(1, 1, now), ErrConflict)
+	assert.ErrorIs(t, service.deletePreferenceVersion(1, 7, now), ErrNotFound)
+	assert.NoError(t, service.deletePreferenceVersion(1, 2, now))
+	mockPrefsRepo.AssertExpectations(t)
+}
+
+func TestFillDefaultDays_UsesPatternInForce(t *testing.T) {
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 1).Return(versionedPrefs(), nil)
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 1).Return([]types.Event{}, nil)
+	mockEventRepo.On("AddEvent", mock.Anything).Return(nil)
+	mockEventRepo.On("GetEventByDateAndType", 1, mock.Anything, "attendance").Return(types.Event{}, nil)
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		eventRepo:      mockEventRepo,
+		preferenceRepo: mockPrefsRepo,
+		periodRepo:     openPeriods(),
+	}
+
+	// Monday June 30 is under M,T; Wednesday July 2 under T,W,Th
+	added, err := service.FillDefaultDays(1, ymd(2025, time.June, 30), ymd(2025, time.July, 2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, added)
+	mockEventRepo.AssertCalled(t, "AddEvent", types.Event{UserID: 1, Date: ymd(2025, time.June, 30), Type: "attendance", IsInOffice: true})
+	mockEventRepo.AssertCalled(t, "AddEvent", types.Event{UserID: 1, Date: ymd(2025, time.July, 1), Type: "attendance", IsInOffice: true})
+	mockEventRepo.AssertCalled(t, "AddEvent", types.Event{UserID: 1, Date: ymd(2025, time.July, 2), Type: "attendance", IsInOffice: true})
+}
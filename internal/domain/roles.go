@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// SetUserRole changes a user's role. The last admin cannot be demoted, so
+// there is always someone who can manage accounts.
+func (s *Service) SetUserRole(userID int, role string) error {
+	if !types.ValidRole(role) {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	last, err := s.isLastAdmin(user)
+	if err != nil {
+		return err
+	}
+	if last {
+		return fmt.Errorf("%w: %q is the only admin", ErrConflict, user.Username)
+	}
+
+	user.Role = role
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error updating role", "userID", user.ID, "error", err)
+		return err
+	}
+	s.logger.Info("Role changed", "userID", user.ID, "role", role)
+	return nil
+}
+
+// isLastAdmin reports whether the user is an admin and no one else is
+func (s *Service) isLastAdmin(user types.User) (bool, error) {
+	if !user.IsAdmin() {
+		return false, nil
+	}
+	users, err := s.GetUsers()
+	if err != nil {
+		return false, err
+	}
+	admins := 0
+	for _, u := range users {
+		if u.IsAdmin() {
+			admins++
+		}
+	}
+	return admins <= 1, nil
+}
+
+// SetUserManager sets who a user reports to; managerID 0 clears it. The
+// manager's role must allow viewing reports, and a user cannot end up
+// managing themselves through a chain of managers.
+func (s *Service) SetUserManager(userID, managerID int) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if managerID == 0 {
+		user.ManagerID = nil
+	} else {
+		manager, err := s.getUser(managerID)
+		if err != nil {
+			return err
+		}
+		if !manager.Can(types.PermViewReports) {
+			return fmt.Errorf("%w: %q is not a manager", ErrInvalidInput, manager.Username)
+		}
+		for next := manager; ; {
+			if next.ID == user.ID {
+				return fmt.Errorf("%w: %q already manages %q", ErrInvalidInput, user.Username, manager.Username)
+			}
+			if next.ManagerID == nil {
+				break
+			}
+			if next, err = s.getUser(int(*next.ManagerID)); err != nil {
+				return err
+			}
+		}
+		id := manager.ID
+		user.ManagerID = &id
+	}
+
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error updating manager", "userID", user.ID, "error", err)
+		return err
+	}
+	s.logger.Info("Manager changed", "userID", user.ID, "managerID", managerID)
+	return nil
+}
+
+// GetReports lists the users who report directly to the manager
+func (s *Service) GetReports(managerID int) ([]types.User, error) {
+	users, err := s.userRepo.GetUsersByManager(managerID)
+	if err != nil {
+		s.logger.Error("Error fetching reports", "managerID", managerID, "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetReport returns a user whose calendar the viewer may read: one of their
+// direct reports, or anyone for an admin. Others are ErrNotFound so callers
+// cannot probe which accounts exist.
+func (s *Service) GetReport(viewerID, userID int) (*types.User, error) {
+	viewer, err := s.getUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case viewer.IsAdmin():
+	case viewer.Can(types.PermViewReports) && user.ReportsTo(viewer.ID):
+	default:
+		return nil, fmt.Errorf("%w: user %d", ErrNotFound, userID)
+	}
+	return &user, nil
+}
+
+// ApproveVacation signs off one of a report's vacation days. Managers approve
+// their direct reports and admins anyone; nobody approves their own. Others
+// get ErrNotFound, as with GetReport. Approving twice keeps the first sign-off.
+func (s *Service) ApproveVacation(approverID, userID, eventID int) (types.Event, error) {
+	return s.approveVacation(approverID, userID, eventID, time.Now())
+}
+
+func (s *Service) approveVacation(approverID, userID, eventID int, now time.Time) (types.Event, error) {
+	approver, err := s.getUser(approverID)
+	if err != nil {
+		return types.Event{}, err
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		return types.Event{}, err
+	}
+
+	allowed := approver.Can(types.PermApprove) && approver.ID != user.ID &&
+		(approver.IsAdmin() || user.ReportsTo(approver.ID))
+	if !allowed {
+		return types.Event{}, fmt.Errorf("%w: user %d", ErrNotFound, userID)
+	}
+
+	event, err := s.eventRepo.GetEventByID(userID, eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return types.Event{}, fmt.Errorf("%w: event %d", ErrNotFound, eventID)
+		}
+		s.logger.Error("Error fetching event to approve", "eventID", eventID, "error", err)
+		return types.Event{}, err
+	}
+	// Shared holidays are visible to everyone but belong to no one
+	if event.UserID != user.ID {
+		return types.Event{}, fmt.Errorf("%w: event %d", ErrNotFound, eventID)
+	}
+	if event.Type != "vacation" {
+		return types.Event{}, fmt.Errorf("%w: only vacation days need approval", ErrInvalidInput)
+	}
+	if event.ApprovedBy != nil {
+		return event, nil
+	}
+
+	event.ApprovedBy = &approver.ID
+	event.ApprovedAt = &now
+	if err := s.eventRepo.UpdateEvent(event); err != nil {
+		s.logger.Error("Error approving vacation", "eventID", eventID, "error", err)
+		return types.Event{}, err
+	}
+	s.logger.Info("Vacation approved", "eventID", eventID, "userID", userID, "approverID", approverID)
+	return event, nil
+}
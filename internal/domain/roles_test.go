@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestSetUserRole_KeepsLastAdmin(t *testing.T) {
+	alice := types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(alice, nil)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{alice, {ID: 2, Username: "bob", Role: types.RoleManager}}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	err := service.SetUserRole(1, types.RoleManager)
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	err = service.SetUserRole(1, "owner")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+	mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+}
+
+func TestSetUserRole_DemotesOneOfTwoAdmins(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "bob", Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{
+		{ID: 1, Username: "alice", Role: types.RoleAdmin},
+		{ID: 2, Username: "bob", Role: types.RoleAdmin},
+	}, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.ID == 2 && u.Role == types.RoleEmployee
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	assert.NoError(t, service.SetUserRole(2, types.RoleEmployee))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSetUserManager(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "mia", Role: types.RoleManager, ManagerID: uintPtr(1)}, nil)
+	mockUserRepo.On("GetUserByID", 3).Return(types.User{ID: 3, Username: "carol", Role: types.RoleEmployee}, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.ID == 3 && u.ReportsTo(2)
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	assert.NoError(t, service.SetUserManager(3, 2))
+
+	// Employees cannot have reports
+	err := service.SetUserManager(2, 3)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	// alice manages mia, so mia cannot manage alice
+	err = service.SetUserManager(1, 2)
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	mockUserRepo.AssertNumberOfCalls(t, "UpdateUser", 1)
+}
+
+func TestGetReport(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "mia", Role: types.RoleManager}, nil)
+	mockUserRepo.On("GetUserByID", 3).Return(types.User{ID: 3, Username: "carol", Role: types.RoleEmployee, ManagerID: uintPtr(2)}, nil)
+	mockUserRepo.On("GetUserByID", 4).Return(types.User{ID: 4, Username: "dave", Role: types.RoleEmployee}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	tests := []struct {
+		viewer, user int
+		allowed      bool
+	}{
+		{2, 3, true},  // mia manages carol
+		{2, 4, false}, // but not dave
+		{1, 4, true},  // admins see everyone
+		{3, 4, false}, // employees see no one else
+		{4, 3, false},
+	}
+	for _, tt := range tests {
+		user, err := service.GetReport(tt.viewer, tt.user)
+		if tt.allowed {
+			if assert.NoError(t, err) {
+				assert.Equal(t, uint(tt.user), user.ID)
+			}
+		} else {
+			assert.True(t, errors.Is(err, ErrNotFound), "viewer %d user %d", tt.viewer, tt.user)
+		}
+	}
+}
+
+func TestApproveVacation(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetUserByID", 2).Return(types.User{ID: 2, Username: "mia", Role: types.RoleManager}, nil)
+	mockUserRepo.On("GetUserByID", 3).Return(types.User{ID: 3, Username: "carol", Role: types.RoleEmployee, ManagerID: uintPtr(2)}, nil)
+	mockUserRepo.On("GetUserByID", 4).Return(types.User{ID: 4, Username: "dave", Role: types.RoleEmployee, ManagerID: uintPtr(3)}, nil)
+	mockUserRepo.On("GetUserByID", 5).Return(types.User{ID: 5, Username: "erin", Role: types.RoleManager, ManagerID: uintPtr(2)}, nil)
+
+	vacation := types.Event{ID: 7, UserID: 3, Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Type: "vacation"}
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetEventByID", 3, 7).Return(vacation, nil)
+	mockEventRepo.On("GetEventByID", 3, 8).Return(types.Event{ID: 8, UserID: 3, Type: "attendance", IsInOffice: true}, nil)
+	mockEventRepo.On("GetEventByID", 3, 9).Return(types.Event{ID: 9, Type: "holiday"}, nil)
+	mockEventRepo.On("GetEventByID", 4, 7).Return(types.Event{}, gorm.ErrRecordNotFound)
+	mockEventRepo.On("UpdateEvent", mock.Anything).Return(nil)
+
+	service := Service{
+		logger:    slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:  mockUserRepo,
+		eventRepo: mockEventRepo,
+	}
+
+	// Employees, other teams' managers and the requester themself are turned away
+	for _, approver := range []int{3, 4, 5} {
+		_, err := service.approveVacation(approver, 3, 7, now)
+		assert.ErrorIs(t, err, ErrNotFound, "approver %d", approver)
+	}
+	_, err := service.approveVacation(2, 2, 7, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockEventRepo.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+
+	// Only vacation days of the user's own are approved
+	_, err = service.approveVacation(2, 3, 8, now)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = service.approveVacation(2, 3, 9, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	event, err := service.approveVacation(2, 3, 7, now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint(2), *event.ApprovedBy)
+		assert.Equal(t, now, *event.ApprovedAt)
+	}
+	// Admins approve anyone's, provided the day is theirs
+	_, err = service.approveVacation(1, 4, 7, now)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = service.approveVacation(1, 3, 7, now)
+	assert.NoError(t, err)
+	mockEventRepo.AssertNumberOfCalls(t, "UpdateEvent", 2)
+}
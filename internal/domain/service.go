@@ -3,6 +3,7 @@ package domain
 
 import (
 	"log/slog"
+	"sync"
 	"time"
 
 	repository "github.com/robstave/rto/internal/adapters/repositories"
@@ -11,50 +12,189 @@ import (
 
 // Interface for the RTO Business logic
 type RTOBLL interface {
-	GetAllEvents() []types.Event
-	GetPrefs() types.Preferences
-	ToggleAttendance(eventDate time.Time) (string, error)
-	AddEvent(event types.Event) error
-	CalculateAttendanceStats() (*types.AttendanceStats, error)
-	UpdatePreferences(defaultDays string, targetDays string) error
-	AddDefaultDays() error
-	DeleteEvent(eventID int) error
-	GetEventByID(eventID int) (types.Event, error)
-	TransformVacationToRemote(eventID int) error
-	GetEventByDateAndType(date time.Time, eventType string) (*types.Event, error)
-	GetEventsByDate(date time.Time) ([]types.Event, error)
-	ClearEventsForDate(date time.Time) error
-
-	UpdateEvent(event types.Event) error
-	BulkAddEvents(events []types.Event) (*types.BulkAddResponse, error)
+	GetAllEvents(userID int) []types.Event
+	GetPrefs(userID int) types.Preferences
+	ToggleAttendance(userID int, eventDate time.Time) (string, error)
+	AddEvent(userID int, event types.Event) error
+	CalculateAttendanceStats(userID int) (*types.AttendanceStats, error)
+	UpdatePreferences(userID int, defaultDays string, targetDays string) error
+	SetPreferences(userID int, version types.PreferenceVersion) error
+	GetPreferenceHistory(userID int) ([]types.PreferenceVersion, error)
+	DeletePreferenceVersion(userID, versionID int) error
+	AddDefaultDays(userID int) error
+	FillDefaultDays(userID int, startDate, endDate time.Time) (int, error)
+	CheckData() ([]types.DataIssue, error)
+	DeleteEvent(userID int, eventID int) error
+	GetEventByID(userID int, eventID int) (types.Event, error)
+	TransformVacationToRemote(userID int, eventID int) error
+	GetEventByDateAndType(userID int, date time.Time, eventType string) (*types.Event, error)
+	GetEventsByDate(userID int, date time.Time) ([]types.Event, error)
+	ClearEventsForDate(userID int, date time.Time) error
+
+	UpdateEvent(userID int, event types.Event) error
+	BulkAddEvents(userID int, events []types.Event) (*types.BulkAddResponse, error)
+
+	QueryEvents(userID int, filter types.EventFilter) ([]types.Event, int64, error)
+	CreateEvent(userID int, event types.Event) (*types.Event, error)
+	CalculateStatsBetween(userID int, start, end time.Time) (*types.AttendanceStats, error)
+	CalculatePlan(userID int, start, end time.Time) (*types.Plan, error)
+	SetAttendance(userID int, date time.Time, inOffice bool) (*types.Event, error)
+	AddVacation(userID int, start, end time.Time, description string) (*types.BulkAddResponse, error)
+
+	GetPeriods() ([]types.Period, error)
+	GetPeriod(periodID int) (*types.Period, error)
+	GetCurrentPeriod() (*types.Period, error)
+	CreatePeriod(period types.Period) (*types.Period, error)
+	DeletePeriod(periodID int) error
+	ClosePeriod(periodID int) (*types.Period, error)
+	ReopenPeriod(periodID int) (*types.Period, error)
+	CloseEndedPeriods() ([]types.Period, error)
+	GetPeriodArchive(userID, periodID int) (*types.PeriodArchive, error)
+	PeriodStats(userID, periodID int) (*types.AttendanceStats, error)
+
+	CreateAPIToken(userID int, name string, scopes []string, expiresAt *time.Time) (string, *types.APIToken, error)
+	GetAPITokens(userID int) ([]types.APIToken, error)
+	RevokeAPIToken(userID int, tokenID int) error
+	AuthenticateAPIToken(plaintext string) (*types.APIToken, error)
+
+	Subscribe(listener types.ChangeListener)
+
+	CreateWebhook(userID int, url string, events []string) (*types.Webhook, error)
+	GetWebhooks(userID int) ([]types.Webhook, error)
+	GetWebhook(userID int, webhookID int) (*types.Webhook, error)
+	SetWebhookActive(userID int, webhookID int, active bool) error
+	DeleteWebhook(userID int, webhookID int) error
+	GetWebhookDeliveries(userID int, webhookID int, limit int) ([]types.WebhookDelivery, error)
+
+	Register(username, password string) (*types.User, error)
+	CreateUser(username, password, role string) (*types.User, error)
+	Authenticate(username, password string) (*types.User, error)
+	SignInExternal(identity types.ExternalIdentity) (*types.User, error)
+	SetUserEmail(username, email string) error
+	ChangePassword(userID int, current, password string) error
+	ResetPassword(username, password string) error
+	GetUser(userID int) (*types.User, error)
+	GetUsers() ([]types.User, error)
+	ResolveUser(username string) (int, error)
+	SetUserRole(userID int, role string) error
+	SetUserManager(userID, managerID int) error
+	GetReports(managerID int) ([]types.User, error)
+	GetReport(viewerID, userID int) (*types.User, error)
+	ApproveVacation(approverID, userID, eventID int) (types.Event, error)
+	GetTeam(viewerID int) ([]types.User, error)
+	TeamDashboard(viewerID int, month time.Time) (*types.TeamDashboard, error)
+	Teammates(userID int) ([]types.User, error)
+	WhoIsIn(userID int, date time.Time) (*types.OfficeDay, error)
+	SuggestOfficeDays(userID int, from time.Time) ([]types.OfficeDay, error)
+	SetCompanyRequest(userID int, date time.Time, want bool) error
+	SetAnchorDays(managerID int, days string) error
+	AnchorCompliance(userID int, start, end time.Time) (*types.AnchorCompliance, error)
+	SuggestDefaultDays(userID int) (string, error)
+	GetOffices() ([]types.Office, error)
+	GetOffice(officeID int) (*types.Office, error)
+	CreateOffice(name string, capacity int) (*types.Office, error)
+	SetOfficeCapacity(officeID, capacity int) error
+	DeleteOffice(officeID int) error
+	GetResources(officeID int) ([]types.Resource, error)
+	AddResource(officeID int, name, kind string) (*types.Resource, error)
+	DeleteResource(officeID, resourceID int) error
+	BookDesk(userID, officeID, resourceID int, date time.Time) (*types.Booking, error)
+	BookRoom(userID, officeID, resourceID int, date time.Time) (*types.Booking, error)
+	CancelBooking(userID, bookingID int) error
+	GetUserBookings(userID int, start, end time.Time) ([]types.Booking, error)
+	OfficeCalendar(officeID int, month time.Time) (*types.OfficeCalendar, error)
+	CountOtherOffices() bool
+	SetCountOtherOffices(count bool) error
+	SetDefaultOffice(userID, officeID int) error
+	SetAttendanceAt(userID int, date time.Time, officeID int) (*types.Event, error)
+	OccupancyForecast(from, to time.Time) (*types.OccupancyForecast, error)
+	HasUsers() (bool, error)
+	RegistrationOpen() bool
+	SetRegistrationOpen(open bool) error
+	SessionKey() ([]byte, error)
+	BeginTOTPEnrollment(userID int) (string, string, error)
+	EnableTOTP(userID int, code string) ([]string, error)
+	DisableTOTP(userID int, password string) error
+	ResetTOTP(username string) error
+	RegenerateRecoveryCodes(userID int, code string) ([]string, error)
+	VerifySecondFactor(userID int, code string) (*types.User, error)
+	TwoFactorRoles() []string
+	SetTwoFactorRoles(roles []string) error
+	TwoFactorRequired(user types.User) bool
+	GetSessions(userID int) ([]types.Session, error)
+	RevokeSession(userID int, sessionID int) error
+	RevokeAllSessions(userID int) (int64, error)
+	ClaimUnownedData() (int64, error)
+
+	SetMailer(mailer Mailer)
+	EmailEnabled() bool
+	GetNotificationSettings(userID int) (types.NotificationSettings, error)
+	UpdateNotificationSettings(userID int, settings types.NotificationSettings) error
+	GetEmailDeliveries(userID int, limit int) ([]types.EmailDelivery, error)
+	SendTestEmail(userID int) error
+	SendReminders() (int, error)
+
+	RollOverPeriod() (*types.Period, int, error)
+	PurgeStaleData() (types.PurgeResult, error)
 }
 
 type Service struct {
-	preferences    types.Preferences
-	logger         *slog.Logger
-	eventRepo      repository.EventRepository
-	preferenceRepo repository.PreferenceRepository
-	quarterStart   time.Time
-	quarterEnd     time.Time
+	logger           *slog.Logger
+	eventRepo        repository.EventRepository
+	preferenceRepo   repository.PreferenceRepository
+	periodRepo       repository.PeriodRepository
+	tokenRepo        repository.TokenRepository
+	webhookRepo      repository.WebhookRepository
+	userRepo         repository.UserRepository
+	settingRepo      repository.SettingRepository
+	sessionRepo      repository.SessionRepository
+	companyRepo      repository.CompanyRepository
+	officeRepo       repository.OfficeRepository
+	notificationRepo repository.NotificationRepository
+	quarterStart     time.Time
+	quarterEnd       time.Time
+
+	changeMu    sync.Mutex
+	listeners   []types.ChangeListener
+	statsLevels map[int]string // last band seen by checkStatsThreshold, per user
+
+	mailer Mailer // nil until SetMailer; reminders are not sent without one
 }
 
 func NewService(
 	logger *slog.Logger,
 	eventRepo repository.EventRepository,
 	preferenceRepo repository.PreferenceRepository,
+	periodRepo repository.PeriodRepository,
+	tokenRepo repository.TokenRepository,
+	webhookRepo repository.WebhookRepository,
+	userRepo repository.UserRepository,
+	settingRepo repository.SettingRepository,
+	sessionRepo repository.SessionRepository,
+	companyRepo repository.CompanyRepository,
+	officeRepo repository.OfficeRepository,
+	notificationRepo repository.NotificationRepository,
 	quarterStart time.Time,
 	quarterEnd time.Time,
 ) RTOBLL {
 
 	service := Service{
-		logger:         logger,
-		eventRepo:      eventRepo,
-		preferenceRepo: preferenceRepo,
-		quarterStart:   quarterStart,
-		quarterEnd:     quarterStart,
+		logger:           logger,
+		eventRepo:        eventRepo,
+		preferenceRepo:   preferenceRepo,
+		periodRepo:       periodRepo,
+		tokenRepo:        tokenRepo,
+		webhookRepo:      webhookRepo,
+		userRepo:         userRepo,
+		settingRepo:      settingRepo,
+		sessionRepo:      sessionRepo,
+		companyRepo:      companyRepo,
+		officeRepo:       officeRepo,
+		notificationRepo: notificationRepo,
+		quarterStart:     quarterStart,
+		quarterEnd:       quarterEnd,
+		statsLevels:      make(map[int]string),
 	}
 
-	service.preferences = initializePreferences(&service)
-
 	return &service
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/robstave/rto/internal/adapters/repositories/mocks"
 	"github.com/robstave/rto/internal/domain/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestUpdatePreferences_Success(t *testing.T) {
@@ -18,19 +20,28 @@ func TestUpdatePreferences_Success(t *testing.T) {
 	// Define the initial preferences
 	initialPrefs := types.Preferences{
 		ID:          1,
+		UserID:      4,
 		DefaultDays: "M,T,W,Th,F",
 		TargetDays:  "2.5",
 	}
 
 	// Define the updated preferences
 	updatedPrefs := types.Preferences{
-		ID:          1,
-		DefaultDays: "T,W,Th,F",
-		TargetDays:  "3.0",
+		ID:                1,
+		UserID:            4,
+		DefaultDays:       "T,W,Th,F",
+		TargetDays:        "3.0",
+		CalculationMethod: types.MethodCalendar,
 	}
 
-	// Setup expectations
-	mockRepo.On("GetPreferences").Return(initialPrefs, nil)
+	// Setup expectations: the old values are kept as the version from the start
+	mockRepo.On("GetPreferences", 4).Return(initialPrefs, nil)
+	mockRepo.On("SavePreferenceVersion", mock.MatchedBy(func(v types.PreferenceVersion) bool {
+		return v.EffectiveFrom.IsZero() && v.TargetDays == "2.5"
+	})).Return(nil).Once()
+	mockRepo.On("SavePreferenceVersion", mock.MatchedBy(func(v types.PreferenceVersion) bool {
+		return !v.EffectiveFrom.IsZero() && v.TargetDays == "3.0"
+	})).Return(nil).Once()
 	mockRepo.On("UpdatePreferences", updatedPrefs).Return(nil)
 
 	// Initialize the service with the mock repository
@@ -39,15 +50,13 @@ func TestUpdatePreferences_Success(t *testing.T) {
 		logger:         logger,
 		eventRepo:      nil, // Not needed for this test
 		preferenceRepo: mockRepo,
-		preferences:    initialPrefs,
 	}
 
 	// Call UpdatePreferences
-	err := service.UpdatePreferences(updatedPrefs.DefaultDays, updatedPrefs.TargetDays)
+	err := service.UpdatePreferences(4, updatedPrefs.DefaultDays, updatedPrefs.TargetDays)
 
 	// Assertions
 	assert.NoError(t, err)
-	assert.Equal(t, updatedPrefs, service.preferences)
 
 	// Ensure that the expectations were met
 	mockRepo.AssertExpectations(t)
@@ -58,7 +67,7 @@ func TestUpdatePreferences_GetPreferencesError(t *testing.T) {
 	mockRepo := new(mocks.PreferenceRepository)
 
 	// Setup expectations
-	mockRepo.On("GetPreferences").Return(types.Preferences{}, errors.New("database error"))
+	mockRepo.On("GetPreferences", 4).Return(types.Preferences{}, errors.New("database error"))
 
 	// Initialize the service with the mock repository
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil)) // Using a simple logger
@@ -69,7 +78,7 @@ func TestUpdatePreferences_GetPreferencesError(t *testing.T) {
 	}
 
 	// Call UpdatePreferences
-	err := service.UpdatePreferences("M,W,F", "2.0")
+	err := service.UpdatePreferences(4, "M,W,F", "2.0")
 
 	// Assertions
 	assert.Error(t, err)
@@ -86,19 +95,28 @@ func TestUpdatePreferences_UpdatePreferencesError(t *testing.T) {
 	// Define the initial preferences
 	initialPrefs := types.Preferences{
 		ID:          1,
+		UserID:      4,
 		DefaultDays: "M,T,W,Th,F",
 		TargetDays:  "2.5",
 	}
 
 	// Define the updated preferences
 	updatedPrefs := types.Preferences{
-		ID:          1,
-		DefaultDays: "T,W,Th,F",
-		TargetDays:  "3.0",
+		ID:                1,
+		UserID:            4,
+		DefaultDays:       "T,W,Th,F",
+		TargetDays:        "3.0",
+		CalculationMethod: types.MethodCalendar,
 	}
 
-	// Setup expectations
-	mockRepo.On("GetPreferences").Return(initialPrefs, nil)
+	// Setup expectations: the old values are kept as the version from the start
+	mockRepo.On("GetPreferences", 4).Return(initialPrefs, nil)
+	mockRepo.On("SavePreferenceVersion", mock.MatchedBy(func(v types.PreferenceVersion) bool {
+		return v.EffectiveFrom.IsZero() && v.TargetDays == "2.5"
+	})).Return(nil).Once()
+	mockRepo.On("SavePreferenceVersion", mock.MatchedBy(func(v types.PreferenceVersion) bool {
+		return !v.EffectiveFrom.IsZero() && v.TargetDays == "3.0"
+	})).Return(nil).Once()
 	mockRepo.On("UpdatePreferences", updatedPrefs).Return(errors.New("update failed"))
 
 	// Initialize the service with the mock repository
@@ -107,11 +125,10 @@ func TestUpdatePreferences_UpdatePreferencesError(t *testing.T) {
 		logger:         logger,
 		eventRepo:      nil, // Not needed for this test
 		preferenceRepo: mockRepo,
-		preferences:    initialPrefs,
 	}
 
 	// Call UpdatePreferences
-	err := service.UpdatePreferences(updatedPrefs.DefaultDays, updatedPrefs.TargetDays)
+	err := service.UpdatePreferences(4, updatedPrefs.DefaultDays, updatedPrefs.TargetDays)
 
 	// Assertions
 	assert.Error(t, err)
@@ -120,3 +137,20 @@ func TestUpdatePreferences_UpdatePreferencesError(t *testing.T) {
 	// Ensure that the expectations were met
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetPrefs_SavesDefaultsForNewUser(t *testing.T) {
+	mockRepo := new(mocks.PreferenceRepository)
+	saved := types.Preferences{ID: 9, UserID: 5, DefaultDays: "M,T,W,Th", TargetDays: "2.5"}
+
+	mockRepo.On("GetPreferences", 5).Return(types.Preferences{}, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("UpdatePreferences", types.Preferences{UserID: 5, DefaultDays: "M,T,W,Th", TargetDays: "2.5"}).Return(nil)
+	mockRepo.On("GetPreferences", 5).Return(saved, nil).Once()
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		preferenceRepo: mockRepo,
+	}
+
+	assert.Equal(t, saved, service.GetPrefs(5))
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// GetSessions lists the browsers the user is signed in on, most recently used first
+func (s *Service) GetSessions(userID int) ([]types.Session, error) {
+	sessions, err := s.sessionRepo.GetSessionsByUser(userID, time.Now())
+	if err != nil {
+		s.logger.Error("Error fetching sessions", "userID", userID, "error", err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's browsers out
+func (s *Service) RevokeSession(userID int, sessionID int) error {
+	if sessionID <= 0 {
+		return fmt.Errorf("%w: session %d", ErrNotFound, sessionID)
+	}
+	deleted, err := s.sessionRepo.DeleteSession(userID, uint(sessionID))
+	if err != nil {
+		s.logger.Error("Error revoking session", "sessionID", sessionID, "error", err)
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: session %d", ErrNotFound, sessionID)
+	}
+	s.logger.Info("Session revoked", "userID", userID, "sessionID", sessionID)
+	return nil
+}
+
+// RevokeAllSessions signs the user out of every browser and returns how many
+// sessions ended. API tokens are not affected.
+func (s *Service) RevokeAllSessions(userID int) (int64, error) {
+	deleted, err := s.sessionRepo.DeleteSessionsByUser(userID)
+	if err != nil {
+		s.logger.Error("Error revoking sessions", "userID", userID, "error", err)
+		return 0, err
+	}
+	s.logger.Info("Signed out everywhere", "userID", userID, "sessions", deleted)
+	return deleted, nil
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeSession_OtherUsersSession(t *testing.T) {
+	mockSessionRepo := new(mocks.SessionRepository)
+	// The delete is limited to the caller's sessions, so another user's matches nothing
+	mockSessionRepo.On("DeleteSession", 2, uint(9)).Return(int64(0), nil)
+	mockSessionRepo.On("DeleteSession", 2, uint(4)).Return(int64(1), nil)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		sessionRepo: mockSessionRepo,
+	}
+
+	err := service.RevokeSession(2, 9)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.NoError(t, service.RevokeSession(2, 4))
+	mockSessionRepo.AssertExpectations(t)
+}
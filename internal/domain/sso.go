@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// SignInExternal signs in a user vouched for by an OpenID Connect provider.
+// The identity is matched to the account it was linked to before, then to an
+// account with the same verified email, and otherwise a new account is created.
+// When the provider sends groups they decide the role, except that the only
+// admin stays an admin; otherwise the first account is an admin, new accounts
+// are employees and existing roles are kept.
+func (s *Service) SignInExternal(identity types.ExternalIdentity) (*types.User, error) {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return nil, fmt.Errorf("%w: identity has no issuer or subject", ErrInvalidInput)
+	}
+
+	user, found, err := s.findExternalUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return s.createExternalUser(identity)
+	}
+
+	if user.ExternalID == "" {
+		s.logger.Info("Linked external identity", "userID", user.ID, "issuer", identity.Issuer)
+	}
+	user.ExternalID = identity.ID()
+	if identity.Role != "" && identity.Role != user.Role {
+		// The provider's groups cannot demote the only admin, as SetUserRole refuses to
+		last, err := s.isLastAdmin(user)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			s.logger.Warn("Kept the admin role of the only admin despite their groups", "userID", user.ID, "role", identity.Role)
+		} else {
+			user.Role = identity.Role
+		}
+	}
+	now := time.Now()
+	user.LastLoginAt = &now
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error recording sign-in", "userID", user.ID, "error", err)
+		return nil, err
+	}
+	return &user, nil
+}
+
+// findExternalUser looks the identity up by its linked ID, then by verified email
+func (s *Service) findExternalUser(identity types.ExternalIdentity) (types.User, bool, error) {
+	user, err := s.userRepo.GetUserByExternalID(identity.ID())
+	if err == nil {
+		return user, true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error looking up external identity", "error", err)
+		return user, false, err
+	}
+
+	email := normalizeEmail(identity.Email)
+	if email == "" || !identity.EmailVerified {
+		return user, false, nil
+	}
+	user, err = s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, false, nil
+		}
+		s.logger.Error("Error looking up user by email", "error", err)
+		return user, false, err
+	}
+	if user.ExternalID != "" {
+		return user, false, fmt.Errorf("%w: the account for %s is linked to another identity", ErrConflict, email)
+	}
+	return user, true, nil
+}
+
+func (s *Service) createExternalUser(identity types.ExternalIdentity) (*types.User, error) {
+	count, err := s.userRepo.CountUsers()
+	if err != nil {
+		s.logger.Error("Error counting users", "error", err)
+		return nil, err
+	}
+
+	username, err := s.availableUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only verified addresses are kept, since accounts are linked by email
+	email := ""
+	if identity.EmailVerified {
+		email = normalizeEmail(identity.Email)
+	}
+	if email != "" {
+		if _, err := s.userRepo.GetUserByEmail(email); err == nil {
+			email = ""
+		}
+	}
+
+	now := time.Now()
+	user := types.User{
+		Username:    username,
+		Email:       email,
+		ExternalID:  identity.ID(),
+		Role:        types.RoleEmployee,
+		LastLoginAt: &now,
+		CreatedAt:   now,
+	}
+	if identity.Role != "" {
+		user.Role = identity.Role
+	} else if count == 0 {
+		// Unless someone else got there first, the first account is the admin
+		added, first, err := s.addFirstAdmin(user)
+		if err != nil || first {
+			return added, err
+		}
+	}
+
+	user, err = s.userRepo.AddUser(user)
+	if err != nil {
+		s.logger.Error("Error adding user", "username", username, "error", err)
+		return nil, err
+	}
+	s.userCreated(user)
+	return &user, nil
+}
+
+// availableUsername turns the identity's preferred username (or the local part
+// of its email) into a valid username no one has taken yet
+func (s *Service) availableUsername(identity types.ExternalIdentity) (string, error) {
+	candidate := identity.Username
+	if candidate == "" {
+		candidate, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base := sanitizeUsername(candidate)
+
+	for i := 1; i <= 100; i++ {
+		username := base
+		if i > 1 {
+			username = fmt.Sprintf("%s-%d", base, i)
+		}
+		_, err := s.userRepo.GetUserByUsername(username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return username, nil
+		}
+		if err != nil {
+			s.logger.Error("Error looking up user", "username", username, "error", err)
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("%w: no free username for %q", ErrConflict, base)
+}
+
+// sanitizeUsername maps any string onto usernamePattern, leaving room for a numeric suffix
+func sanitizeUsername(name string) string {
+	var b strings.Builder
+	for _, r := range normalizeUsername(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	username := strings.TrimLeft(b.String(), "._-")
+	if len(username) > 28 {
+		username = username[:28]
+	}
+	if len(username) < 3 {
+		username = "user" + username
+	}
+	return username
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SetUserEmail sets the address a user's single sign-on identity is linked by.
+// It is an admin task: whoever controls an account's email can sign in to it.
+// An empty email clears it.
+func (s *Service) SetUserEmail(username, email string) error {
+	user, err := s.userRepo.GetUserByUsername(normalizeUsername(username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: user %q", ErrNotFound, username)
+		}
+		s.logger.Error("Error looking up user", "username", username, "error", err)
+		return err
+	}
+
+	email = normalizeEmail(email)
+	if email != "" {
+		local, host, ok := strings.Cut(email, "@")
+		if !ok || local == "" || host == "" || strings.ContainsAny(email, " \t,;<>") {
+			return fmt.Errorf("%w: %q is not an email address", ErrInvalidInput, email)
+		}
+		other, err := s.userRepo.GetUserByEmail(email)
+		if err == nil && other.ID != user.ID {
+			return fmt.Errorf("%w: %s is used by %q", ErrConflict, email, other.Username)
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Error("Error looking up user by email", "error", err)
+			return err
+		}
+	}
+
+	user.Email = email
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error updating email", "userID", user.ID, "error", err)
+		return err
+	}
+	s.logger.Info("Email changed", "userID", user.ID)
+	return nil
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func TestSignInExternal_LinkedIdentity(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-1").
+		Return(types.User{ID: 3, Username: "alice", ExternalID: testIssuer + " sub-1"}, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.ID == 3 && u.IsAdmin() && u.LastLoginAt != nil
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	user, err := service.SignInExternal(types.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", Role: types.RoleAdmin})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), user.ID)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSignInExternal_LinksByVerifiedEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-2").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("GetUserByEmail", "bob@example.com").Return(types.User{ID: 4, Username: "bob", Email: "bob@example.com"}, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.ID == 4 && u.ExternalID == testIssuer+" sub-2" && !u.IsAdmin()
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	user, err := service.SignInExternal(types.ExternalIdentity{
+		Issuer: testIssuer, Subject: "sub-2", Email: "Bob@Example.com", EmailVerified: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", user.Username)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSignInExternal_UnverifiedEmailCreatesAccount(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-3").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("CountUsers").Return(int64(2), nil)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(types.User{ID: 4, Username: "bob"}, nil)
+	mockUserRepo.On("GetUserByUsername", "bob-2").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("AddUser", mock.MatchedBy(func(u types.User) bool {
+		return u.Username == "bob-2" && u.Email == "" && u.PasswordHash == "" && !u.IsAdmin()
+	})).Return(types.User{ID: 5, Username: "bob-2"}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	user, err := service.SignInExternal(types.ExternalIdentity{
+		Issuer: testIssuer, Subject: "sub-3", Email: "bob@example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bob-2", user.Username)
+	// An unverified address never links to an existing account
+	mockUserRepo.AssertNotCalled(t, "GetUserByEmail", mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSignInExternal_FirstAccountIsAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-4").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("GetUserByEmail", "carol@example.com").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("CountUsers").Return(int64(0), nil)
+	mockUserRepo.On("GetUserByUsername", "carol.k").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("AddFirstUser", mock.MatchedBy(func(u types.User) bool {
+		return u.Username == "carol.k" && u.Email == "carol@example.com" && u.IsAdmin()
+	})).Return(types.User{ID: 1, Username: "carol.k", Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 1, Username: "carol.k", Role: types.RoleAdmin}}, nil)
+	mockUserRepo.On("AssignUnownedData", 1).Return(int64(0), nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	user, err := service.SignInExternal(types.ExternalIdentity{
+		Issuer: testIssuer, Subject: "sub-4", Email: "carol@example.com", EmailVerified: true, Username: "Carol.K",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, user.IsAdmin())
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSignInExternal_EmailLinkedToOtherIdentity(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-5").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("GetUserByEmail", "dan@example.com").
+		Return(types.User{ID: 6, Username: "dan", Email: "dan@example.com", ExternalID: "https://other.example.com x"}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	_, err := service.SignInExternal(types.ExternalIdentity{
+		Issuer: testIssuer, Subject: "sub-5", Email: "dan@example.com", EmailVerified: true,
+	})
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "AddUser", mock.Anything)
+}
+
+func TestSanitizeUsername(t *testing.T) {
+	assert.Equal(t, "jane-doe", sanitizeUsername("Jane Doe"))
+	assert.Equal(t, "user", sanitizeUsername(""))
+	assert.Equal(t, "userx", sanitizeUsername("_x"))
+	assert.Len(t, sanitizeUsername("a-very-long-name-from-the-identity-provider"), 28)
+}
+
+func TestSetUserEmail_Taken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "alice").Return(types.User{ID: 1, Username: "alice"}, nil)
+	mockUserRepo.On("GetUserByEmail", "shared@example.com").Return(types.User{ID: 2, Username: "bob"}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	err := service.SetUserEmail("alice", "shared@example.com")
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	err = service.SetUserEmail("alice", "not an address")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+	mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+}
+
+func TestSignInExternal_GroupsCannotDemoteLastAdmin(t *testing.T) {
+	for _, admins := range []int{1, 2} {
+		alice := types.User{ID: 3, Username: "alice", Role: types.RoleAdmin, ExternalID: testIssuer + " sub-1"}
+		users := []types.User{alice, {ID: 4, Username: "bob", Role: types.RoleEmployee}}
+		if admins == 2 {
+			users[1].Role = types.RoleAdmin
+		}
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetUserByExternalID", testIssuer+" sub-1").Return(alice, nil)
+		mockUserRepo.On("GetAllUsers").Return(users, nil)
+		mockUserRepo.On("UpdateUser", mock.Anything).Return(nil)
+
+		service := Service{
+			logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+			userRepo: mockUserRepo,
+		}
+
+		// Dropped from the admin group at the provider
+		user, err := service.SignInExternal(types.ExternalIdentity{Issuer: testIssuer, Subject: "sub-1", Role: types.RoleEmployee})
+
+		if assert.NoError(t, err) {
+			if admins == 1 {
+				assert.Equal(t, types.RoleAdmin, user.Role, "the only admin keeps the role")
+			} else {
+				assert.Equal(t, types.RoleEmployee, user.Role)
+			}
+		}
+	}
+}
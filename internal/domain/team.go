@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+// GetTeam returns the users on the viewer's team dashboard: everyone for an
+// admin, otherwise their direct reports
+func (s *Service) GetTeam(viewerID int) ([]types.User, error) {
+	viewer, err := s.getUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if viewer.IsAdmin() {
+		return s.GetUsers()
+	}
+	return s.GetReports(viewerID)
+}
+
+// TeamDashboard builds the viewer's team heatmap for the weekdays of the
+// month containing the date. Averages and anchor-day compliance cover the
+// period containing the first of that month; averages use the same
+// calculation as CalculateStatsBetween.
+func (s *Service) TeamDashboard(viewerID int, month time.Time) (*types.TeamDashboard, error) {
+	viewer, err := s.getUser(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.GetTeam(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	period, err := s.periodFor(first)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &types.TeamDashboard{Period: *period, AnchorDays: viewer.AnchorDays}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if !utils.IsWeekend(day) {
+			dashboard.Days = append(dashboard.Days, day)
+		}
+	}
+	dashboard.InOffice = make([]int, len(dashboard.Days))
+
+	anchorTotal, anchorAttended := 0, 0
+
+	for _, user := range team {
+		events, err := s.eventRepo.GetAllEvents(int(user.ID))
+		if err != nil {
+			s.logger.Error("Error fetching events", "userID", user.ID, "error", err)
+			return nil, err
+		}
+
+		member := types.TeamMember{
+			User:  user,
+			Days:  dayStatuses(events, dashboard.Days),
+			Stats: s.attendanceStats(int(user.ID), events, period.StartDate, period.EndDate),
+		}
+		member.OnTarget = member.Stats.AverageDays >= member.Stats.TargetDays
+
+		anchorDays := viewer.AnchorDays
+		if !user.ReportsTo(viewer.ID) {
+			if anchorDays, err = s.teamAnchorDays(user); err != nil {
+				return nil, err
+			}
+		}
+		member.Anchors = anchorCompliance(anchorDays, events, period.StartDate, period.EndDate, time.Now())
+		anchorTotal += member.Anchors.Days
+		anchorAttended += member.Anchors.Attended
+
+		for i, status := range member.Days {
+			if status == types.DayInOffice {
+				dashboard.InOffice[i]++
+			}
+		}
+		if member.OnTarget {
+			dashboard.OnTarget++
+		}
+		dashboard.AverageDays += member.Stats.AverageDays
+		dashboard.Members = append(dashboard.Members, member)
+	}
+	if len(dashboard.Members) > 0 {
+		dashboard.AverageDays /= float64(len(dashboard.Members))
+	}
+	if anchorTotal > 0 {
+		dashboard.AnchorPercent = float64(anchorAttended) / float64(anchorTotal) * 100
+	}
+	return dashboard, nil
+}
+
+// dayStatuses sums up each day's events into one status. A holiday wins over
+// vacation, and vacation over the attendance logged for the day.
+func dayStatuses(events []types.Event, days []time.Time) []string {
+	rank := map[string]int{
+		types.DayUnknown:  0,
+		types.DayRemote:   1,
+		types.DayInOffice: 2,
+		types.DayVacation: 3,
+		types.DayHoliday:  4,
+	}
+
+	byDate := make(map[string]string)
+	for _, event := range events {
+		status := types.DayUnknown
+		switch event.Type {
+		case "holiday":
+			status = types.DayHoliday
+		case "vacation":
+			status = types.DayVacation
+		case "attendance":
+			status = types.DayRemote
+			if event.IsInOffice {
+				status = types.DayInOffice
+			}
+		}
+		key := event.Date.Format("2006-01-02")
+		if rank[status] > rank[byDate[key]] {
+			byDate[key] = status
+		}
+	}
+
+	statuses := make([]string, len(days))
+	for i, day := range days {
+		statuses[i] = byDate[day.Format("2006-01-02")]
+	}
+	return statuses
+}
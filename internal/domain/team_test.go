@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTeamDashboard(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.October, d, 0, 0, 0, 0, time.UTC) }
+	period := types.Period{Name: "Q4 2024", StartDate: day(1), EndDate: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)}
+	holiday := types.Event{Date: day(14), Type: "holiday", Description: "Columbus Day"}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Role: types.RoleManager}, nil)
+	mockUserRepo.On("GetUsersByManager", 1).Return([]types.User{
+		{ID: 2, Username: "ann"},
+		{ID: 3, Username: "bo"},
+	}, nil)
+
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", day(1)).Return(period, nil)
+
+	mockEventRepo := new(mocks.EventRepository)
+	mockEventRepo.On("GetAllEvents", 2).Return([]types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true},
+		{Date: day(2), Type: "attendance", IsInOffice: false},
+		{Date: day(3), Type: "attendance", IsInOffice: true},
+		{Date: day(3), Type: "vacation"},
+		{Date: day(14), Type: "attendance", IsInOffice: true},
+		holiday,
+	}, nil)
+	mockEventRepo.On("GetAllEvents", 3).Return([]types.Event{
+		{Date: day(1), Type: "attendance", IsInOffice: true},
+		holiday,
+	}, nil)
+
+	mockPrefsRepo := new(mocks.PreferenceRepository)
+	mockPrefsRepo.On("GetPreferences", 2).Return(types.Preferences{TargetDays: "0.1"}, nil)
+	mockPrefsRepo.On("GetPreferences", 3).Return(types.Preferences{TargetDays: "2.5"}, nil)
+
+	service := Service{
+		logger:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:       mockUserRepo,
+		eventRepo:      mockEventRepo,
+		periodRepo:     mockPeriodRepo,
+		preferenceRepo: mockPrefsRepo,
+	}
+
+	dashboard, err := service.TeamDashboard(1, day(17))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Q4 2024", dashboard.Period.Name)
+	assert.Len(t, dashboard.Days, 23) // weekdays in October 2024
+	assert.Len(t, dashboard.Members, 2)
+
+	ann := dashboard.Members[0]
+	assert.Equal(t, []string{types.DayInOffice, types.DayRemote, types.DayVacation, types.DayUnknown}, ann.Days[:4])
+	assert.Equal(t, types.DayHoliday, ann.Days[9]) // Oct 14
+	// The same numbers CalculateStatsBetween gives
+	assert.Equal(t, 3, ann.Stats.InOfficeCount)
+	assert.Equal(t, 92, ann.Stats.TotalDays)
+	assert.True(t, ann.OnTarget)
+	assert.False(t, dashboard.Members[1].OnTarget)
+
+	assert.Equal(t, 2, dashboard.InOffice[0])
+	assert.Equal(t, 0, dashboard.InOffice[1])
+	assert.Equal(t, 1, dashboard.OnTarget)
+	assert.InDelta(t, (3.0/92*7+1.0/92*7)/2, dashboard.AverageDays, 0.0001)
+}
+
+func TestTeamDashboard_FallsBackToQuarter(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 1).Return(types.User{ID: 1, Role: types.RoleAdmin}, nil)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{}, nil)
+	mockPeriodRepo := new(mocks.PeriodRepository)
+	mockPeriodRepo.On("GetPeriodForDate", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)).
+		Return(types.Period{}, gorm.ErrRecordNotFound)
+
+	quarterStart := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	quarterEnd := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	service := Service{
+		logger:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:     mockUserRepo,
+		periodRepo:   mockPeriodRepo,
+		quarterStart: quarterStart,
+		quarterEnd:   quarterEnd,
+	}
+
+	dashboard, err := service.TeamDashboard(1, time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, quarterStart, dashboard.Period.StartDate)
+	assert.Empty(t, dashboard.Members)
+	assert.Zero(t, dashboard.AverageDays)
+}
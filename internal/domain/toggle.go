@@ -1,17 +1,20 @@
 package domain
 
 import (
-	"errors"
-	"strconv"
+	"fmt"
 	"time"
 
 	"github.com/robstave/rto/internal/domain/types"
 	"github.com/robstave/rto/internal/utils"
 )
 
-func (s *Service) ToggleAttendance(eventDate time.Time) (string, error) {
+func (s *Service) ToggleAttendance(userID int, eventDate time.Time) (string, error) {
+	if err := s.checkOpen(eventDate); err != nil {
+		return "", err
+	}
+
 	// Retrieve all events
-	events, err := s.eventRepo.GetAllEvents()
+	events, err := s.eventRepo.GetAllEvents(userID)
 	if err != nil {
 		s.logger.Error("Error retrieving events", "error", err)
 		return "", err
@@ -26,6 +29,7 @@ func (s *Service) ToggleAttendance(eventDate time.Time) (string, error) {
 		if utils.SameDay(event.Date, eventDate) && event.Type == "attendance" {
 			// Toggle the IsInOffice flag
 			event.IsInOffice = !event.IsInOffice
+			event.OfficeID = 0
 			eventToUpdate = event
 			if event.IsInOffice {
 				newStatus = "in"
@@ -41,48 +45,89 @@ func (s *Service) ToggleAttendance(eventDate time.Time) (string, error) {
 	}
 
 	if !found {
-		return "", errors.New("attendance event not found on the specified date")
+		return "", fmt.Errorf("%w: attendance event not found on the specified date", ErrNotFound)
+	}
+
+	// An in-office day is spent at the default office, at a desk if it has them
+	if eventToUpdate, err = s.locateEvent(userID, eventToUpdate); err != nil {
+		return "", err
+	}
+	booking, err := s.claimDesk(userID, eventToUpdate)
+	if err != nil {
+		return "", err
 	}
 
 	// Update the event in the database
 	err = s.eventRepo.UpdateEvent(eventToUpdate)
 	if err != nil {
 		s.logger.Error("Error updating event", "error", err)
+		s.undoBooking(userID, booking)
 		return "", err
 	}
 
+	s.releaseIfAway(userID, eventToUpdate)
+	s.notify(userID, types.Change{
+		Kind:   types.ChangeAttendanceToggled,
+		Event:  &eventToUpdate,
+		Status: newStatus,
+	})
 	return newStatus, nil
 }
 
 // CalculateAttendanceStats calculates all the stats
-func (s *Service) CalculateAttendanceStats() (*types.AttendanceStats, error) {
+func (s *Service) CalculateAttendanceStats(userID int) (*types.AttendanceStats, error) {
 	currentYear := time.Now().Year()
 	startDate := time.Date(currentYear, time.October, 1, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(currentYear, time.December, 31, 0, 0, 0, 0, time.UTC)
 
-	// normalize ?
+	return s.CalculateStatsBetween(userID, startDate, endDate)
+}
 
-	allTheEvents, err := s.eventRepo.GetAllEvents()
+// CalculateStatsBetween calculates the attendance stats for an arbitrary date range
+func (s *Service) CalculateStatsBetween(userID int, startDate, endDate time.Time) (*types.AttendanceStats, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidInput)
+	}
+
+	allTheEvents, err := s.eventRepo.GetAllEvents(userID)
 	if err != nil {
 		s.logger.Error("Error fetching  events", "error", err)
 		return nil, err
 	}
-	inOfficeCount, totalDays := utils.CalculateInOfficeAverage(allTheEvents, startDate, endDate)
+	stats := s.attendanceStats(userID, allTheEvents, startDate, endDate)
+	return &stats, nil
+}
+
+// attendanceStats measures already fetched events against the user's target.
+// It is the one calculation behind the calendar stats and the team dashboard.
+// The target and method are the ones in force at the time, not today's.
+func (s *Service) attendanceStats(userID int, events []types.Event, startDate, endDate time.Time) types.AttendanceStats {
+	prefs := s.preferencesBetween(userID, startDate, endDate, time.Now())
+	method := calculationMethod(prefs)
+	inOfficeCount, totalDays := utils.CalculateInOfficeAverage(events, startDate, endDate)
+	if method == types.MethodWorkdays {
+		totalDays = workdays(events, startDate, endDate)
+	}
+
+	// Days at another office may not count, depending on the policy
+	byOffice := officeSplit(events, startDate, endDate)
+	uncounted := 0
+	if len(byOffice) > 0 {
+		uncounted = uncountedDays(byOffice, prefs.DefaultOfficeID)
+		if uncounted > 0 && s.CountOtherOffices() {
+			uncounted = 0
+		}
+	}
+	inOfficeCount -= uncounted
 
 	average := 0.0
 	averageDays := 0.0
 	if totalDays > 0 {
 		average = (float64(inOfficeCount) / float64(totalDays)) * 100
-		averageDays = (float64(inOfficeCount) / float64(totalDays)) * 7 // Average days/week
+		averageDays = (float64(inOfficeCount) / float64(totalDays)) * types.DaysPerWeek(method) // Average days/week
 	}
 
-	// Fetch targetDays from preferences
-	targetDaysStr := s.preferences.TargetDays
-	targetDays, err := strconv.ParseFloat(targetDaysStr, 64)
-	if err != nil {
-		// Fallback to default target if parsing fails
-		targetDays = 2.5
-	}
+	targetDays := targetDays(prefs)
 
 	// Calculate Average Percent
 	averagePercent := 0.0
@@ -90,12 +135,32 @@ func (s *Service) CalculateAttendanceStats() (*types.AttendanceStats, error) {
 		averagePercent = (averageDays / targetDays) * 100
 	}
 
-	return &types.AttendanceStats{
+	return types.AttendanceStats{
 		InOfficeCount:  inOfficeCount,
 		TotalDays:      totalDays,
 		Average:        average,
 		AverageDays:    averageDays,
 		TargetDays:     targetDays,
 		AveragePercent: averagePercent,
-	}, nil
+		ByOffice:       byOffice,
+		Uncounted:      uncounted,
+		Method:         method,
+	}
+}
+
+// workdays counts the weekdays from start to end that are not holidays or vacation
+func workdays(events []types.Event, start, end time.Time) int {
+	var days []time.Time
+	for d := utils.NormalizeDate(start); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !utils.IsWeekend(d) {
+			days = append(days, d)
+		}
+	}
+	count := 0
+	for _, status := range dayStatuses(events, days) {
+		if status != types.DayHoliday && status != types.DayVacation {
+			count++
+		}
+	}
+	return count
 }
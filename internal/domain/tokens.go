@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// tokenPrefix marks RTO personal access tokens so they are easy to spot in scripts and logs
+const tokenPrefix = "rto_"
+
+// ErrInvalidToken is returned when a bearer token is unknown, revoked or expired
+var ErrInvalidToken = errors.New("invalid or expired API token")
+
+var validScopes = map[string]bool{
+	types.ScopeRead:        true,
+	types.ScopeWriteEvents: true,
+	types.ScopeAdmin:       true,
+}
+
+// hashToken returns the hex SHA-256 of a plaintext token. Tokens carry 256 bits of
+// randomness, so a fast hash is sufficient here.
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIToken generates a new token that acts as the user. The plaintext is
+// returned once and never stored.
+func (s *Service) CreateAPIToken(userID int, name string, scopes []string, expiresAt *time.Time) (string, *types.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: token name is required", ErrInvalidInput)
+	}
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+	for _, scope := range scopes {
+		if !validScopes[scope] {
+			return "", nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidInput)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error("Failed to generate token", "error", err)
+		return "", nil, err
+	}
+	plaintext := tokenPrefix + hex.EncodeToString(raw)
+
+	token, err := s.tokenRepo.AddToken(types.APIToken{
+		Name:      name,
+		Prefix:    plaintext[:len(tokenPrefix)+6],
+		TokenHash: hashToken(plaintext),
+		Scopes:    strings.Join(scopes, ","),
+		UserID:    uint(userID),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to store token", "name", name, "error", err)
+		return "", nil, err
+	}
+
+	s.logger.Info("API token created", "tokenID", token.ID, "scopes", token.Scopes)
+	return plaintext, &token, nil
+}
+
+// GetAPITokens lists the user's tokens, newest first
+func (s *Service) GetAPITokens(userID int) ([]types.APIToken, error) {
+	tokens, err := s.tokenRepo.GetAllTokens(userID)
+	if err != nil {
+		s.logger.Error("Error fetching tokens", "error", err)
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken marks one of the user's tokens as revoked. Revoking twice is a no-op.
+func (s *Service) RevokeAPIToken(userID int, tokenID int) error {
+	token, err := s.tokenRepo.GetTokenByID(tokenID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: token %d", ErrNotFound, tokenID)
+		}
+		s.logger.Error("Error fetching token", "tokenID", tokenID, "error", err)
+		return err
+	}
+	if token.UserID != uint(userID) {
+		return fmt.Errorf("%w: token %d", ErrNotFound, tokenID)
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	if err := s.tokenRepo.UpdateToken(token); err != nil {
+		s.logger.Error("Error revoking token", "tokenID", tokenID, "error", err)
+		return err
+	}
+
+	s.logger.Info("API token revoked", "tokenID", tokenID)
+	return nil
+}
+
+// AuthenticateAPIToken resolves a plaintext bearer token and records its use
+func (s *Service) AuthenticateAPIToken(plaintext string) (*types.APIToken, error) {
+	if !strings.HasPrefix(plaintext, tokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.GetTokenByHash(hashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		s.logger.Error("Error looking up token", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.Active(now) {
+		return nil, ErrInvalidToken
+	}
+
+	token.LastUsedAt = &now
+	if err := s.tokenRepo.UpdateToken(token); err != nil {
+		// Not fatal; the token is still valid
+		s.logger.Error("Error recording token use", "tokenID", token.ID, "error", err)
+	}
+	return &token, nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateAPIToken_HashesAtRest(t *testing.T) {
+	// Initialize the mock repository
+	mockRepo := new(mocks.TokenRepository)
+
+	var stored types.APIToken
+	mockRepo.On("AddToken", mock.AnythingOfType("types.APIToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(types.APIToken) }).
+		Return(func(token types.APIToken) types.APIToken {
+			token.ID = 1
+			return token
+		}, nil)
+
+	service := Service{
+		logger:    slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		tokenRepo: mockRepo,
+	}
+
+	plaintext, token, err := service.CreateAPIToken(2, "phone", []string{types.ScopeWriteEvents}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), stored.UserID)
+	assert.True(t, strings.HasPrefix(plaintext, "rto_"))
+	assert.Equal(t, uint(1), token.ID)
+	assert.Equal(t, hashToken(plaintext), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, plaintext)
+	assert.True(t, strings.HasPrefix(plaintext, stored.Prefix))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAPIToken_UnknownScope(t *testing.T) {
+	service := Service{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	}
+
+	_, _, err := service.CreateAPIToken(2, "phone", []string{"superuser"}, nil)
+
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestRevokeAPIToken_OtherUsersToken(t *testing.T) {
+	mockRepo := new(mocks.TokenRepository)
+	mockRepo.On("GetTokenByID", 6).Return(types.APIToken{ID: 6, UserID: 1, Scopes: "read"}, nil)
+
+	service := Service{
+		logger:    slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		tokenRepo: mockRepo,
+	}
+
+	err := service.RevokeAPIToken(2, 6)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	mockRepo.AssertNotCalled(t, "UpdateToken", mock.Anything)
+}
+
+func TestAuthenticateAPIToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		token   types.APIToken
+		lookup  error
+		wantErr error
+	}{
+		{name: "active", token: types.APIToken{ID: 1, Scopes: "read", ExpiresAt: &future}},
+		{name: "expired", token: types.APIToken{ID: 2, Scopes: "read", ExpiresAt: &past}, wantErr: ErrInvalidToken},
+		{name: "revoked", token: types.APIToken{ID: 3, Scopes: "read", RevokedAt: &past}, wantErr: ErrInvalidToken},
+		{name: "unknown", lookup: gorm.ErrRecordNotFound, wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.TokenRepository)
+			plaintext := "rto_" + tt.name
+			mockRepo.On("GetTokenByHash", hashToken(plaintext)).Return(tt.token, tt.lookup)
+			mockRepo.On("UpdateToken", mock.AnythingOfType("types.APIToken")).Return(nil)
+
+			service := Service{
+				logger:    slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				tokenRepo: mockRepo,
+			}
+
+			token, err := service.AuthenticateAPIToken(plaintext)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				mockRepo.AssertNotCalled(t, "UpdateToken", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, token.LastUsedAt)
+		})
+	}
+}
+
+func TestAPITokenHasScope(t *testing.T) {
+	admin := types.APIToken{Scopes: "admin"}
+	writer := types.APIToken{Scopes: "write:events"}
+	reader := types.APIToken{Scopes: "read"}
+
+	assert.True(t, admin.HasScope(types.ScopeWriteEvents))
+	assert.True(t, writer.HasScope(types.ScopeRead))
+	assert.False(t, writer.HasScope(types.ScopeAdmin))
+	assert.False(t, reader.HasScope(types.ScopeWriteEvents))
+}
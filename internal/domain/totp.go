@@ -0,0 +1,336 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ErrSecondFactorRequired is returned by Authenticate, along with the user,
+// when the password is right but the account also needs a TOTP code
+var ErrSecondFactorRequired = errors.New("two-factor code required")
+
+// TOTP parameters (RFC 6238 defaults, which every authenticator app supports)
+const (
+	totpIssuer = "RTO"
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	totpSkew   = 1 // steps either side of now that are accepted, for clock drift
+
+	RecoveryCodeCount = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpCode is the code for a time step (RFC 4226 dynamic truncation)
+func totpCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// verifyTOTP checks a code against the steps around now and returns the
+// matching step. Steps at or before lastStep are refused so a code works once.
+func verifyTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	current := now.Unix() / int64(totpPeriod/time.Second)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpURI is the otpauth:// URI authenticator apps read from the QR code
+func totpURI(username, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", totpIssuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+	label := url.PathEscape(totpIssuer + ":" + username)
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}
+
+// normalizeCode strips the spaces and dashes people type into codes
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// newRecoveryCodes returns fresh codes like "3f9a1-c07b2" and their hashes
+func newRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, "", err
+		}
+		plain := hex.EncodeToString(raw)
+		codes[i] = plain[:5] + "-" + plain[5:]
+		hashes[i] = hashToken(plain)
+	}
+	return codes, strings.Join(hashes, ","), nil
+}
+
+// useRecoveryCode removes the code from the user's unused codes if it is one of them
+func useRecoveryCode(user *types.User, code string) bool {
+	hash := hashToken(normalizeCode(code))
+	var remaining []string
+	found := false
+	for _, stored := range strings.Split(user.RecoveryCodes, ",") {
+		if stored == "" {
+			continue
+		}
+		if !found && subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			found = true
+			continue
+		}
+		remaining = append(remaining, stored)
+	}
+	if found {
+		user.RecoveryCodes = strings.Join(remaining, ",")
+	}
+	return found
+}
+
+// RecoveryCodesLeft counts the user's unused recovery codes
+func RecoveryCodesLeft(user types.User) int {
+	count := 0
+	for _, stored := range strings.Split(user.RecoveryCodes, ",") {
+		if stored != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// BeginTOTPEnrollment gives the user a secret to add to an authenticator app.
+// Two-factor sign-in stays off until EnableTOTP confirms a code from it. The
+// same secret is returned until enrollment finishes, so reloading the page
+// does not invalidate a QR code already scanned.
+func (s *Service) BeginTOTPEnrollment(userID int) (string, string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TOTPEnabled {
+		return "", "", fmt.Errorf("%w: two-factor sign-in is already on", ErrConflict)
+	}
+	if user.TOTPSecret == "" {
+		raw := make([]byte, 20)
+		if _, err := rand.Read(raw); err != nil {
+			return "", "", err
+		}
+		user.TOTPSecret = totpEncoding.EncodeToString(raw)
+		if err := s.userRepo.UpdateUser(user); err != nil {
+			s.logger.Error("Error saving TOTP secret", "userID", userID, "error", err)
+			return "", "", err
+		}
+	}
+	return user.TOTPSecret, totpURI(user.Username, user.TOTPSecret), nil
+}
+
+// EnableTOTP turns on two-factor sign-in once the user proves their app
+// produces the right codes, and returns the recovery codes to show them once.
+// Every other session is signed out, so none stays in without a second factor.
+func (s *Service) EnableTOTP(userID int, code string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("%w: two-factor sign-in is already on", ErrConflict)
+	}
+	if user.TOTPSecret == "" {
+		return nil, fmt.Errorf("%w: start enrollment first", ErrInvalidInput)
+	}
+	step, ok := verifyTOTP(user.TOTPSecret, normalizeCode(code), time.Now(), 0)
+	if !ok {
+		return nil, fmt.Errorf("%w: that code is not right; check the time on your device", ErrInvalidInput)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+	user.SessionVersion++
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error enabling TOTP", "userID", userID, "error", err)
+		return nil, err
+	}
+	s.logger.Info("Two-factor sign-in enabled", "userID", userID)
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor sign-in off after checking the password. It
+// cannot be turned off while the user's role requires it.
+func (s *Service) DisableTOTP(userID int, password string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return fmt.Errorf("%w: password is wrong", ErrInvalidInput)
+	}
+	if s.TwoFactorRequired(user) {
+		return fmt.Errorf("%w: two-factor sign-in is required for the %s role", ErrConflict, user.Role)
+	}
+	return s.clearTOTP(user)
+}
+
+// ResetTOTP turns two-factor sign-in off for a user who lost their device.
+// If their role requires it they enroll again at their next sign-in.
+func (s *Service) ResetTOTP(username string) error {
+	user, err := s.userRepo.GetUserByUsername(normalizeUsername(username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: user %q", ErrNotFound, username)
+		}
+		s.logger.Error("Error looking up user", "username", username, "error", err)
+		return err
+	}
+	return s.clearTOTP(user)
+}
+
+// clearTOTP turns two-factor sign-in off and signs out every session
+func (s *Service) clearTOTP(user types.User) error {
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = ""
+	user.SessionVersion++
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error disabling TOTP", "userID", user.ID, "error", err)
+		return err
+	}
+	s.logger.Info("Two-factor sign-in disabled", "userID", user.ID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a current TOTP code
+func (s *Service) RegenerateRecoveryCodes(userID int, code string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, fmt.Errorf("%w: two-factor sign-in is off", ErrConflict)
+	}
+	step, ok := verifyTOTP(user.TOTPSecret, normalizeCode(code), time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, fmt.Errorf("%w: that code is not right", ErrInvalidInput)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error saving recovery codes", "userID", userID, "error", err)
+		return nil, err
+	}
+	s.logger.Info("Recovery codes regenerated", "userID", userID)
+	return codes, nil
+}
+
+// VerifySecondFactor finishes a password sign-in with a TOTP code or an unused
+// recovery code. Wrong codes count towards the same lockout as wrong passwords.
+func (s *Service) VerifySecondFactor(userID int, code string) (*types.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if user.Locked(now) {
+		return nil, ErrAccountLocked
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrInvalidCredentials
+	}
+
+	code = normalizeCode(code)
+	if step, ok := verifyTOTP(user.TOTPSecret, code, now, user.TOTPLastStep); ok {
+		user.TOTPLastStep = step
+	} else if useRecoveryCode(&user, code) {
+		s.logger.Info("Recovery code used", "userID", user.ID, "remaining", RecoveryCodesLeft(user))
+	} else {
+		s.recordFailedSignIn(user, now)
+		return nil, ErrInvalidCredentials
+	}
+	return s.recordSignIn(user, now)
+}
+
+// TwoFactorRoles returns the roles that must use two-factor sign-in
+func (s *Service) TwoFactorRoles() []string {
+	value, err := s.settingRepo.GetSetting(types.SettingTwoFactorRoles)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Error("Error reading two-factor setting", "error", err)
+		}
+		return nil
+	}
+	var roles []string
+	for _, role := range strings.Split(value, ",") {
+		if types.ValidRole(role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// SetTwoFactorRoles chooses the roles that must use two-factor sign-in
+func (s *Service) SetTwoFactorRoles(roles []string) error {
+	for _, role := range roles {
+		if !types.ValidRole(role) {
+			return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+		}
+	}
+	if err := s.settingRepo.SetSetting(types.SettingTwoFactorRoles, strings.Join(roles, ",")); err != nil {
+		s.logger.Error("Error saving two-factor setting", "error", err)
+		return err
+	}
+	s.logger.Info("Two-factor requirement changed", "roles", roles)
+	return nil
+}
+
+// TwoFactorRequired reports whether the user's role must use two-factor sign-in
+func (s *Service) TwoFactorRequired(user types.User) bool {
+	for _, role := range s.TwoFactorRoles() {
+		if role == user.Role {
+			return true
+		}
+	}
+	return false
+}
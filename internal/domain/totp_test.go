@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// The SHA1 vectors from RFC 6238 appendix B, cut to six digits
+func TestTOTPCode_RFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	for unix, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		assert.Equal(t, want, totpCode(secret, unix/30), "time %d", unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	step, ok := verifyTOTP(secret, "050471", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/30), step)
+
+	// The previous step is still accepted for clock drift
+	_, ok = verifyTOTP(secret, "081804", now, 0)
+	assert.True(t, ok)
+
+	// A code is good once
+	_, ok = verifyTOTP(secret, "050471", now, step)
+	assert.False(t, ok)
+
+	_, ok = verifyTOTP(secret, "000000", now, 0)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("alice", "ABCDEF")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/RTO:alice?"))
+	assert.Contains(t, uri, "secret=ABCDEF")
+	assert.Contains(t, uri, "issuer=RTO")
+}
+
+func TestAuthenticate_SecondFactorRequired(t *testing.T) {
+	user := types.User{ID: 2, Username: "bob", PasswordHash: mustHash(t, "correct horse"), FailedLogins: 3, TOTPEnabled: true}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(user, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	signedIn, err := service.Authenticate("bob", "correct horse")
+
+	assert.True(t, errors.Is(err, ErrSecondFactorRequired))
+	assert.Equal(t, uint(2), signedIn.ID)
+	// Failures stay counted until the code is right too
+	mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+}
+
+func TestVerifySecondFactor_RecoveryCodeWorksOnce(t *testing.T) {
+	codes, hashes, err := newRecoveryCodes()
+	assert.NoError(t, err)
+	assert.Len(t, codes, RecoveryCodeCount)
+
+	user := types.User{ID: 2, Username: "bob", TOTPEnabled: true, TOTPSecret: "GEZDGNBVGY3TQOJQ", RecoveryCodes: hashes}
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(user, nil).Once()
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return RecoveryCodesLeft(u) == RecoveryCodeCount-1 && u.LastLoginAt != nil
+	})).Return(nil).Once()
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	// Typed in capitals, with spaces, still counts
+	_, err = service.VerifySecondFactor(2, " "+strings.ToUpper(codes[3])+" ")
+	assert.NoError(t, err)
+
+	// The stored user no longer has the code
+	used := mockUserRepo.Calls[1].Arguments.Get(0).(types.User)
+	mockUserRepo.On("GetUserByID", 2).Return(used, nil).Once()
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.FailedLogins == 1
+	})).Return(nil).Once()
+
+	_, err = service.VerifySecondFactor(2, codes[3])
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestDisableTOTP_RequiredByRole(t *testing.T) {
+	user := types.User{ID: 2, Username: "bob", Role: types.RoleManager, PasswordHash: mustHash(t, "correct horse"), TOTPEnabled: true}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(user, nil)
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockSettingRepo.On("GetSetting", types.SettingTwoFactorRoles).Return("manager,admin", nil)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:    mockUserRepo,
+		settingRepo: mockSettingRepo,
+	}
+
+	err := service.DisableTOTP(2, "wrong")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	err = service.DisableTOTP(2, "correct horse")
+	assert.True(t, errors.Is(err, ErrConflict))
+	mockUserRepo.AssertNotCalled(t, "UpdateUser", mock.Anything)
+}
+
+// Turning two-factor on or off signs out every other session
+func TestEnableDisableTOTP_SignsOutSessions(t *testing.T) {
+	raw := []byte("12345678901234567890")
+	secret := totpEncoding.EncodeToString(raw)
+	user := types.User{ID: 2, Username: "bob", Role: types.RoleEmployee, PasswordHash: mustHash(t, "correct horse"), TOTPSecret: secret, SessionVersion: 4}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(user, nil).Once()
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.TOTPEnabled && u.SessionVersion == 5
+	})).Return(nil).Once()
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockSettingRepo.On("GetSetting", types.SettingTwoFactorRoles).Return("", nil)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:    mockUserRepo,
+		settingRepo: mockSettingRepo,
+	}
+
+	_, err := service.EnableTOTP(2, totpCode(raw, time.Now().Unix()/30))
+	assert.NoError(t, err)
+
+	user.TOTPEnabled = true
+	user.SessionVersion = 5
+	mockUserRepo.On("GetUserByID", 2).Return(user, nil).Once()
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return !u.TOTPEnabled && u.SessionVersion == 6
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.DisableTOTP(2, "correct horse"))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSetTwoFactorRoles_UnknownRole(t *testing.T) {
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		settingRepo: new(mocks.SettingRepository),
+	}
+
+	err := service.SetTwoFactorRoles([]string{types.RoleAdmin, "owner"})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
@@ -9,9 +9,9 @@ import (
 )
 
 // TransformVacationToRemote transforms a vacation event into a remote attendance day
-func (s *Service) TransformVacationToRemote(eventID int) error {
+func (s *Service) TransformVacationToRemote(userID int, eventID int) error {
 	// Retrieve the vacation event by ID
-	event, err := s.GetEventByID(eventID)
+	event, err := s.GetEventByID(userID, eventID)
 	if err != nil {
 		return err // Event not found or other error
 	}
@@ -19,20 +19,27 @@ func (s *Service) TransformVacationToRemote(eventID int) error {
 	if event.Type != "vacation" {
 		return errors.New("only vacation events can be transformed into remote days")
 	}
+	if err := s.checkOpen(event.Date); err != nil {
+		return err
+	}
 
 	// Delete the vacation event
-	err = s.eventRepo.DeleteEvent(eventID)
+	err = s.eventRepo.DeleteEvent(userID, eventID)
 	if err != nil {
 		s.logger.Error("Failed to delete vacation event", "eventID", eventID, "error", err)
 		return err
 	}
 
+	changes := []types.Change{eventChange(types.ChangeEventDeleted, event)}
+	defer func() { s.notify(userID, changes...) }()
+
 	// Check if an attendance event exists on that date
-	existingAttendance, err := s.eventRepo.GetEventByDateAndType(event.Date, "attendance")
+	existingAttendance, err := s.eventRepo.GetEventByDateAndType(userID, event.Date, "attendance")
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// No attendance event exists; create one as remote
 			newAttendance := types.Event{
+				UserID:      uint(userID),
 				Date:        event.Date,
 				Description: "Remote day (transformed from vacation)",
 				Type:        "attendance",
@@ -43,6 +50,7 @@ func (s *Service) TransformVacationToRemote(eventID int) error {
 				s.logger.Error("Failed to add new remote attendance event", "date", newAttendance.Date, "error", err)
 				return err
 			}
+			changes = append(changes, eventChange(types.ChangeEventCreated, s.storedEvent(userID, newAttendance)))
 		} else {
 			s.logger.Error("Error fetching attendance event by date", "date", event.Date, "error", err)
 			return err
@@ -56,6 +64,7 @@ func (s *Service) TransformVacationToRemote(eventID int) error {
 			s.logger.Error("Failed to update attendance event to remote", "eventID", existingAttendance.ID, "error", err)
 			return err
 		}
+		changes = append(changes, eventChange(types.ChangeEventUpdated, existingAttendance))
 	}
 
 	return nil
@@ -2,15 +2,20 @@ package types
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 type Event struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Date        time.Time `gorm:"type:date;not null"` // Use 'date' type to store only the date
-	Description string    `gorm:"type:varchar(255);not null"`
-	Type        string    `gorm:"type:varchar(50);not null"` // "holiday", "vacation", "attendance"
-	IsInOffice  bool      `gorm:"default:false"`             // Relevant for "attendance" type
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index;not null;default:0" json:"userId"` // owner; holidays are shared and have none
+	Date        time.Time  `gorm:"type:date;not null"`                     // Use 'date' type to store only the date
+	Description string     `gorm:"type:varchar(255);not null"`
+	Type        string     `gorm:"type:varchar(50);not null"`                // "holiday", "vacation", "attendance"
+	IsInOffice  bool       `gorm:"default:false"`                            // Relevant for "attendance" type
+	OfficeID    uint       `gorm:"index;not null;default:0" json:"officeId"` // where an in-office day was spent; 0 when not recorded
+	ApprovedBy  *uint      `json:"approvedBy,omitempty"`                     // the manager who signed off a vacation; nil until then
+	ApprovedAt  *time.Time `json:"approvedAt,omitempty"`
 }
 
 func (e Event) String() string {
@@ -22,10 +27,521 @@ func (e Event) String() string {
 		e.Type)
 }
 
+// Period is a reporting window (usually a quarter) that stats are measured against
+type Period struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	StartDate time.Time `gorm:"type:date;not null" json:"startDate"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"endDate"`
+	// ClosedAt is set once the period is closed and its stats archived; edits
+	// to days inside a closed period are refused until it is reopened
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+}
+
+// Closed reports whether the period has been closed
+func (p Period) Closed() bool {
+	return p.ClosedAt != nil
+}
+
+// Contains reports whether the date falls inside the period, inclusive
+func (p Period) Contains(date time.Time) bool {
+	return !date.Before(p.StartDate) && !date.After(p.EndDate)
+}
+
+// EventFilter narrows an event query. Zero values are ignored.
+type EventFilter struct {
+	Type     string
+	From     time.Time
+	To       time.Time
+	InOffice *bool  // only attendance events with this in-office status
+	Search   string // case-insensitive match on the description
+	Sort     string // one of EventSortFields, prefixed with "-" for descending
+	Limit    int
+	Offset   int
+}
+
+// EventSortFields are the fields events can be sorted by. Ties are broken by date then ID.
+var EventSortFields = []string{"date", "type", "description"}
+
+// API token scopes. Admin implies every other scope and write:events implies read.
+const (
+	ScopeRead        = "read"
+	ScopeWriteEvents = "write:events"
+	ScopeAdmin       = "admin"
+)
+
+// APIToken is a personal access token for scripted access. Only a hash of the
+// token is stored; the plaintext is shown once when the token is created.
+type APIToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"` // first characters, for display
+	TokenHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"type:varchar(255);not null" json:"scopes"` // comma separated
+	UserID     uint       `gorm:"index;not null;default:0" json:"userId"`   // the token acts as this user
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ScopeList returns the token's scopes as a slice
+func (t APIToken) ScopeList() []string {
+	var scopes []string
+	for _, scope := range strings.Split(t.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// HasScope reports whether the token grants the given scope
+func (t APIToken) HasScope(scope string) bool {
+	for _, granted := range t.ScopeList() {
+		switch {
+		case granted == scope, granted == ScopeAdmin:
+			return true
+		case granted == ScopeWriteEvents && scope == ScopeRead:
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the token is neither revoked nor expired at the given time
+func (t APIToken) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// User is an account that can sign in to the web app. Only a bcrypt hash of the
+// password is stored.
+type User struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Username       string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"username"`
+	Email          string     `gorm:"type:varchar(255);index" json:"email,omitempty"`
+	PasswordHash   string     `gorm:"type:varchar(100);not null" json:"-"` // empty for accounts that only use single sign-on
+	ExternalID     string     `gorm:"type:varchar(500);index" json:"-"`    // "<issuer> <subject>" of a linked OpenID Connect identity
+	Role           string     `gorm:"type:varchar(20);not null;default:employee" json:"role"`
+	ManagerID      *uint      `gorm:"index" json:"managerId,omitempty"`             // the manager this user reports to
+	AnchorDays     string     `gorm:"type:varchar(32)" json:"anchorDays,omitempty"` // for a manager, the days their team all come in, e.g. "T,W"
+	FailedLogins   int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	SessionVersion int        `gorm:"not null;default:0" json:"-"` // bumped to sign out every session
+	TOTPSecret     string     `gorm:"type:varchar(64)" json:"-"`   // base32; set when enrollment starts
+	TOTPEnabled    bool       `gorm:"not null;default:false" json:"totpEnabled"`
+	TOTPLastStep   int64      `gorm:"not null;default:0" json:"-"` // time step of the last accepted code, so each code works once
+	RecoveryCodes  string     `gorm:"type:text" json:"-"`          // sha256 hashes of the unused recovery codes, comma separated
+	LastLoginAt    *time.Time `json:"lastLoginAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// Locked reports whether sign-in is blocked after too many failed attempts
+func (u User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// IsAdmin reports whether the user has the admin role
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// Can reports whether the user's role grants the permission
+func (u User) Can(permission string) bool {
+	return RoleAllows(u.Role, permission)
+}
+
+// ReportsTo reports whether the user's manager is the given user
+func (u User) ReportsTo(managerID uint) bool {
+	return u.ManagerID != nil && *u.ManagerID == managerID
+}
+
+// Roles, from least to most privileged
+const (
+	RoleEmployee = "employee"
+	RoleManager  = "manager"
+	RoleAdmin    = "admin"
+)
+
+// Roles lists every role, in the order they are offered to admins
+var Roles = []string{RoleEmployee, RoleManager, RoleAdmin}
+
+// Permissions a route can require. Roles grant them; API tokens are further
+// limited by their scopes (see PermissionScope).
+const (
+	PermViewOwn        = "own:read"         // own calendar, preferences and stats; shared holidays and periods
+	PermEditOwn        = "own:write"        // own calendar and preferences
+	PermViewReports    = "reports:read"     // the calendars and stats of the user's reports
+	PermApprove        = "requests:approve" // sign off requests made by reports
+	PermManageHolidays = "holidays:manage"
+	PermManagePeriods  = "periods:manage"
+	PermManageUsers    = "users:manage"
+	PermManagePolicies = "policies:manage" // app-wide settings such as sign-up
+	PermManageOffices  = "offices:manage"  // offices and their desks and rooms
+	PermViewOccupancy  = "occupancy:read"  // head-count forecasts across every office, for facilities
+	PermManageJobs     = "jobs:manage"     // the scheduled jobs' status, and running them by hand
+)
+
+var rolePermissions = map[string][]string{
+	RoleEmployee: {PermViewOwn, PermEditOwn},
+	RoleManager:  {PermViewOwn, PermEditOwn, PermViewReports, PermApprove},
+	RoleAdmin: {PermViewOwn, PermEditOwn, PermViewReports, PermApprove,
+		PermManageHolidays, PermManagePeriods, PermManageUsers, PermManagePolicies, PermManageOffices,
+		PermViewOccupancy, PermManageJobs},
+}
+
+// RoleAllows reports whether the role grants the permission. Unknown roles grant nothing.
+func RoleAllows(role, permission string) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidRole reports whether role is one of Roles
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// PermissionScope is the API token scope a permission needs on top of the role
+func PermissionScope(permission string) string {
+	switch permission {
+	case PermViewOwn, PermViewReports, PermViewOccupancy:
+		return ScopeRead
+	case PermEditOwn, PermApprove:
+		return ScopeWriteEvents
+	default:
+		return ScopeAdmin
+	}
+}
+
+// ExternalIdentity is a user vouched for by an OpenID Connect provider
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string // preferred_username, if the provider sends one
+	Role          string // set when the provider sends groups; empty leaves the role alone
+}
+
+// ID is the value stored in User.ExternalID
+func (i ExternalIdentity) ID() string {
+	return i.Issuer + " " + i.Subject
+}
+
+// Session is a browser sign-in kept on the server. The cookie only carries
+// the signed session ID; the database keeps its hash and the session values.
+type Session struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TokenHash  string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UserID     uint      `gorm:"index;not null;default:0" json:"userId"` // 0 until someone signs in
+	Data       string    `gorm:"type:text;not null" json:"-"`            // encoded session values
+	UserAgent  string    `gorm:"type:varchar(255)" json:"userAgent"`
+	IP         string    `gorm:"type:varchar(64)" json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `gorm:"index" json:"expiresAt"`
+}
+
+// Device names the browser and OS from the user agent, e.g. "Firefox on Linux"
+func (s Session) Device() string {
+	ua := s.UserAgent
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.token) {
+			return browser + " on " + o.name
+		}
+	}
+	return browser
+}
+
+// Setting is an app-wide key/value setting
+type Setting struct {
+	Key   string `gorm:"primaryKey;type:varchar(64)"`
+	Value string `gorm:"type:text;not null"`
+}
+
+// Setting keys
+const (
+	SettingRegistrationOpen  = "registration_open"
+	SettingSessionKey        = "session_key"
+	SettingTwoFactorRoles    = "two_factor_roles"    // roles that must use two-factor sign-in, comma separated
+	SettingCountOtherOffices = "count_other_offices" // whether days at an office other than the user's default count
+	SettingRolledOver        = "rolled_over_period"  // ID of the last period default days were filled into by the rollover job
+)
+
+// Change kinds published by the service whenever calendar data changes
+const (
+	ChangeEventCreated      = "event.created"
+	ChangeEventUpdated      = "event.updated"
+	ChangeEventDeleted      = "event.deleted"
+	ChangeAttendanceToggled = "attendance.toggled"
+	ChangeStatsThreshold    = "stats.threshold_crossed"
+)
+
+// ChangeKinds lists every change kind, in the order they are offered to users
+var ChangeKinds = []string{
+	ChangeEventCreated,
+	ChangeEventUpdated,
+	ChangeEventDeleted,
+	ChangeAttendanceToggled,
+	ChangeStatsThreshold,
+}
+
+// Change describes a single mutation made by the service
+type Change struct {
+	Kind       string
+	UserID     uint // whose calendar changed
+	OccurredAt time.Time
+	Event      *Event           // the affected event, for event.* and attendance.toggled
+	Status     string           // "in" or "remote", for attendance.toggled
+	Stats      *AttendanceStats // current period stats, for stats.threshold_crossed
+	Level      string           // "below_minimum", "below_target" or "on_target", for stats.threshold_crossed
+	PrevLevel  string
+}
+
+// ChangeListener receives changes published by the service. Listeners are called
+// synchronously and must not block.
+type ChangeListener func(change Change)
+
+// Webhook is an outbound URL that receives signed JSON payloads for selected changes
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	URL       string    `gorm:"type:varchar(500);not null" json:"url"`
+	Secret    string    `gorm:"type:varchar(100);not null" json:"-"`
+	Events    string    `gorm:"type:varchar(255)" json:"events"` // comma separated change kinds, empty for all
+	Active    bool      `gorm:"default:true" json:"active"`
+	UserID    uint      `gorm:"index;not null;default:0" json:"userId"` // only this user's changes are sent
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Wants reports whether the webhook subscribes to the given change kind
+func (w Webhook) Wants(kind string) bool {
+	if strings.TrimSpace(w.Events) == "" {
+		return true
+	}
+	for _, k := range strings.Split(w.Events, ",") {
+		if strings.TrimSpace(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one payload sent (or being retried) to a webhook
+type WebhookDelivery struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	WebhookID     uint       `gorm:"index;not null" json:"webhookId"`
+	EventType     string     `gorm:"type:varchar(50);not null" json:"eventType"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Attempts      int        `json:"attempts"`
+	StatusCode    int        `json:"statusCode"`
+	Error         string     `gorm:"type:varchar(500)" json:"error"`
+	Success       bool       `json:"success"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+}
+
+// Outcomes of a scheduled job run
+const (
+	JobOK     = "ok"
+	JobFailed = "failed"
+)
+
+// ScheduledJob is the state of a recurring job kept between restarts. While
+// a run is in progress RunningSince is set, which keeps any other server
+// sharing the database from starting the same job.
+type ScheduledJob struct {
+	Name         string     `gorm:"primaryKey;type:varchar(50)" json:"name"`
+	Schedule     string     `gorm:"type:varchar(100)" json:"schedule"` // cron expression, or "off"
+	NextRunAt    *time.Time `json:"nextRunAt,omitempty"`               // nil when the schedule is off
+	LastRunAt    *time.Time `json:"lastRunAt,omitempty"`
+	LastDuration int64      `json:"lastDurationMs"`
+	LastStatus   string     `gorm:"type:varchar(10)" json:"lastStatus,omitempty"` // JobOK or JobFailed; empty before the first run
+	LastResult   string     `gorm:"type:varchar(500)" json:"lastResult,omitempty"`
+	LastError    string     `gorm:"type:varchar(500)" json:"lastError,omitempty"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`
+	RunningSince *time.Time `json:"runningSince,omitempty"`
+	RunningOwner string     `gorm:"type:varchar(100)" json:"-"` // the process holding the run
+}
+
+// PurgeResult counts what the purge job removed
+type PurgeResult struct {
+	Sessions          int64 // expired browser sessions
+	WebhookDeliveries int64
+	EmailDeliveries   int64
+	CompanyRequests   int64 // requests for company on days long past
+}
+
+// Reminder kinds for email notifications
+const (
+	ReminderBehind   = "behind"   // behind the target for the period
+	ReminderUnlogged = "unlogged" // weekdays of last week with nothing logged
+	ReminderVacation = "vacation" // a vacation starting soon
+	ReminderTest     = "test"     // sent on request to check the setup
+)
+
+// NotificationSettings are a user's email reminder choices. Every reminder is
+// off until the user opts in.
+type NotificationSettings struct {
+	UserID       uint    `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	Behind       bool    `json:"behind"`
+	BehindDays   float64 `gorm:"not null;default:1" json:"behindDays"` // how many days behind before a nudge
+	Unlogged     bool    `json:"unlogged"`
+	Vacation     bool    `json:"vacation"`
+	VacationLead int     `gorm:"not null;default:3" json:"vacationLead"` // days ahead to mention an upcoming vacation
+	PTODays      int     `gorm:"not null;default:0" json:"ptoDays"`      // yearly vacation allowance; 0 leaves the balance out
+}
+
+// DefaultNotificationSettings are used until a user saves their own
+func DefaultNotificationSettings(userID uint) NotificationSettings {
+	return NotificationSettings{UserID: userID, BehindDays: 1, VacationLead: 3}
+}
+
+// Reminder is an email the service wants sent, before it is rendered.
+// Only the fields for its kind are set.
+type Reminder struct {
+	Kind string
+	Key  string // a reminder is sent once per user, kind and key
+	User User
+
+	BehindDays float64 // behind
+	TargetDays float64
+	WeeksLeft  float64
+	PeriodName string
+
+	Missing []time.Time // unlogged weekdays
+
+	VacationStart time.Time // vacation
+	VacationEnd   time.Time
+	VacationDays  int
+	HasPTO        bool
+	PTOBalance    int // allowance left after this vacation
+}
+
+// EmailDelivery is one logged attempt to send a reminder
+type EmailDelivery struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"userId"`
+	Kind      string    `gorm:"type:varchar(20);not null" json:"kind"`
+	Key       string    `gorm:"type:varchar(100);index" json:"key"`
+	To        string    `gorm:"type:varchar(255)" json:"to"`
+	Subject   string    `gorm:"type:varchar(255)" json:"subject"`
+	Body      string    `gorm:"type:text" json:"body"`
+	Success   bool      `json:"success"`
+	Error     string    `gorm:"type:varchar(500)" json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type Preferences struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
-	DefaultDays string `json:"defaultDays"` // e.g., "M,T,W,Th,F"
+	UserID      uint   `gorm:"index;not null;default:0" json:"userId"`
+	DefaultDays string `json:"defaultDays"` // e.g., "M,T,W,Th,F", or TeamDefaultDays
 	TargetDays  string `json:"targetDays"`  // e.g., "2.5"
+
+	DefaultOfficeID uint `gorm:"not null;default:0" json:"defaultOfficeId"` // where in-office days are spent unless said otherwise; 0 for none
+
+	CalculationMethod string `json:"calculationMethod"` // MethodCalendar or MethodWorkdays; empty is MethodCalendar
+
+	// Versions are the dated changes to the default days, target and method,
+	// oldest first. The fields above hold the values in force today.
+	Versions []PreferenceVersion `gorm:"-" json:"-"`
+}
+
+// At returns the preferences with the default days, target and method in
+// force on the date
+func (p Preferences) At(date time.Time) Preferences {
+	if version, ok := p.VersionAt(date); ok {
+		p.DefaultDays = version.DefaultDays
+		p.TargetDays = version.TargetDays
+		p.CalculationMethod = version.CalculationMethod
+	}
+	return p
+}
+
+// VersionAt returns the latest version effective on or before the date
+func (p Preferences) VersionAt(date time.Time) (PreferenceVersion, bool) {
+	var found PreferenceVersion
+	ok := false
+	for _, version := range p.Versions {
+		if !version.EffectiveFrom.After(date) && (!ok || version.EffectiveFrom.After(found.EffectiveFrom)) {
+			found, ok = version, true
+		}
+	}
+	return found, ok
+}
+
+// PreferenceVersion is the default days, target and calculation method in
+// force from a date until the user's next version. The first version has a
+// zero EffectiveFrom and covers everything before the others.
+type PreferenceVersion struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	UserID            uint      `gorm:"uniqueIndex:idx_pref_version_user_date;not null" json:"userId"`
+	EffectiveFrom     time.Time `gorm:"uniqueIndex:idx_pref_version_user_date" json:"effectiveFrom"`
+	DefaultDays       string    `json:"defaultDays"`
+	TargetDays        string    `json:"targetDays"`
+	CalculationMethod string    `json:"calculationMethod"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// Calculation methods turn in-office days into days a week to compare with
+// the target
+const (
+	// MethodCalendar spreads in-office days over every calendar day, seven to a week
+	MethodCalendar = "calendar"
+	// MethodWorkdays spreads them over weekdays that are not holidays or
+	// vacation, five to a week
+	MethodWorkdays = "workdays"
+)
+
+// DaysPerWeek is how many counted days make up a week under the method
+func DaysPerWeek(method string) float64 {
+	if method == MethodWorkdays {
+		return 5
+	}
+	return 7
+}
+
+// TeamDefaultDays as Preferences.DefaultDays follows the pattern suggested for
+// the user's team instead of a fixed list of days
+const TeamDefaultDays = "team"
+
+// AnchorCompliance is how often a user came in on their team's anchor days,
+// kept apart from the overall average
+type AnchorCompliance struct {
+	AnchorDays string // e.g. "T,W"; empty when the team has none
+	Days       int    // anchor days so far, not counting holidays and vacation
+	Attended   int    // of those, the days in the office
+	Percent    float64
 }
 
 // CalendarDay represents a single day in the calendar
@@ -46,6 +562,209 @@ type AttendanceStats struct {
 	AverageDays    float64
 	TargetDays     float64
 	AveragePercent float64
+
+	ByOffice  map[uint]int // in-office days per office ID; 0 is days with no office recorded
+	Uncounted int          // days at other offices left out of InOfficeCount by the policy
+
+	Method string // the calculation method TotalDays and AverageDays were worked out with
+}
+
+// WeekStats counts the weekdays of one week of a period by status. Start is
+// the Monday, or the period's first weekday for a week cut short.
+type WeekStats struct {
+	Start    time.Time `json:"start"`
+	InOffice int       `json:"inOffice"`
+	Remote   int       `json:"remote"`
+	Vacation int       `json:"vacation"`
+	Holiday  int       `json:"holiday"`
+	Unlogged int       `json:"unlogged"`
+}
+
+// PeriodArchive is a user's final stats for a closed period, kept as they
+// were when it closed so later edits to old days do not change them
+type PeriodArchive struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	PeriodID       uint         `gorm:"uniqueIndex:idx_period_archive_user;not null" json:"periodId"`
+	UserID         uint         `gorm:"uniqueIndex:idx_period_archive_user;not null" json:"userId"`
+	InOfficeCount  int          `json:"inOfficeCount"`
+	TotalDays      int          `json:"totalDays"`
+	Average        float64      `json:"average"`
+	AverageDays    float64      `json:"averageDays"`
+	TargetDays     float64      `json:"targetDays"`
+	AveragePercent float64      `json:"averagePercent"`
+	ByOffice       map[uint]int `gorm:"serializer:json" json:"byOffice"`
+	Uncounted      int          `json:"uncounted"`
+	Weeks          []WeekStats  `gorm:"serializer:json" json:"weeks"`
+	Method         string       `json:"method"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+// Stats returns the archived figures as AttendanceStats
+func (a PeriodArchive) Stats() AttendanceStats {
+	return AttendanceStats{
+		InOfficeCount:  a.InOfficeCount,
+		TotalDays:      a.TotalDays,
+		Average:        a.Average,
+		AverageDays:    a.AverageDays,
+		TargetDays:     a.TargetDays,
+		AveragePercent: a.AveragePercent,
+		ByOffice:       a.ByOffice,
+		Uncounted:      a.Uncounted,
+		Method:         a.Method,
+	}
+}
+
+// Day statuses shown in the team heatmap. A weekday with nothing logged is DayUnknown.
+const (
+	DayUnknown  = ""
+	DayInOffice = "in"
+	DayRemote   = "remote"
+	DayVacation = "vacation"
+	DayHoliday  = "holiday"
+)
+
+// TeamMember is one row of the team dashboard
+type TeamMember struct {
+	User     User
+	Days     []string        // a Day status for each of TeamDashboard.Days
+	Stats    AttendanceStats // over TeamDashboard.Period
+	OnTarget bool
+	Anchors  AnchorCompliance // over TeamDashboard.Period
+}
+
+// TeamDashboard is the attendance of a manager's reports, day by day, with
+// each member's period average against their target
+type TeamDashboard struct {
+	Period        Period
+	Days          []time.Time // the weekdays of the month shown
+	Members       []TeamMember
+	InOffice      []int   // members in the office on each day
+	AverageDays   float64 // mean of the members' in-office days per week
+	OnTarget      int     // members at or above their target
+	AnchorDays    string  // the anchor days the viewer set for their team
+	AnchorPercent float64 // share of the members' anchor days spent in the office
+}
+
+// CompanyRequest marks a day a user would like teammates to join them in the office
+type CompanyRequest struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"uniqueIndex:idx_company_user_date;not null"`
+	Date      time.Time `gorm:"type:date;uniqueIndex:idx_company_user_date;not null"`
+	CreatedAt time.Time
+}
+
+// OfficeDay is who on a user's team is in the office on a date: planned for
+// days ahead, recorded for days gone by
+type OfficeDay struct {
+	Date            time.Time
+	InOffice        []User // teammates in that day
+	WantCompany     []User // teammates who asked for company that day
+	Status          string // the user's own Day status
+	AskedForCompany bool
+}
+
+// Headcount is how many people are in, counting the user
+func (d OfficeDay) Headcount() int {
+	if d.Status == DayInOffice {
+		return len(d.InOffice) + 1
+	}
+	return len(d.InOffice)
+}
+
+// Office is a company site with desks and meeting rooms that can be booked by the day
+type Office struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Capacity  int       `gorm:"not null;default:0" json:"capacity"` // people allowed in per day; 0 means one per desk
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Resource kinds
+const (
+	ResourceDesk = "desk"
+	ResourceRoom = "room"
+)
+
+// Resource is a desk or meeting room in an office
+type Resource struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	OfficeID uint   `gorm:"uniqueIndex:idx_resource_office_name;not null" json:"officeId"`
+	Name     string `gorm:"type:varchar(100);uniqueIndex:idx_resource_office_name;not null" json:"name"`
+	Kind     string `gorm:"type:varchar(10);not null" json:"kind"`
+}
+
+// Booking holds a desk or room for a user for one day
+type Booking struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index;not null" json:"userId"`
+	OfficeID   uint      `gorm:"index;not null" json:"officeId"`
+	ResourceID uint      `gorm:"uniqueIndex:idx_booking_resource_date;not null" json:"resourceId"`
+	Kind       string    `gorm:"type:varchar(10);not null" json:"kind"` // copied from the resource
+	Date       time.Time `gorm:"type:date;uniqueIndex:idx_booking_resource_date;not null" json:"date"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// BookingDay is one day of an office's booking calendar
+type BookingDay struct {
+	Date     time.Time
+	Capacity int
+	Desks    []Booking
+	Rooms    []Booking
+}
+
+// Free is how many more people can book a desk that day
+func (d BookingDay) Free() int {
+	if free := d.Capacity - len(d.Desks); free > 0 {
+		return free
+	}
+	return 0
+}
+
+// OfficeCalendar is an office's bookings for the weekdays of a month
+type OfficeCalendar struct {
+	Office    Office
+	Resources []Resource
+	Days      []BookingDay
+	Usernames map[uint]string // who holds each booking, by user ID
+}
+
+// OccupancyDay is how many people one office expects on a day and, once the
+// day is past, how many came in
+type OccupancyDay struct {
+	Date     time.Time
+	OfficeID uint // 0 for people with no default office, or at one since deleted
+	Planned  int  // on a default day or planning to be in, less vacation and holidays
+	Actual   int  // logged in office; 0 for days still to come
+	Past     bool
+}
+
+// OccupancyForecast is the per-office, per-day head count over a date range.
+// Past days compare the default-day patterns with what was logged.
+type OccupancyForecast struct {
+	From     time.Time
+	To       time.Time
+	Offices  []Office
+	Days     []OccupancyDay // by date, then office
+	Planned  int            // planned head count summed over the past days
+	Actual   int            // actual head count summed over the past days
+	Accuracy float64        // 100 less the planned miss as a percentage of actual; 0 with no past days
+}
+
+// Plan describes what it takes to reach the target over a date range
+type Plan struct {
+	From          time.Time
+	To            time.Time
+	TargetDays    float64 // target in-office days per week
+	TotalDays     int     // days in the range, counted as AttendanceStats does under Method
+	RequiredDays  int     // in-office days needed over the whole range to meet the target
+	LoggedDays    int     // in-office days up to and including today
+	PlannedDays   int     // in-office days already marked after today
+	RemainingDays int     // in-office days still to schedule, never negative
+	OpenDays      int     // future weekdays not yet in-office and free of holidays and vacation
+	WeeksLeft     float64
+	PerWeek       float64 // RemainingDays spread over WeeksLeft
+	Achievable    bool    // RemainingDays fits into OpenDays
+	Method        string  // the calculation method in force
 }
 
 type BulkAddResult struct {
@@ -64,3 +783,11 @@ type BulkAddResponse struct {
 	Message string          `json:"message"`
 	Results []BulkAddResult `json:"results"`
 }
+
+// DataIssue is a problem found by the consistency check
+type DataIssue struct {
+	Check   string // e.g. "duplicate", "conflict"
+	Date    time.Time
+	EventID uint
+	Message string
+}
@@ -0,0 +1,440 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/domain/types"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Account errors. Unknown usernames and wrong passwords both give
+// ErrInvalidCredentials so the login form doesn't reveal which accounts exist.
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrAccountLocked      = errors.New("too many failed sign-in attempts; try again later")
+	ErrRegistrationClosed = errors.New("registration is closed")
+)
+
+const (
+	// MaxFailedLogins wrong passwords in a row lock the account for LockoutDuration
+	MaxFailedLogins = 5
+	LockoutDuration = 15 * time.Minute
+
+	MinPasswordLength = 8
+)
+
+// passwordCost is the bcrypt cost for new hashes; tests lower it
+var passwordCost = bcrypt.DefaultCost
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,31}$`)
+
+// dummyHash is compared against when a username is unknown, so a failed lookup
+// takes as long as a wrong password
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+func compareDummyHash(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not the password"), passwordCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func validatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidInput, MinPasswordLength)
+	}
+	if len(password) > 72 {
+		// bcrypt ignores everything after 72 bytes
+		return fmt.Errorf("%w: password must be at most 72 bytes", ErrInvalidInput)
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Register creates an account from the sign-up form. The first account is an
+// admin and can always be created; after that registration must be open.
+func (s *Service) Register(username, password string) (*types.User, error) {
+	count, err := s.userRepo.CountUsers()
+	if err != nil {
+		s.logger.Error("Error counting users", "error", err)
+		return nil, err
+	}
+	if count == 0 {
+		user, err := s.newUser(username, password, types.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		added, first, err := s.addFirstAdmin(user)
+		if err != nil || first {
+			return added, err
+		}
+		// Someone else signed up first; this is an ordinary sign-up after all
+	}
+	if !s.RegistrationOpen() {
+		return nil, ErrRegistrationClosed
+	}
+	return s.CreateUser(username, password, types.RoleEmployee)
+}
+
+// CreateUser adds an account with the given role without checking whether
+// registration is open
+func (s *Service) CreateUser(username, password, role string) (*types.User, error) {
+	user, err := s.newUser(username, password, role)
+	if err != nil {
+		return nil, err
+	}
+	user, err = s.userRepo.AddUser(user)
+	if err != nil {
+		s.logger.Error("Error adding user", "username", user.Username, "error", err)
+		return nil, err
+	}
+
+	s.userCreated(user)
+	return &user, nil
+}
+
+// newUser checks the username, password and role of a new account and
+// hashes the password, ready to be stored
+func (s *Service) newUser(username, password, role string) (types.User, error) {
+	username = normalizeUsername(username)
+	if !types.ValidRole(role) {
+		return types.User{}, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+	if !usernamePattern.MatchString(username) {
+		return types.User{}, fmt.Errorf("%w: username must be 3-32 letters, digits, '.', '_' or '-'", ErrInvalidInput)
+	}
+	if err := validatePassword(password); err != nil {
+		return types.User{}, err
+	}
+
+	if _, err := s.userRepo.GetUserByUsername(username); err == nil {
+		return types.User{}, fmt.Errorf("%w: username %q is taken", ErrConflict, username)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error looking up user", "username", username, "error", err)
+		return types.User{}, err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		s.logger.Error("Error hashing password", "error", err)
+		return types.User{}, err
+	}
+	return types.User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// addFirstAdmin stores the user as an admin if no account exists yet. The
+// check and the insert are one step in the repository, so two sign-ups on an
+// empty database cannot both become admin; the later one reports false and
+// nothing is stored.
+func (s *Service) addFirstAdmin(user types.User) (*types.User, bool, error) {
+	user.Role = types.RoleAdmin
+	added, err := s.userRepo.AddFirstUser(user)
+	if errors.Is(err, repository.ErrNotFirstUser) {
+		return nil, false, nil
+	}
+	if err != nil {
+		s.logger.Error("Error adding first user", "username", user.Username, "error", err)
+		return nil, false, err
+	}
+	s.userCreated(added)
+	return &added, true, nil
+}
+
+// userCreated logs a new account and, for an admin, hands it any unowned data
+func (s *Service) userCreated(user types.User) {
+	s.logger.Info("User created", "userID", user.ID, "username", user.Username, "role", user.Role)
+
+	if user.IsAdmin() {
+		// Not fatal; it is tried again at the next start
+		if _, err := s.ClaimUnownedData(); err != nil {
+			s.logger.Error("Error claiming unowned data", "error", err)
+		}
+	}
+}
+
+// ClaimUnownedData gives calendar data recorded before accounts existed to the
+// first admin, so a single-user install carries over to its owner. Shared
+// holidays are left alone. It does nothing until there is an admin.
+func (s *Service) ClaimUnownedData() (int64, error) {
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		s.logger.Error("Error fetching users", "error", err)
+		return 0, err
+	}
+
+	var owner *types.User
+	for i := range users {
+		if users[i].IsAdmin() && (owner == nil || users[i].ID < owner.ID) {
+			owner = &users[i]
+		}
+	}
+	if owner == nil {
+		return 0, nil
+	}
+
+	assigned, err := s.userRepo.AssignUnownedData(int(owner.ID))
+	if err != nil {
+		s.logger.Error("Error assigning unowned data", "userID", owner.ID, "error", err)
+		return 0, err
+	}
+	if assigned > 0 {
+		s.logger.Info("Unowned data assigned", "userID", owner.ID, "username", owner.Username, "rows", assigned)
+	}
+	return assigned, nil
+}
+
+// Authenticate checks a username and password. Repeated failures lock the
+// account; a success clears the failure count and records the sign-in. For
+// accounts with two-factor sign-in it returns the user with
+// ErrSecondFactorRequired instead, and VerifySecondFactor finishes the sign-in.
+func (s *Service) Authenticate(username, password string) (*types.User, error) {
+	user, err := s.userRepo.GetUserByUsername(normalizeUsername(username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			compareDummyHash(password)
+			return nil, ErrInvalidCredentials
+		}
+		s.logger.Error("Error looking up user", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.Locked(now) {
+		return nil, ErrAccountLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		s.recordFailedSignIn(user, now)
+		return nil, ErrInvalidCredentials
+	}
+
+	// Failures are only cleared once the second factor is right too, so the
+	// password step cannot be used to reset the count between code guesses
+	if user.TOTPEnabled {
+		return &user, ErrSecondFactorRequired
+	}
+	return s.recordSignIn(user, now)
+}
+
+// recordFailedSignIn counts a wrong password or code, locking the account
+// after MaxFailedLogins in a row
+func (s *Service) recordFailedSignIn(user types.User, now time.Time) {
+	user.FailedLogins++
+	if user.FailedLogins >= MaxFailedLogins {
+		lockedUntil := now.Add(LockoutDuration)
+		user.LockedUntil = &lockedUntil
+		user.FailedLogins = 0
+		s.logger.Warn("Account locked after failed sign-ins", "userID", user.ID)
+	}
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error recording failed sign-in", "userID", user.ID, "error", err)
+	}
+}
+
+// recordSignIn clears failures and stamps the sign-in time
+func (s *Service) recordSignIn(user types.User, now time.Time) (*types.User, error) {
+	user.FailedLogins = 0
+	user.LockedUntil = nil
+	user.LastLoginAt = &now
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error recording sign-in", "userID", user.ID, "error", err)
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangePassword replaces a user's password after checking the current one.
+// Every other session of the user is signed out.
+func (s *Service) ChangePassword(userID int, current, password string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)) != nil {
+		return fmt.Errorf("%w: current password is wrong", ErrInvalidInput)
+	}
+	return s.setPassword(user, password)
+}
+
+// ResetPassword sets a new password without the current one (for admins) and
+// clears any lockout
+func (s *Service) ResetPassword(username, password string) error {
+	user, err := s.userRepo.GetUserByUsername(normalizeUsername(username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: user %q", ErrNotFound, username)
+		}
+		s.logger.Error("Error looking up user", "username", username, "error", err)
+		return err
+	}
+	user.FailedLogins = 0
+	user.LockedUntil = nil
+	return s.setPassword(user, password)
+}
+
+func (s *Service) setPassword(user types.User, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		s.logger.Error("Error hashing password", "error", err)
+		return err
+	}
+	user.PasswordHash = hash
+	user.SessionVersion++
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		s.logger.Error("Error updating password", "userID", user.ID, "error", err)
+		return err
+	}
+	s.logger.Info("Password changed", "userID", user.ID)
+	return nil
+}
+
+func (s *Service) getUser(userID int) (types.User, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, fmt.Errorf("%w: user %d", ErrNotFound, userID)
+		}
+		s.logger.Error("Error fetching user", "userID", userID, "error", err)
+		return user, err
+	}
+	return user, nil
+}
+
+// GetUser returns a user by ID
+func (s *Service) GetUser(userID int) (*types.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUsers lists all accounts by username
+func (s *Service) GetUsers() ([]types.User, error) {
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		s.logger.Error("Error fetching users", "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// ResolveUser picks the account a command line tool acts as: the named one,
+// or the only account when no name is given. Before any account exists it
+// returns 0, the owner of single-user data.
+func (s *Service) ResolveUser(username string) (int, error) {
+	if username != "" {
+		user, err := s.userRepo.GetUserByUsername(normalizeUsername(username))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, fmt.Errorf("%w: user %q", ErrNotFound, username)
+			}
+			s.logger.Error("Error looking up user", "username", username, "error", err)
+			return 0, err
+		}
+		return int(user.ID), nil
+	}
+
+	users, err := s.GetUsers()
+	if err != nil {
+		return 0, err
+	}
+	switch len(users) {
+	case 0:
+		return 0, nil
+	case 1:
+		return int(users[0].ID), nil
+	default:
+		return 0, fmt.Errorf("%w: there are %d accounts; choose one by username", ErrInvalidInput, len(users))
+	}
+}
+
+// HasUsers reports whether any account exists yet
+func (s *Service) HasUsers() (bool, error) {
+	count, err := s.userRepo.CountUsers()
+	if err != nil {
+		s.logger.Error("Error counting users", "error", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RegistrationOpen reports whether new accounts can sign up. It is off until an admin opens it.
+func (s *Service) RegistrationOpen() bool {
+	value, err := s.settingRepo.GetSetting(types.SettingRegistrationOpen)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Error("Error reading registration setting", "error", err)
+		}
+		return false
+	}
+	open, _ := strconv.ParseBool(value)
+	return open
+}
+
+// SetRegistrationOpen opens or closes sign-up
+func (s *Service) SetRegistrationOpen(open bool) error {
+	if err := s.settingRepo.SetSetting(types.SettingRegistrationOpen, strconv.FormatBool(open)); err != nil {
+		s.logger.Error("Error saving registration setting", "error", err)
+		return err
+	}
+	s.logger.Info("Registration setting changed", "open", open)
+	return nil
+}
+
+// SessionKey returns the key that signs session cookies, generating and storing
+// one on first use so sessions survive restarts
+func (s *Service) SessionKey() ([]byte, error) {
+	value, err := s.settingRepo.GetSetting(types.SettingSessionKey)
+	if err == nil {
+		if key, decodeErr := hex.DecodeString(value); decodeErr == nil && len(key) >= 32 {
+			return key, nil
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Error reading session key", "error", err)
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := s.settingRepo.SetSetting(types.SettingSessionKey, hex.EncodeToString(key)); err != nil {
+		s.logger.Error("Error saving session key", "error", err)
+		return nil, err
+	}
+	s.logger.Info("Generated a new session key")
+	return key, nil
+}
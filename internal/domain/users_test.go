@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	repository "github.com/robstave/rto/internal/adapters/repositories"
+	"github.com/robstave/rto/internal/adapters/repositories/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func init() {
+	// Keep bcrypt fast in tests
+	passwordCost = bcrypt.MinCost
+}
+
+func mustHash(t *testing.T, password string) string {
+	hash, err := hashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestRegister_FirstUserIsAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("CountUsers").Return(int64(0), nil)
+	mockUserRepo.On("GetUserByUsername", "alice").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("AddFirstUser", mock.MatchedBy(func(u types.User) bool {
+		return u.Username == "alice" && u.IsAdmin() && u.PasswordHash != "correct horse"
+	})).Return(types.User{ID: 1, Username: "alice", Role: types.RoleAdmin}, nil)
+	// The first admin takes over data recorded before accounts existed
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 1, Username: "alice", Role: types.RoleAdmin}}, nil)
+	mockUserRepo.On("AssignUnownedData", 1).Return(int64(12), nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	user, err := service.Register("  Alice ", "correct horse")
+
+	assert.NoError(t, err)
+	assert.True(t, user.IsAdmin())
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestRegister_LosingRaceForFirstUser(t *testing.T) {
+	// No account when counted, but another sign-up is stored first
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("CountUsers").Return(int64(0), nil)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("AddFirstUser", mock.Anything).Return(types.User{}, repository.ErrNotFirstUser)
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockSettingRepo.On("GetSetting", types.SettingRegistrationOpen).Return("", gorm.ErrRecordNotFound)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:    mockUserRepo,
+		settingRepo: mockSettingRepo,
+	}
+
+	_, err := service.Register("bob", "correct horse")
+
+	assert.True(t, errors.Is(err, ErrRegistrationClosed))
+	mockUserRepo.AssertNotCalled(t, "AddUser", mock.Anything)
+}
+
+func TestRegister_ClosedAfterFirstUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("CountUsers").Return(int64(1), nil)
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockSettingRepo.On("GetSetting", types.SettingRegistrationOpen).Return("", gorm.ErrRecordNotFound)
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo:    mockUserRepo,
+		settingRepo: mockSettingRepo,
+	}
+
+	_, err := service.Register("bob", "correct horse")
+
+	assert.True(t, errors.Is(err, ErrRegistrationClosed))
+	mockUserRepo.AssertNotCalled(t, "AddUser", mock.Anything)
+}
+
+func TestClaimUnownedData(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 2, Username: "bob"}}, nil).Once()
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	// No admin yet, so nothing is assigned
+	claimed, err := service.ClaimUnownedData()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), claimed)
+	mockUserRepo.AssertNotCalled(t, "AssignUnownedData", mock.Anything)
+
+	// The admin with the lowest ID is the first admin
+	mockUserRepo.On("GetAllUsers").Return([]types.User{
+		{ID: 5, Username: "carol", Role: types.RoleAdmin},
+		{ID: 2, Username: "bob"},
+		{ID: 3, Username: "dave", Role: types.RoleAdmin},
+	}, nil)
+	mockUserRepo.On("AssignUnownedData", 3).Return(int64(4), nil)
+
+	claimed, err = service.ClaimUnownedData()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), claimed)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestResolveUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(types.User{ID: 2, Username: "bob"}, nil)
+	mockUserRepo.On("GetUserByUsername", "zed").Return(types.User{}, gorm.ErrRecordNotFound)
+	mockUserRepo.On("GetAllUsers").Return([]types.User{}, nil).Once()
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 2, Username: "bob"}}, nil).Once()
+	mockUserRepo.On("GetAllUsers").Return([]types.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob"}}, nil).Once()
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	userID, err := service.ResolveUser("Bob")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, userID)
+
+	_, err = service.ResolveUser("zed")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	// Without a name: nobody yet, the only account, then too many to choose from
+	userID, err = service.ResolveUser("")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, userID)
+
+	userID, err = service.ResolveUser("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, userID)
+
+	_, err = service.ResolveUser("")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func TestCreateUser_Validation(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "taken").Return(types.User{ID: 3, Username: "taken"}, nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	_, err := service.CreateUser("no", "correct horse", types.RoleEmployee)
+	assert.True(t, errors.Is(err, ErrInvalidInput), "short username")
+
+	_, err = service.CreateUser("bob smith", "correct horse", types.RoleEmployee)
+	assert.True(t, errors.Is(err, ErrInvalidInput), "space in username")
+
+	_, err = service.CreateUser("bob", "short", types.RoleEmployee)
+	assert.True(t, errors.Is(err, ErrInvalidInput), "short password")
+
+	_, err = service.CreateUser("taken", "correct horse", types.RoleEmployee)
+	assert.True(t, errors.Is(err, ErrConflict), "duplicate")
+}
+
+func TestAuthenticate_LocksAfterRepeatedFailures(t *testing.T) {
+	user := types.User{ID: 2, Username: "bob", PasswordHash: mustHash(t, "correct horse"), FailedLogins: MaxFailedLogins - 1}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(user, nil).Once()
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.FailedLogins == 0 && u.Locked(time.Now())
+	})).Return(nil).Once()
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	_, err := service.Authenticate("bob", "wrong")
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+
+	// Even the right password is refused while locked
+	lockedUntil := time.Now().Add(LockoutDuration)
+	user.LockedUntil = &lockedUntil
+	mockUserRepo.On("GetUserByUsername", "bob").Return(user, nil).Once()
+
+	_, err = service.Authenticate("bob", "correct horse")
+	assert.True(t, errors.Is(err, ErrAccountLocked))
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate_SuccessClearsFailures(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	user := types.User{ID: 2, Username: "bob", PasswordHash: mustHash(t, "correct horse"), FailedLogins: 3, LockedUntil: &expired}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "bob").Return(user, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.FailedLogins == 0 && u.LockedUntil == nil && u.LastLoginAt != nil
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	signedIn, err := service.Authenticate("BOB", "correct horse")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), signedIn.ID)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate_UnknownUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByUsername", "nobody").Return(types.User{}, gorm.ErrRecordNotFound)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	_, err := service.Authenticate("nobody", "whatever")
+
+	assert.True(t, errors.Is(err, ErrInvalidCredentials))
+}
+
+func TestChangePassword_SignsOutOtherSessions(t *testing.T) {
+	user := types.User{ID: 2, Username: "bob", PasswordHash: mustHash(t, "correct horse"), SessionVersion: 4}
+
+	mockUserRepo := new(mocks.UserRepository)
+	mockUserRepo.On("GetUserByID", 2).Return(user, nil)
+	mockUserRepo.On("UpdateUser", mock.MatchedBy(func(u types.User) bool {
+		return u.SessionVersion == 5 &&
+			bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("battery staple")) == nil
+	})).Return(nil)
+
+	service := Service{
+		logger:   slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		userRepo: mockUserRepo,
+	}
+
+	err := service.ChangePassword(2, "wrong", "battery staple")
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	err = service.ChangePassword(2, "correct horse", "battery staple")
+	assert.NoError(t, err)
+	mockUserRepo.AssertNumberOfCalls(t, "UpdateUser", 1)
+}
+
+func TestSessionKey_GeneratedOnce(t *testing.T) {
+	mockSettingRepo := new(mocks.SettingRepository)
+	mockSettingRepo.On("GetSetting", types.SettingSessionKey).Return("", gorm.ErrRecordNotFound).Once()
+	mockSettingRepo.On("SetSetting", types.SettingSessionKey, mock.AnythingOfType("string")).Return(nil).Once()
+
+	service := Service{
+		logger:      slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		settingRepo: mockSettingRepo,
+	}
+
+	key, err := service.SessionKey()
+	assert.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	stored := mockSettingRepo.Calls[1].Arguments.String(1)
+	mockSettingRepo.On("GetSetting", types.SettingSessionKey).Return(stored, nil)
+
+	again, err := service.SessionKey()
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+	mockSettingRepo.AssertExpectations(t)
+}
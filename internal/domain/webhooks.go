@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+// webhookSecretPrefix marks webhook signing secrets
+const webhookSecretPrefix = "whsec_"
+
+// CreateWebhook registers a URL for the given kinds of change to the user's
+// calendar. An empty list subscribes to every kind. The signing secret is generated here.
+func (s *Service) CreateWebhook(userID int, rawURL string, events []string) (*types.Webhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook URL must be an absolute http(s) URL", ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(types.ChangeKinds))
+	for _, kind := range types.ChangeKinds {
+		known[kind] = true
+	}
+	for _, kind := range events {
+		if !known[kind] {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidInput, kind)
+		}
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error("Failed to generate webhook secret", "error", err)
+		return nil, err
+	}
+
+	webhook, err := s.webhookRepo.AddWebhook(types.Webhook{
+		URL:       rawURL,
+		Secret:    webhookSecretPrefix + hex.EncodeToString(raw),
+		Events:    strings.Join(events, ","),
+		Active:    true,
+		UserID:    uint(userID),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to store webhook", "url", rawURL, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("Webhook created", "webhookID", webhook.ID, "events", webhook.Events)
+	return &webhook, nil
+}
+
+// GetWebhooks lists the user's webhooks
+func (s *Service) GetWebhooks(userID int) ([]types.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetAllWebhooks(userID)
+	if err != nil {
+		s.logger.Error("Error fetching webhooks", "error", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook retrieves one of the user's webhooks by ID
+func (s *Service) GetWebhook(userID int, webhookID int) (*types.Webhook, error) {
+	webhook, err := s.webhookRepo.GetWebhookByID(webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: webhook %d", ErrNotFound, webhookID)
+		}
+		s.logger.Error("Error fetching webhook", "webhookID", webhookID, "error", err)
+		return nil, err
+	}
+	if webhook.UserID != uint(userID) {
+		return nil, fmt.Errorf("%w: webhook %d", ErrNotFound, webhookID)
+	}
+	return &webhook, nil
+}
+
+// SetWebhookActive pauses or resumes deliveries to a webhook
+func (s *Service) SetWebhookActive(userID int, webhookID int, active bool) error {
+	webhook, err := s.GetWebhook(userID, webhookID)
+	if err != nil {
+		return err
+	}
+	webhook.Active = active
+	if err := s.webhookRepo.UpdateWebhook(*webhook); err != nil {
+		s.logger.Error("Error updating webhook", "webhookID", webhookID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (s *Service) DeleteWebhook(userID int, webhookID int) error {
+	if _, err := s.GetWebhook(userID, webhookID); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.DeleteWebhook(webhookID); err != nil {
+		s.logger.Error("Error deleting webhook", "webhookID", webhookID, "error", err)
+		return err
+	}
+	s.logger.Info("Webhook deleted", "webhookID", webhookID)
+	return nil
+}
+
+// GetWebhookDeliveries returns the most recent deliveries for a webhook
+func (s *Service) GetWebhookDeliveries(userID int, webhookID int, limit int) ([]types.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	deliveries, err := s.webhookRepo.GetDeliveries(webhookID, limit)
+	if err != nil {
+		s.logger.Error("Error fetching webhook deliveries", "webhookID", webhookID, "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -10,8 +10,27 @@ import (
 
 	"github.com/labstack/echo-contrib/session"
 	"github.com/robstave/rto/internal/adapters/controller"
+	"github.com/robstave/rto/internal/config"
+	"github.com/robstave/rto/internal/domain/types"
 )
 
+// CSRFContextKey holds the request's CSRF token, which the renderer adds to
+// every page as .CSRF; CSRFHeader is where AJAX calls send it back
+const (
+	CSRFContextKey = "csrf"
+	CSRFHeader     = "X-CSRF-Token"
+)
+
+// contentSecurityPolicy allows this site plus the CDNs the templates load.
+// The templates still use inline scripts and styles.
+const contentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' https://code.jquery.com https://cdnjs.cloudflare.com https://d3js.org; " +
+	"style-src 'self' 'unsafe-inline' https://cdnjs.cloudflare.com; " +
+	"font-src 'self' https://cdnjs.cloudflare.com; " +
+	"img-src 'self' data:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"
+
 func GetEcho(rtoCtl *controller.RTOController) *echo.Echo {
 
 	e := echo.New()
@@ -20,22 +39,49 @@ func GetEcho(rtoCtl *controller.RTOController) *echo.Echo {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	//e.Pre(middleware.RemoveTrailingSlash())
-	// CORS
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{"*"},
+
+	// Security headers; HSTS is only sent on HTTPS requests
+	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            31536000,
+		ContentSecurityPolicy: contentSecurityPolicy,
+		ReferrerPolicy:        "same-origin",
 	}))
 
-	store := sessions.NewCookieStore([]byte("    ffffff"))
-	store.Options = &sessions.Options{
-		Path: "/",
-		//Domain:   "192.168.86.176", // Update as per your domain
+	// CORS: only the configured origins, and never with cookies, so other
+	// sites can call the API with a token but not with a visitor's session
+	if origins := config.CORSOrigins(); len(origins) > 0 {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins: origins,
+			AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+			AllowHeaders: []string{echo.HeaderAuthorization, echo.HeaderContentType},
+		}))
+	}
+
+	secureCookies := config.SecureCookies()
+	e.Use(session.Middleware(rtoCtl.SessionStore(&sessions.Options{
+		Path:     "/",
 		MaxAge:   86400 * 7, // 7 days
 		HttpOnly: true,
-		Secure:   false,                // Set to true in production (requires HTTPS)
-		SameSite: http.SameSiteLaxMode, // Adjust as needed
-	}
-	e.Use(session.Middleware(store))
+		Secure:   secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})))
+
+	// Forms and AJAX calls that change state must echo the token from the
+	// _csrf cookie. Bearer requests are skipped since they do not use cookies.
+	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{
+		Skipper:        rtoCtl.SkipCSRF,
+		TokenLookup:    "header:" + CSRFHeader + ",form:_csrf",
+		ContextKey:     CSRFContextKey,
+		CookieName:     "_csrf",
+		CookiePath:     "/",
+		CookieHTTPOnly: true,
+		CookieSecure:   secureCookies,
+		CookieSameSite: http.SameSiteLaxMode,
+		ErrorHandler:   rtoCtl.CSRFFailed,
+	}))
 
 	// Static files
 	e.Static("/static", "static")
@@ -43,34 +89,198 @@ func GetEcho(rtoCtl *controller.RTOController) *echo.Echo {
 	// Public Routes
 	e.GET("/login", rtoCtl.ShowLoginForm)
 	e.POST("/login", rtoCtl.ProcessLogin)
-	e.GET("/logout", rtoCtl.Logout)
+	e.GET("/login/2fa", rtoCtl.ShowSecondFactor)
+	e.POST("/login/2fa", rtoCtl.ProcessSecondFactor)
+	e.POST("/logout", rtoCtl.Logout) // POST, so the CSRF check stops other sites signing users out
+	e.GET("/register", rtoCtl.ShowRegister)
+	e.POST("/register", rtoCtl.ProcessRegister)
+	e.GET("/auth/oidc/login", rtoCtl.SSOLogin)
+	e.GET("/auth/oidc/callback", rtoCtl.SSOCallback)
+	e.GET("/api/v1/openapi.yaml", rtoCtl.OpenAPISpec)
 
-	// Group protected routes
+	// Group protected routes. Every route declares the permission it needs;
+	// API tokens are further limited to the matching scope.
 	r := e.Group("")
 	r.Use(rtoCtl.AuthMiddleware)
 
-	r.GET("/add-event", rtoCtl.ShowAddEventForm) //  show add event form
-	r.POST("/add-event", rtoCtl.AddEvent)        //  handle form submission
+	viewOwn := rtoCtl.Require(types.PermViewOwn)
+	editOwn := rtoCtl.Require(types.PermEditOwn)
 
-	r.GET("/events", rtoCtl.EventsList)
-	r.GET("/prefs", rtoCtl.ShowPrefs)
-	r.POST("/prefs/update", rtoCtl.UpdatePreferences) // New route for updating preferences
+	r.GET("/add-event", rtoCtl.ShowAddEventForm, viewOwn) //  show add event form
+	r.POST("/add-event", rtoCtl.AddEvent, editOwn)        //  handle form submission; holidays need PermManageHolidays
+
+	r.GET("/events", rtoCtl.EventsList, viewOwn)
+	r.GET("/prefs", rtoCtl.ShowPrefs, viewOwn)
+	r.POST("/prefs/update", rtoCtl.UpdatePreferences, editOwn, rtoCtl.RequireScope(types.ScopeAdmin)) // New route for updating preferences
+	r.POST("/prefs/history/:id/delete", rtoCtl.DeletePreferenceVersion, editOwn, rtoCtl.RequireScope(types.ScopeAdmin))
 
 	// Routes
-	r.GET("/", rtoCtl.Home)
-	r.GET("", rtoCtl.Home)
+	r.GET("/", rtoCtl.Home, viewOwn)
+	r.GET("", rtoCtl.Home, viewOwn)
+
+	r.POST("/toggle-attendance", rtoCtl.ToggleAttendance, editOwn)
+
+	r.POST("/prefs/add-default-days", rtoCtl.AddDefaultDays, editOwn)
+	r.POST("/prefs/office", rtoCtl.UpdateDefaultOffice, editOwn)
+	r.DELETE("/events/delete/:id", rtoCtl.DeleteEvent, editOwn)
+	r.POST("/add-events-json", rtoCtl.BulkAddEventsJSON, editOwn)
+
+	r.DELETE("/events/clear/:date", rtoCtl.ClearEventsForDate, editOwn)
+
+	r.GET("/export/markdown", rtoCtl.ExportEventsMarkdown, viewOwn)
+
+	r.GET("/chart-data", rtoCtl.GetChartData, viewOwn)
+
+	// Live event and stats changes for open tabs (Server-Sent Events)
+	r.GET("/events/stream", rtoCtl.EventStream, viewOwn)
+
+	// API token management is only reachable from a browser session
+	r.GET("/tokens", rtoCtl.ShowTokens, rtoCtl.SessionOnly, viewOwn)
+	r.POST("/tokens", rtoCtl.CreateToken, rtoCtl.SessionOnly, editOwn)
+	r.POST("/tokens/:id/revoke", rtoCtl.RevokeToken, rtoCtl.SessionOnly, editOwn)
+
+	// Webhooks carry a signing secret, so they are also managed from a browser session
+	r.GET("/webhooks", rtoCtl.ShowWebhooks, rtoCtl.SessionOnly, viewOwn)
+	r.POST("/webhooks", rtoCtl.CreateWebhook, rtoCtl.SessionOnly, editOwn)
+	r.POST("/webhooks/:id/active", rtoCtl.SetWebhookActive, rtoCtl.SessionOnly, editOwn)
+	r.POST("/webhooks/:id/delete", rtoCtl.DeleteWebhook, rtoCtl.SessionOnly, editOwn)
+	r.POST("/webhooks/deliveries/:id/replay", rtoCtl.ReplayWebhookDelivery, rtoCtl.SessionOnly, editOwn)
+
+	// Email reminders and the log of what was sent
+	r.GET("/notifications", rtoCtl.ShowNotifications, viewOwn)
+	r.POST("/notifications", rtoCtl.UpdateNotifications, editOwn)
+	r.POST("/notifications/test", rtoCtl.SendTestEmail, editOwn)
+
+	// Account settings; sign-up control and user roles are for admins
+	r.GET("/account", rtoCtl.ShowAccount, rtoCtl.SessionOnly, viewOwn)
+	r.POST("/account/password", rtoCtl.ChangePassword, rtoCtl.SessionOnly, editOwn)
+	r.POST("/account/registration", rtoCtl.SetRegistration, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManagePolicies))
+	r.POST("/account/two-factor", rtoCtl.SetTwoFactorPolicy, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManagePolicies))
+	r.POST("/account/other-offices", rtoCtl.SetOtherOffices, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManagePolicies))
+	r.POST("/account/users/:id", rtoCtl.SetUserAccess, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManageUsers))
+	r.POST("/account/users/:id/sign-out", rtoCtl.SignOutUser, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManageUsers))
+	r.POST("/account/users/:id/email", rtoCtl.SetUserEmail, rtoCtl.SessionOnly, rtoCtl.Require(types.PermManageUsers))
+
+	// Scheduled jobs: when they last ran and how it went, and running them by hand
+	manageJobs := rtoCtl.Require(types.PermManageJobs)
+	r.GET("/jobs", rtoCtl.ShowJobs, rtoCtl.SessionOnly, manageJobs)
+	r.POST("/jobs/:name/run", rtoCtl.RunJob, rtoCtl.SessionOnly, manageJobs)
+
+	// Two-factor sign-in
+	r.GET("/account/2fa", rtoCtl.ShowTwoFactor, rtoCtl.SessionOnly, viewOwn)
+	r.POST("/account/2fa/enable", rtoCtl.EnableTwoFactor, rtoCtl.SessionOnly, editOwn)
+	r.POST("/account/2fa/disable", rtoCtl.DisableTwoFactor, rtoCtl.SessionOnly, editOwn)
+	r.POST("/account/2fa/recovery-codes", rtoCtl.RegenerateRecoveryCodes, rtoCtl.SessionOnly, editOwn)
+
+	// Signed-in devices
+	r.GET("/devices", rtoCtl.ShowDevices, rtoCtl.SessionOnly, viewOwn)
+	r.POST("/devices/:id/revoke", rtoCtl.RevokeDevice, rtoCtl.SessionOnly, editOwn)
+	r.POST("/devices/sign-out-all", rtoCtl.SignOutEverywhere, rtoCtl.SessionOnly, editOwn)
+
+	// Periods with each one's results; closing one archives them and locks its days
+	r.GET("/periods", rtoCtl.ShowPeriods, viewOwn)
+	r.POST("/periods/:id/close", rtoCtl.ClosePeriod, rtoCtl.Require(types.PermManagePeriods))
+	r.POST("/periods/:id/reopen", rtoCtl.ReopenPeriod, rtoCtl.Require(types.PermManagePeriods))
+
+	// Who's in the office, for coordinating days with teammates
+	r.GET("/office", rtoCtl.ShowOffice, viewOwn)
+	r.POST("/office/company", rtoCtl.SetCompany, editOwn)
+
+	// Offices with desks and rooms to book by the day; admins keep the inventory
+	manageOffices := rtoCtl.Require(types.PermManageOffices)
+	r.GET("/offices", rtoCtl.ShowOffices, viewOwn)
+	r.POST("/offices", rtoCtl.CreateOffice, manageOffices)
+	r.GET("/offices/:id", rtoCtl.ShowOfficeBookings, viewOwn)
+	r.POST("/offices/:id", rtoCtl.UpdateOffice, manageOffices)
+	r.POST("/offices/:id/delete", rtoCtl.DeleteOffice, manageOffices)
+	r.POST("/offices/:id/book", rtoCtl.BookResource, editOwn)
+	r.POST("/offices/:id/resources", rtoCtl.AddResource, manageOffices)
+	r.POST("/offices/:id/resources/:resourceId/delete", rtoCtl.DeleteResource, manageOffices)
+	r.POST("/bookings/:id/cancel", rtoCtl.CancelBooking, editOwn)
+
+	// Team dashboard for managers, with a read-only calendar per member
+	r.GET("/team", rtoCtl.ShowTeam, rtoCtl.Require(types.PermViewReports))
+	r.GET("/team/:id", rtoCtl.ShowTeamMember, rtoCtl.Require(types.PermViewReports), rtoCtl.ForReport)
+	r.POST("/team/anchors", rtoCtl.SetAnchorDays, rtoCtl.Require(types.PermViewReports), editOwn)
+
+	// Versioned REST API
+	v1 := e.Group("/api/v1")
+	v1.Use(rtoCtl.APIAuthMiddleware)
+
+	manageHolidays := rtoCtl.Require(types.PermManageHolidays)
+	managePeriods := rtoCtl.Require(types.PermManagePeriods)
+	viewReports := rtoCtl.Require(types.PermViewReports)
+
+	v1.GET("/events", rtoCtl.APIListEvents, viewOwn)
+	v1.POST("/events", rtoCtl.APICreateEvent, editOwn)
+	v1.GET("/events/:id", rtoCtl.APIGetEvent, viewOwn)
+	v1.PUT("/events/:id", rtoCtl.APIUpdateEvent, editOwn)
+	v1.DELETE("/events/:id", rtoCtl.APIDeleteEvent, editOwn)
+	v1.POST("/attendance/:date/toggle", rtoCtl.APIToggleAttendance, editOwn)
+	v1.PUT("/attendance/:date", rtoCtl.APISetAttendance, editOwn)
+	v1.POST("/vacations", rtoCtl.APIAddVacation, editOwn)
+
+	v1.GET("/preferences", rtoCtl.APIGetPreferences, viewOwn)
+	v1.PUT("/preferences", rtoCtl.APIUpdatePreferences, editOwn, rtoCtl.RequireScope(types.ScopeAdmin))
+	v1.GET("/preferences/history", rtoCtl.APIGetPreferenceHistory, viewOwn)
+	v1.POST("/preferences/history", rtoCtl.APIAddPreferenceVersion, editOwn, rtoCtl.RequireScope(types.ScopeAdmin))
+	v1.DELETE("/preferences/history/:id", rtoCtl.APIDeletePreferenceVersion, editOwn, rtoCtl.RequireScope(types.ScopeAdmin))
+
+	v1.GET("/notifications", rtoCtl.APIGetNotifications, viewOwn)
+	v1.PUT("/notifications", rtoCtl.APIUpdateNotifications, editOwn)
+	v1.GET("/notifications/deliveries", rtoCtl.APIListEmailDeliveries, viewOwn)
+	v1.POST("/notifications/test", rtoCtl.APISendTestEmail, editOwn)
+
+	v1.GET("/holidays", rtoCtl.APIListHolidays, viewOwn)
+	v1.POST("/holidays", rtoCtl.APICreateHoliday, manageHolidays)
+	v1.DELETE("/holidays/:id", rtoCtl.APIDeleteHoliday, manageHolidays)
+
+	v1.GET("/periods", rtoCtl.APIListPeriods, viewOwn)
+	v1.POST("/periods", rtoCtl.APICreatePeriod, managePeriods)
+	v1.GET("/periods/current", rtoCtl.APIGetCurrentPeriod, viewOwn)
+	v1.GET("/periods/:id", rtoCtl.APIGetPeriod, viewOwn)
+	v1.DELETE("/periods/:id", rtoCtl.APIDeletePeriod, managePeriods)
+	v1.POST("/periods/:id/close", rtoCtl.APIClosePeriod, managePeriods)
+	v1.POST("/periods/:id/reopen", rtoCtl.APIReopenPeriod, managePeriods)
+	v1.GET("/periods/:id/archive", rtoCtl.APIGetPeriodArchive, viewOwn)
+
+	v1.GET("/office/suggestions", rtoCtl.APIOfficeSuggestions, viewOwn)
+	v1.GET("/office/:date", rtoCtl.APIWhoIsIn, viewOwn)
+	v1.PUT("/office/:date/company", rtoCtl.APIRequestCompany, editOwn)
+	v1.DELETE("/office/:date/company", rtoCtl.APIClearCompany, editOwn)
+
+	v1.GET("/offices", rtoCtl.APIListOffices, viewOwn)
+	v1.POST("/offices", rtoCtl.APICreateOffice, manageOffices)
+	v1.PUT("/offices/:id", rtoCtl.APIUpdateOffice, manageOffices)
+	v1.DELETE("/offices/:id", rtoCtl.APIDeleteOffice, manageOffices)
+	v1.GET("/offices/:id/resources", rtoCtl.APIListResources, viewOwn)
+	v1.POST("/offices/:id/resources", rtoCtl.APICreateResource, manageOffices)
+	v1.DELETE("/offices/:id/resources/:resourceId", rtoCtl.APIDeleteResource, manageOffices)
+	v1.GET("/offices/:id/bookings", rtoCtl.APIOfficeBookings, viewOwn)
+	v1.POST("/offices/:id/bookings", rtoCtl.APICreateBooking, editOwn)
+	v1.GET("/bookings", rtoCtl.APIListBookings, viewOwn)
+	v1.DELETE("/bookings/:id", rtoCtl.APICancelBooking, editOwn)
 
-	r.POST("/toggle-attendance", rtoCtl.ToggleAttendance)
+	// Head-count forecasts across every office, for facilities
+	viewOccupancy := rtoCtl.Require(types.PermViewOccupancy)
+	v1.GET("/occupancy", rtoCtl.APIGetOccupancy, viewOccupancy)
+	v1.GET("/occupancy.csv", rtoCtl.APIExportOccupancy, viewOccupancy)
 
-	r.POST("/prefs/add-default-days", rtoCtl.AddDefaultDays)
-	r.DELETE("/events/delete/:id", rtoCtl.DeleteEvent)
-	r.POST("/add-events-json", rtoCtl.BulkAddEventsJSON)
+	v1.GET("/jobs", rtoCtl.APIListJobs, manageJobs)
+	v1.POST("/jobs/:name/run", rtoCtl.APIRunJob, manageJobs)
 
-	r.DELETE("/events/clear/:date", rtoCtl.ClearEventsForDate)
+	v1.GET("/anchors", rtoCtl.APIGetAnchors, viewOwn)
+	v1.PUT("/anchors", rtoCtl.APISetAnchorDays, viewReports, editOwn)
 
-	r.GET("/export/markdown", rtoCtl.ExportEventsMarkdown)
+	v1.GET("/stats", rtoCtl.APIGetStats, viewOwn)
+	v1.GET("/plan", rtoCtl.APIGetPlan, viewOwn)
 
-	r.GET("/chart-data", rtoCtl.GetChartData)
+	// Managers read their reports' calendars and approve their vacations; admins
+	// can do both for anyone
+	v1.GET("/reports", rtoCtl.APIListReports, viewReports)
+	v1.GET("/users/:id/events", rtoCtl.APIListEvents, viewReports, rtoCtl.ForReport)
+	v1.GET("/users/:id/stats", rtoCtl.APIGetStats, viewReports, rtoCtl.ForReport)
+	v1.POST("/users/:id/events/:eventId/approve", rtoCtl.APIApproveEvent, rtoCtl.Require(types.PermApprove))
 
 	return e
 }
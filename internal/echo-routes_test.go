@@ -0,0 +1,431 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robstave/rto/internal/adapters/controller"
+	"github.com/robstave/rto/internal/domain"
+	"github.com/robstave/rto/internal/domain/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// publicRoutes need no sign-in and so no permission
+var publicRoutes = map[string]bool{
+	"GET /login":               true,
+	"POST /login":              true,
+	"GET /login/2fa":           true,
+	"POST /login/2fa":          true,
+	"POST /logout":             true,
+	"GET /register":            true,
+	"POST /register":           true,
+	"GET /auth/oidc/login":     true,
+	"GET /auth/oidc/callback":  true,
+	"GET /api/v1/openapi.yaml": true,
+	"GET /static*":             true,
+	"HEAD /static*":            true,
+}
+
+var routeParam = regexp.MustCompile(`:[a-z]+`)
+
+// newRouteTest serves GetEcho with users of every role, each with a password
+// of "pw" and a username equal to their role ("nobody" has no role)
+func newRouteTest(t *testing.T) (*echo.Echo, *mocks.RTOBLL) {
+	t.Setenv("SESSION_SECRET", "test-secret-test-secret-test-secret")
+
+	mockService := new(mocks.RTOBLL)
+	users := []*types.User{
+		{ID: 1, Username: "nobody"},
+		{ID: 2, Username: types.RoleEmployee, Role: types.RoleEmployee},
+		{ID: 3, Username: types.RoleManager, Role: types.RoleManager},
+		{ID: 4, Username: types.RoleAdmin, Role: types.RoleAdmin},
+	}
+	for _, user := range users {
+		mockService.On("Authenticate", user.Username, "pw").Return(user, nil)
+		mockService.On("GetUser", int(user.ID)).Return(user, nil)
+	}
+	mockService.On("HasUsers").Return(true, nil).Maybe()
+	mockService.On("RegistrationOpen").Return(false).Maybe()
+	mockService.On("TwoFactorRequired", mock.Anything).Return(false).Maybe()
+
+	ctlr := controller.NewRTOControllerWithMock("none", mockService, time.Time{}, time.Time{})
+	e := GetEcho(ctlr)
+	e.Renderer = &pageRenderer{}
+	return e, mockService
+}
+
+// pageRenderer renders a page as its CSRF token
+type pageRenderer struct{}
+
+func (r *pageRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	_, err := fmt.Fprint(w, c.Get(CSRFContextKey))
+	return err
+}
+
+// csrfCookie returns the CSRF cookie a page load sets
+func csrfCookie(t *testing.T, e *echo.Echo) *http.Cookie {
+	rec := serve(e, http.MethodGet, "/login", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == "_csrf" {
+			return cookie
+		}
+	}
+	t.Fatal("no CSRF cookie")
+	return nil
+}
+
+// signIn returns the session and CSRF cookies of the named user
+func signIn(t *testing.T, e *echo.Echo, username string) []*http.Cookie {
+	csrf := csrfCookie(t, e)
+	form := url.Values{"username": {username}, "password": {"pw"}, "_csrf": {csrf.Value}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.AddCookie(csrf)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("sign in as %s answered %d", username, rec.Code)
+	}
+	return append(rec.Result().Cookies(), csrf)
+}
+
+// serve sends the request with the cookies, echoing the CSRF cookie in the
+// header like the pages' scripts do
+func serve(e *echo.Echo, method, path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+		if cookie.Name == "_csrf" {
+			req.Header.Set(CSRFHeader, cookie.Value)
+		}
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// A user whose role grants nothing is turned away by every protected route,
+// which shows that every route declares a permission
+func TestRoutes_AllDeclareAPermission(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	cookies := signIn(t, e, "nobody")
+
+	checked := 0
+	for _, route := range e.Routes() {
+		if publicRoutes[route.Method+" "+route.Path] || route.Method == echo.RouteNotFound {
+			continue
+		}
+		rec := serve(e, route.Method, routeParam.ReplaceAllString(route.Path, "1"), cookies)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code, route.Method+" "+route.Path)
+		assert.Contains(t, rec.Body.String(), `"code":"forbidden"`, route.Method+" "+route.Path)
+		checked++
+	}
+	assert.Greater(t, checked, 40)
+
+	// Denied requests never reach the service
+	for _, call := range mockService.Calls {
+		assert.Contains(t, []string{"Authenticate", "GetUser", "HasUsers", "RegistrationOpen", "TwoFactorRequired"}, call.Method)
+	}
+}
+
+func TestRoutes_RoleDenials(t *testing.T) {
+	e, _ := newRouteTest(t)
+
+	adminOnly := []string{
+		"POST /api/v1/holidays",
+		"DELETE /api/v1/holidays/1",
+		"POST /api/v1/periods",
+		"DELETE /api/v1/periods/1",
+		"POST /api/v1/periods/1/close",
+		"POST /api/v1/periods/1/reopen",
+		"POST /periods/1/close",
+		"POST /account/registration",
+		"POST /account/other-offices",
+		"POST /account/users/1",
+		"POST /account/users/1/sign-out",
+		"POST /account/users/1/email",
+		"POST /offices",
+		"POST /offices/1/resources",
+		"POST /api/v1/offices",
+		"PUT /api/v1/offices/1",
+		"DELETE /api/v1/offices/1/resources/1",
+		"GET /api/v1/occupancy",
+		"GET /api/v1/occupancy.csv",
+		"GET /jobs",
+		"POST /jobs/backup/run",
+		"GET /api/v1/jobs",
+		"POST /api/v1/jobs/backup/run",
+	}
+	managers := []string{
+		"GET /api/v1/reports",
+		"GET /api/v1/users/1/events",
+		"GET /api/v1/users/1/stats",
+		"GET /team",
+		"GET /team/1",
+		"POST /team/anchors",
+		"PUT /api/v1/anchors",
+		"POST /api/v1/users/1/events/1/approve",
+	}
+
+	for role, denied := range map[string][]string{
+		types.RoleEmployee: append(adminOnly, managers...),
+		types.RoleManager:  adminOnly,
+	} {
+		cookies := signIn(t, e, role)
+		for _, route := range denied {
+			method, path, _ := strings.Cut(route, " ")
+			rec := serve(e, method, path, cookies)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code, role+" "+route)
+			assert.Contains(t, rec.Body.String(), `"code":"forbidden"`, role+" "+route)
+		}
+	}
+}
+
+// Managers approve their own reports' vacations only; the service turns the
+// rest away as not found
+func TestRoutes_ApproveVacation(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	approved := uint(3)
+	mockService.On("ApproveVacation", 3, 5, 8).Return(types.Event{ID: 8, UserID: 5, Type: "vacation", Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), ApprovedBy: &approved}, nil)
+	mockService.On("ApproveVacation", 3, 6, 8).Return(types.Event{}, fmt.Errorf("%w: user 6", domain.ErrNotFound))
+
+	cookies := signIn(t, e, types.RoleManager)
+	rec := serve(e, http.MethodPost, "/api/v1/users/5/events/8/approve", cookies)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"approvedBy":3`)
+
+	rec = serve(e, http.MethodPost, "/api/v1/users/6/events/8/approve", cookies)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Employees are stopped before the service is asked
+	rec = serve(e, http.MethodPost, "/api/v1/users/5/events/8/approve", signIn(t, e, types.RoleEmployee))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertNumberOfCalls(t, "ApproveVacation", 2)
+}
+
+func TestRoutes_SetUserEmail(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	mockService.On("SetUserEmail", types.RoleEmployee, "bob@example.com").Return(nil)
+
+	// Admins set the address reminders go to for a password account
+	rec := serve(e, http.MethodPost, "/account/users/2/email?email=bob@example.com", signIn(t, e, types.RoleAdmin))
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+
+	// Nobody else can, not even for themselves
+	rec = serve(e, http.MethodPost, "/account/users/2/email?email=bob@example.com", signIn(t, e, types.RoleEmployee))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockService.AssertNumberOfCalls(t, "SetUserEmail", 1)
+}
+
+func TestTwoFactorSignIn(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	user := &types.User{ID: 5, Username: "tess", Role: types.RoleEmployee, TOTPEnabled: true}
+	mockService.On("Authenticate", "tess", "pw").Return(user, domain.ErrSecondFactorRequired)
+	mockService.On("GetUser", 5).Return(user, nil)
+	mockService.On("VerifySecondFactor", 5, "000000").Return(nil, domain.ErrInvalidCredentials)
+	mockService.On("VerifySecondFactor", 5, "123456").Return(user, nil)
+
+	// The password alone leads to the code form, not a signed-in session
+	csrf := csrfCookie(t, e)
+	post := func(path string, form url.Values, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		form.Set("_csrf", csrf.Value)
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		for _, cookie := range append(cookies, csrf) {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := post("/login", url.Values{"username": {"tess"}, "password": {"pw"}}, nil)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/login/2fa", rec.Header().Get(echo.HeaderLocation))
+	pending := rec.Result().Cookies()
+
+	rec = serve(e, http.MethodGet, "/", append(pending, csrf))
+	assert.Equal(t, "/login", rec.Header().Get(echo.HeaderLocation))
+
+	rec = post("/login/2fa", url.Values{"code": {"000000"}}, pending)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = post("/login/2fa", url.Values{"code": {"123456"}}, pending)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/", rec.Header().Get(echo.HeaderLocation))
+
+	// Without a password step first the code form sends people back
+	rec = post("/login/2fa", url.Values{"code": {"123456"}}, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNumberOfCalls(t, "VerifySecondFactor", 2)
+}
+
+func TestTwoFactorEnrollmentRequired(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	for _, call := range mockService.ExpectedCalls {
+		if call.Method == "TwoFactorRequired" {
+			call.Unset()
+		}
+	}
+	mockService.On("TwoFactorRequired", mock.MatchedBy(func(u types.User) bool {
+		return u.Role == types.RoleEmployee
+	})).Return(true)
+	mockService.On("TwoFactorRequired", mock.Anything).Return(false)
+	mockService.On("BeginTOTPEnrollment", 2).Return("SECRET", "otpauth://totp/RTO:employee?secret=SECRET", nil)
+
+	cookies := signIn(t, e, types.RoleEmployee)
+
+	rec := serve(e, http.MethodGet, "/", cookies)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/account/2fa", rec.Header().Get(echo.HeaderLocation))
+
+	rec = serve(e, http.MethodGet, "/api/v1/events", cookies)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"two_factor_required"`)
+
+	rec = serve(e, http.MethodGet, "/account/2fa", cookies)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Managers are not required to
+	mockService.On("GetReports", 3).Return([]types.User{}, nil)
+	rec = serve(e, http.MethodGet, "/api/v1/reports", signIn(t, e, types.RoleManager))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCSRF(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	mockService.On("ClearEventsForDate", 4, mock.Anything).Return(nil)
+	cookies := signIn(t, e, types.RoleAdmin)
+
+	// The page's token is the one in the cookie
+	rec := serve(e, http.MethodGet, "/login", cookies)
+	assert.Equal(t, cookies[len(cookies)-1].Value, rec.Body.String())
+
+	// Without the token, e.g. from a form on another site
+	req := httptest.NewRequest(http.MethodDelete, "/events/clear/2025-01-06", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"csrf_failed"`)
+	mockService.AssertNotCalled(t, "ClearEventsForDate", mock.Anything, mock.Anything)
+
+	// A forged token
+	req.Header.Set(CSRFHeader, "forged")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, http.MethodDelete, "/events/clear/2025-01-06", cookies)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Logging in needs the token too
+	form := url.Values{"username": {types.RoleAdmin}, "password": {"pw"}}
+	req = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// Signing out changes state, so a link or image on another site cannot do it
+func TestLogout_NeedsCSRFToken(t *testing.T) {
+	e, _ := newRouteTest(t)
+	cookies := signIn(t, e, types.RoleEmployee)
+
+	rec := serve(e, http.MethodGet, "/logout", cookies)
+	assert.NotEqual(t, http.StatusSeeOther, rec.Code, "GET no longer signs out")
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"csrf_failed"`)
+
+	rec = serve(e, http.MethodPost, "/logout", cookies)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/login", rec.Header().Get(echo.HeaderLocation))
+}
+
+func TestCSRF_BearerTokensSkipped(t *testing.T) {
+	e, mockService := newRouteTest(t)
+	mockService.On("AuthenticateAPIToken", "rto_script").
+		Return(&types.APIToken{ID: 1, Scopes: types.ScopeWriteEvents, UserID: 2}, nil)
+	mockService.On("ClearEventsForDate", 2, mock.Anything).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/clear/2025-01-06", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer rto_script")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertCalled(t, "ClearEventsForDate", 2, mock.Anything)
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	e, _ := newRouteTest(t)
+
+	rec := serve(e, http.MethodGet, "/login", nil)
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'")
+	// Plain HTTP gets no HSTS
+	assert.Empty(t, rec.Header().Get("Strict-Transport-Security"))
+
+	req := httptest.NewRequest(http.MethodGet, "/login", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Header().Get("Strict-Transport-Security"), "max-age=31536000")
+}
+
+func TestCookieFlags(t *testing.T) {
+	t.Setenv("COOKIE_SECURE", "true")
+	e, _ := newRouteTest(t)
+
+	cookies := signIn(t, e, types.RoleEmployee)
+	for _, cookie := range cookies {
+		assert.True(t, cookie.Secure, cookie.Name)
+		assert.True(t, cookie.HttpOnly, cookie.Name)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite, cookie.Name)
+	}
+}
+
+func TestCORS_Allowlist(t *testing.T) {
+	t.Setenv("CORS_ORIGINS", "https://dash.example.com")
+	e, _ := newRouteTest(t)
+
+	for origin, allowed := range map[string]bool{
+		"https://dash.example.com": true,
+		"https://evil.example.com": false,
+	} {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/events", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if allowed {
+			assert.Equal(t, origin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		} else {
+			assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin), origin)
+		}
+		assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials), origin)
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import "unicode/utf8"
+
+// Key names produced by decodeKeys. Printable characters are returned as themselves.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyEnter     = "enter"
+	keyEsc       = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl+c"
+)
+
+// decodeKeys splits raw terminal input into key names. Unknown escape
+// sequences are dropped.
+func decodeKeys(buf []byte) []string {
+	var keys []string
+	for len(buf) > 0 {
+		switch b := buf[0]; {
+		case b == 0x1b:
+			if len(buf) >= 3 && (buf[1] == '[' || buf[1] == 'O') {
+				switch buf[2] {
+				case 'A':
+					keys = append(keys, keyUp)
+				case 'B':
+					keys = append(keys, keyDown)
+				case 'C':
+					keys = append(keys, keyRight)
+				case 'D':
+					keys = append(keys, keyLeft)
+				}
+				// Skip the rest of longer sequences such as ESC [ 3 ~
+				n := 2
+				for n < len(buf) && (buf[n] < 0x40 || buf[n] > 0x7e) {
+					n++
+				}
+				buf = buf[min(n+1, len(buf)):]
+				continue
+			}
+			keys = append(keys, keyEsc)
+			buf = buf[1:]
+		case b == '\r' || b == '\n':
+			keys = append(keys, keyEnter)
+			buf = buf[1:]
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, keyBackspace)
+			buf = buf[1:]
+		case b == 0x03:
+			keys = append(keys, keyCtrlC)
+			buf = buf[1:]
+		case b < 0x20:
+			buf = buf[1:]
+		default:
+			r, size := utf8.DecodeRune(buf)
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			buf = buf[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,266 @@
+// Package tui is a keyboard-driven month calendar for the terminal. It shows
+// the same grid as the home page and works through a client.Backend, so it runs
+// against the local database or a server.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/client"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/robstave/rto/internal/utils"
+)
+
+type mode int
+
+const (
+	modeCalendar      mode = iota
+	modeVacationRange      // choosing the last day of a vacation
+	modeVacationDesc       // typing the vacation description
+	modeHelp
+)
+
+// Model holds the calendar state. Update applies a key and View draws the screen.
+type Model struct {
+	backend client.Backend
+	now     func() time.Time
+
+	cursor time.Time
+	weeks  [][]types.CalendarDay
+	report *client.Report
+
+	mode          mode
+	vacationStart time.Time
+	input         string
+	message       string
+	quit          bool
+}
+
+// New returns a model positioned on today
+func New(backend client.Backend, now func() time.Time) *Model {
+	return &Model{
+		backend: backend,
+		now:     now,
+		cursor:  utils.NormalizeDate(now()),
+	}
+}
+
+// Quit reports whether the user asked to leave
+func (m *Model) Quit() bool {
+	return m.quit
+}
+
+// Load fetches the events for the month on screen and the current stats
+func (m *Model) Load() error {
+	weeks := utils.GetCalendarMonth(m.cursor)
+	first := weeks[0][0].Date
+	last := weeks[len(weeks)-1][6].Date
+
+	events, _, err := m.backend.ListEvents(types.EventFilter{From: first, To: last, Limit: 500})
+	if err != nil {
+		return err
+	}
+	byDate := make(map[string][]types.Event)
+	for _, event := range events {
+		key := event.Date.Format("2006-01-02")
+		byDate[key] = append(byDate[key], event)
+	}
+
+	today := utils.NormalizeDate(m.now())
+	for w := range weeks {
+		for d := range weeks[w] {
+			day := &weeks[w][d]
+			day.Events = byDate[day.Date.Format("2006-01-02")]
+			day.Today = day.Date.Equal(today)
+			day.IsFuture = day.Date.After(today) && !day.IsWeekend
+		}
+	}
+	m.weeks = weeks
+
+	report, err := m.backend.Stats()
+	if err != nil {
+		return err
+	}
+	m.report = report
+	return nil
+}
+
+// reload refreshes after a change and reports failures on the message line
+func (m *Model) reload() {
+	if err := m.Load(); err != nil {
+		m.message = "Refresh failed: " + err.Error()
+	}
+}
+
+// Update handles one key
+func (m *Model) Update(key string) {
+	if key == keyCtrlC {
+		m.quit = true
+		return
+	}
+
+	switch m.mode {
+	case modeHelp:
+		m.mode = modeCalendar
+		return
+	case modeVacationDesc:
+		m.updateDescription(key)
+		return
+	}
+
+	if m.mode == modeVacationRange && key == keyEsc {
+		m.mode = modeCalendar
+		m.message = "Vacation cancelled"
+		return
+	}
+
+	switch key {
+	case "q":
+		m.quit = true
+	case "?":
+		m.mode = modeHelp
+	case keyLeft, "h":
+		m.move(0, 0, -1)
+	case keyRight, "l":
+		m.move(0, 0, 1)
+	case keyUp, "k":
+		m.move(0, 0, -7)
+	case keyDown, "j":
+		m.move(0, 0, 7)
+	case "n", "]":
+		m.move(0, 1, 0)
+	case "p", "[":
+		m.move(0, -1, 0)
+	case "t":
+		m.jump(utils.NormalizeDate(m.now()))
+	case "g":
+		m.reload()
+		m.message = "Refreshed"
+	case " ", keyEnter:
+		if m.mode == modeCalendar {
+			m.toggle()
+		}
+	case "i":
+		m.setAttendance(true)
+	case "r":
+		m.setAttendance(false)
+	case "v":
+		m.vacationKey()
+	}
+}
+
+// move shifts the cursor, reloading when it leaves the month on screen
+func (m *Model) move(years, months, days int) {
+	m.jump(m.cursor.AddDate(years, months, days))
+}
+
+func (m *Model) jump(date time.Time) {
+	sameMonth := date.Year() == m.cursor.Year() && date.Month() == m.cursor.Month()
+	m.cursor = date
+	if !sameMonth {
+		m.reload()
+	}
+}
+
+// eventsOn returns the events on a date in the loaded grid
+func (m *Model) eventsOn(date time.Time) []types.Event {
+	for _, week := range m.weeks {
+		for _, day := range week {
+			if day.Date.Equal(date) {
+				return day.Events
+			}
+		}
+	}
+	return nil
+}
+
+// attendanceOn returns the day's attendance event, if any
+func attendanceOn(events []types.Event) *types.Event {
+	for i := range events {
+		if events[i].Type == "attendance" {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// toggle flips the day between in office and remote; a day without
+// attendance becomes an office day
+func (m *Model) toggle() {
+	inOffice := true
+	if existing := attendanceOn(m.eventsOn(m.cursor)); existing != nil {
+		inOffice = !existing.IsInOffice
+	}
+	m.setAttendance(inOffice)
+}
+
+func (m *Model) setAttendance(inOffice bool) {
+	if m.mode != modeCalendar {
+		return
+	}
+	if _, err := m.backend.SetAttendance(m.cursor, inOffice); err != nil {
+		m.message = "Error: " + err.Error()
+		return
+	}
+	status := "remote"
+	if inOffice {
+		status = "in office"
+	}
+	m.message = fmt.Sprintf("%s marked %s", m.cursor.Format("Mon Jan 2"), status)
+	m.reload()
+}
+
+// vacationKey starts a vacation range or, on the second press, asks for its description
+func (m *Model) vacationKey() {
+	switch m.mode {
+	case modeCalendar:
+		m.vacationStart = m.cursor
+		m.mode = modeVacationRange
+		m.message = "Vacation from " + m.cursor.Format("Mon Jan 2") + ": move to the last day and press v (esc cancels)"
+	case modeVacationRange:
+		m.mode = modeVacationDesc
+		m.input = ""
+		m.message = ""
+	}
+}
+
+// vacationRange returns the selected range in order
+func (m *Model) vacationRange() (time.Time, time.Time) {
+	if m.cursor.Before(m.vacationStart) {
+		return m.cursor, m.vacationStart
+	}
+	return m.vacationStart, m.cursor
+}
+
+func (m *Model) updateDescription(key string) {
+	switch key {
+	case keyEsc:
+		m.mode = modeCalendar
+		m.message = "Vacation cancelled"
+	case keyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case keyEnter:
+		desc := strings.TrimSpace(m.input)
+		if desc == "" {
+			m.message = "A description is required"
+			return
+		}
+		m.mode = modeCalendar
+		from, to := m.vacationRange()
+		result, err := m.backend.AddVacation(from, to, desc)
+		if err != nil {
+			m.message = "Error: " + err.Error()
+			return
+		}
+		m.message = result.Message
+		m.reload()
+	default:
+		if len([]rune(key)) == 1 {
+			m.input += key
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robstave/rto/internal/client"
+	"github.com/robstave/rto/internal/client/mocks"
+	"github.com/robstave/rto/internal/domain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testNow = func() time.Time { return time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC) }
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+// newTestBackend serves a January 2025 calendar with the 15th in office
+func newTestBackend() *mocks.Backend {
+	backend := new(mocks.Backend)
+	backend.On("ListEvents", mock.AnythingOfType("types.EventFilter")).Return([]types.Event{
+		{ID: 1, Date: day(1), Type: "holiday", Description: "New Year"},
+		{ID: 2, Date: day(15), Type: "attendance", IsInOffice: true},
+		{ID: 3, Date: day(16), Type: "attendance", IsInOffice: false},
+	}, int64(3), nil)
+	backend.On("Stats").Return(&client.Report{
+		From: day(1),
+		To:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		Stats: types.AttendanceStats{
+			InOfficeCount: 9, TotalDays: 30, Average: 30, AverageDays: 2.1, TargetDays: 2.5,
+		},
+	}, nil)
+	return backend
+}
+
+func TestDecodeKeys(t *testing.T) {
+	keys := decodeKeys([]byte("\x1b[A\x1b[Dq \r\x7f\x1b\x03\x1b[3~é"))
+	assert.Equal(t, []string{keyUp, keyLeft, "q", " ", keyEnter, keyBackspace, keyEsc, keyCtrlC, "é"}, keys)
+}
+
+func TestModel_LoadCoversTheGrid(t *testing.T) {
+	backend := newTestBackend()
+	m := New(backend, testNow)
+
+	assert.NoError(t, m.Load())
+
+	// January 2025 starts on a Wednesday, so the grid runs Sun Dec 29 to Sat Feb 1
+	backend.AssertCalled(t, "ListEvents", types.EventFilter{
+		From:  time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Limit: 500,
+	})
+	assert.Len(t, m.eventsOn(day(15)), 1)
+	assert.True(t, m.weeks[2][3].Today)
+}
+
+func TestModel_ToggleFlipsAttendance(t *testing.T) {
+	backend := newTestBackend()
+	backend.On("SetAttendance", day(15), false).Return(&types.Event{ID: 2, Date: day(15), Type: "attendance"}, nil)
+	backend.On("SetAttendance", day(17), true).Return(&types.Event{ID: 4, Date: day(17), Type: "attendance", IsInOffice: true}, nil)
+
+	m := New(backend, testNow)
+	assert.NoError(t, m.Load())
+
+	m.Update(" ") // the 15th is in office
+	assert.Equal(t, "Wed Jan 15 marked remote", m.message)
+
+	m.Update(keyRight)
+	m.Update(keyRight)
+	m.Update(keyEnter) // the 17th has no attendance yet
+
+	backend.AssertExpectations(t)
+}
+
+func TestModel_VacationRange(t *testing.T) {
+	backend := newTestBackend()
+	backend.On("AddVacation", day(20), day(24), "Ski trip").Return(&types.BulkAddResponse{
+		Message: "Successfully added 5 event(s).",
+	}, nil)
+
+	m := New(backend, testNow)
+	assert.NoError(t, m.Load())
+
+	for _, key := range []string{"j", "h", "h", "v", "l", "l", "l", "l", "v"} {
+		m.Update(key)
+	}
+	assert.Equal(t, modeVacationDesc, m.mode)
+	assert.Contains(t, m.View(), "Vacation Jan 20 to Jan 24")
+
+	for _, key := range strings.Split("Ski tripx", "") {
+		m.Update(key)
+	}
+	m.Update(keyBackspace)
+	m.Update(keyEnter)
+
+	assert.Equal(t, modeCalendar, m.mode)
+	assert.Equal(t, "Successfully added 5 event(s).", m.message)
+	backend.AssertExpectations(t)
+}
+
+func TestModel_VacationCancel(t *testing.T) {
+	backend := newTestBackend()
+	m := New(backend, testNow)
+	assert.NoError(t, m.Load())
+
+	m.Update("v")
+	m.Update("v")
+	m.Update("a")
+	m.Update(keyEsc)
+
+	assert.Equal(t, modeCalendar, m.mode)
+	backend.AssertNotCalled(t, "AddVacation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestModel_MonthChangeReloads(t *testing.T) {
+	backend := newTestBackend()
+	m := New(backend, testNow)
+	assert.NoError(t, m.Load())
+
+	m.Update("n")
+	assert.Equal(t, time.February, m.cursor.Month())
+	backend.AssertNumberOfCalls(t, "ListEvents", 2)
+
+	m.Update("h") // still February
+	backend.AssertNumberOfCalls(t, "ListEvents", 2)
+
+	m.Update("t")
+	assert.Equal(t, day(15), m.cursor)
+	backend.AssertNumberOfCalls(t, "ListEvents", 3)
+}
+
+func TestModel_View(t *testing.T) {
+	backend := newTestBackend()
+	m := New(backend, testNow)
+	assert.NoError(t, m.Load())
+
+	view := m.View()
+
+	assert.Contains(t, view, "January 2025")
+	assert.Contains(t, view, " 1 HOL")
+	assert.Contains(t, view, "15 IN ")
+	assert.Contains(t, view, "16 REM")
+	assert.Contains(t, view, yellow) // 2.1 days/week is under the 2.5 target
+	assert.Contains(t, view, "30% In Office")
+	assert.Contains(t, view, "Wed Jan 15")
+	assert.NotContains(t, strings.ReplaceAll(view, "\r\n", ""), "\n")
+}
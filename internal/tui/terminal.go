@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/robstave/rto/internal/client"
+	"golang.org/x/term"
+)
+
+// refreshInterval is how often the calendar reloads to pick up changes made elsewhere
+const refreshInterval = 30 * time.Second
+
+// Run takes over the terminal until the user quits
+func Run(backend client.Backend, in *os.File, out io.Writer) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("tui needs an interactive terminal")
+	}
+
+	m := New(backend, time.Now)
+	if err := m.Load(); err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("switch terminal to raw mode: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	// Alternate screen, hidden cursor; undone on the way out
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Fprint(out, "\x1b[H\x1b[2J", m.View())
+
+		select {
+		case buf, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range decodeKeys(buf) {
+				m.Update(key)
+			}
+		case <-ticker.C:
+			if m.mode == modeCalendar {
+				m.reload()
+			}
+		}
+		if m.Quit() {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robstave/rto/internal/domain/types"
+)
+
+// ANSI styles
+const (
+	reset     = "\x1b[0m"
+	bold      = "\x1b[1m"
+	dim       = "\x1b[2m"
+	underline = "\x1b[4m"
+	reverse   = "\x1b[7m"
+
+	styleIn       = "\x1b[30;42m" // black on green
+	styleRemote   = "\x1b[97;44m" // white on blue
+	styleVacation = "\x1b[30;43m" // black on yellow
+	styleHoliday  = "\x1b[97;45m" // white on magenta
+
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	green  = "\x1b[32m"
+)
+
+const (
+	cellWidth = 8
+	barWidth  = 30
+)
+
+const helpText = `Keys
+
+  arrows / h j k l   move a day or a week
+  n ] / p [          next / previous month
+  t                  jump to today
+  space, enter       toggle in office / remote (a blank day becomes in office)
+  i / r              mark in office / remote
+  v                  start a vacation here, move to the last day, press v again
+  g                  refresh
+  ?                  this help
+  q, ctrl+c          quit
+
+Press any key to go back.`
+
+// View draws the whole screen. Lines end in \r\n because the terminal is in raw mode.
+func (m *Model) View() string {
+	var b strings.Builder
+	if m.mode == modeHelp {
+		b.WriteString(helpText)
+		return strings.ReplaceAll(b.String(), "\n", "\r\n")
+	}
+
+	fmt.Fprintf(&b, " %s%s%s\n\n", bold, m.cursor.Format("January 2006"), reset)
+	for _, name := range []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"} {
+		fmt.Fprintf(&b, " %-*s", cellWidth-1, name)
+	}
+	b.WriteString("\n")
+
+	for _, week := range m.weeks {
+		for _, day := range week {
+			b.WriteString(m.cell(day))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, " %sIN%s in office  %sREM%s remote  %sVAC%s vacation  %sHOL%s holiday\n\n",
+		styleIn, reset, styleRemote, reset, styleVacation, reset, styleHoliday, reset)
+
+	if m.report != nil {
+		b.WriteString(progressBar(m.report.Stats))
+		fmt.Fprintf(&b, "\n %s to %s: %d of %d days in office, %.2f days/week against a target of %.2f\n",
+			m.report.From.Format("2006-01-02"), m.report.To.Format("2006-01-02"),
+			m.report.Stats.InOfficeCount, m.report.Stats.TotalDays,
+			m.report.Stats.AverageDays, m.report.Stats.TargetDays)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, " %s%s%s  %s\n", bold, m.cursor.Format("Mon Jan 2"), reset, describe(m.eventsOn(m.cursor)))
+	if m.mode == modeVacationDesc {
+		from, to := m.vacationRange()
+		fmt.Fprintf(&b, " Vacation %s to %s, description: %s_\n",
+			from.Format("Jan 2"), to.Format("Jan 2"), m.input)
+	} else {
+		fmt.Fprintf(&b, " %s\n", m.message)
+	}
+	fmt.Fprintf(&b, " %s←↓↑→ move  space toggle  i in  r remote  v vacation  n/p month  t today  ? help  q quit%s",
+		dim, reset)
+
+	return strings.ReplaceAll(b.String(), "\n", "\r\n")
+}
+
+// cell draws one day, e.g. " 15 IN  "
+func (m *Model) cell(day types.CalendarDay) string {
+	label, style := dayLabel(day.Events)
+	text := fmt.Sprintf(" %2d %-3s ", day.Date.Day(), label)
+
+	var prefix string
+	switch {
+	case !day.InMonth:
+		prefix = dim
+		style = ""
+	case day.IsWeekend && style == "":
+		prefix = dim
+	}
+	prefix += style
+	if day.Today {
+		prefix += bold + underline
+	}
+	if m.selected(day.Date) {
+		prefix += reverse
+	}
+	return prefix + text + reset
+}
+
+// selected reports whether a date is under the cursor or inside the vacation being chosen
+func (m *Model) selected(date time.Time) bool {
+	if m.mode == modeVacationRange || m.mode == modeVacationDesc {
+		from, to := m.vacationRange()
+		return !date.Before(from) && !date.After(to)
+	}
+	return date.Equal(m.cursor)
+}
+
+// dayLabel picks the event to show; holidays and vacations win over attendance
+func dayLabel(events []types.Event) (string, string) {
+	label, style := "", ""
+	for _, event := range events {
+		switch event.Type {
+		case "holiday":
+			return "HOL", styleHoliday
+		case "vacation":
+			label, style = "VAC", styleVacation
+		case "attendance":
+			if style == styleVacation {
+				continue
+			}
+			if event.IsInOffice {
+				label, style = "IN", styleIn
+			} else {
+				label, style = "REM", styleRemote
+			}
+		}
+	}
+	return label, style
+}
+
+// describe lists a day's events for the status line
+func describe(events []types.Event) string {
+	if len(events) == 0 {
+		return "no events"
+	}
+	var parts []string
+	for _, event := range events {
+		switch {
+		case event.Type == "attendance" && event.IsInOffice:
+			parts = append(parts, "in office")
+		case event.Type == "attendance":
+			parts = append(parts, "remote")
+		case event.Description != "":
+			parts = append(parts, event.Type+": "+event.Description)
+		default:
+			parts = append(parts, event.Type)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// progressBar mirrors the home page: the fill is the share of days in office,
+// red under 2 days/week, yellow under the target and green at or above it
+func progressBar(stats types.AttendanceStats) string {
+	color := green
+	switch {
+	case stats.AverageDays < 2:
+		color = red
+	case stats.AverageDays < stats.TargetDays:
+		color = yellow
+	}
+
+	filled := int(stats.Average / 100 * barWidth)
+	filled = max(0, min(filled, barWidth))
+	return fmt.Sprintf(" %s%s%s%s %.0f%% In Office", color,
+		strings.Repeat("█", filled), reset, strings.Repeat("░", barWidth-filled), stats.Average)
+}
@@ -0,0 +1,15 @@
+// Sends the page's CSRF token with every jQuery request that changes state.
+// Pages put the token in <meta name="csrf-token">.
+(function () {
+    var meta = document.querySelector('meta[name="csrf-token"]');
+    if (!meta || !window.jQuery) {
+        return;
+    }
+    jQuery.ajaxSetup({
+        beforeSend: function (xhr, settings) {
+            if (!/^(GET|HEAD|OPTIONS)$/i.test(settings.type)) {
+                xhr.setRequestHeader('X-CSRF-Token', meta.getAttribute('content'));
+            }
+        }
+    });
+})();